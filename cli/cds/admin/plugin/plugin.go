@@ -1,8 +1,15 @@
 package plugin
 
 import (
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
 
+	"github.com/olekukonko/tablewriter"
 	"github.com/ovh/cds/sdk"
 	"github.com/spf13/cobra"
 )
@@ -19,12 +26,43 @@ func Cmd() *cobra.Command {
 	cmd.AddCommand(updatePluginCmd)
 	cmd.AddCommand(deletePluginCmd)
 	cmd.AddCommand(downloadPluginCmd)
+	cmd.AddCommand(versionsPluginCmd)
+	cmd.AddCommand(deprecatePluginCmd)
+
+	addPluginCmd.Flags().StringVarP(&pluginVersionFlag, "version", "", "", "Version of the plugin binary, the version returned by the plugin by default")
+	updatePluginCmd.Flags().StringVarP(&pluginVersionFlag, "version", "", "", "Version of the plugin binary, the version returned by the plugin by default")
+	deprecatePluginCmd.Flags().BoolVarP(&undeprecateFlag, "undo", "", false, "Cancel the deprecation of the version")
 	return cmd
 }
 
+var (
+	pluginVersionFlag string
+	undeprecateFlag   bool
+)
+
+// uploadPlugin uploads a plugin binary, or declares a docker plugin from a .json file
+func uploadPlugin(file string, update bool) error {
+	if filepath.Ext(file) != ".json" {
+		_, err := sdk.UploadPlugin(file, pluginVersionFlag, update)
+		return err
+	}
+
+	btes, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	var ap sdk.ActionPlugin
+	if err := json.Unmarshal(btes, &ap); err != nil {
+		return err
+	}
+	_, err = sdk.UploadImagePlugin(ap, update)
+	return err
+}
+
 var addPluginCmd = &cobra.Command{
 	Use:   "add",
-	Short: "cds plugin add <file>",
+	Short: "cds plugin add <file> [--version <version>]",
+	Long:  "Add a version of a plugin from its binary, or of a docker plugin from a JSON file describing its name, description, author, version, image and parameters",
 	Run: func(cmd *cobra.Command, args []string) {
 		if ok, err := sdk.IsAdmin(); !ok {
 			if err != nil {
@@ -38,7 +76,7 @@ var addPluginCmd = &cobra.Command{
 		}
 		var err error
 		for i := 0; i < 5; i++ {
-			_, err = sdk.UploadPlugin(args[0], false)
+			err = uploadPlugin(args[0], false)
 			if err == nil {
 				break
 			}
@@ -52,7 +90,8 @@ var addPluginCmd = &cobra.Command{
 
 var updatePluginCmd = &cobra.Command{
 	Use:   "update",
-	Short: "cds plugin update <file>",
+	Short: "cds plugin update <file> [--version <version>]",
+	Long:  "Add a new version of an existing plugin from its binary, or of a docker plugin from a JSON file describing its name, description, author, version, image and parameters. An existing version cannot be replaced",
 	Run: func(cmd *cobra.Command, args []string) {
 		if ok, err := sdk.IsAdmin(); !ok {
 			if err != nil {
@@ -66,7 +105,7 @@ var updatePluginCmd = &cobra.Command{
 		}
 		var err error
 		for i := 0; i < 5; i++ {
-			_, err = sdk.UploadPlugin(args[0], true)
+			err = uploadPlugin(args[0], true)
 			if err == nil {
 				break
 			}
@@ -80,7 +119,8 @@ var updatePluginCmd = &cobra.Command{
 
 var deletePluginCmd = &cobra.Command{
 	Use:   "delete",
-	Short: "cds plugin delete <name>",
+	Short: "cds plugin delete <name>[@<version>]",
+	Long:  "Delete a plugin with all its versions, or a version of a plugin",
 	Run: func(cmd *cobra.Command, args []string) {
 		if ok, err := sdk.IsAdmin(); !ok {
 			if err != nil {
@@ -92,7 +132,12 @@ var deletePluginCmd = &cobra.Command{
 		if len(args) != 1 {
 			sdk.Exit("Wrong usage: %s\n", cmd.Short)
 		}
-		if err := sdk.DeletePlugin(args[0]); err != nil {
+		name, version := sdk.ParsePluginRef(args[0])
+		if version != "" {
+			if err := sdk.DeletePluginVersion(name, version); err != nil {
+				sdk.Exit("Error: cannot delete plugin %s (%s)\n", args[0], err)
+			}
+		} else if err := sdk.DeletePlugin(name); err != nil {
 			sdk.Exit("Error: cannot delete plugin %s (%s)\n", args[0], err)
 		}
 		fmt.Printf("OK\n")
@@ -101,7 +146,7 @@ var deletePluginCmd = &cobra.Command{
 
 var downloadPluginCmd = &cobra.Command{
 	Use:   "download",
-	Short: "cds plugin download <name>",
+	Short: "cds plugin download <name>[@<version>]",
 	Run: func(cmd *cobra.Command, args []string) {
 		if ok, err := sdk.IsAdmin(); !ok {
 			if err != nil {
@@ -113,9 +158,72 @@ var downloadPluginCmd = &cobra.Command{
 		if len(args) != 1 {
 			sdk.Exit("Wrong usage: %s\n", cmd.Short)
 		}
-		if err := sdk.DownloadPlugin(args[0], "."); err != nil {
+		name, version := sdk.ParsePluginRef(args[0])
+		if err := sdk.DownloadPluginVersion(name, version, "."); err != nil {
 			sdk.Exit("Error: cannot download plugin %s (%s)\n", args[0], err)
 		}
 		fmt.Printf("OK\n")
 	},
 }
+
+var versionsPluginCmd = &cobra.Command{
+	Use:   "versions",
+	Short: "cds plugin versions <name>",
+	Run: func(cmd *cobra.Command, args []string) {
+		if ok, err := sdk.IsAdmin(); !ok {
+			if err != nil {
+				fmt.Printf("Error : %v\n", err)
+			}
+			sdk.Exit("You are not allowed to run this command")
+		}
+
+		if len(args) != 1 {
+			sdk.Exit("Wrong usage: %s\n", cmd.Short)
+		}
+		versions, err := sdk.GetPluginVersions(args[0])
+		if err != nil {
+			sdk.Exit("Error: cannot list versions of plugin %s (%s)\n", args[0], err)
+		}
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Version", "Kind", "Deprecated", "Usage", "Created"})
+		table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
+		table.SetCenterSeparator("|")
+
+		for _, v := range versions {
+			kind := "binary"
+			if v.IsImage() {
+				kind = v.Image
+			}
+			table.Append([]string{v.Version, kind, strconv.FormatBool(v.Deprecated), strconv.FormatInt(v.Usage, 10), v.Created.Format(time.RFC3339)})
+		}
+
+		table.Render()
+	},
+}
+
+var deprecatePluginCmd = &cobra.Command{
+	Use:   "deprecate",
+	Short: "cds plugin deprecate <name>@<version> [--undo]",
+	Long:  "Deprecate a version of a plugin: the version is only used if no other version matches the version pinned by a pipeline",
+	Run: func(cmd *cobra.Command, args []string) {
+		if ok, err := sdk.IsAdmin(); !ok {
+			if err != nil {
+				fmt.Printf("Error : %v\n", err)
+			}
+			sdk.Exit("You are not allowed to run this command")
+		}
+
+		if len(args) != 1 {
+			sdk.Exit("Wrong usage: %s\n", cmd.Short)
+		}
+		name, version := sdk.ParsePluginRef(args[0])
+		if version == "" {
+			sdk.Exit("Wrong usage: %s\n", cmd.Short)
+		}
+		if err := sdk.DeprecatePluginVersion(name, version, !undeprecateFlag); err != nil {
+			sdk.Exit("Error: cannot deprecate plugin %s (%s)\n", args[0], err)
+		}
+		fmt.Printf("OK\n")
+	},
+}
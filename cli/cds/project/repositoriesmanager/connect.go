@@ -31,6 +31,19 @@ func connectReposManager(cmd *cobra.Command, args []string) {
 	if err != nil {
 		sdk.Exit("✘ Error: %s\n", err)
 	}
+
+	//Repositories managers without any API are reached with a SSH key of the project
+	if url == "" {
+		var sshKey string
+		fmt.Println("Enter the name of the project SSH key ?")
+		fmt.Scan(&sshKey)
+		if err := sdk.ConnectReposManagerWithSSHKey(projectKey, rmName, token, sshKey); err != nil {
+			sdk.Exit("✘ Error: %s\n", err)
+		}
+		fmt.Printf("✔ Connection successful to %s \n", rmName)
+		os.Exit(0)
+	}
+
 	fmt.Printf("Go to the following link in your browser\n - %s\n", url)
 
 	if strings.HasPrefix(url, "https://github.com") {
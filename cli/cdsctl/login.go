@@ -10,11 +10,14 @@ import (
 	"reflect"
 	"regexp"
 	"runtime"
+	"time"
 
 	"github.com/howeyc/gopass"
 	"github.com/naoina/toml"
+	"github.com/skratchdot/open-golang/open"
 
 	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/cdsclient"
 	"github.com/ovh/cds/sdk/keychain"
 )
@@ -42,6 +45,10 @@ var loginCmd = cli.Command{
 			ShortHand: "p",
 			Usage:     "CDS Password",
 			Kind:      reflect.String,
+		}, {
+			Name:  "oidc",
+			Usage: "Login with the OpenID Connect provider configured on CDS API",
+			Kind:  reflect.Bool,
 		}, {
 			Name:  "env",
 			Usage: "Display the commands to set up the environment for the cds client",
@@ -56,6 +63,10 @@ func loginRun(v cli.Values) error {
 	password := v.GetString("password")
 	env := v.GetBool("env")
 
+	if v.GetBool("oidc") {
+		return doLoginOIDC(url, env)
+	}
+
 	if env &&
 		(url == "" || username == "" || password == "") {
 		return fmt.Errorf("Please set flags to use --env option")
@@ -104,6 +115,57 @@ func doLogin(url, username, password string, env bool) error {
 		return fmt.Errorf("login failed")
 	}
 
+	return saveLogin(url, username, token, env)
+}
+
+// doLoginOIDC logs in with the device authorization flow: the user approves the login in a browser
+func doLoginOIDC(url string, env bool) error {
+	conf := cdsclient.Config{
+		Host:    url,
+		Verbose: os.Getenv("CDS_VERBOSE") == "true",
+	}
+
+	client = cdsclient.New(conf)
+	device, err := client.UserLoginOIDCDevice()
+	if err != nil {
+		return err
+	}
+
+	// With --env, stdout is evaluated by the shell
+	out := os.Stdout
+	if env {
+		out = os.Stderr
+	}
+	verificationURI := device.VerificationURIComplete
+	if verificationURI == "" {
+		verificationURI = device.VerificationURI
+	}
+	fmt.Fprintf(out, "Open %s in your browser and check the code %s to log in\n", verificationURI, device.UserCode)
+	// Try to open the browser, the user can still do it by himself
+	_ = open.Start(verificationURI)
+
+	interval := time.Duration(device.Interval) * time.Second
+	deadline := time.Now().Add(time.Duration(device.ExpiresIn) * time.Second)
+	for time.Now().Before(deadline) {
+		time.Sleep(interval)
+		res, err := client.UserLoginOIDCDeviceToken(device.DeviceCode)
+		if sdk.ErrorIs(err, sdk.ErrOIDCAuthorizationPending) {
+			continue
+		}
+		// RFC 8628: the interval is increased by 5 seconds on slow_down
+		if sdk.ErrorIs(err, sdk.ErrOIDCSlowDown) {
+			interval += 5 * time.Second
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		return saveLogin(url, res.User.Username, res.Token, env)
+	}
+	return fmt.Errorf("login failed: authorization expired")
+}
+
+func saveLogin(url, username, token string, env bool) error {
 	if env && runtime.GOOS == "windows" {
 		fmt.Println("env option is not supported on windows yet")
 		os.Exit(1)
@@ -58,7 +58,7 @@ func main() {
 		cfg, err = loadConfig(configFile)
 		cli.ExitOnError(err, login.Help)
 
-		if cfg.Host != "" && cfg.User != "" && cfg.Token != "" {
+		if cfg.Host != "" && (cfg.AccessToken != "" || (cfg.User != "" && cfg.Token != "")) {
 			client = cdsclient.New(*cfg)
 		} else {
 			client, err = loadClient(cfg)
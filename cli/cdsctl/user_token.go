@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var (
+	userTokenCmd = cli.Command{
+		Name:  "token",
+		Short: "Manage CDS personal access tokens",
+	}
+
+	userToken = cli.NewCommand(userTokenCmd, nil,
+		[]*cobra.Command{
+			cli.NewGetCommand(userTokenCreateCmd, userTokenCreateRun, nil),
+			cli.NewListCommand(userTokenListCmd, userTokenListRun, nil),
+			cli.NewCommand(userTokenRevokeCmd, userTokenRevokeRun, nil),
+		})
+)
+
+// tokenUsername returns the user owning the tokens. The API can't be called with an empty username,
+// which is the case when cdsctl is only authenticated with CDS_ACCESS_TOKEN.
+func tokenUsername() (string, error) {
+	if cfg.User == "" {
+		return "", fmt.Errorf("unknown user: set CDS_USER with your username")
+	}
+	return cfg.User, nil
+}
+
+var userTokenCreateCmd = cli.Command{
+	Name:  "create",
+	Short: "Create a personal access token. Scopes are comma separated: " + strings.Join(sdk.AvailableAccessTokenScopes, ","),
+	Args: []cli.Arg{
+		{Name: "name"},
+		{Name: "scopes"},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:  "project",
+			Usage: "Restrict the token to a project key",
+			Kind:  reflect.String,
+		},
+		{
+			Name:  "expiration",
+			Usage: "Number of days before the token expires. The token never expires if not set",
+			IsValid: func(s string) bool {
+				_, err := strconv.Atoi(s)
+				return s == "" || err == nil
+			},
+			Kind: reflect.String,
+		},
+	},
+}
+
+func userTokenCreateRun(v cli.Values) (interface{}, error) {
+	username, err := tokenUsername()
+	if err != nil {
+		return nil, err
+	}
+
+	t := sdk.AccessToken{
+		Name:       v["name"],
+		Scopes:     strings.Split(v["scopes"], ","),
+		ProjectKey: v.GetString("project"),
+	}
+	if days := v.GetString("expiration"); days != "" {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return nil, err
+		}
+		exp := time.Now().AddDate(0, 0, n)
+		t.ExpireAt = &exp
+	}
+
+	res, err := client.UserAccessTokenCreate(username, t)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Println("Copy the token now, it won't be displayed again")
+	return *res, nil
+}
+
+var userTokenListCmd = cli.Command{
+	Name:  "list",
+	Short: "List your personal access tokens",
+}
+
+func userTokenListRun(v cli.Values) (cli.ListResult, error) {
+	username, err := tokenUsername()
+	if err != nil {
+		return nil, err
+	}
+	tokens, err := client.UserAccessTokenList(username)
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(tokens), nil
+}
+
+var userTokenRevokeCmd = cli.Command{
+	Name:  "revoke",
+	Short: "Revoke a personal access token",
+	Args: []cli.Arg{
+		{Name: "id"},
+	},
+}
+
+func userTokenRevokeRun(v cli.Values) error {
+	id, err := strconv.ParseInt(v["id"], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid token id %s", v["id"])
+	}
+	username, err := tokenUsername()
+	if err != nil {
+		return err
+	}
+	return client.UserAccessTokenRevoke(username, id)
+}
@@ -12,14 +12,39 @@ import (
 )
 
 func do() {
-	event.ConsumeKafka(viper.GetString("event_kafka_broker_addresses"),
+	if err := consume(func(e sdk.Event) error {
+		return process(e)
+	}); err != nil {
+		log.Errorf("Error while consuming events: %s", err)
+	}
+}
+
+func consume(processFunc func(sdk.Event) error) error {
+	switch viper.GetString("event_bus") {
+	case "amqp":
+		return event.ConsumeAMQP(viper.GetString("event_amqp_url"),
+			viper.GetString("event_amqp_exchange"),
+			viper.GetString("event_amqp_queue"),
+			viper.GetString("event_amqp_binding_key"),
+			processFunc,
+			log.Errorf,
+		)
+	case "nats":
+		return event.ConsumeNATS(viper.GetString("event_nats_url"),
+			viper.GetString("event_nats_subject"),
+			viper.GetString("event_nats_queue"),
+			viper.GetString("event_nats_user"),
+			viper.GetString("event_nats_password"),
+			processFunc,
+			log.Errorf,
+		)
+	}
+	return event.ConsumeKafka(viper.GetString("event_kafka_broker_addresses"),
 		viper.GetString("event_kafka_topic"),
 		viper.GetString("event_kafka_group"),
 		viper.GetString("event_kafka_user"),
 		viper.GetString("event_kafka_password"),
-		func(e sdk.Event) error {
-			return process(e)
-		},
+		processFunc,
 		log.Errorf,
 	)
 }
@@ -30,6 +30,15 @@ func Exists(db gorp.SqlExecutor, name string) (bool, error) {
 
 // InsertAction insert given action into given database
 func InsertAction(tx gorp.SqlExecutor, a *sdk.Action, public bool) error {
+	if err := a.CheckStepNames(); err != nil {
+		return err
+	}
+	for _, c := range a.Actions {
+		if err := sdk.CheckPluginVersionRange(c.PluginVersion); err != nil {
+			return err
+		}
+	}
+
 	ok, errLoop := isTreeLoopFree(tx, a, nil)
 	if errLoop != nil {
 		return errLoop
@@ -81,7 +90,7 @@ func InsertAction(tx gorp.SqlExecutor, a *sdk.Action, public bool) error {
 			}
 		}
 		// Now for each requirement of child, check if it exists in parent
-		for _, cr := range c.Requirements {
+		for _, cr := range stepRequirements(c) {
 			found := false
 			for _, pr := range a.Requirements {
 				if pr.Type == cr.Type && pr.Value == cr.Value {
@@ -229,7 +238,7 @@ func loadActionDependencies(db gorp.SqlExecutor, a *sdk.Action) error {
 	// Requirements of children are requirement of parent
 	for _, c := range a.Actions {
 		// Now for each requirement of child, check if it exists in parent
-		for _, cr := range c.Requirements {
+		for _, cr := range stepRequirements(c) {
 			found := false
 			for _, pr := range a.Requirements {
 				if pr.Type == cr.Type && pr.Value == cr.Value {
@@ -248,6 +257,15 @@ func loadActionDependencies(db gorp.SqlExecutor, a *sdk.Action) error {
 
 // UpdateActionDB  Update an action
 func UpdateActionDB(db gorp.SqlExecutor, a *sdk.Action, userID int64) error {
+	if err := a.CheckStepNames(); err != nil {
+		return err
+	}
+	for _, c := range a.Actions {
+		if err := sdk.CheckPluginVersionRange(c.PluginVersion); err != nil {
+			return err
+		}
+	}
+
 	ok, errLoop := isTreeLoopFree(db, a, nil)
 	if errLoop != nil {
 		return errLoop
@@ -295,7 +313,7 @@ func UpdateActionDB(db gorp.SqlExecutor, a *sdk.Action, userID int64) error {
 	// Requirements of children are requirement of parent
 	for _, c := range a.Actions {
 		// Now for each requirement of child, check if it exists in parent
-		for _, cr := range c.Requirements {
+		for _, cr := range stepRequirements(c) {
 			found := false
 			for _, pr := range a.Requirements {
 				if pr.Type == cr.Type && pr.Value == cr.Value {
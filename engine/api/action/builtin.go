@@ -40,6 +40,57 @@ Parse given file to extract Unit Test results.`
 		return err
 	}
 
+	// ----------------------------------- Coverage ---------------------------
+	coverage := sdk.NewAction(sdk.CoverageAction)
+	coverage.Type = sdk.BuiltinAction
+	coverage.Description = `CDS Builtin Action.
+Parse given files to extract code coverage (Cobertura XML, LCOV or Go coverprofile).`
+	coverage.Parameter(sdk.Parameter{
+		Name:        "path",
+		Description: `Path to coverage files, can be a glob pattern.`,
+		Type:        sdk.StringParameter})
+	coverage.Parameter(sdk.Parameter{
+		Name:        "format",
+		Description: `Format of the coverage files: cobertura, lcov or go. Detected from the content if empty.`,
+		Type:        sdk.StringParameter})
+	coverage.Parameter(sdk.Parameter{
+		Name:        "minimum",
+		Description: `Fail if the line coverage (in percent) is under this value.`,
+		Type:        sdk.StringParameter})
+	coverage.Parameter(sdk.Parameter{
+		Name:        "maxDecrease",
+		Description: `Fail if the line coverage (in percent) decreased more than this value since the previous run on the same branch.`,
+		Type:        sdk.StringParameter})
+	if err := checkBuiltinAction(db, coverage); err != nil {
+		return err
+	}
+
+	// ----------------------------------- Static analysis -------------------
+	analysis := sdk.NewAction(sdk.AnalysisAction)
+	analysis.Type = sdk.BuiltinAction
+	analysis.Description = `CDS Builtin Action.
+Parse given files to extract static analysis and security scan findings (SARIF or Checkstyle XML).`
+	analysis.Parameter(sdk.Parameter{
+		Name:        "path",
+		Description: `Path to report files, can be a glob pattern.`,
+		Type:        sdk.StringParameter})
+	analysis.Parameter(sdk.Parameter{
+		Name:        "format",
+		Description: `Format of the report files: sarif or checkstyle. Detected from the content if empty.`,
+		Type:        sdk.StringParameter})
+	analysis.Parameter(sdk.Parameter{
+		Name:        "failOn",
+		Description: `Fail if there are findings with this severity or higher: info, low, medium, high or critical.`,
+		Type:        sdk.StringParameter})
+	analysis.Parameter(sdk.Parameter{
+		Name:        "onlyNew",
+		Description: `Only consider findings not reported by the previous run on the same branch to fail.`,
+		Value:       "true",
+		Type:        sdk.BooleanParameter})
+	if err := checkBuiltinAction(db, analysis); err != nil {
+		return err
+	}
+
 	// ----------------------------------- Git clone    -----------------------
 	gitclone := sdk.NewAction(sdk.GitCloneAction)
 	gitclone.Type = sdk.BuiltinAction
@@ -10,11 +10,11 @@ import (
 	"github.com/ovh/cds/sdk/log"
 )
 
-func insertEdge(db gorp.SqlExecutor, parentID, childID int64, execOrder int, optional, alwaysExecuted, enabled bool) (int64, error) {
-	query := `INSERT INTO action_edge (parent_id, child_id, exec_order, optional, always_executed, enabled) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`
+func insertEdge(db gorp.SqlExecutor, parentID, childID int64, execOrder int, optional, alwaysExecuted, enabled, parallel bool, stepName, condition, pluginVersion string) (int64, error) {
+	query := `INSERT INTO action_edge (parent_id, child_id, exec_order, optional, always_executed, enabled, parallel, step_name, condition, plugin_version) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id`
 
 	var id int64
-	err := db.QueryRow(query, parentID, childID, execOrder, optional, alwaysExecuted, enabled).Scan(&id)
+	err := db.QueryRow(query, parentID, childID, execOrder, optional, alwaysExecuted, enabled, parallel, stepName, condition, pluginVersion).Scan(&id)
 	if err != nil {
 		return 0, err
 	}
@@ -27,7 +27,7 @@ func insertActionChild(db gorp.SqlExecutor, actionID int64, child sdk.Action, ex
 		return fmt.Errorf("insertActionChild: child action has no id")
 	}
 
-	id, err := insertEdge(db, actionID, child.ID, execOrder, child.Optional, child.AlwaysExecuted, child.Enabled)
+	id, err := insertEdge(db, actionID, child.ID, execOrder, child.Optional, child.AlwaysExecuted, child.Enabled, child.Parallel, child.StepName, child.Condition, child.PluginVersion)
 	if err != nil {
 		return err
 	}
@@ -67,7 +67,7 @@ func loadActionChildren(db gorp.SqlExecutor, actionID int64) ([]sdk.Action, erro
 	var children []sdk.Action
 	var edgeIDs []int64
 	var childrenIDs []int64
-	query := `SELECT id, child_id, exec_order, optional, always_executed, enabled FROM action_edge WHERE parent_id = $1 ORDER BY exec_order ASC`
+	query := `SELECT id, child_id, exec_order, optional, always_executed, enabled, parallel, step_name, condition, plugin_version FROM action_edge WHERE parent_id = $1 ORDER BY exec_order ASC`
 
 	rows, err := db.Query(query, actionID)
 	if err != nil {
@@ -77,13 +77,18 @@ func loadActionChildren(db gorp.SqlExecutor, actionID int64) ([]sdk.Action, erro
 
 	var edgeID, childID int64
 	var execOrder int
-	var optional, alwaysExecuted, enabled bool
+	var optional, alwaysExecuted, enabled, parallel bool
 	var mapOptional = make(map[int64]bool)
 	var mapAlwaysExecuted = make(map[int64]bool)
 	var mapEnabled = make(map[int64]bool)
+	var mapParallel = make(map[int64]bool)
+	var stepName, condition, pluginVersion string
+	var mapStepName = make(map[int64]string)
+	var mapCondition = make(map[int64]string)
+	var mapPluginVersion = make(map[int64]string)
 
 	for rows.Next() {
-		err = rows.Scan(&edgeID, &childID, &execOrder, &optional, &alwaysExecuted, &enabled)
+		err = rows.Scan(&edgeID, &childID, &execOrder, &optional, &alwaysExecuted, &enabled, &parallel, &stepName, &condition, &pluginVersion)
 		if err != nil {
 			return nil, err
 		}
@@ -92,6 +97,10 @@ func loadActionChildren(db gorp.SqlExecutor, actionID int64) ([]sdk.Action, erro
 		mapOptional[edgeID] = optional
 		mapAlwaysExecuted[edgeID] = alwaysExecuted
 		mapEnabled[edgeID] = enabled
+		mapParallel[edgeID] = parallel
+		mapStepName[edgeID] = stepName
+		mapCondition[edgeID] = condition
+		mapPluginVersion[edgeID] = pluginVersion
 	}
 	rows.Close()
 
@@ -118,6 +127,13 @@ func loadActionChildren(db gorp.SqlExecutor, actionID int64) ([]sdk.Action, erro
 		children[i].AlwaysExecuted = mapAlwaysExecuted[edgeIDs[i]]
 		// Get enable flag
 		children[i].Enabled = mapEnabled[edgeIDs[i]]
+		// Get parallel flag
+		children[i].Parallel = mapParallel[edgeIDs[i]]
+		// Get step name and condition
+		children[i].StepName = mapStepName[edgeIDs[i]]
+		children[i].Condition = mapCondition[edgeIDs[i]]
+		// Get pinned plugin version
+		children[i].PluginVersion = mapPluginVersion[edgeIDs[i]]
 	}
 
 	return children, nil
@@ -227,3 +243,19 @@ SELECT action.id FROM action WHERE type = $2 and id = ANY(string_to_array($1, ',
 
 	return joinedActions, nil
 }
+
+// stepRequirements returns the requirements that a step gives to its parent: the plugin requirement
+// of a step pinning a plugin version references the version range as plugin-name@range
+func stepRequirements(c sdk.Action) []sdk.Requirement {
+	if c.PluginVersion == "" {
+		return c.Requirements
+	}
+	reqs := make([]sdk.Requirement, len(c.Requirements))
+	for i, r := range c.Requirements {
+		if r.Type == sdk.PluginRequirement && r.Name == c.Name {
+			r.Value = sdk.PluginRef(c.Name, c.PluginVersion)
+		}
+		reqs[i] = r
+	}
+	return reqs
+}
@@ -3,10 +3,13 @@ package actionplugin
 import (
 	"context"
 	"crypto/md5"
+	"database/sql"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
+	"strings"
 
 	"github.com/go-gorp/gorp"
 
@@ -17,7 +20,7 @@ import (
 )
 
 //Get returns action plugin metadata and parameters list
-func Get(name, path string) (*sdk.ActionPlugin, *plugin.Parameters, error) {
+func Get(name, path string) (*sdk.ActionPlugin, error) {
 	//FIXME: run this in a jail with apparmor
 	log.Debug("actionplugin.Get> Getting info from '%s' (%s)", name, path)
 	client := plugin.NewClient(context.Background(), name, path, "ID", "http://127.0.0.1:8081", true)
@@ -28,23 +31,23 @@ func Get(name, path string) (*sdk.ActionPlugin, *plugin.Parameters, error) {
 	log.Debug("actionplugin.Get> Client '%s'", name)
 	_plugin, err := client.Instance()
 	if err != nil {
-		return nil, nil, sdk.WrapError(err, "actionplugin.Get> ")
+		return nil, sdk.WrapError(err, "actionplugin.Get> ")
 	}
 
 	fi, err := os.Open(path)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 	defer fi.Close()
 	stat, err := fi.Stat()
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
 	//Compute md5sum
 	hash := md5.New()
 	if _, err := io.Copy(hash, fi); err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 	hashInBytes := hash.Sum(nil)[:16]
 	md5sumStr := hex.EncodeToString(hashInBytes)
@@ -58,14 +61,42 @@ func Get(name, path string) (*sdk.ActionPlugin, *plugin.Parameters, error) {
 		Size:        stat.Size(),
 		Perm:        uint32(stat.Mode().Perm()),
 		MD5sum:      md5sumStr,
+		Version:     _plugin.Version(),
 	}
 
 	params := _plugin.Parameters()
+	actionParams, err := pluginParameters(&ap, &params)
+	if err != nil {
+		return nil, err
+	}
+	ap.Parameters = actionParams
 
-	return &ap, &params, nil
+	return &ap, nil
 }
 
-func actionPluginToAction(ap *sdk.ActionPlugin, params *plugin.Parameters) (*sdk.Action, error) {
+//CheckImage checks the metadata and the parameters declared for a docker action plugin
+func CheckImage(ap *sdk.ActionPlugin) error {
+	if ap.Name == "" || ap.Image == "" {
+		return sdk.WrapError(sdk.ErrPluginInvalid, "actionplugin.CheckImage> name and image are mandatory")
+	}
+	if strings.Contains(ap.Image, "://") {
+		return sdk.WrapError(sdk.ErrPluginInvalid, "actionplugin.CheckImage> invalid image %s", ap.Image)
+	}
+	for _, p := range ap.Parameters {
+		if p.Name == "" {
+			return sdk.WrapError(sdk.ErrPluginInvalid, "actionplugin.CheckImage> parameter name is mandatory")
+		}
+		switch p.Type {
+		case sdk.EnvironmentParameter, sdk.PipelineParameter, sdk.ListParameter, sdk.NumberParameter,
+			sdk.StringParameter, sdk.TextParameter, sdk.BooleanParameter:
+		default:
+			return sdk.WrapError(sdk.ErrPluginInvalid, "actionplugin.CheckImage> unsupported parameter type '%s' in plugin '%s'", p.Type, ap.Name)
+		}
+	}
+	return nil
+}
+
+func pluginParameters(ap *sdk.ActionPlugin, params *plugin.Parameters) ([]sdk.Parameter, error) {
 	actionParams := []sdk.Parameter{}
 	names := params.Names()
 	for _, p := range names {
@@ -99,8 +130,11 @@ func actionPluginToAction(ap *sdk.ActionPlugin, params *plugin.Parameters) (*sdk
 			Description: params.GetDescription(p),
 		})
 	}
+	return actionParams, nil
+}
 
-	a := &sdk.Action{
+func actionPluginToAction(ap *sdk.ActionPlugin) *sdk.Action {
+	return &sdk.Action{
 		Name:        ap.Name,
 		Type:        sdk.PluginAction,
 		Description: ap.Description,
@@ -111,59 +145,237 @@ func actionPluginToAction(ap *sdk.ActionPlugin, params *plugin.Parameters) (*sdk
 				Value: ap.Name,
 			},
 		},
-		Parameters: actionParams,
+		Parameters: ap.Parameters,
 		Enabled:    true,
 	}
-	return a, nil
 }
 
-//Insert create action in database
-func Insert(db gorp.SqlExecutor, ap *sdk.ActionPlugin, params *plugin.Parameters) (*sdk.Action, error) {
-	a, err := actionPluginToAction(ap, params)
+//Insert inserts a new version of a plugin. The action of the plugin is created with its first version,
+//and updated with its latest version.
+func Insert(db gorp.SqlExecutor, ap *sdk.ActionPlugin, userID int64) (*sdk.Action, error) {
+	exists, err := action.Exists(db, ap.Name)
 	if err != nil {
 		return nil, err
 	}
 
-	if err = action.InsertAction(db, a, true); err != nil {
-		log.Warning("plugin.Insert> Action: Cannot insert action: %s\n", err)
+	if !exists {
+		a := actionPluginToAction(ap)
+		if err := action.InsertAction(db, a, true); err != nil {
+			log.Warning("plugin.Insert> Action: Cannot insert action: %s\n", err)
+			return nil, err
+		}
+		if err := insert(db, ap); err != nil {
+			return nil, err
+		}
+		return a, nil
+	}
+
+	oldA, err := action.LoadPublicAction(db, ap.Name)
+	if err != nil {
 		return nil, err
 	}
+	if oldA.Type != sdk.PluginAction {
+		return nil, sdk.WrapError(sdk.ErrConflict, "plugin.Insert> action %s is not a plugin", ap.Name)
+	}
 
-	query := `INSERT INTO plugin (name, size, perm, md5sum, object_path) VALUES ($1, $2, $3, $4, $5) RETURNING id`
-	if err = db.QueryRow(query, ap.Name, ap.Size, ap.Perm, ap.MD5sum, ap.ObjectPath).Scan(&ap.ID); err != nil {
+	if err := insert(db, ap); err != nil {
 		return nil, err
 	}
-	return a, nil
+	return updateAction(db, ap, oldA, userID)
 }
 
-//Update action in database
-func Update(db gorp.SqlExecutor, ap *sdk.ActionPlugin, params *plugin.Parameters, userID int64) (*sdk.Action, error) {
-	a, err := actionPluginToAction(ap, params)
+// updateAction updates the action of a plugin with the parameters of the given version if it is the latest one
+func updateAction(db gorp.SqlExecutor, ap *sdk.ActionPlugin, oldA *sdk.Action, userID int64) (*sdk.Action, error) {
+	versions, err := LoadVersions(db, ap.Name)
 	if err != nil {
 		return nil, err
 	}
-
-	//oldA, err := action.LoadPublicAction(db, a.Name, action.WithClearPasswords())
-	oldA, err := action.LoadPublicAction(db, a.Name)
+	latest, err := sdk.ResolvePluginVersion(versions, "")
 	if err != nil {
 		return nil, err
 	}
-	a.ID = oldA.ID
+	if latest.ID != ap.ID {
+		return oldA, nil
+	}
 
+	a := actionPluginToAction(ap)
+	a.ID = oldA.ID
 	if err := action.UpdateActionDB(db, a, userID); err != nil {
 		return nil, err
 	}
+	return a, nil
+}
 
-	query := "DELETE FROM plugin WHERE name = $1"
-	if _, err := db.Exec(query, a.Name); err != nil {
-		return nil, err
+// parametersJSON returns the parameters of a version of a plugin, stored to update the action when the latest version is deleted
+func parametersJSON(ap *sdk.ActionPlugin) ([]byte, error) {
+	params := ap.Parameters
+	if params == nil {
+		params = []sdk.Parameter{}
+	}
+	btes, err := json.Marshal(params)
+	if err != nil {
+		return nil, sdk.WrapError(err, "actionplugin.parametersJSON> cannot marshal parameters of plugin %s", ap.GetName())
+	}
+	return btes, nil
+}
+
+func insert(db gorp.SqlExecutor, ap *sdk.ActionPlugin) error {
+	params, err := parametersJSON(ap)
+	if err != nil {
+		return err
 	}
+	query := `INSERT INTO plugin (name, size, perm, md5sum, object_path, image, version, parameters) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id, created`
+	return db.QueryRow(query, ap.Name, ap.Size, ap.Perm, ap.MD5sum, ap.ObjectPath, ap.Image, ap.Version, params).Scan(&ap.ID, &ap.Created)
+}
+
+const loadVersionsQuery = `SELECT id, name, size, perm, md5sum, object_path, image, version, deprecated, usage, created, parameters FROM plugin`
 
-	query = `INSERT INTO plugin (name, size, perm, md5sum, object_path) VALUES ($1, $2, $3, $4, $5) RETURNING id`
-	if err = db.QueryRow(query, ap.Name, ap.Size, ap.Perm, ap.MD5sum, ap.ObjectPath).Scan(&ap.ID); err != nil {
+func loadVersions(db gorp.SqlExecutor, query string, args ...interface{}) ([]sdk.ActionPlugin, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
 		return nil, err
 	}
-	return a, nil
+	defer rows.Close()
+
+	versions := []sdk.ActionPlugin{}
+	for rows.Next() {
+		var ap sdk.ActionPlugin
+		var size, perm sql.NullInt64
+		var md5sum, objectPath sql.NullString
+		var params []byte
+		if err := rows.Scan(&ap.ID, &ap.Name, &size, &perm, &md5sum, &objectPath, &ap.Image, &ap.Version, &ap.Deprecated, &ap.Usage, &ap.Created, &params); err != nil {
+			return nil, err
+		}
+		ap.Size = size.Int64
+		ap.Perm = uint32(perm.Int64)
+		ap.MD5sum = md5sum.String
+		ap.ObjectPath = objectPath.String
+		// the parameters are unknown for the versions uploaded before they were stored
+		if params != nil {
+			if err := json.Unmarshal(params, &ap.Parameters); err != nil {
+				return nil, err
+			}
+		}
+		versions = append(versions, ap)
+	}
+	sdk.SortPluginVersions(versions)
+	return versions, nil
+}
+
+//LoadVersions loads the versions of a plugin, the highest first
+func LoadVersions(db gorp.SqlExecutor, name string) ([]sdk.ActionPlugin, error) {
+	versions, err := loadVersions(db, loadVersionsQuery+` WHERE name = $1`, name)
+	if err != nil {
+		return nil, sdk.WrapError(err, "actionplugin.LoadVersions> cannot load versions of plugin %s", name)
+	}
+	return versions, nil
+}
+
+//LoadVersion loads a version of a plugin
+func LoadVersion(db gorp.SqlExecutor, name, version string) (*sdk.ActionPlugin, error) {
+	versions, err := loadVersions(db, loadVersionsQuery+` WHERE name = $1 AND version = $2`, name, version)
+	if err != nil {
+		return nil, sdk.WrapError(err, "actionplugin.LoadVersion> cannot load plugin %s", sdk.PluginRef(name, version))
+	}
+	if len(versions) == 0 {
+		return nil, sdk.ErrPluginVersionNotFound
+	}
+	return &versions[0], nil
+}
+
+//Deprecate deprecates a version of a plugin, or cancels its deprecation
+func Deprecate(db gorp.SqlExecutor, name, version string, deprecated bool) error {
+	res, err := db.Exec(`UPDATE plugin SET deprecated = $3 WHERE name = $1 AND version = $2`, name, version, deprecated)
+	if err != nil {
+		return sdk.WrapError(err, "actionplugin.Deprecate> cannot update plugin %s", sdk.PluginRef(name, version))
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sdk.ErrPluginVersionNotFound
+	}
+	return nil
+}
+
+//DeleteVersion deletes a version of a plugin. The last version can't be deleted, the plugin has to be deleted.
+//If the latest version is deleted, the action is updated with the parameters of the new latest version.
+func DeleteVersion(db gorp.SqlExecutor, name, version string, userID int64) error {
+	versions, err := LoadVersions(db, name)
+	if err != nil {
+		return err
+	}
+	found := false
+	for _, v := range versions {
+		if v.Version == version {
+			found = true
+		}
+	}
+	if !found {
+		return sdk.ErrPluginVersionNotFound
+	}
+	if len(versions) == 1 {
+		return sdk.WrapError(sdk.ErrWrongRequest, "actionplugin.DeleteVersion> %s is the last version of plugin %s", version, name)
+	}
+
+	latest, err := sdk.ResolvePluginVersion(versions, "")
+	if err != nil {
+		return sdk.WrapError(err, "actionplugin.DeleteVersion>")
+	}
+
+	if _, err := db.Exec(`DELETE FROM plugin WHERE name = $1 AND version = $2`, name, version); err != nil {
+		return sdk.WrapError(err, "actionplugin.DeleteVersion> cannot delete plugin %s", sdk.PluginRef(name, version))
+	}
+	if latest.Version != version {
+		return nil
+	}
+
+	versions, err = LoadVersions(db, name)
+	if err != nil {
+		return err
+	}
+	newLatest, err := sdk.ResolvePluginVersion(versions, "")
+	if err != nil {
+		return sdk.WrapError(err, "actionplugin.DeleteVersion>")
+	}
+	oldA, err := action.LoadPublicAction(db, name)
+	if err != nil {
+		return sdk.WrapError(err, "actionplugin.DeleteVersion> cannot load action %s", name)
+	}
+	if newLatest.Parameters == nil {
+		log.Warning("actionplugin.DeleteVersion> parameters of %s are unknown, the action is not updated", newLatest.GetName())
+		return nil
+	}
+	if _, err := updateAction(db, newLatest, oldA, userID); err != nil {
+		return sdk.WrapError(err, "actionplugin.DeleteVersion> cannot update action %s", name)
+	}
+	return nil
+}
+
+//IncUsage counts a use of a version of a plugin by a worker
+func IncUsage(db gorp.SqlExecutor, id int64) error {
+	if _, err := db.Exec(`UPDATE plugin SET usage = usage + 1 WHERE id = $1`, id); err != nil {
+		return sdk.WrapError(err, "actionplugin.IncUsage> cannot update plugin %d", id)
+	}
+	return nil
+}
+
+//IncStepUsage counts a use of the plugin version run by a step of a job, if the step is a plugin
+func IncStepUsage(db gorp.SqlExecutor, job *sdk.Action, stepOrder int) error {
+	if stepOrder < 0 || stepOrder >= len(job.Actions) {
+		return nil
+	}
+	step := job.Actions[stepOrder]
+	if step.Type != sdk.PluginAction {
+		return nil
+	}
+
+	versions, err := LoadVersions(db, step.Name)
+	if err != nil {
+		return sdk.WrapError(err, "actionplugin.IncStepUsage>")
+	}
+	ap, err := sdk.ResolvePluginVersion(versions, step.PluginVersion)
+	if err != nil {
+		return sdk.WrapError(err, "actionplugin.IncStepUsage> Unable to resolve %s", sdk.PluginRef(step.Name, step.PluginVersion))
+	}
+	return IncUsage(db, ap.ID)
 }
 
 //Delete action in database
@@ -22,6 +22,11 @@ func (api *API) InitRouter() {
 
 	r := api.Router
 	r.Handle("/login", r.POST(api.loginUserHandler, Auth(false)))
+	r.Handle("/login/oidc/authorize", r.GET(api.loginOIDCAuthorizeHandler, Auth(false)))
+	r.Handle("/login/oidc/callback", r.GET(api.loginOIDCCallbackHandler, Auth(false)))
+	r.Handle("/login/oidc/session", r.POST(api.loginOIDCSessionHandler, Auth(false)))
+	r.Handle("/login/oidc/device", r.POST(api.loginOIDCDeviceHandler, Auth(false)))
+	r.Handle("/login/oidc/device/token", r.POST(api.loginOIDCDeviceTokenHandler, Auth(false)))
 
 	// Action
 	r.Handle("/action", r.GET(api.getActionsHandler))
@@ -43,6 +48,9 @@ func (api *API) InitRouter() {
 	r.Handle("/plugin", r.POST(api.addPluginHandler, NeedAdmin(true)), r.PUT(api.updatePluginHandler, NeedAdmin(true)))
 	r.Handle("/plugin/{name}", r.DELETE(api.deletePluginHandler, NeedAdmin(true)))
 	r.Handle("/plugin/download/{name}", r.GET(api.downloadPluginHandler))
+	r.Handle("/plugin/{name}/version", r.GET(api.getPluginVersionHandler))
+	r.Handle("/plugin/{name}/versions", r.GET(api.getPluginVersionsHandler))
+	r.Handle("/plugin/{name}/versions/{version}", r.PUT(api.putPluginVersionHandler, NeedAdmin(true)), r.DELETE(api.deletePluginVersionHandler, NeedAdmin(true)))
 
 	// Download file
 	r.ServeAbsoluteFile("/download/cli/x86_64", path.Join(api.Config.Directories.Download, "cds-linux-amd64"), "cds")
@@ -111,6 +119,10 @@ func (api *API) InitRouter() {
 	r.Handle("/project/{permProjectKey}/notifications", r.GET(api.getProjectNotificationsHandler))
 	r.Handle("/project/{permProjectKey}/keys", r.GET(api.getKeysInProjectHandler), r.POST(api.addKeyInProjectHandler))
 	r.Handle("/project/{permProjectKey}/keys/{name}", r.DELETE(api.deleteKeyInProjectHandler))
+	r.Handle("/project/{permProjectKey}/subscriptions", r.GET(api.getSubscriptionsInProjectHandler), r.POST(api.addSubscriptionInProjectHandler))
+	r.Handle("/project/{permProjectKey}/subscriptions/{id}", r.GET(api.getSubscriptionInProjectHandler), r.PUT(api.updateSubscriptionInProjectHandler), r.DELETE(api.deleteSubscriptionInProjectHandler))
+	r.Handle("/project/{permProjectKey}/subscriptions/{id}/deliveries", r.GET(api.getSubscriptionDeliveriesHandler))
+	r.Handle("/project/{permProjectKey}/subscriptions/{id}/deliveries/{deliveryID}/retry", r.POST(api.retrySubscriptionDeliveryHandler))
 	// Import Application
 	r.Handle("/project/{permProjectKey}/import/application", r.POST(api.postApplicationImportHandler))
 	// Export Application
@@ -210,7 +222,12 @@ func (api *API) InitRouter() {
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/nodes/{nodeID}/history", r.GET(api.getWorkflowNodeRunHistoryHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/nodes/{nodeRunID}/job/{runJobId}/step/{stepOrder}", r.GET(api.getWorkflowNodeRunJobStepHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/nodes/{nodeRunID}/artifacts", r.GET(api.getWorkflowNodeRunArtifactsHandler))
+	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/nodes/{nodeRunID}/findings", r.GET(api.getWorkflowNodeRunFindingsHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/artifact/{artifactId}", r.GET(api.getDownloadArtifactHandler))
+	r.Handle("/project/{key}/workflows/{permWorkflowName}/coverage", r.GET(api.getWorkflowCoverageHistoryHandler))
+	r.Handle("/project/{key}/workflows/{permWorkflowName}/tests", r.GET(api.getWorkflowTestsHandler))
+	r.Handle("/project/{key}/workflows/{permWorkflowName}/tests/quarantine", r.GET(api.getWorkflowTestsQuarantineHandler), r.POST(api.postWorkflowTestQuarantineHandler))
+	r.Handle("/project/{key}/workflows/{permWorkflowName}/tests/quarantine/{id}", r.DELETE(api.deleteWorkflowTestQuarantineHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/node/{nodeID}/triggers/condition", r.GET(api.getWorkflowTriggerConditionHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/join/{joinID}/triggers/condition", r.GET(api.getWorkflowTriggerJoinConditionHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/nodes/{nodeRunID}/release", r.POST(api.releaseApplicationWorkflowHandler))
@@ -285,8 +302,12 @@ func (api *API) InitRouter() {
 	r.Handle("/queue/workflows/{permID}/result", r.POSTEXECUTE(api.postWorkflowJobResultHandler, NeedWorker()))
 	r.Handle("/queue/workflows/{permID}/log", r.POSTEXECUTE(r.Asynchronous(api.postWorkflowJobLogsHandler, 5), NeedWorker()))
 	r.Handle("/queue/workflows/{permID}/test", r.POSTEXECUTE(api.postWorkflowJobTestsResultsHandler, NeedWorker()))
+	r.Handle("/queue/workflows/{permID}/test/quarantine", r.GET(api.getWorkflowJobTestsQuarantineHandler, NeedWorker()))
+	r.Handle("/queue/workflows/{permID}/coverage", r.POSTEXECUTE(api.postWorkflowJobCoverageHandler, NeedWorker()))
+	r.Handle("/queue/workflows/{permID}/findings", r.POSTEXECUTE(api.postWorkflowJobFindingsHandler, NeedWorker()))
 	r.Handle("/queue/workflows/{permID}/tag", r.POSTEXECUTE(api.postWorkflowJobTagsHandler, NeedWorker()))
 	r.Handle("/queue/workflows/{permID}/variable", r.POSTEXECUTE(api.postWorkflowJobVariableHandler, NeedWorker()))
+	r.Handle("/queue/workflows/{permID}/output", r.POSTEXECUTE(api.postWorkflowJobOutputHandler, NeedWorker()))
 	r.Handle("/queue/workflows/{permID}/step", r.POSTEXECUTE(api.postWorkflowJobStepStatusHandler, NeedWorker()))
 	r.Handle("/queue/workflows/{permID}/artifact/{tag}", r.POSTEXECUTE(api.postWorkflowJobArtifactHandler, NeedWorker()))
 
@@ -335,6 +356,8 @@ func (api *API) InitRouter() {
 	r.Handle("/user/import", r.POST(api.importUsersHandler, NeedAdmin(true)))
 	r.Handle("/user/{username}", r.GET(api.getUserHandler, NeedUsernameOrAdmin(true)), r.PUT(api.updateUserHandler, NeedUsernameOrAdmin(true)), r.DELETE(api.deleteUserHandler, NeedUsernameOrAdmin(true)))
 	r.Handle("/user/{username}/groups", r.GET(api.getUserGroupsHandler, NeedUsernameOrAdmin(true)))
+	r.Handle("/user/{username}/tokens", r.GET(api.getUserAccessTokensHandler, NeedUsernameOrAdmin(true)), r.POST(api.postUserAccessTokenHandler, NeedUsernameOrAdmin(true)))
+	r.Handle("/user/{username}/tokens/{id}", r.DELETE(api.deleteUserAccessTokenHandler, NeedUsernameOrAdmin(true)))
 	r.Handle("/user/{username}/confirm/{token}", r.GET(api.confirmUserHandler, Auth(false)))
 	r.Handle("/user/{username}/reset", r.POST(api.resetUserHandler, Auth(false)))
 	r.Handle("/auth/mode", r.GET(api.authModeHandler, Auth(false)))
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/go-gorp/gorp"
 
@@ -13,6 +14,8 @@ import (
 	"github.com/ovh/cds/engine/api/hatchery"
 	"github.com/ovh/cds/engine/api/services"
 	"github.com/ovh/cds/engine/api/sessionstore"
+	"github.com/ovh/cds/engine/api/token"
+	"github.com/ovh/cds/engine/api/user"
 	"github.com/ovh/cds/engine/api/worker"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/cdsclient"
@@ -27,6 +30,7 @@ const (
 	ContextWorker
 	ContextService
 	ContextUserSession
+	ContextAccessToken
 )
 
 //Driver is an interface to all auth method (local, ldap and beyond...)
@@ -51,6 +55,10 @@ func GetDriver(c context.Context, mode string, options interface{}, storeOptions
 		d = &LDAPClient{
 			dbFunc: DBFunc,
 		}
+	case "oidc":
+		d = &OIDCClient{
+			LocalClient: LocalClient{dbFunc: DBFunc},
+		}
 	default:
 		d = &LocalClient{
 			dbFunc: DBFunc,
@@ -177,6 +185,32 @@ func CheckServiceAuth(ctx context.Context, db *gorp.DbMap, store cache.Store, he
 	return ctx, nil
 }
 
+// CheckAccessTokenAuth checks personal access token authentication
+func CheckAccessTokenAuth(ctx context.Context, db gorp.SqlExecutor, headers http.Header) (context.Context, error) {
+	t, err := token.LoadAccessToken(db, headers.Get(sdk.AccessTokenHeader))
+	if err != nil {
+		return ctx, err
+	}
+
+	now := time.Now()
+	if t.IsExpired(now) {
+		return ctx, fmt.Errorf("access token %d expired on %s", t.ID, t.ExpireAt)
+	}
+
+	u, err := user.LoadUserWithoutAuthByID(db, t.UserID)
+	if err != nil {
+		return ctx, fmt.Errorf("cannot load user %d: %s", t.UserID, err)
+	}
+
+	if err := token.UpdateAccessTokenLastUsed(db, t.ID, now); err != nil {
+		log.Warning("CheckAccessTokenAuth> %v", err)
+	}
+
+	ctx = context.WithValue(ctx, ContextUser, u)
+	ctx = context.WithValue(ctx, ContextAccessToken, t)
+	return ctx, nil
+}
+
 // CheckHatcheryAuth checks hatchery authentication
 func CheckHatcheryAuth(ctx context.Context, db *gorp.DbMap, headers http.Header) (context.Context, error) {
 	uid, err := base64.StdEncoding.DecodeString(headers.Get(sdk.AuthHeader))
@@ -0,0 +1,184 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// jsonWebKey is a public key published by the OpenID provider on its jwks_uri
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type jsonWebKeySet struct {
+	Keys []jsonWebKey `json:"keys"`
+}
+
+func (k jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RSA modulus: %v", err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RSA exponent: %v", err)
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		default:
+			return nil, fmt.Errorf("unsupported curve %s", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("invalid EC x: %v", err)
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("invalid EC y: %v", err)
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+	}
+	return nil, fmt.Errorf("unsupported key type %s", k.Kty)
+}
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// parseJWT decodes a compact serialized JWT without checking its signature
+func parseJWT(token string) (jwtHeader, map[string]interface{}, []byte, []byte, error) {
+	var header jwtHeader
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return header, nil, nil, nil, fmt.Errorf("malformed token")
+	}
+
+	h, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return header, nil, nil, nil, fmt.Errorf("malformed token header: %v", err)
+	}
+	if err := json.Unmarshal(h, &header); err != nil {
+		return header, nil, nil, nil, fmt.Errorf("malformed token header: %v", err)
+	}
+
+	p, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return header, nil, nil, nil, fmt.Errorf("malformed token payload: %v", err)
+	}
+	claims := map[string]interface{}{}
+	if err := json.Unmarshal(p, &claims); err != nil {
+		return header, nil, nil, nil, fmt.Errorf("malformed token payload: %v", err)
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return header, nil, nil, nil, fmt.Errorf("malformed token signature: %v", err)
+	}
+
+	return header, claims, []byte(parts[0] + "." + parts[1]), sig, nil
+}
+
+// verifySignature checks a JWS signature for the RS* and ES* algorithms
+func verifySignature(alg string, key crypto.PublicKey, signed, sig []byte) error {
+	var hash crypto.Hash
+	switch alg {
+	case "RS256", "ES256":
+		hash = crypto.SHA256
+	case "RS384", "ES384":
+		hash = crypto.SHA384
+	case "RS512":
+		hash = crypto.SHA512
+	default:
+		return fmt.Errorf("unsupported signing algorithm %s", alg)
+	}
+	h := hash.New()
+	h.Write(signed)
+	digest := h.Sum(nil)
+
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		if !strings.HasPrefix(alg, "RS") {
+			return fmt.Errorf("algorithm %s does not match RSA key", alg)
+		}
+		return rsa.VerifyPKCS1v15(k, hash, digest, sig)
+	case *ecdsa.PublicKey:
+		if !strings.HasPrefix(alg, "ES") {
+			return fmt.Errorf("algorithm %s does not match EC key", alg)
+		}
+		size := (k.Curve.Params().BitSize + 7) / 8
+		if len(sig) != 2*size {
+			return fmt.Errorf("invalid signature length")
+		}
+		r := new(big.Int).SetBytes(sig[:size])
+		s := new(big.Int).SetBytes(sig[size:])
+		if !ecdsa.Verify(k, digest, r, s) {
+			return fmt.Errorf("invalid signature")
+		}
+		return nil
+	}
+	return fmt.Errorf("unsupported key")
+}
+
+// validateClaims checks the registered claims of an ID Token
+func validateClaims(claims map[string]interface{}, issuer, clientID, nonce string, now time.Time) error {
+	if iss, _ := claims["iss"].(string); iss != issuer {
+		return fmt.Errorf("invalid issuer %q", iss)
+	}
+
+	var audOK bool
+	switch aud := claims["aud"].(type) {
+	case string:
+		audOK = aud == clientID
+	case []interface{}:
+		for _, a := range aud {
+			if s, _ := a.(string); s == clientID {
+				audOK = true
+			}
+		}
+	}
+	if !audOK {
+		return fmt.Errorf("token was not issued for client %s", clientID)
+	}
+
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return fmt.Errorf("missing exp claim")
+	}
+	if now.After(time.Unix(int64(exp), 0)) {
+		return fmt.Errorf("token is expired")
+	}
+
+	if nonce != "" {
+		if n, _ := claims["nonce"].(string); n != nonce {
+			return fmt.Errorf("invalid nonce")
+		}
+	}
+	return nil
+}
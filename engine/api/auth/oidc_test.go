@@ -0,0 +1,162 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+type fakeOIDCProvider struct {
+	*httptest.Server
+	key      *rsa.PrivateKey
+	keysHits int32
+}
+
+func newFakeOIDCProvider(t *testing.T) *fakeOIDCProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	p := &fakeOIDCProvider{key: key}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(oidcProvider{
+			Issuer:                      p.URL,
+			AuthorizationEndpoint:       p.URL + "/authorize",
+			TokenEndpoint:               p.URL + "/token",
+			JWKSURI:                     p.URL + "/keys",
+			DeviceAuthorizationEndpoint: p.URL + "/device",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&p.keysHits, 1)
+		json.NewEncoder(w).Encode(jsonWebKeySet{Keys: []jsonWebKey{{
+			Kty: "RSA",
+			Kid: "key1",
+			Use: "sig",
+			N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		w.WriteHeader(http.StatusBadRequest)
+		if r.Form.Get("device_code") == "slow-device-code" {
+			json.NewEncoder(w).Encode(oidcTokenResponse{Error: "slow_down"})
+			return
+		}
+		json.NewEncoder(w).Encode(oidcTokenResponse{Error: "authorization_pending"})
+	})
+	p.Server = httptest.NewServer(mux)
+	return p
+}
+
+func (p *fakeOIDCProvider) sign(t *testing.T, kid string, claims map[string]interface{}) string {
+	h, _ := json.Marshal(jwtHeader{Alg: "RS256", Kid: kid})
+	c, _ := json.Marshal(claims)
+	signed := base64.RawURLEncoding.EncodeToString(h) + "." + base64.RawURLEncoding.EncodeToString(c)
+	digest := sha256.Sum256([]byte(signed))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, p.key, crypto.SHA256, digest[:])
+	assert.NoError(t, err)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func TestOIDCClient(t *testing.T) {
+	p := newFakeOIDCProvider(t)
+	defer p.Close()
+
+	c := &OIDCClient{}
+	assert.NoError(t, c.Open(OIDCConfig{
+		Issuer:      p.URL,
+		ClientID:    "cds",
+		RedirectURL: "http://cds.local/login/oidc/callback",
+	}, nil))
+
+	// Authorization URL with PKCE challenge
+	a, redirect, err := c.NewAuthorization()
+	assert.NoError(t, err)
+	u, err := url.Parse(redirect)
+	assert.NoError(t, err)
+	challenge := sha256.Sum256([]byte(a.CodeVerifier))
+	assert.Equal(t, p.URL+"/authorize", u.Scheme+"://"+u.Host+u.Path)
+	assert.Equal(t, base64.RawURLEncoding.EncodeToString(challenge[:]), u.Query().Get("code_challenge"))
+	assert.Equal(t, "S256", u.Query().Get("code_challenge_method"))
+	assert.Equal(t, a.State, u.Query().Get("state"))
+	assert.Equal(t, "openid profile email", u.Query().Get("scope"))
+
+	claims := func() map[string]interface{} {
+		return map[string]interface{}{
+			"iss":                p.URL,
+			"aud":                []string{"cds"},
+			"exp":                time.Now().Add(time.Minute).Unix(),
+			"nonce":              a.Nonce,
+			"sub":                "42",
+			"preferred_username": "john",
+			"name":               "John Doe",
+			"email":              "john@cds.local",
+			"groups":             []string{"devs", "ops"},
+		}
+	}
+
+	// Valid ID token
+	got, err := c.verifyIDToken(p.sign(t, "key1", claims()), a.Nonce)
+	assert.NoError(t, err)
+	id, err := c.identity(got)
+	assert.NoError(t, err)
+	assert.Equal(t, oidcIdentity{Username: "john", Fullname: "John Doe", Email: "john@cds.local", Groups: []string{"devs", "ops"}}, id)
+
+	// Invalid ID tokens
+	wrongNonce := claims()
+	wrongNonce["nonce"] = "replayed"
+	expired := claims()
+	expired["exp"] = time.Now().Add(-time.Minute).Unix()
+	wrongAudience := claims()
+	wrongAudience["aud"] = "another-client"
+	wrongIssuer := claims()
+	wrongIssuer["iss"] = "https://evil.local"
+	for name, token := range map[string]string{
+		"wrong nonce":    p.sign(t, "key1", wrongNonce),
+		"expired":        p.sign(t, "key1", expired),
+		"wrong audience": p.sign(t, "key1", wrongAudience),
+		"wrong issuer":   p.sign(t, "key1", wrongIssuer),
+		"unknown key":    p.sign(t, "key2", claims()),
+		"tampered":       p.sign(t, "key1", claims())[:40] + "x" + p.sign(t, "key1", claims())[41:],
+	} {
+		_, err := c.verifyIDToken(token, a.Nonce)
+		assert.Error(t, err, name)
+	}
+	// Unknown keys don't refresh the provider keys more than once per minute
+	assert.Equal(t, int32(1), atomic.LoadInt32(&p.keysHits))
+
+	// An empty groups claim is not ignored, so the user is removed from his groups
+	noGroups := claims()
+	noGroups["groups"] = []string{}
+	got, err = c.verifyIDToken(p.sign(t, "key1", noGroups), a.Nonce)
+	assert.NoError(t, err)
+	id, err = c.identity(got)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{}, id.Groups)
+
+	// Missing username claim
+	_, err = c.identity(map[string]interface{}{"sub": "42"})
+	assert.Error(t, err)
+
+	// Device flow
+	_, err = c.DeviceToken("device-code")
+	assert.Equal(t, sdk.ErrOIDCAuthorizationPending, err)
+	_, err = c.DeviceToken("slow-device-code")
+	assert.Equal(t, sdk.ErrOIDCSlowDown, err)
+}
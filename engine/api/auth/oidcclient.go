@@ -0,0 +1,492 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/sessionstore"
+	"github.com/ovh/cds/engine/api/user"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+//OIDCConfig handles all config to connect to an OpenID Connect provider
+type OIDCConfig struct {
+	Issuer        string
+	ClientID      string
+	ClientSecret  string
+	RedirectURL   string
+	Scopes        []string
+	UsernameClaim string
+	FullnameClaim string
+	EmailClaim    string
+	GroupsClaim   string
+}
+
+// oidcKeysRefreshInterval limits the refreshes of the provider keys triggered by unknown key IDs
+const oidcKeysRefreshInterval = time.Minute
+
+//OIDCClient delegates users authentication to an OpenID Connect provider.
+//Local users are still able to log in with their password.
+type OIDCClient struct {
+	LocalClient
+	conf          OIDCConfig
+	provider      oidcProvider
+	keysMutex     sync.RWMutex
+	keys          map[string]crypto.PublicKey
+	keysRefreshed time.Time
+	httpClient    *http.Client
+}
+
+// oidcProvider is the OpenID provider metadata, from its discovery document
+type oidcProvider struct {
+	Issuer                      string `json:"issuer"`
+	AuthorizationEndpoint       string `json:"authorization_endpoint"`
+	TokenEndpoint               string `json:"token_endpoint"`
+	UserinfoEndpoint            string `json:"userinfo_endpoint"`
+	JWKSURI                     string `json:"jwks_uri"`
+	DeviceAuthorizationEndpoint string `json:"device_authorization_endpoint"`
+}
+
+type oidcTokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	IDToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// OIDCAuthorization holds the secrets of a pending authorization code flow
+type OIDCAuthorization struct {
+	State        string `json:"state"`
+	Nonce        string `json:"nonce"`
+	CodeVerifier string `json:"code_verifier"`
+}
+
+//Open fetches the OpenID provider metadata
+func (c *OIDCClient) Open(options interface{}, store sessionstore.Store) error {
+	conf, ok := options.(OIDCConfig)
+	if !ok {
+		return fmt.Errorf("invalid OpenID Connect configuration")
+	}
+	if conf.UsernameClaim == "" {
+		conf.UsernameClaim = "preferred_username"
+	}
+	if conf.FullnameClaim == "" {
+		conf.FullnameClaim = "name"
+	}
+	if conf.EmailClaim == "" {
+		conf.EmailClaim = "email"
+	}
+	if conf.GroupsClaim == "" {
+		conf.GroupsClaim = "groups"
+	}
+	if len(conf.Scopes) == 0 {
+		conf.Scopes = []string{"openid", "profile", "email"}
+	}
+	c.conf = conf
+	c.keys = map[string]crypto.PublicKey{}
+	if c.httpClient == nil {
+		c.httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	if err := c.LocalClient.Open(options, store); err != nil {
+		return err
+	}
+
+	log.Info("Auth> Connecting to OpenID provider %s", conf.Issuer)
+	discovery := strings.TrimSuffix(conf.Issuer, "/") + "/.well-known/openid-configuration"
+	if err := c.getJSON(discovery, &c.provider); err != nil {
+		return sdk.WrapError(err, "OIDCClient.Open> Unable to get provider metadata")
+	}
+	if c.provider.Issuer != conf.Issuer {
+		return fmt.Errorf("OIDCClient.Open> issuer %s does not match configuration %s", c.provider.Issuer, conf.Issuer)
+	}
+	return nil
+}
+
+// NewAuthorization starts an authorization code flow with PKCE. It returns the pending authorization,
+// to keep until the callback, and the provider URL where the user has to be redirected.
+func (c *OIDCClient) NewAuthorization() (*OIDCAuthorization, string, error) {
+	a := &OIDCAuthorization{}
+	for _, s := range []*string{&a.State, &a.Nonce, &a.CodeVerifier} {
+		r, err := randomString()
+		if err != nil {
+			return nil, "", err
+		}
+		*s = r
+	}
+
+	challenge := sha256.Sum256([]byte(a.CodeVerifier))
+	v := url.Values{}
+	v.Set("response_type", "code")
+	v.Set("client_id", c.conf.ClientID)
+	v.Set("redirect_uri", c.conf.RedirectURL)
+	v.Set("scope", strings.Join(c.conf.Scopes, " "))
+	v.Set("state", a.State)
+	v.Set("nonce", a.Nonce)
+	v.Set("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:]))
+	v.Set("code_challenge_method", "S256")
+
+	sep := "?"
+	if strings.Contains(c.provider.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return a, c.provider.AuthorizationEndpoint + sep + v.Encode(), nil
+}
+
+// Exchange trades an authorization code against the user identity, and creates or updates the CDS user
+func (c *OIDCClient) Exchange(a OIDCAuthorization, code string) (*sdk.User, error) {
+	v := url.Values{}
+	v.Set("grant_type", "authorization_code")
+	v.Set("client_id", c.conf.ClientID)
+	v.Set("code", code)
+	v.Set("redirect_uri", c.conf.RedirectURL)
+	v.Set("code_verifier", a.CodeVerifier)
+
+	tokens, err := c.tokenRequest(v)
+	if err != nil {
+		return nil, err
+	}
+	if tokens.Error != "" {
+		return nil, sdk.WrapError(sdk.ErrOIDCLoginFailed, "OIDCClient.Exchange> %s: %s", tokens.Error, tokens.ErrorDescription)
+	}
+	return c.userFromTokens(tokens, a.Nonce)
+}
+
+// DeviceAuthorize starts a device authorization flow, for clients without a browser such as cdsctl
+func (c *OIDCClient) DeviceAuthorize() (*sdk.OIDCDeviceAuthorization, error) {
+	if c.provider.DeviceAuthorizationEndpoint == "" {
+		return nil, sdk.WrapError(sdk.ErrNotImplemented, "OIDCClient.DeviceAuthorize> OpenID provider does not support device authorization")
+	}
+
+	v := url.Values{}
+	v.Set("client_id", c.conf.ClientID)
+	v.Set("scope", strings.Join(c.conf.Scopes, " "))
+
+	req, err := http.NewRequest(http.MethodPost, c.provider.DeviceAuthorizationEndpoint, strings.NewReader(v.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	res := &sdk.OIDCDeviceAuthorization{}
+	if err := c.doForm(req, res); err != nil {
+		return nil, sdk.WrapError(err, "OIDCClient.DeviceAuthorize> Unable to start device authorization")
+	}
+	if res.Interval == 0 {
+		res.Interval = 5
+	}
+	return res, nil
+}
+
+// DeviceToken checks if the user has approved a device authorization.
+// It returns sdk.ErrOIDCAuthorizationPending until the user does so, and sdk.ErrOIDCSlowDown
+// when the client has to increase its polling interval by 5 seconds.
+func (c *OIDCClient) DeviceToken(deviceCode string) (*sdk.User, error) {
+	v := url.Values{}
+	v.Set("grant_type", "urn:ietf:params:oauth:grant-type:device_code")
+	v.Set("device_code", deviceCode)
+	v.Set("client_id", c.conf.ClientID)
+
+	tokens, err := c.tokenRequest(v)
+	if err != nil {
+		return nil, err
+	}
+	switch tokens.Error {
+	case "":
+	case "authorization_pending":
+		return nil, sdk.ErrOIDCAuthorizationPending
+	case "slow_down":
+		return nil, sdk.ErrOIDCSlowDown
+	default:
+		return nil, sdk.WrapError(sdk.ErrOIDCLoginFailed, "OIDCClient.DeviceToken> %s: %s", tokens.Error, tokens.ErrorDescription)
+	}
+	return c.userFromTokens(tokens, "")
+}
+
+func (c *OIDCClient) userFromTokens(tokens *oidcTokenResponse, nonce string) (*sdk.User, error) {
+	if tokens.IDToken == "" {
+		return nil, sdk.WrapError(sdk.ErrOIDCLoginFailed, "OIDCClient> No ID token received")
+	}
+	claims, err := c.verifyIDToken(tokens.IDToken, nonce)
+	if err != nil {
+		return nil, sdk.WrapError(sdk.ErrOIDCLoginFailed, "OIDCClient> Invalid ID token: %v", err)
+	}
+
+	// Some providers only return the profile claims on the userinfo endpoint
+	if _, ok := claims[c.conf.UsernameClaim]; !ok && c.provider.UserinfoEndpoint != "" && tokens.AccessToken != "" {
+		info, err := c.userinfo(tokens.AccessToken)
+		if err != nil {
+			return nil, sdk.WrapError(sdk.ErrOIDCLoginFailed, "OIDCClient> Unable to get userinfo: %v", err)
+		}
+		if info["sub"] != claims["sub"] {
+			return nil, sdk.WrapError(sdk.ErrOIDCLoginFailed, "OIDCClient> userinfo subject does not match ID token")
+		}
+		for k, v := range info {
+			claims[k] = v
+		}
+	}
+
+	id, err := c.identity(claims)
+	if err != nil {
+		return nil, sdk.WrapError(sdk.ErrOIDCLoginFailed, "OIDCClient> %v", err)
+	}
+	return c.provisionUser(c.dbFunc(), id)
+}
+
+// oidcIdentity is a user as described by the OpenID provider
+type oidcIdentity struct {
+	Username string
+	Fullname string
+	Email    string
+	Groups   []string
+}
+
+func (c *OIDCClient) identity(claims map[string]interface{}) (oidcIdentity, error) {
+	id := oidcIdentity{}
+	id.Username, _ = claims[c.conf.UsernameClaim].(string)
+	if id.Username == "" {
+		return id, fmt.Errorf("missing claim %s", c.conf.UsernameClaim)
+	}
+	id.Fullname, _ = claims[c.conf.FullnameClaim].(string)
+	id.Email, _ = claims[c.conf.EmailClaim].(string)
+
+	// Groups is not nil if the provider sends the groups claim, even empty
+	switch groups := claims[c.conf.GroupsClaim].(type) {
+	case []interface{}:
+		id.Groups = []string{}
+		for _, g := range groups {
+			if s, ok := g.(string); ok {
+				id.Groups = append(id.Groups, s)
+			}
+		}
+	case string:
+		id.Groups = strings.Split(groups, ",")
+	}
+	return id, nil
+}
+
+// provisionUser creates or updates the user, and synchronizes his CDS groups with the groups listed by the provider
+func (c *OIDCClient) provisionUser(db gorp.SqlExecutor, id oidcIdentity) (*sdk.User, error) {
+	u, err := user.LoadUserAndAuth(db, id.Username)
+	switch {
+	case err == sql.ErrNoRows:
+		u = &sdk.User{
+			Username: id.Username,
+			Fullname: id.Fullname,
+			Email:    id.Email,
+			Origin:   "oidc",
+		}
+		a := &sdk.Auth{
+			EmailVerified: true,
+		}
+		if err := user.InsertUser(db, u, a); err != nil {
+			return nil, sdk.WrapError(err, "OIDCClient> Unable to insert user %s", id.Username)
+		}
+		u.Auth = *a
+	case err != nil:
+		return nil, sdk.WrapError(err, "OIDCClient> Unable to load user %s", id.Username)
+	case u.Origin != "oidc":
+		// Don't let the provider take over an existing account
+		return nil, sdk.WrapError(sdk.ErrOIDCLoginFailed, "OIDCClient> User %s already exists with origin %s", id.Username, u.Origin)
+	default:
+		u.Fullname = id.Fullname
+		u.Email = id.Email
+		if err := user.UpdateUser(db, *u); err != nil {
+			return nil, sdk.WrapError(err, "OIDCClient> Unable to update user %s", id.Username)
+		}
+	}
+
+	if id.Groups == nil {
+		return u, nil
+	}
+
+	// Remove the user from the groups he is not member of anymore, except the default group
+	claimed := map[string]bool{}
+	for _, name := range id.Groups {
+		claimed[strings.TrimSpace(name)] = true
+	}
+	groups, err := group.LoadGroupByUser(db, u.ID)
+	if err != nil {
+		return nil, sdk.WrapError(err, "OIDCClient> Unable to load groups of user %s", u.Username)
+	}
+	for _, g := range groups {
+		if claimed[g.Name] || group.IsDefaultGroupID(g.ID) {
+			continue
+		}
+		if err := group.DeleteUserFromGroup(db, g.ID, u.ID); err != nil {
+			if err == sdk.ErrNotEnoughAdmin {
+				log.Warning("OIDCClient> User %s is the last admin of group %s, he is not removed from it", u.Username, g.Name)
+				continue
+			}
+			return nil, sdk.WrapError(err, "OIDCClient> Unable to remove user %s from group %s", u.Username, g.Name)
+		}
+	}
+
+	for _, name := range id.Groups {
+		g, err := group.LoadGroup(db, strings.TrimSpace(name))
+		if err != nil {
+			log.Debug("OIDCClient> Group %s not found: %v", name, err)
+			continue
+		}
+		in, err := group.CheckUserInGroup(db, g.ID, u.ID)
+		if err != nil {
+			return nil, sdk.WrapError(err, "OIDCClient> Unable to check user %s in group %s", u.Username, g.Name)
+		}
+		if in {
+			continue
+		}
+		if err := group.InsertUserInGroup(db, g.ID, u.ID, false); err != nil {
+			return nil, sdk.WrapError(err, "OIDCClient> Unable to add user %s in group %s", u.Username, g.Name)
+		}
+	}
+	return u, nil
+}
+
+func (c *OIDCClient) verifyIDToken(raw, nonce string) (map[string]interface{}, error) {
+	header, claims, signed, sig, err := parseJWT(raw)
+	if err != nil {
+		return nil, err
+	}
+	key, err := c.publicKey(header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifySignature(header.Alg, key, signed, sig); err != nil {
+		return nil, err
+	}
+	if err := validateClaims(claims, c.provider.Issuer, c.conf.ClientID, nonce, time.Now()); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// publicKey returns the provider key with the given ID, refreshing keys on rotation.
+// Keys are refreshed at most once per oidcKeysRefreshInterval, the old keys are kept if the refresh fails.
+func (c *OIDCClient) publicKey(kid string) (crypto.PublicKey, error) {
+	c.keysMutex.RLock()
+	k, ok := c.keys[kid]
+	c.keysMutex.RUnlock()
+	if ok {
+		return k, nil
+	}
+
+	c.keysMutex.Lock()
+	defer c.keysMutex.Unlock()
+	// The keys may have been refreshed while waiting for the lock
+	if k, ok := c.keys[kid]; ok {
+		return k, nil
+	}
+	if time.Since(c.keysRefreshed) < oidcKeysRefreshInterval {
+		return nil, fmt.Errorf("unknown key %q", kid)
+	}
+	c.keysRefreshed = time.Now()
+
+	var set jsonWebKeySet
+	if err := c.getJSON(c.provider.JWKSURI, &set); err != nil {
+		return nil, fmt.Errorf("unable to get provider keys: %v", err)
+	}
+
+	c.keys = map[string]crypto.PublicKey{}
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		pub, err := jwk.publicKey()
+		if err != nil {
+			log.Warning("OIDCClient> Ignoring key %s: %v", jwk.Kid, err)
+			continue
+		}
+		c.keys[jwk.Kid] = pub
+	}
+
+	k, ok = c.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key %q", kid)
+	}
+	return k, nil
+}
+
+func (c *OIDCClient) userinfo(accessToken string) (map[string]interface{}, error) {
+	req, err := http.NewRequest(http.MethodGet, c.provider.UserinfoEndpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	info := map[string]interface{}{}
+	if err := c.do(req, &info); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+func (c *OIDCClient) tokenRequest(v url.Values) (*oidcTokenResponse, error) {
+	req, err := http.NewRequest(http.MethodPost, c.provider.TokenEndpoint, strings.NewReader(v.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	tokens := &oidcTokenResponse{}
+	if err := c.doForm(req, tokens); err != nil && tokens.Error == "" {
+		return nil, sdk.WrapError(err, "OIDCClient> Token request failed")
+	}
+	return tokens, nil
+}
+
+// doForm sends a form authenticated with the client credentials
+func (c *OIDCClient) doForm(req *http.Request, out interface{}) error {
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if c.conf.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(c.conf.ClientID), url.QueryEscape(c.conf.ClientSecret))
+	}
+	return c.do(req, out)
+}
+
+func (c *OIDCClient) getJSON(u string, out interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	return c.do(req, out)
+}
+
+// do sends the request and unmarshals the JSON response, even on error status
+func (c *OIDCClient) do(req *http.Request, out interface{}) error {
+	req.Header.Set("Accept", "application/json")
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("invalid response from %s (HTTP %d): %v", req.URL.Host, res.StatusCode, err)
+	}
+	if res.StatusCode >= 400 {
+		return fmt.Errorf("%s returned HTTP %d", req.URL.Host, res.StatusCode)
+	}
+	return nil
+}
+
+func randomString() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/ovh/venom"
 
 	"github.com/golang/protobuf/ptypes"
+	"github.com/ovh/cds/engine/api/actionplugin"
 	"github.com/ovh/cds/engine/api/application"
 	"github.com/ovh/cds/engine/api/artifact"
 	"github.com/ovh/cds/engine/api/cache"
@@ -42,6 +43,13 @@ func (api *API) updateStepStatusHandler() Handler {
 			return sdk.WrapError(err, "updateStepStatusHandler> Error while unmarshal job")
 		}
 
+		// A step is building once per run
+		if step.Status == sdk.StatusBuilding.String() {
+			if err := actionplugin.IncStepUsage(api.mustDB(), &pbJob.Job.Action, step.StepOrder); err != nil {
+				log.Warning("updateStepStatusHandler> Unable to count plugin usage: %s", err)
+			}
+		}
+
 		found := false
 		for i := range pbJob.Job.StepStatus {
 			jobStep := &pbJob.Job.StepStatus[i]
@@ -3,6 +3,7 @@ package cache
 import (
 	"context"
 	"strings"
+	"time"
 
 	"github.com/ovh/cds/sdk/log"
 )
@@ -36,6 +37,9 @@ type Store interface {
 	SetRemove(rootKey string, memberKey string, member interface{})
 	SetCard(key string) int
 	SetScan(key string, members ...interface{}) error
+	Lock(key string, owner string, expiration time.Duration) bool
+	ExtendLock(key string, owner string, expiration time.Duration) bool
+	Unlock(key string, owner string)
 }
 
 //New init a cache
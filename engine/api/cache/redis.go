@@ -323,3 +323,58 @@ func (s *RedisStore) SetScan(key string, members ...interface{}) error {
 	}
 	return nil
 }
+
+//extendLockScript extends a lock only if it's owned by the given owner
+var extendLockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+
+//unlockScript releases a lock only if it's owned by the given owner
+var unlockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+// Lock acquires a lock for an owner until its expiration. It returns false if the lock is already held, even by the
+// same owner: use ExtendLock to keep a lock.
+func (s *RedisStore) Lock(key string, owner string, expiration time.Duration) bool {
+	if s.Client == nil {
+		log.Error("redis> cannot get redis client")
+		return false
+	}
+	locked, err := s.Client.SetNX(key, owner, expiration).Result()
+	if err != nil {
+		log.Warning("redis> Error locking %s: %s", key, err)
+		return false
+	}
+	return locked
+}
+
+// ExtendLock extends the expiration of a lock held by the owner. It returns false if the owner doesn't hold the lock.
+func (s *RedisStore) ExtendLock(key string, owner string, expiration time.Duration) bool {
+	if s.Client == nil {
+		log.Error("redis> cannot get redis client")
+		return false
+	}
+	res, err := extendLockScript.Run(s.Client, []string{key}, owner, int64(expiration/time.Millisecond)).Result()
+	if err != nil && err != redis.Nil {
+		log.Warning("redis> Error extending lock %s: %s", key, err)
+		return false
+	}
+	extended, _ := res.(int64)
+	return extended == 1
+}
+
+// Unlock releases a lock if it's held by the owner
+func (s *RedisStore) Unlock(key string, owner string) {
+	if s.Client == nil {
+		log.Error("redis> cannot get redis client")
+		return
+	}
+	if err := unlockScript.Run(s.Client, []string{key}, owner).Err(); err != nil && err != redis.Nil {
+		log.Warning("redis> Error unlocking %s: %s", key, err)
+	}
+}
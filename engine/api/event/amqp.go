@@ -0,0 +1,144 @@
+package event
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/streadway/amqp"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// AMQPClient enbeddes the AMQP 0.9.1 connection
+type AMQPClient struct {
+	options AMQPConfig
+	mutex   sync.Mutex
+	conn    *amqp.Connection
+	channel *amqp.Channel
+	closed  chan *amqp.Error
+}
+
+// AMQPConfig handles all config to connect to an AMQP 0.9.1 broker (RabbitMQ...)
+type AMQPConfig struct {
+	Enabled bool
+	// URL is amqp[s]://user:password@host:port/vhost
+	URL      string
+	Exchange string
+	// RoutingKey of the events, the event type if empty
+	RoutingKey string
+	// CloudEvents mode, structured or binary. In binary mode, the attributes are sent as cloudEvents:* headers.
+	CloudEvents string
+}
+
+// initialize returns broker, isInit and err if
+func (c *AMQPClient) initialize(options interface{}) (Broker, error) {
+	conf, ok := options.(AMQPConfig)
+	if !ok {
+		return nil, fmt.Errorf("Invalid AMQP Initialization")
+	}
+
+	if conf.URL == "" || conf.Exchange == "" {
+		return nil, fmt.Errorf("initAMQP> Invalid AMQP Configuration")
+	}
+	if err := checkCloudEventsMode(conf.CloudEvents, true); err != nil {
+		return nil, fmt.Errorf("initAMQP> %s", err)
+	}
+	c.options = conf
+
+	if err := c.connect(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// connect opens the connection and declares the exchange
+func (c *AMQPClient) connect() error {
+	conn, err := amqp.Dial(c.options.URL)
+	if err != nil {
+		return fmt.Errorf("initAMQP> Error while connecting: %s", err)
+	}
+	channel, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("initAMQP> Error while opening channel: %s", err)
+	}
+	if err := channel.ExchangeDeclare(c.options.Exchange, amqp.ExchangeTopic, true, false, false, false, nil); err != nil {
+		conn.Close()
+		return fmt.Errorf("initAMQP> Error while declaring exchange %s: %s", c.options.Exchange, err)
+	}
+
+	log.Debug("initAMQP> AMQP used on exchange:%s", c.options.Exchange)
+	c.conn = conn
+	c.channel = channel
+	c.closed = conn.NotifyClose(make(chan *amqp.Error, 1))
+	return nil
+}
+
+// close closes the connection
+func (c *AMQPClient) close() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.conn != nil {
+		if err := c.conn.Close(); err != nil {
+			log.Warning("closeAMQP> Error while closing AMQP connection:%s", err)
+		}
+	}
+}
+
+// sendEvent publishes an event on the exchange, and reconnects once if the connection is lost
+func (c *AMQPClient) sendEvent(event *sdk.Event) error {
+	contentType, data, attributes, errm := encodeEvent(event, c.options.CloudEvents)
+	if errm != nil {
+		return errm
+	}
+
+	key := c.options.RoutingKey
+	if key == "" {
+		key = event.EventType
+	}
+	msg := amqp.Publishing{
+		ContentType:  contentType,
+		DeliveryMode: amqp.Persistent,
+		Timestamp:    event.Timestamp,
+		Type:         event.EventType,
+		Body:         data,
+	}
+	if len(attributes) > 0 {
+		msg.Headers = amqp.Table{}
+		for k, v := range attributes {
+			msg.Headers["cloudEvents:"+k] = v
+		}
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	err := c.channel.Publish(c.options.Exchange, key, false, false, msg)
+	if err == amqp.ErrClosed {
+		log.Warning("sendEvent> AMQP connection closed, reconnecting")
+		if err = c.connect(); err != nil {
+			return err
+		}
+		err = c.channel.Publish(c.options.Exchange, key, false, false, msg)
+	}
+	if err != nil {
+		return err
+	}
+	log.Debug("Event %s sent to exchange %s with key %s", event.EventType, c.options.Exchange, key)
+	return nil
+}
+
+// status returns the status of the AMQP connection
+func (c *AMQPClient) status() string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.conn == nil {
+		return "AMQP KO"
+	}
+	select {
+	case <-c.closed:
+		return "AMQP KO"
+	default:
+		return "AMQP OK"
+	}
+}
@@ -0,0 +1,248 @@
+package event
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/secret"
+	"github.com/ovh/cds/sdk"
+)
+
+const subscriptionColumns = `project_event_subscription.id, project_event_subscription.project_id, project.projectkey,
+	project_event_subscription.name, project_event_subscription.url, project_event_subscription.secret,
+	project_event_subscription.event_types, project_event_subscription.workflows, project_event_subscription.statuses,
+	project_event_subscription.enabled, project_event_subscription.created, project_event_subscription.cloud_events`
+
+const subscriptionFrom = ` FROM project_event_subscription JOIN project ON project.id = project_event_subscription.project_id `
+
+const deliveryColumns = `id, subscription_id, event_type, payload, status, attempts, response_code, error, created, last_attempt, next_attempt`
+
+// InsertSubscription inserts a webhook subscription on a project
+func InsertSubscription(db gorp.SqlExecutor, s *sdk.EventSubscription) error {
+	secret, eventTypes, workflows, statuses, err := subscriptionArgs(s)
+	if err != nil {
+		return sdk.WrapError(err, "InsertSubscription> Unable to prepare subscription %s", s.Name)
+	}
+	s.Created = time.Now()
+	query := `INSERT INTO project_event_subscription (project_id, name, url, secret, event_types, workflows, statuses, enabled, created, cloud_events)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id`
+	if err := db.QueryRow(query, s.ProjectID, s.Name, s.URL, secret, eventTypes, workflows, statuses, s.Enabled, s.Created, s.CloudEvents).Scan(&s.ID); err != nil {
+		return sdk.WrapError(err, "InsertSubscription> Unable to insert subscription %s", s.Name)
+	}
+	return nil
+}
+
+// UpdateSubscription updates a webhook subscription. The secret is kept if not set.
+func UpdateSubscription(db gorp.SqlExecutor, s *sdk.EventSubscription) error {
+	secret, eventTypes, workflows, statuses, err := subscriptionArgs(s)
+	if err != nil {
+		return sdk.WrapError(err, "UpdateSubscription> Unable to prepare subscription %s", s.Name)
+	}
+	var secretArg interface{}
+	if secret != nil {
+		secretArg = secret
+	}
+	query := `UPDATE project_event_subscription
+		SET name = $3, url = $4, secret = COALESCE($5, secret), event_types = $6, workflows = $7, statuses = $8, enabled = $9, cloud_events = $10
+		WHERE id = $1 AND project_id = $2`
+	res, err := db.Exec(query, s.ID, s.ProjectID, s.Name, s.URL, secretArg, eventTypes, workflows, statuses, s.Enabled, s.CloudEvents)
+	if err != nil {
+		return sdk.WrapError(err, "UpdateSubscription> Unable to update subscription %d", s.ID)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sdk.ErrNotFound
+	}
+	return nil
+}
+
+// DeleteSubscription deletes a webhook subscription and its deliveries
+func DeleteSubscription(db gorp.SqlExecutor, projectID, id int64) error {
+	res, err := db.Exec(`DELETE FROM project_event_subscription WHERE id = $1 AND project_id = $2`, id, projectID)
+	if err != nil {
+		return sdk.WrapError(err, "DeleteSubscription> Unable to delete subscription %d", id)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sdk.ErrNotFound
+	}
+	return nil
+}
+
+// LoadSubscriptions returns the webhook subscriptions of a project, without their secrets
+func LoadSubscriptions(db gorp.SqlExecutor, projectKey string) ([]sdk.EventSubscription, error) {
+	subs, err := loadSubscriptions(db, `WHERE project.projectkey = $1 ORDER BY project_event_subscription.name`, projectKey)
+	if err != nil {
+		return nil, sdk.WrapError(err, "LoadSubscriptions> Unable to load subscriptions of project %s", projectKey)
+	}
+	for i := range subs {
+		subs[i].Secret = ""
+	}
+	return subs, nil
+}
+
+// LoadSubscription returns a webhook subscription of a project, without its secret
+func LoadSubscription(db gorp.SqlExecutor, projectKey string, id int64) (*sdk.EventSubscription, error) {
+	subs, err := loadSubscriptions(db, `WHERE project.projectkey = $1 AND project_event_subscription.id = $2`, projectKey, id)
+	if err != nil {
+		return nil, sdk.WrapError(err, "LoadSubscription> Unable to load subscription %d of project %s", id, projectKey)
+	}
+	if len(subs) == 0 {
+		return nil, sdk.ErrNotFound
+	}
+	subs[0].Secret = ""
+	return &subs[0], nil
+}
+
+func loadEnabledSubscriptions(db gorp.SqlExecutor, projectKey string) ([]sdk.EventSubscription, error) {
+	return loadSubscriptions(db, `WHERE project.projectkey = $1 AND project_event_subscription.enabled = true`, projectKey)
+}
+
+func loadSubscriptionByID(db gorp.SqlExecutor, id int64) (*sdk.EventSubscription, error) {
+	subs, err := loadSubscriptions(db, `WHERE project_event_subscription.id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(subs) == 0 {
+		return nil, sdk.ErrNotFound
+	}
+	return &subs[0], nil
+}
+
+func loadSubscriptions(db gorp.SqlExecutor, where string, args ...interface{}) ([]sdk.EventSubscription, error) {
+	rows, err := db.Query(`SELECT `+subscriptionColumns+subscriptionFrom+where, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	subs := []sdk.EventSubscription{}
+	for rows.Next() {
+		var s sdk.EventSubscription
+		var clearSecret, eventTypes, workflows, statuses []byte
+		if err := rows.Scan(&s.ID, &s.ProjectID, &s.ProjectKey, &s.Name, &s.URL, &clearSecret, &eventTypes, &workflows, &statuses, &s.Enabled, &s.Created, &s.CloudEvents); err != nil {
+			return nil, err
+		}
+		if len(clearSecret) > 0 {
+			clear, err := secret.Decrypt(clearSecret)
+			if err != nil {
+				return nil, err
+			}
+			s.Secret = string(clear)
+		}
+		for _, v := range []struct {
+			b []byte
+			s *[]string
+		}{{eventTypes, &s.EventTypes}, {workflows, &s.Workflows}, {statuses, &s.Statuses}} {
+			if len(v.b) == 0 {
+				continue
+			}
+			if err := json.Unmarshal(v.b, v.s); err != nil {
+				return nil, err
+			}
+		}
+		subs = append(subs, s)
+	}
+	return subs, nil
+}
+
+func subscriptionArgs(s *sdk.EventSubscription) (encryptedSecret, eventTypes, workflows, statuses []byte, err error) {
+	if s.Secret != "" {
+		if encryptedSecret, err = secret.Encrypt([]byte(s.Secret)); err != nil {
+			return
+		}
+	}
+	if eventTypes, err = json.Marshal(s.EventTypes); err != nil {
+		return
+	}
+	if workflows, err = json.Marshal(s.Workflows); err != nil {
+		return
+	}
+	statuses, err = json.Marshal(s.Statuses)
+	return
+}
+
+func insertDelivery(db gorp.SqlExecutor, d *sdk.EventDelivery) error {
+	query := `INSERT INTO project_event_delivery (subscription_id, event_type, payload, status, created, next_attempt)
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`
+	return db.QueryRow(query, d.SubscriptionID, d.EventType, []byte(d.Payload), d.Status, d.Created, d.NextAttempt).Scan(&d.ID)
+}
+
+func updateDelivery(db gorp.SqlExecutor, d *sdk.EventDelivery) error {
+	query := `UPDATE project_event_delivery
+		SET status = $2, attempts = $3, response_code = $4, error = $5, last_attempt = $6, next_attempt = $7
+		WHERE id = $1`
+	_, err := db.Exec(query, d.ID, d.Status, d.Attempts, d.ResponseCode, d.Error, d.LastAttempt, d.NextAttempt)
+	return err
+}
+
+// lockDueDeliveries takes a lease on the pending deliveries to attempt now, so that other API instances skip them
+func lockDueDeliveries(db gorp.SqlExecutor, now, lease time.Time, limit int) ([]sdk.EventDelivery, error) {
+	query := `UPDATE project_event_delivery SET next_attempt = $2
+		WHERE id IN (
+			SELECT id FROM project_event_delivery
+			WHERE status = $3 AND next_attempt <= $1
+			ORDER BY next_attempt LIMIT $4
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + deliveryColumns
+	return scanDeliveries(db.Query(query, now, lease, sdk.EventDeliveryPending, limit))
+}
+
+// LoadDeliveries returns the last deliveries of a subscription, optionally filtered by status
+func LoadDeliveries(db gorp.SqlExecutor, subscriptionID int64, status string, limit int) ([]sdk.EventDelivery, error) {
+	query := `SELECT ` + deliveryColumns + ` FROM project_event_delivery WHERE subscription_id = $1`
+	args := []interface{}{subscriptionID}
+	if status != "" {
+		query += ` AND status = $2`
+		args = append(args, status)
+	}
+	query += fmt.Sprintf(` ORDER BY created DESC LIMIT %d`, limit)
+	deliveries, err := scanDeliveries(db.Query(query, args...))
+	if err != nil {
+		return nil, sdk.WrapError(err, "LoadDeliveries> Unable to load deliveries of subscription %d", subscriptionID)
+	}
+	return deliveries, nil
+}
+
+// RetryDelivery schedules a new attempt of a delivery, dead or not
+func RetryDelivery(db gorp.SqlExecutor, subscriptionID, id int64) error {
+	res, err := db.Exec(`UPDATE project_event_delivery SET status = $3, next_attempt = $4 WHERE id = $1 AND subscription_id = $2`,
+		id, subscriptionID, sdk.EventDeliveryPending, time.Now())
+	if err != nil {
+		return sdk.WrapError(err, "RetryDelivery> Unable to update delivery %d", id)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sdk.ErrNotFound
+	}
+	return nil
+}
+
+func deleteDeliveries(db gorp.SqlExecutor, before time.Time) (int64, error) {
+	res, err := db.Exec(`DELETE FROM project_event_delivery WHERE created < $1 AND status <> $2`, before, sdk.EventDeliveryPending)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func scanDeliveries(rows *sql.Rows, err error) ([]sdk.EventDelivery, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []sdk.EventDelivery{}
+	for rows.Next() {
+		var d sdk.EventDelivery
+		var payload []byte
+		if err := rows.Scan(&d.ID, &d.SubscriptionID, &d.EventType, &payload, &d.Status, &d.Attempts, &d.ResponseCode, &d.Error, &d.Created, &d.LastAttempt, &d.NextAttempt); err != nil {
+			return nil, err
+		}
+		d.Payload = payload
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, nil
+}
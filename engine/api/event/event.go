@@ -2,6 +2,7 @@ package event
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 
@@ -11,7 +12,7 @@ import (
 	"github.com/ovh/cds/sdk/log"
 )
 
-var hostname, cdsname string
+var hostname, cdsname, source string
 var kafkaBroker Broker
 var brokers []Broker
 
@@ -28,28 +29,71 @@ func getBroker(t string, option interface{}) (Broker, error) {
 	case "kafka":
 		k := &KafkaClient{}
 		return k.initialize(option)
+	case "amqp":
+		a := &AMQPClient{}
+		return a.initialize(option)
+	case "nats":
+		n := &NATSClient{}
+		return n.initialize(option)
+	case "webhook":
+		w := &WebhookClient{}
+		return w.initialize(option)
 	}
 	return nil, fmt.Errorf("Invalid Broker Type %s", t)
 }
 
+// Config handles the configuration of all the brokers
+type Config struct {
+	// Source of the events in CloudEvents encoding, the API URL
+	Source  string
+	Kafka   KafkaConfig
+	AMQP    AMQPConfig
+	NATS    NATSConfig
+	Webhook WebhookConfig
+}
+
 // Initialize initializes event system
-func Initialize(k KafkaConfig) error {
+func Initialize(c Config) error {
 	var err error
 	hostname, err = os.Hostname()
 	if err != nil {
 		hostname = fmt.Sprintf("Error while getting Hostname: %s", err.Error())
 	}
 	cdsname = namesgenerator.GetRandomName(0)
+	source = c.Source
 
 	brokers = []Broker{}
-	if k.Enabled {
+	webhookBroker = nil
+	if c.Kafka.Enabled {
 		var errk error
-		kafkaBroker, errk = getBroker("kafka", k)
+		kafkaBroker, errk = getBroker("kafka", c.Kafka)
 		if errk != nil {
 			return errk
 		}
 		brokers = append(brokers, kafkaBroker)
 	}
+	if c.AMQP.Enabled {
+		b, erra := getBroker("amqp", c.AMQP)
+		if erra != nil {
+			return erra
+		}
+		brokers = append(brokers, b)
+	}
+	if c.NATS.Enabled {
+		b, errn := getBroker("nats", c.NATS)
+		if errn != nil {
+			return errn
+		}
+		brokers = append(brokers, b)
+	}
+	if c.Webhook.Enabled {
+		b, errw := getBroker("webhook", c.Webhook)
+		if errw != nil {
+			return errw
+		}
+		webhookBroker = b.(*WebhookClient)
+		brokers = append(brokers, b)
+	}
 	return nil
 }
 
@@ -71,6 +115,34 @@ func DequeueEvent(c context.Context) {
 	}
 }
 
+// encodeEvent returns the content type, the body and the headers of an event.
+// Events are sent as sdk.Event if mode is empty, else as CloudEvents in structured or binary mode.
+func encodeEvent(event *sdk.Event, mode string) (string, []byte, map[string]string, error) {
+	if mode == "" {
+		b, err := json.Marshal(event)
+		return "application/json", b, nil, err
+	}
+	ce, err := sdk.NewCloudEvent(*event, source)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	return ce.Encode(mode)
+}
+
+// checkCloudEventsMode checks the CloudEvents mode of a broker
+func checkCloudEventsMode(mode string, binary bool) error {
+	switch mode {
+	case "", sdk.CloudEventsStructured:
+		return nil
+	case sdk.CloudEventsBinary:
+		if binary {
+			return nil
+		}
+		return fmt.Errorf("CloudEvents binary mode is not supported by this broker")
+	}
+	return fmt.Errorf("Invalid CloudEvents mode %s", mode)
+}
+
 // GetHostname returns Hostname of this cds instance
 func GetHostname() string {
 	return hostname
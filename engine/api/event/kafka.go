@@ -1,7 +1,6 @@
 package event
 
 import (
-	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -26,6 +25,8 @@ type KafkaConfig struct {
 	User            string
 	Password        string
 	Topic           string
+	// CloudEvents mode, structured or empty: record headers needed by the binary mode are not supported by the Kafka client
+	CloudEvents string
 }
 
 // initialize returns broker, isInit and err if
@@ -41,6 +42,9 @@ func (c *KafkaClient) initialize(options interface{}) (Broker, error) {
 		conf.Topic == "" {
 		return nil, fmt.Errorf("initKafka> Invalid Kafka Configuration")
 	}
+	if err := checkCloudEventsMode(conf.CloudEvents, false); err != nil {
+		return nil, fmt.Errorf("initKafka> %s", err)
+	}
 	c.options = conf
 
 	if err := c.initProducer(); err != nil {
@@ -81,7 +85,7 @@ func (c *KafkaClient) initProducer() error {
 
 // sendOnKafkaTopic send a hook on a topic kafka
 func (c *KafkaClient) sendEvent(event *sdk.Event) error {
-	data, errm := json.Marshal(event)
+	_, data, _, errm := encodeEvent(event, c.options.CloudEvents)
 	if errm != nil {
 		return errm
 	}
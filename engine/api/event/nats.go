@@ -0,0 +1,85 @@
+package event
+
+import (
+	"fmt"
+
+	"github.com/nats-io/go-nats"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// NATSClient enbeddes the NATS connection
+type NATSClient struct {
+	options NATSConfig
+	conn    *nats.Conn
+}
+
+// NATSConfig handles all config to connect to NATS
+type NATSConfig struct {
+	Enabled bool
+	// URL is nats://host:port, several comma-separated URLs are allowed for a cluster
+	URL      string
+	User     string
+	Password string
+	Subject  string
+	// CloudEvents mode, structured or empty: NATS messages have no headers for the binary mode
+	CloudEvents string
+}
+
+// initialize returns broker, isInit and err if
+func (c *NATSClient) initialize(options interface{}) (Broker, error) {
+	conf, ok := options.(NATSConfig)
+	if !ok {
+		return nil, fmt.Errorf("Invalid NATS Initialization")
+	}
+
+	if conf.URL == "" || conf.Subject == "" {
+		return nil, fmt.Errorf("initNATS> Invalid NATS Configuration")
+	}
+	if err := checkCloudEventsMode(conf.CloudEvents, false); err != nil {
+		return nil, fmt.Errorf("initNATS> %s", err)
+	}
+	c.options = conf
+
+	opts := []nats.Option{nats.Name(cdsname), nats.MaxReconnects(-1)}
+	if conf.User != "" {
+		opts = append(opts, nats.UserInfo(conf.User, conf.Password))
+	}
+	conn, err := nats.Connect(conf.URL, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("initNATS> Error while connecting on %s: %s", conf.URL, err)
+	}
+
+	log.Debug("initNATS> NATS used at %s on subject:%s", conf.URL, conf.Subject)
+	c.conn = conn
+	return c, nil
+}
+
+// close closes the connection
+func (c *NATSClient) close() {
+	if c.conn != nil {
+		c.conn.Close()
+	}
+}
+
+// sendEvent publishes an event on the subject
+func (c *NATSClient) sendEvent(event *sdk.Event) error {
+	_, data, _, errm := encodeEvent(event, c.options.CloudEvents)
+	if errm != nil {
+		return errm
+	}
+	if err := c.conn.Publish(c.options.Subject, data); err != nil {
+		return err
+	}
+	log.Debug("Event %s sent to subject %s", event.EventType, c.options.Subject)
+	return nil
+}
+
+// status returns the status of the NATS connection
+func (c *NATSClient) status() string {
+	if c.conn == nil || !c.conn.IsConnected() {
+		return "NATS KO"
+	}
+	return "NATS OK"
+}
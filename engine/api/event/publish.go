@@ -6,6 +6,7 @@ import (
 
 	"github.com/fatih/structs"
 	"github.com/go-gorp/gorp"
+	"github.com/satori/go.uuid"
 
 	"github.com/ovh/cds/engine/api/cache"
 	"github.com/ovh/cds/engine/api/notification"
@@ -18,6 +19,7 @@ var Cache cache.Store
 //func Publish(event sdk.Event, eventType string) {
 func Publish(payload interface{}) {
 	event := sdk.Event{
+		ID:        uuid.NewV4().String(),
 		Timestamp: time.Now(),
 		Hostname:  hostname,
 		CDSName:   cdsname,
@@ -28,6 +30,12 @@ func Publish(payload interface{}) {
 	Cache.Enqueue("events", event)
 	// send to cache for cds repositories manager
 	Cache.Enqueue("events_repositoriesmanager", event)
+
+	// send to cache for workflow hooks
+	switch payload.(type) {
+	case sdk.EventWorkflowRun, sdk.EventWorkflowNodeRun:
+		Cache.Enqueue("events_workflowhooks", event)
+	}
 }
 
 // PublishActionBuild sends a actionBuild event
@@ -102,7 +110,14 @@ func PublishWorkflowRun(wr sdk.WorkflowRun, projectKey string) {
 }
 
 // PublishWorkflowNodeRun publish event on a workflow node run
-func PublishWorkflowNodeRun(nr sdk.WorkflowNodeRun, wr sdk.WorkflowRun, projectKey string) {
+func PublishWorkflowNodeRun(db gorp.SqlExecutor, nr sdk.WorkflowNodeRun, wr sdk.WorkflowRun, previous *sdk.WorkflowNodeRun, projectKey string) {
+	// get and send all user notifications
+	if len(wr.Workflow.Notifications) > 0 {
+		for _, event := range notification.GetUserWorkflowEvents(db, wr, previous, nr) {
+			Publish(event)
+		}
+	}
+
 	e := sdk.EventWorkflowNodeRun{
 		ID:             nr.ID,
 		Number:         nr.Number,
@@ -114,11 +129,14 @@ func PublishWorkflowNodeRun(nr sdk.WorkflowNodeRun, wr sdk.WorkflowRun, projectK
 		HookEvent:      nr.HookEvent,
 		Payload:        nr.Payload,
 		SourceNodeRuns: nr.SourceNodeRuns,
+		WorkflowName:   wr.Workflow.Name,
+		WorkflowRunID:  wr.ID,
 	}
 
 	node := wr.Workflow.GetNode(nr.WorkflowNodeID)
 	if node != nil {
 		e.PipelineName = node.Pipeline.Name
+		e.WorkflowNodeName = node.Name
 	}
 	if node.Context != nil {
 		if node.Context.Application != nil {
@@ -137,7 +155,7 @@ func PublishWorkflowNodeRun(nr sdk.WorkflowNodeRun, wr sdk.WorkflowRun, projectK
 }
 
 // EventWorkflowNodeJobRun publish event on a workflow node job run
-func PublishWorkflowNodeJobRun(njr sdk.WorkflowNodeJobRun) {
+func PublishWorkflowNodeJobRun(njr sdk.WorkflowNodeJobRun, projectKey, workflowName string) {
 	e := sdk.EventWorkflowNodeJobRun{
 		ID:                njr.ID,
 		Status:            njr.Status,
@@ -145,6 +163,8 @@ func PublishWorkflowNodeJobRun(njr sdk.WorkflowNodeJobRun) {
 		Start:             njr.Start.Unix(),
 		Model:             njr.Model,
 		Queued:            njr.Queued.Unix(),
+		ProjectKey:        projectKey,
+		WorkflowName:      workflowName,
 	}
 	if njr.Status != sdk.StatusBuilding.String() && njr.Status != sdk.StatusWaiting.String() {
 		e.Done = njr.Done.Unix()
@@ -0,0 +1,291 @@
+package event
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+var webhookBroker *WebhookClient
+
+// WebhookClient delivers events to the webhook subscriptions of the projects
+type WebhookClient struct {
+	options WebhookConfig
+	client  *http.Client
+}
+
+// WebhookConfig handles all config to deliver events to webhook subscriptions
+type WebhookConfig struct {
+	Enabled     bool
+	MaxAttempts int
+	Timeout     time.Duration
+	Retention   time.Duration
+	DBFunc      func() *gorp.DbMap
+	Cache       cache.Store
+}
+
+// subscriptionsCacheTTL is the lifetime in seconds of the cached subscriptions of a project,
+// they are also invalidated by InvalidateSubscriptions
+const subscriptionsCacheTTL = 300
+
+func subscriptionsCacheKey(projectKey string) string {
+	return cache.Key("events", "webhook", "subscriptions", projectKey)
+}
+
+// InvalidateSubscriptions removes the cached subscriptions of a project, it must be called when they change
+func InvalidateSubscriptions(store cache.Store, projectKey string) {
+	if store != nil {
+		store.Delete(subscriptionsCacheKey(projectKey))
+	}
+}
+
+// initialize returns broker, isInit and err if
+func (c *WebhookClient) initialize(options interface{}) (Broker, error) {
+	conf, ok := options.(WebhookConfig)
+	if !ok {
+		return nil, fmt.Errorf("Invalid Webhook Initialization")
+	}
+	if conf.DBFunc == nil || conf.MaxAttempts <= 0 || conf.Timeout <= 0 {
+		return nil, fmt.Errorf("initWebhook> Invalid Webhook Configuration")
+	}
+	c.options = conf
+	c.client = &http.Client{Timeout: conf.Timeout}
+	return c, nil
+}
+
+// close does nothing, pending deliveries are kept in database
+func (c *WebhookClient) close() {}
+
+// sendEvent records a delivery for each subscription matching the event.
+// Deliveries are attempted by DeliverWebhooks.
+func (c *WebhookClient) sendEvent(event *sdk.Event) error {
+	var supported bool
+	for _, t := range sdk.AvailableEventSubscriptionTypes {
+		if t == event.EventType {
+			supported = true
+			break
+		}
+	}
+	projectKey, _ := event.Payload["ProjectKey"].(string)
+	if !supported || projectKey == "" {
+		return nil
+	}
+
+	db := c.options.DBFunc()
+	if db == nil {
+		return fmt.Errorf("sendEvent> Database unavailable")
+	}
+	subs, err := c.enabledSubscriptions(db, projectKey)
+	if err != nil {
+		return err
+	}
+
+	var payload []byte
+	for _, s := range subs {
+		if !s.Matches(*event) {
+			continue
+		}
+		if payload == nil {
+			if payload, err = json.Marshal(event); err != nil {
+				return err
+			}
+		}
+		now := time.Now()
+		d := sdk.EventDelivery{
+			SubscriptionID: s.ID,
+			EventType:      event.EventType,
+			Payload:        payload,
+			Status:         sdk.EventDeliveryPending,
+			Created:        now,
+			NextAttempt:    now,
+		}
+		if err := insertDelivery(db, &d); err != nil {
+			return sdk.WrapError(err, "sendEvent> Unable to insert delivery for subscription %d", s.ID)
+		}
+	}
+	return nil
+}
+
+// enabledSubscriptions returns the enabled subscriptions of a project, from the cache if possible.
+// Secrets are not needed to record deliveries, they are not cached.
+func (c *WebhookClient) enabledSubscriptions(db gorp.SqlExecutor, projectKey string) ([]sdk.EventSubscription, error) {
+	key := subscriptionsCacheKey(projectKey)
+	subs := []sdk.EventSubscription{}
+	if c.options.Cache != nil && c.options.Cache.Get(key, &subs) {
+		return subs, nil
+	}
+
+	subs, err := loadEnabledSubscriptions(db, projectKey)
+	if err != nil {
+		return nil, sdk.WrapError(err, "sendEvent> Unable to load subscriptions of project %s", projectKey)
+	}
+	for i := range subs {
+		subs[i].Secret = ""
+	}
+	if c.options.Cache != nil {
+		c.options.Cache.SetWithTTL(key, subs, subscriptionsCacheTTL)
+	}
+	return subs, nil
+}
+
+// status: here, if c is initialized, webhooks are ok
+func (c *WebhookClient) status() string {
+	return "Webhook OK"
+}
+
+// DeliverWebhooks runs in a goroutine, attempts the pending deliveries and purges the old ones
+func DeliverWebhooks(c context.Context) {
+	if webhookBroker == nil {
+		return
+	}
+	tick := time.NewTicker(2 * time.Second)
+	defer tick.Stop()
+	purge := time.NewTicker(time.Hour)
+	defer purge.Stop()
+
+	for {
+		select {
+		case <-c.Done():
+			if c.Err() != nil {
+				log.Error("Exiting event.DeliverWebhooks: %v", c.Err())
+			}
+			return
+		case <-tick.C:
+			db := webhookBroker.options.DBFunc()
+			if db == nil {
+				continue
+			}
+			if err := webhookBroker.deliverPending(db); err != nil {
+				log.Warning("DeliverWebhooks> Error while delivering events: %s", err)
+			}
+		case <-purge.C:
+			db := webhookBroker.options.DBFunc()
+			if db == nil {
+				continue
+			}
+			n, err := deleteDeliveries(db, time.Now().Add(-webhookBroker.options.Retention))
+			if err != nil {
+				log.Warning("DeliverWebhooks> Error while purging deliveries: %s", err)
+				continue
+			}
+			log.Debug("DeliverWebhooks> %d deliveries purged", n)
+		}
+	}
+}
+
+// webhookBatchSize is the number of deliveries attempted sequentially at each tick
+const webhookBatchSize = 10
+
+func (c *WebhookClient) deliverPending(db gorp.SqlExecutor) error {
+	now := time.Now()
+	// each delivery of the batch may take up to the timeout: the lease must outlive the whole batch,
+	// otherwise another API instance would attempt the last deliveries again
+	lease := now.Add(time.Duration(webhookBatchSize+1) * c.options.Timeout)
+	deliveries, err := lockDueDeliveries(db, now, lease, webhookBatchSize)
+	if err != nil {
+		return sdk.WrapError(err, "deliverPending> Unable to load pending deliveries")
+	}
+
+	subs := map[int64]*sdk.EventSubscription{}
+	for i := range deliveries {
+		d := &deliveries[i]
+		s, ok := subs[d.SubscriptionID]
+		if !ok {
+			s, err = loadSubscriptionByID(db, d.SubscriptionID)
+			if err != nil {
+				log.Warning("deliverPending> Unable to load subscription %d: %s", d.SubscriptionID, err)
+				continue
+			}
+			subs[d.SubscriptionID] = s
+		}
+
+		c.deliver(*s, d)
+		if err := updateDelivery(db, d); err != nil {
+			log.Warning("deliverPending> Unable to update delivery %d: %s", d.ID, err)
+		}
+	}
+	return nil
+}
+
+// deliver attempts a delivery, and computes the next attempt on failure
+func (c *WebhookClient) deliver(s sdk.EventSubscription, d *sdk.EventDelivery) {
+	now := time.Now()
+	d.Attempts++
+	d.LastAttempt = &now
+	d.ResponseCode = 0
+	d.Error = ""
+
+	var req *http.Request
+	contentType, body, attributes, err := encodeDelivery(d, s.CloudEvents)
+	if err == nil {
+		req, err = http.NewRequest(http.MethodPost, s.URL, bytes.NewReader(body))
+	}
+	if err == nil {
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Set(sdk.EventTypeHeader, d.EventType)
+		req.Header.Set(sdk.EventDeliveryHeader, strconv.FormatInt(d.ID, 10))
+		for k, v := range attributes {
+			req.Header.Set(sdk.CloudEventsHeaderPrefix+k, v)
+		}
+		if s.Secret != "" {
+			req.Header.Set(sdk.EventSignatureHeader, sdk.EventSignature(s.Secret, body))
+		}
+
+		var resp *http.Response
+		resp, err = c.client.Do(req)
+		if err == nil {
+			resp.Body.Close()
+			d.ResponseCode = resp.StatusCode
+			if resp.StatusCode >= 300 {
+				err = fmt.Errorf("%s returned %s", req.URL.Host, resp.Status)
+			}
+		}
+	}
+
+	if err == nil {
+		d.Status = sdk.EventDeliverySuccess
+		return
+	}
+
+	d.Error = err.Error()
+	if d.Attempts >= c.options.MaxAttempts {
+		log.Warning("deliver> Delivery %d to subscription %d is dead after %d attempts: %s", d.ID, s.ID, d.Attempts, err)
+		d.Status = sdk.EventDeliveryDead
+		return
+	}
+	d.NextAttempt = now.Add(webhookBackoff(d.Attempts))
+}
+
+// encodeDelivery encodes the event of a delivery according to the CloudEvents mode of the subscription
+func encodeDelivery(d *sdk.EventDelivery, mode string) (string, []byte, map[string]string, error) {
+	if mode == "" {
+		return "application/json", d.Payload, nil, nil
+	}
+	var e sdk.Event
+	if err := json.Unmarshal(d.Payload, &e); err != nil {
+		return "", nil, nil, err
+	}
+	return encodeEvent(&e, mode)
+}
+
+// webhookBackoff returns the delay before the next attempt: 30s, 1m, 2m, 4m... up to 1h
+func webhookBackoff(attempts int) time.Duration {
+	if attempts > 7 {
+		return time.Hour
+	}
+	d := 30 * time.Second << uint(attempts-1)
+	if d > time.Hour {
+		return time.Hour
+	}
+	return d
+}
@@ -0,0 +1,91 @@
+package event
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestWebhookDeliver(t *testing.T) {
+	var fail bool
+	var received []byte
+	var signature, eventType string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received, _ = ioutil.ReadAll(r.Body)
+		signature = r.Header.Get(sdk.EventSignatureHeader)
+		eventType = r.Header.Get(sdk.EventTypeHeader)
+		if fail {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer s.Close()
+
+	b, err := (&WebhookClient{}).initialize(WebhookConfig{MaxAttempts: 2, Timeout: time.Second, DBFunc: nil})
+	assert.Error(t, err)
+	assert.Nil(t, b)
+
+	c := &WebhookClient{options: WebhookConfig{MaxAttempts: 2, Timeout: time.Second}, client: http.DefaultClient}
+	sub := sdk.EventSubscription{ID: 1, URL: s.URL, Secret: "s3cr3t"}
+	d := &sdk.EventDelivery{ID: 42, EventType: "sdk.EventWorkflowRun", Payload: []byte(`{"type_event":"sdk.EventWorkflowRun"}`), Status: sdk.EventDeliveryPending}
+
+	c.deliver(sub, d)
+	assert.Equal(t, sdk.EventDeliverySuccess, d.Status)
+	assert.Equal(t, http.StatusOK, d.ResponseCode)
+	assert.Equal(t, "sdk.EventWorkflowRun", eventType)
+	assert.True(t, sdk.CheckEventSignature("s3cr3t", received, signature))
+	assert.False(t, sdk.CheckEventSignature("another", received, signature))
+
+	// Failures are retried with backoff until the delivery is dead
+	fail = true
+	d = &sdk.EventDelivery{ID: 43, Payload: []byte(`{}`), Status: sdk.EventDeliveryPending}
+	c.deliver(sub, d)
+	assert.Equal(t, sdk.EventDeliveryPending, d.Status)
+	assert.Equal(t, http.StatusServiceUnavailable, d.ResponseCode)
+	assert.NotEmpty(t, d.Error)
+	assert.True(t, d.NextAttempt.After(time.Now().Add(25*time.Second)))
+
+	c.deliver(sub, d)
+	assert.Equal(t, sdk.EventDeliveryDead, d.Status)
+	assert.Equal(t, 2, d.Attempts)
+}
+
+func TestWebhookBackoff(t *testing.T) {
+	assert.Equal(t, 30*time.Second, webhookBackoff(1))
+	assert.Equal(t, time.Minute, webhookBackoff(2))
+	assert.Equal(t, 32*time.Minute, webhookBackoff(7))
+	assert.Equal(t, time.Hour, webhookBackoff(8))
+	assert.Equal(t, time.Hour, webhookBackoff(100))
+}
+
+func TestEventSubscriptionMatches(t *testing.T) {
+	sub := sdk.EventSubscription{
+		ProjectKey: "PROJ",
+		Enabled:    true,
+		EventTypes: []string{"sdk.EventWorkflowNodeRun"},
+		Workflows:  []string{"deploy"},
+		Statuses:   []string{sdk.StatusFail.String()},
+	}
+	e := sdk.Event{
+		EventType: "sdk.EventWorkflowNodeRun",
+		Payload:   map[string]interface{}{"ProjectKey": "PROJ", "WorkflowName": "deploy", "Status": sdk.StatusFail.String()},
+	}
+	assert.True(t, sub.Matches(e))
+
+	e.Payload["Status"] = sdk.StatusSuccess.String()
+	assert.False(t, sub.Matches(e))
+	sub.Statuses = nil
+	assert.True(t, sub.Matches(e))
+
+	e.Payload["ProjectKey"] = "OTHER"
+	assert.False(t, sub.Matches(e))
+	e.Payload["ProjectKey"] = "PROJ"
+
+	e.EventType = "sdk.EventWorkflowRun"
+	assert.False(t, sub.Matches(e))
+}
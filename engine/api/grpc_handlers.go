@@ -57,7 +57,7 @@ func (h *grpcHandlers) SendLog(stream grpc.WorkflowQueue_SendLogServer) error {
 		log.Debug("grpc.SendLog> Got %+v", in)
 
 		db := h.dbConnectionFactory.GetDBMap()
-		if err := workflow.AddLog(db, nil, in); err != nil {
+		if err := workflow.AddLog(db, h.store, nil, in); err != nil {
 			return sdk.WrapError(err, "grpc.SendLog> Unable to insert log ")
 		}
 	}
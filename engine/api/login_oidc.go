@@ -0,0 +1,175 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/ovh/cds/engine/api/auth"
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/sessionstore"
+	"github.com/ovh/cds/engine/api/user"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+const (
+	// oidcStateTTL is the time given to the user to log in on the OpenID provider, in seconds
+	oidcStateTTL = 600
+	// oidcCodeTTL is the time given to the UI to exchange its one-time code against a session, in seconds
+	oidcCodeTTL = 60
+)
+
+func (api *API) oidcDriver() (*auth.OIDCClient, error) {
+	d, ok := api.Router.AuthDriver.(*auth.OIDCClient)
+	if !ok {
+		return nil, sdk.WrapError(sdk.ErrNotImplemented, "oidcDriver> OpenID Connect authentication is not enabled")
+	}
+	return d, nil
+}
+
+// loginOIDCAuthorizeHandler redirects the user to the OpenID provider
+func (api *API) loginOIDCAuthorizeHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		d, err := api.oidcDriver()
+		if err != nil {
+			return err
+		}
+
+		a, redirect, err := d.NewAuthorization()
+		if err != nil {
+			return sdk.WrapError(err, "loginOIDCAuthorizeHandler> Unable to start authorization")
+		}
+		api.Cache.SetWithTTL(cache.Key("login", "oidc", "state", a.State), a, oidcStateTTL)
+
+		http.Redirect(w, r, redirect, http.StatusFound)
+		return nil
+	}
+}
+
+// loginOIDCCallbackHandler is called by the OpenID provider once the user is authenticated.
+// It redirects to the UI with a one-time code, to exchange against a session.
+func (api *API) loginOIDCCallbackHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		d, err := api.oidcDriver()
+		if err != nil {
+			return err
+		}
+
+		q := r.URL.Query()
+		if e := q.Get("error"); e != "" {
+			return sdk.WrapError(sdk.ErrOIDCLoginFailed, "loginOIDCCallbackHandler> %s: %s", e, q.Get("error_description"))
+		}
+
+		state := q.Get("state")
+		key := cache.Key("login", "oidc", "state", state)
+		var a auth.OIDCAuthorization
+		if state == "" || !api.Cache.Get(key, &a) {
+			return sdk.WrapError(sdk.ErrOIDCLoginFailed, "loginOIDCCallbackHandler> Unknown or expired state")
+		}
+		api.Cache.Delete(key)
+
+		u, err := d.Exchange(a, q.Get("code"))
+		if err != nil {
+			return sdk.WrapError(err, "loginOIDCCallbackHandler> Unable to exchange code")
+		}
+
+		if err := group.CheckUserInDefaultGroup(api.mustDB(), u.ID); err != nil {
+			log.Warning("loginOIDCCallbackHandler> Error while check user in default group:%s\n", err)
+		}
+
+		code, err := sessionstore.NewSessionKey()
+		if err != nil {
+			return sdk.WrapError(err, "loginOIDCCallbackHandler> Unable to generate code")
+		}
+		api.Cache.SetWithTTL(cache.Key("login", "oidc", "code", string(code)), u.Username, oidcCodeTTL)
+
+		http.Redirect(w, r, api.Config.URL.UI+"/account/login?oidc_code="+url.QueryEscape(string(code)), http.StatusFound)
+		return nil
+	}
+}
+
+// loginOIDCSessionHandler exchanges the one-time code given to the UI against a session
+func (api *API) loginOIDCSessionHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		var req sdk.OIDCSessionRequest
+		if err := UnmarshalBody(r, &req); err != nil {
+			return err
+		}
+
+		key := cache.Key("login", "oidc", "code", req.Code)
+		var username string
+		if req.Code == "" || !api.Cache.Get(key, &username) {
+			return sdk.WrapError(sdk.ErrOIDCLoginFailed, "loginOIDCSessionHandler> Unknown or expired code")
+		}
+		api.Cache.Delete(key)
+
+		u, err := user.LoadUserWithoutAuth(api.mustDB(), username)
+		if err != nil {
+			return sdk.WrapError(err, "loginOIDCSessionHandler> Unable to load user %s", username)
+		}
+
+		sessionKey, err := auth.NewSession(api.Router.AuthDriver, u)
+		if err != nil {
+			return sdk.WrapError(err, "loginOIDCSessionHandler> Unable to create session")
+		}
+		return api.writeOIDCLogin(w, r, u, sessionKey)
+	}
+}
+
+// loginOIDCDeviceHandler starts a device authorization flow, used by cdsctl
+func (api *API) loginOIDCDeviceHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		d, err := api.oidcDriver()
+		if err != nil {
+			return err
+		}
+
+		res, err := d.DeviceAuthorize()
+		if err != nil {
+			return err
+		}
+		return WriteJSON(w, r, res, http.StatusOK)
+	}
+}
+
+// loginOIDCDeviceTokenHandler returns a persistent session once the user has approved the device authorization
+func (api *API) loginOIDCDeviceTokenHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		d, err := api.oidcDriver()
+		if err != nil {
+			return err
+		}
+
+		var req sdk.OIDCDeviceTokenRequest
+		if err := UnmarshalBody(r, &req); err != nil {
+			return err
+		}
+
+		u, err := d.DeviceToken(req.DeviceCode)
+		if err != nil {
+			return err
+		}
+
+		if err := group.CheckUserInDefaultGroup(api.mustDB(), u.ID); err != nil {
+			log.Warning("loginOIDCDeviceTokenHandler> Error while check user in default group:%s\n", err)
+		}
+
+		sessionKey, err := auth.NewPersistentSession(api.mustDB(), api.Router.AuthDriver, u)
+		if err != nil {
+			return sdk.WrapError(err, "loginOIDCDeviceTokenHandler> Unable to create session")
+		}
+		return api.writeOIDCLogin(w, r, u, sessionKey)
+	}
+}
+
+func (api *API) writeOIDCLogin(w http.ResponseWriter, r *http.Request, u *sdk.User, sessionKey sessionstore.SessionKey) error {
+	response := sdk.UserAPIResponse{
+		User:  *u,
+		Token: string(sessionKey),
+	}
+	response.User.Auth = sdk.Auth{}
+	w.Header().Set(sdk.SessionTokenHeader, string(sessionKey))
+	return WriteJSON(w, r, response, http.StatusOK)
+}
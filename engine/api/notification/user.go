@@ -94,7 +94,7 @@ func GetUserEvents(db gorp.SqlExecutor, pb *sdk.PipelineBuild, previous *sdk.Pip
 				}
 				//Finally deduplicate everyone
 				removeDuplicates(&jn.Recipients)
-				events = append(events, getEvent(pb, jn, params))
+				events = append(events, getEvent(jn, params))
 			case sdk.EmailUserNotification:
 				jn, ok := notif.(*sdk.JabberEmailUserNotificationSettings)
 				if !ok {
@@ -129,7 +129,7 @@ func GetUserEvents(db gorp.SqlExecutor, pb *sdk.PipelineBuild, previous *sdk.Pip
 				}
 				//Finally deduplicate everyone
 				removeDuplicates(&jn.Recipients)
-				go SendMailNotif(getEvent(pb, jn, params))
+				go SendMailNotif(getEvent(jn, params))
 			}
 		}
 	}
@@ -180,15 +180,9 @@ func ShouldSendUserNotification(notif sdk.UserNotificationSettings, current *sdk
 	return false
 }
 
-func getEvent(pb *sdk.PipelineBuild, notif *sdk.JabberEmailUserNotificationSettings, params map[string]string) sdk.EventNotif {
-
-	subject := notif.Template.Subject
-	body := notif.Template.Body
-	for k, value := range params {
-		key := "{{." + k + "}}"
-		subject = strings.Replace(subject, key, value, -1)
-		body = strings.Replace(body, key, value, -1)
-	}
+func getEvent(notif *sdk.JabberEmailUserNotificationSettings, params map[string]string) sdk.EventNotif {
+	subject := interpolate(notif.Template.Subject, params)
+	body := interpolate(notif.Template.Body, params)
 
 	e := sdk.EventNotif{
 		Subject: subject,
@@ -201,6 +195,14 @@ func getEvent(pb *sdk.PipelineBuild, notif *sdk.JabberEmailUserNotificationSetti
 	return e
 }
 
+//interpolate replaces all the {{.key}} of the template by their value
+func interpolate(s string, params map[string]string) string {
+	for k, value := range params {
+		s = strings.Replace(s, "{{."+k+"}}", value, -1)
+	}
+	return s
+}
+
 //UserNotificationInput is a way to parse notification
 type UserNotificationInput struct {
 	Notifications         map[string]interface{} `json:"notifications"`
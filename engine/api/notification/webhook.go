@@ -0,0 +1,55 @@
+package notification
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+var webhookClient = &http.Client{Timeout: 10 * time.Second}
+
+// SendWebhookNotif calls the URL of a webhook notification.
+// The body is the template body if set, else the JSON encoded parameters.
+func SendWebhookNotif(notif sdk.WebhookUserNotificationSettings, params map[string]string) {
+	var body []byte
+	if notif.Template.Body != "" {
+		body = []byte(interpolate(notif.Template.Body, params))
+	} else {
+		var err error
+		body, err = json.Marshal(params)
+		if err != nil {
+			log.Error("notification.SendWebhookNotif> Unable to marshal parameters: %s", err)
+			return
+		}
+	}
+
+	method := notif.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+
+	req, err := http.NewRequest(method, interpolate(notif.URL, params), bytes.NewReader(body))
+	if err != nil {
+		log.Error("notification.SendWebhookNotif> Unable to create request on %s: %s", notif.URL, err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range notif.Headers {
+		req.Header.Set(k, interpolate(v, params))
+	}
+
+	log.Info("notification.SendWebhookNotif> Send notif to %s", req.URL.Host)
+	resp, err := webhookClient.Do(req)
+	if err != nil {
+		log.Warning("notification.SendWebhookNotif> Unable to call %s: %s", req.URL.Host, err)
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		log.Warning("notification.SendWebhookNotif> %s returned %d", req.URL.Host, resp.StatusCode)
+	}
+}
@@ -0,0 +1,52 @@
+package notification
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestSendWebhookNotif(t *testing.T) {
+	var method, body, auth string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		method, body, auth = r.Method, string(b), r.Header.Get("Authorization")
+	}))
+	defer s.Close()
+
+	SendWebhookNotif(sdk.WebhookUserNotificationSettings{
+		URL:      s.URL,
+		Method:   http.MethodPut,
+		Headers:  map[string]string{"Authorization": "Bearer {{.cds.proj.token}}"},
+		Template: sdk.UserNotificationTemplate{Body: `{"text": "{{.cds.workflow}} is {{.cds.status}}"}`},
+	}, map[string]string{
+		"cds.workflow":   "build-and-deploy",
+		"cds.status":     sdk.StatusFail.String(),
+		"cds.proj.token": "secret",
+	})
+	assert.Equal(t, http.MethodPut, method)
+	assert.Equal(t, `{"text": "build-and-deploy is Fail"}`, body)
+	assert.Equal(t, "Bearer secret", auth)
+
+	// Without template, the parameters are sent
+	SendWebhookNotif(sdk.WebhookUserNotificationSettings{URL: s.URL}, map[string]string{"cds.status": "Success"})
+	assert.Equal(t, http.MethodPost, method)
+	assert.Equal(t, `{"cds.status":"Success"}`, body)
+}
+
+func TestShouldSendUserWorkflowNotification(t *testing.T) {
+	notif := &sdk.JabberEmailUserNotificationSettings{OnSuccess: sdk.UserNotificationChange, OnFailure: sdk.UserNotificationAlways}
+	success := sdk.WorkflowNodeRun{Status: sdk.StatusSuccess.String()}
+	fail := sdk.WorkflowNodeRun{Status: sdk.StatusFail.String()}
+
+	assert.True(t, ShouldSendUserWorkflowNotification(notif, success, nil))
+	assert.False(t, ShouldSendUserWorkflowNotification(notif, success, &success))
+	assert.True(t, ShouldSendUserWorkflowNotification(notif, success, &fail))
+	assert.True(t, ShouldSendUserWorkflowNotification(notif, fail, &fail))
+	assert.False(t, ShouldSendUserWorkflowNotification(notif, sdk.WorkflowNodeRun{Status: sdk.StatusBuilding.String()}, nil))
+}
@@ -0,0 +1,129 @@
+package notification
+
+import (
+	"fmt"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/permission"
+	"github.com/ovh/cds/engine/api/user"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// GetUserWorkflowEvents returns the events of the notifications of a workflow for a node run.
+// Emails and webhooks are sent asynchronously, jabber events have to be published.
+func GetUserWorkflowEvents(db gorp.SqlExecutor, wr sdk.WorkflowRun, previous *sdk.WorkflowNodeRun, nr sdk.WorkflowNodeRun) []sdk.EventNotif {
+	node := wr.Workflow.GetNode(nr.WorkflowNodeID)
+	if node == nil {
+		log.Warning("notification.GetUserWorkflowEvents> node %d not found in workflow %s", nr.WorkflowNodeID, wr.Workflow.Name)
+		return nil
+	}
+
+	//Compute notification
+	params := map[string]string{}
+	for _, p := range nr.BuildParameters {
+		params[p.Name] = p.Value
+	}
+	params["cds.project"] = wr.Workflow.ProjectKey
+	params["cds.workflow"] = wr.Workflow.Name
+	params["cds.node"] = node.Name
+	params["cds.status"] = nr.Status
+	params["cds.run.number"] = fmt.Sprintf("%d", wr.Number)
+	//Set WorkflowRun UI URL
+	params["cds.buildURL"] = fmt.Sprintf("%s/project/%s/workflow/%s/run/%d", uiURL, wr.Workflow.ProjectKey, wr.Workflow.Name, wr.Number)
+	//find author (manual user or changes author)
+	if nr.Manual != nil && nr.Manual.User.Username != "" {
+		params["cds.author"] = nr.Manual.User.Username
+	} else if params["git.author"] != "" {
+		params["cds.author"] = params["git.author"]
+	}
+
+	events := []sdk.EventNotif{}
+	for _, notif := range wr.Workflow.Notifications {
+		if !notif.Matches(node.Name) || !ShouldSendUserWorkflowNotification(notif.Settings, nr, previous) {
+			continue
+		}
+
+		switch notif.Type {
+		case sdk.JabberUserNotification, sdk.EmailUserNotification:
+			settings, ok := notif.Settings.(*sdk.JabberEmailUserNotificationSettings)
+			if !ok {
+				log.Error("notification.GetUserWorkflowEvents> cannot deal with %s", notif.Settings)
+				continue
+			}
+			jn := *settings
+			jn.Recipients = append([]string{}, settings.Recipients...)
+
+			//Get recipents from groups
+			if jn.SendToGroups {
+				u, errPerm := permission.WorkflowUsers(db, wr.WorkflowID, permission.PermissionRead)
+				if errPerm != nil {
+					log.Error("notification[%s].GetUserWorkflowEvents> error while loading permission:%s", notif.Type, errPerm)
+				}
+				for i := range u {
+					if notif.Type == sdk.EmailUserNotification {
+						jn.Recipients = append(jn.Recipients, u[i].Email)
+					} else {
+						jn.Recipients = append(jn.Recipients, u[i].Username)
+					}
+				}
+			}
+			if jn.SendToAuthor && params["cds.author"] != "" {
+				if notif.Type == sdk.EmailUserNotification {
+					u, err := user.LoadUserWithoutAuth(db, params["cds.author"])
+					if err != nil {
+						log.Warning("notification[Email].GetUserWorkflowEvents> Cannot load author %s: %s", params["cds.author"], err)
+					} else {
+						jn.Recipients = append(jn.Recipients, u.Email)
+					}
+				} else {
+					jn.Recipients = append(jn.Recipients, params["cds.author"])
+				}
+			}
+			//Finally deduplicate everyone
+			removeDuplicates(&jn.Recipients)
+
+			if notif.Type == sdk.EmailUserNotification {
+				go SendMailNotif(getEvent(&jn, params))
+			} else {
+				events = append(events, getEvent(&jn, params))
+			}
+		case sdk.WebhookUserNotification:
+			settings, ok := notif.Settings.(*sdk.WebhookUserNotificationSettings)
+			if !ok {
+				log.Error("notification.GetUserWorkflowEvents> cannot deal with %s", notif.Settings)
+				continue
+			}
+			go SendWebhookNotif(*settings, params)
+		}
+	}
+	return events
+}
+
+//ShouldSendUserWorkflowNotification check if user notification has to be sent for a workflow node run
+func ShouldSendUserWorkflowNotification(notif sdk.UserNotificationSettings, current sdk.WorkflowNodeRun, previous *sdk.WorkflowNodeRun) bool {
+	var check = func(s sdk.UserNotificationEventType) bool {
+		switch s {
+		case sdk.UserNotificationAlways:
+			return true
+		case sdk.UserNotificationNever:
+			return false
+		case sdk.UserNotificationChange:
+			if previous == nil {
+				return true
+			}
+			return current.Status != previous.Status
+		}
+		return false
+	}
+	switch current.Status {
+	case sdk.StatusSuccess.String():
+		return check(notif.Success())
+	case sdk.StatusFail.String():
+		return check(notif.Failure())
+	case sdk.StatusBuilding.String():
+		return notif.Start()
+	}
+	return false
+}
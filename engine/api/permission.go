@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/go-gorp/gorp"
 	"github.com/gorilla/mux"
@@ -34,6 +35,19 @@ func permissionFunc(api *API) map[string]PermCheckFunc {
 	}
 }
 
+// isWorkflowRunRoute returns true if the request is on the runs of a workflow
+func isWorkflowRunRoute(req *http.Request) bool {
+	route := mux.CurrentRoute(req)
+	if route == nil {
+		return false
+	}
+	tmpl, err := route.GetPathTemplate()
+	if err != nil {
+		return false
+	}
+	return strings.Contains(tmpl, "/workflows/{permWorkflowName}/runs")
+}
+
 func getPermissionByMethod(method string, isExecution bool) int {
 	switch method {
 	case "POST":
@@ -83,7 +97,11 @@ func (api *API) authMiddleware(ctx context.Context, w http.ResponseWriter, req *
 			}
 		default:
 			var err error
-			ctx, err = api.Router.AuthDriver.CheckAuth(ctx, w, req)
+			if headers.Get(sdk.AccessTokenHeader) != "" {
+				ctx, err = auth.CheckAccessTokenAuth(ctx, api.mustDB(), headers)
+			} else {
+				ctx, err = api.Router.AuthDriver.CheckAuth(ctx, w, req)
+			}
 			if err != nil {
 				return ctx, sdk.WrapError(sdk.ErrUnauthorized, "Router> Authorization denied on %s %s for %s agent %s : %s", req.Method, req.URL, req.RemoteAddr, getAgent(req), err)
 			}
@@ -136,6 +154,16 @@ func (api *API) authMiddleware(ctx context.Context, w http.ResponseWriter, req *
 		if err := loadUserPermissions(api.mustDB(), api.Cache, getUser(ctx)); err != nil {
 			return ctx, sdk.WrapError(sdk.ErrUnauthorized, "Router> Unable to load user %s permission: %s", getUser(ctx).ID, err)
 		}
+		//Personal access tokens only have a subset of the user permissions
+		if t := getAccessToken(ctx); t != nil {
+			if !t.Allows(req.Method, rc.Options["isExecution"] == "true", isWorkflowRunRoute(req)) {
+				return ctx, sdk.WrapError(sdk.ErrForbidden, "Router> Access token %d not allowed on %s %s", t.ID, req.Method, req.URL)
+			}
+			if !t.HasScope(sdk.AccessTokenScopeAdmin) {
+				getUser(ctx).Admin = false
+			}
+			t.RestrictToProject(getUser(ctx).Groups)
+		}
 	}
 
 	if rc.Options["auth"] != "true" {
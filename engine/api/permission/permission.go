@@ -233,3 +233,60 @@ func ApplicationPipelineEnvironmentUsers(db gorp.SqlExecutor, appID, pipID, envI
 	}
 	return users, nil
 }
+
+// WorkflowsGroupAccess checks that a group has at least the access srcAccess on the workflow srcID and the access dstAccess on the workflow dstID
+func WorkflowsGroupAccess(db gorp.SqlExecutor, srcID int64, srcAccess int, dstID int64, dstAccess int) (bool, error) {
+	query := `
+		SELECT 	COUNT(1)
+		FROM 	workflow_group src
+		JOIN 	workflow_group dst ON src.group_id = dst.group_id
+		WHERE	src.workflow_id = $1
+		AND  	src.role >= $2
+		AND 	dst.workflow_id = $3
+		AND 	dst.role >= $4
+	`
+	n, err := db.SelectInt(query, srcID, srcAccess, dstID, dstAccess)
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// WorkflowUsers returns the users having at least the given access on the workflow
+func WorkflowUsers(db gorp.SqlExecutor, workflowID int64, access int) ([]sdk.User, error) {
+	query := `
+		SELECT 	DISTINCT "user".id, "user".username, "user".data
+		FROM 	"group"
+		JOIN 	workflow_group ON "group".id = workflow_group.group_id
+		JOIN	group_user ON "group".id = group_user.group_id
+		JOIN 	"user" ON group_user.user_id = "user".id
+		WHERE	workflow_group.workflow_id = $1
+		AND  	workflow_group.role >= $2
+	`
+	rows, err := db.Query(query, workflowID, access)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return []sdk.User{}, nil
+		}
+		return []sdk.User{}, err
+	}
+	defer rows.Close()
+
+	users := []sdk.User{}
+	for rows.Next() {
+		u := sdk.User{}
+		var data string
+		if err := rows.Scan(&u.ID, &u.Username, &data); err != nil {
+			log.Warning("permission.WorkflowUsers> error while scanning user : %s", err)
+			continue
+		}
+
+		uTemp := &sdk.User{}
+		if err := json.Unmarshal([]byte(data), uTemp); err != nil {
+			log.Warning("permission.WorkflowUsers> error while parsing user : %s", err)
+			continue
+		}
+		users = append(users, *uTemp)
+	}
+	return users, nil
+}
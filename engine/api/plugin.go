@@ -7,26 +7,24 @@ import (
 	"io/ioutil"
 	"net/http"
 	"os"
-	"path"
 	"path/filepath"
 	"strings"
 
+	"github.com/go-gorp/gorp"
 	"github.com/gorilla/mux"
 
-	"github.com/ovh/cds/engine/api/action"
 	"github.com/ovh/cds/engine/api/actionplugin"
 	"github.com/ovh/cds/engine/api/objectstore"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
-	"github.com/ovh/cds/sdk/plugin"
 )
 
-func fileUploadAndGetPlugin(w http.ResponseWriter, r *http.Request) (*sdk.ActionPlugin, *plugin.Parameters, io.ReadCloser, func(), error) {
+func fileUploadAndGetPlugin(w http.ResponseWriter, r *http.Request) (*sdk.ActionPlugin, io.ReadCloser, func(), error) {
 	r.ParseMultipartForm(64 << 20)
 	file, handler, err := r.FormFile("UploadFile")
 	if err != nil {
 		log.Debug("fileUploadAndGetPlugin> %v", r.Header)
-		return nil, nil, nil, nil, sdk.WrapError(err, "fileUploadAndGetPlugin> err on formFile")
+		return nil, nil, nil, sdk.WrapError(err, "fileUploadAndGetPlugin> err on formFile")
 	}
 
 	filename := handler.Filename
@@ -40,7 +38,7 @@ func fileUploadAndGetPlugin(w http.ResponseWriter, r *http.Request) (*sdk.Action
 
 	tmp, err := ioutil.TempDir("", "cds-plugin")
 	if err != nil {
-		return nil, nil, nil, nil, sdk.WrapError(err, "fileUploadAndGetPlugin> err on temp dir.")
+		return nil, nil, nil, sdk.WrapError(err, "fileUploadAndGetPlugin> err on temp dir.")
 	}
 	deferFunc := func() {
 		log.Debug("fileUploadAndGetPlugin> deleting file %s", tmp)
@@ -51,7 +49,7 @@ func fileUploadAndGetPlugin(w http.ResponseWriter, r *http.Request) (*sdk.Action
 	tmpfn := filepath.Join(tmp, filename)
 	f, err := os.OpenFile(tmpfn, os.O_WRONLY|os.O_CREATE, 0700)
 	if err != nil {
-		return nil, nil, nil, deferFunc, sdk.WrapError(err, "fileUploadAndGetPlugin> err on openFile")
+		return nil, nil, deferFunc, sdk.WrapError(err, "fileUploadAndGetPlugin> err on openFile")
 	}
 
 	log.Debug("fileUploadAndGetPlugin> writing file %s", tmpfn)
@@ -60,160 +58,173 @@ func fileUploadAndGetPlugin(w http.ResponseWriter, r *http.Request) (*sdk.Action
 
 	content, err := os.Open(tmpfn)
 	if err != nil {
-		return nil, nil, nil, deferFunc, sdk.WrapError(err, "fileUploadAndGetPlugin> err on Open")
+		return nil, nil, deferFunc, sdk.WrapError(err, "fileUploadAndGetPlugin> err on Open")
 	}
 
-	ap, params, err := actionplugin.Get(filename, tmpfn)
+	ap, err := actionplugin.Get(filename, tmpfn)
 	if err != nil {
-		return nil, nil, nil, deferFunc, sdk.WrapError(sdk.ErrPluginInvalid, "fileUploadAndGetPlugin> unable to get plugin info: %s", err)
+		return nil, nil, deferFunc, sdk.WrapError(sdk.ErrPluginInvalid, "fileUploadAndGetPlugin> unable to get plugin info: %s", err)
 	}
 
-	return ap, params, content, deferFunc, nil
-}
+	// The version given with the binary overrides the version of the plugin
+	if v := r.FormValue("version"); v != "" {
+		ap.Version = v
+	}
+	ap.Version, err = sdk.CheckPluginVersion(ap.Version)
+	if err != nil {
+		return nil, nil, deferFunc, sdk.WrapError(err, "fileUploadAndGetPlugin> invalid version of plugin %s", ap.Name)
+	}
 
-func (api *API) addPluginHandler() Handler {
-	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
-		//Upload file and get plugin information
-		ap, params, file, deferFunc, err := fileUploadAndGetPlugin(w, r)
-		if deferFunc != nil {
-			defer deferFunc()
-		}
-		if err != nil {
-			return sdk.WrapError(err, "addPluginHandler>%T", err)
-		}
-		defer file.Close()
+	return ap, content, deferFunc, nil
+}
 
-		// Check that action does not already exists
-		conflict, err := action.Exists(api.mustDB(), ap.Name)
+// checkNewPluginVersion checks that a version of a plugin has not already been uploaded, versions are immutable.
+// On update, the plugin must already exist.
+func checkNewPluginVersion(db gorp.SqlExecutor, ap *sdk.ActionPlugin, update bool) error {
+	if update {
+		versions, err := actionplugin.LoadVersions(db, ap.Name)
 		if err != nil {
-			return sdk.WrapError(err, "updatePluginHandler>%T", err)
+			return err
 		}
-		if conflict {
-			return sdk.ErrConflict
+		if len(versions) == 0 {
+			return sdk.WrapError(sdk.ErrNotFound, "checkNewPluginVersion> plugin %s does not exist", ap.Name)
 		}
+	}
 
-		//Upload it to objectstore
-		objectPath, err := objectstore.StorePlugin(*ap, file)
-		if err != nil {
-			return sdk.WrapError(err, "addPluginHandler> Error while uploading to object store %s", ap.Name)
-		}
-		ap.ObjectPath = objectPath
+	_, err := actionplugin.LoadVersion(db, ap.Name, ap.Version)
+	if err == nil {
+		return sdk.WrapError(sdk.ErrPluginVersionExists, "checkNewPluginVersion> %s already exists", sdk.PluginRef(ap.Name, ap.Version))
+	}
+	if err != sdk.ErrPluginVersionNotFound {
+		return err
+	}
+	return nil
+}
 
-		tx, err := api.mustDB().Begin()
-		if err != nil {
-			return sdk.WrapError(err, "addPluginHandler> Cannot start transaction")
-		}
-		defer tx.Rollback()
+// isImagePluginRequest returns true if the request declares a docker action plugin instead of uploading a binary
+func isImagePluginRequest(r *http.Request) bool {
+	return strings.HasPrefix(r.Header.Get("Content-Type"), "application/json")
+}
 
-		//Insert in database
-		a, err := actionplugin.Insert(tx, ap, params)
-		if err != nil {
-			objectstore.DeletePlugin(*ap)
-			return sdk.WrapError(err, "addPluginHandler> Error while inserting action %s in database", ap.Name)
-		}
+func imagePluginFromBody(r *http.Request) (*sdk.ActionPlugin, error) {
+	ap := &sdk.ActionPlugin{}
+	if err := UnmarshalBody(r, ap); err != nil {
+		return nil, sdk.WrapError(err, "imagePluginFromBody> cannot unmarshal request")
+	}
+	ap.ID = 0
+	ap.Filename = ""
+	ap.Path = ""
+	ap.Size = 0
+	ap.Perm = 0
+	ap.MD5sum = ""
+	ap.ObjectPath = ""
+	ap.Deprecated = false
+	ap.Usage = 0
+	if err := actionplugin.CheckImage(ap); err != nil {
+		return nil, sdk.WrapError(err, "imagePluginFromBody>")
+	}
+	var err error
+	ap.Version, err = sdk.CheckPluginVersion(ap.Version)
+	if err != nil {
+		return nil, sdk.WrapError(err, "imagePluginFromBody> invalid version of plugin %s", ap.Name)
+	}
+	return ap, nil
+}
 
-		if err := tx.Commit(); err != nil {
-			return sdk.WrapError(err, "addPluginHandler> Cannot commit transaction")
-		}
+func (api *API) addImagePlugin(w http.ResponseWriter, r *http.Request, userID int64, update bool) error {
+	ap, err := imagePluginFromBody(r)
+	if err != nil {
+		return sdk.WrapError(err, "addImagePlugin>")
+	}
 
-		return WriteJSON(w, r, a, http.StatusCreated)
+	if err := checkNewPluginVersion(api.mustDB(), ap, update); err != nil {
+		return sdk.WrapError(err, "addImagePlugin>")
+	}
+
+	tx, err := api.mustDB().Begin()
+	if err != nil {
+		return sdk.WrapError(err, "addImagePlugin> Cannot start transaction")
 	}
+	defer tx.Rollback()
+
+	a, err := actionplugin.Insert(tx, ap, userID)
+	if err != nil {
+		return sdk.WrapError(err, "addImagePlugin> Error while inserting action %s in database", ap.Name)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return sdk.WrapError(err, "addImagePlugin> Cannot commit transaction")
+	}
+
+	return WriteJSON(w, r, a, addPluginStatus(update))
 }
 
-func (api *API) updatePluginHandler() Handler {
-	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
-		//Upload file and get plugin information
-		ap, params, file, deferFunc, errUpload := fileUploadAndGetPlugin(w, r)
-		if deferFunc != nil {
-			defer deferFunc()
-		}
-		if errUpload != nil {
-			return sdk.WrapError(errUpload, "updatePluginHandler> fileUploadAndGetPlugin error")
-		}
+// addPluginStatus returns the status of a successful upload of a new version of a plugin
+func addPluginStatus(update bool) int {
+	if update {
+		return http.StatusOK
+	}
+	return http.StatusCreated
+}
 
-		// Check that action does not already exists
-		exists, errExists := action.Exists(api.mustDB(), ap.Name)
-		if errExists != nil {
-			return sdk.WrapError(errExists, "updatePluginHandler> unable to check if action %s exists", ap.Name)
-		}
-		if !exists {
-			return sdk.WrapError(sdk.ErrNoAction, "updatePluginHandler")
-		}
+// addPluginVersion uploads a new version of a plugin. On update, the plugin must already exist
+func (api *API) addPluginVersion(ctx context.Context, w http.ResponseWriter, r *http.Request, update bool) error {
+	if isImagePluginRequest(r) {
+		return api.addImagePlugin(w, r, getUser(ctx).ID, update)
+	}
 
-		//Store previous file from objectstore
-		buf, errFetch := objectstore.FetchPlugin(*ap)
-		if errFetch != nil {
-			log.Warning("updatePluginHandler>Unable to fetch plugin: %s", errFetch)
-			// do no raise error... it just mean that we cannot fetch the old version of the plugin
-		}
+	//Upload file and get plugin information
+	ap, file, deferFunc, err := fileUploadAndGetPlugin(w, r)
+	if deferFunc != nil {
+		defer deferFunc()
+	}
+	if err != nil {
+		return sdk.WrapError(err, "addPluginVersion>%T", err)
+	}
+	defer file.Close()
 
-		var tmpFile string
-		if buf != nil && errFetch == nil {
-			defer buf.Close()
-			//Read it
-			btes, errr := ioutil.ReadAll(buf)
-			if errr != nil {
-				return sdk.WrapError(errr, "updatePluginHandler> Unable to read old plugin buffer")
-			}
-			//Get a dir
-			tmpDir, errtmp := ioutil.TempDir("", "old-plugin")
-			if errtmp != nil {
-				return sdk.WrapError(errtmp, "updatePluginHandler> error with tempdir")
-			}
-			os.MkdirAll(tmpDir, os.FileMode(0700))
+	// Check that the version does not already exists
+	if err := checkNewPluginVersion(api.mustDB(), ap, update); err != nil {
+		return sdk.WrapError(err, "addPluginVersion>")
+	}
 
-			//Get a temp file
-			tmpFile = path.Join(tmpDir, ap.Name)
+	//Upload it to objectstore
+	objectPath, err := objectstore.StorePlugin(*ap, file)
+	if err != nil {
+		return sdk.WrapError(err, "addPluginVersion> Error while uploading to object store %s", ap.Name)
+	}
+	ap.ObjectPath = objectPath
 
-			//Write it
-			log.Debug("updatePluginHandler>store oldfile %s in case of error", tmpFile)
-			if err := ioutil.WriteFile(tmpFile, btes, os.FileMode(0600)); err != nil {
-				return sdk.WrapError(err, "updatePluginHandler>Error writing file %s", tmpFile)
-			}
-			defer func() {
-				log.Debug("updatePluginHandler> deleting file %s", tmpFile)
-				os.RemoveAll(tmpFile)
-			}()
-			//Delete previous file from objectstore
-			if err := objectstore.DeletePlugin(*ap); err != nil {
-				return sdk.WrapError(err, "updatePluginHandler>Error deleting file")
-			}
-		}
+	tx, err := api.mustDB().Begin()
+	if err != nil {
+		return sdk.WrapError(err, "addPluginVersion> Cannot start transaction")
+	}
+	defer tx.Rollback()
 
-		//Upload it to objectstore
-		objectPath, errStore := objectstore.StorePlugin(*ap, file)
-		if errStore != nil {
-			return sdk.WrapError(errStore, "updatePluginHandler> Error while uploading to object store %s", ap.Name)
-		}
-		ap.ObjectPath = objectPath
+	//Insert in database
+	a, err := actionplugin.Insert(tx, ap, getUser(ctx).ID)
+	if err != nil {
+		objectstore.DeletePlugin(*ap)
+		return sdk.WrapError(err, "addPluginVersion> Error while inserting action %s in database", ap.Name)
+	}
 
-		tx, errBegin := api.mustDB().Begin()
-		if errBegin != nil {
-			return sdk.WrapError(errBegin, "updatePluginHandler> Cannot start transaction")
-		}
-		defer tx.Rollback()
+	if err := tx.Commit(); err != nil {
+		return sdk.WrapError(err, "addPluginVersion> Cannot commit transaction")
+	}
 
-		//Update in database
-		a, errDB := actionplugin.Update(tx, ap, params, getUser(ctx).ID)
-		if errDB != nil && tmpFile != "" {
-			log.Warning("updatePluginHandler> Error while updating action %s in database: %s\n", ap.Name, errDB)
-			//Restore previous file
-			oldFile, errO := os.Open(tmpFile)
-			if errO != nil {
-				return sdk.WrapError(errO, "updatePluginHandler>Error opening file %s ", tmpFile)
-			}
-			//re-store the old plugin file
-			if _, errStore := objectstore.StorePlugin(*ap, oldFile); errStore != nil {
-				return sdk.WrapError(errStore, "updatePluginHandler> Error while uploading to object store %s", ap.Name)
-			}
+	return WriteJSON(w, r, a, addPluginStatus(update))
+}
 
-			return sdk.WrapError(errDB, "updatePluginHandler> Unable to update plugin", ap.Name)
-		}
-		if err := tx.Commit(); err != nil {
-			return sdk.WrapError(err, "updatePluginHandler> Cannot commit transaction")
-		}
+func (api *API) addPluginHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		return api.addPluginVersion(ctx, w, r, false)
+	}
+}
 
-		return WriteJSON(w, r, a, http.StatusOK)
+// updatePluginHandler adds a new version of an existing plugin, the versions of a plugin are never replaced
+func (api *API) updatePluginHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		return api.addPluginVersion(ctx, w, r, true)
 	}
 }
 
@@ -226,14 +237,24 @@ func (api *API) deletePluginHandler() Handler {
 			return sdk.ErrWrongRequest
 		}
 
+		versions, err := actionplugin.LoadVersions(api.mustDB(), name)
+		if err != nil {
+			return sdk.WrapError(err, "deletePluginHandler> Unable to load plugin %s", name)
+		}
+
 		//Delete in database
 		if err := actionplugin.Delete(api.mustDB(), name, getUser(ctx).ID); err != nil {
 			return sdk.WrapError(err, "deletePluginHandler> Error while deleting action %s in database", name)
 		}
 
-		//Delete from objectstore
-		if err := objectstore.DeletePlugin(sdk.ActionPlugin{Name: name}); err != nil {
-			return sdk.WrapError(err, "deletePluginHandler> Error while deleting action %s in objectstore", name)
+		//Delete from objectstore, a docker action plugin has no file in objectstore
+		for _, ap := range versions {
+			if ap.IsImage() {
+				continue
+			}
+			if err := objectstore.DeletePlugin(ap); err != nil {
+				return sdk.WrapError(err, "deletePluginHandler> Error while deleting action %s in objectstore", ap.GetName())
+			}
 		}
 		return nil
 	}
@@ -248,9 +269,33 @@ func (api *API) downloadPluginHandler() Handler {
 			return sdk.ErrWrongRequest
 		}
 
-		f, err := objectstore.FetchPlugin(sdk.ActionPlugin{Name: name})
+		// Without version, the latest version is downloaded
+		var ap *sdk.ActionPlugin
+		version := r.FormValue("version")
+		if version != "" {
+			var err error
+			ap, err = actionplugin.LoadVersion(api.mustDB(), name, version)
+			if err != nil {
+				return sdk.WrapError(err, "downloadPluginHandler> Unable to load plugin %s", sdk.PluginRef(name, version))
+			}
+		} else {
+			versions, err := actionplugin.LoadVersions(api.mustDB(), name)
+			if err != nil {
+				return sdk.WrapError(err, "downloadPluginHandler> Unable to load plugin %s", name)
+			}
+			ap, err = sdk.ResolvePluginVersion(versions, "")
+			if err != nil {
+				return sdk.WrapError(err, "downloadPluginHandler> Unable to get latest version of plugin %s", name)
+			}
+		}
+
+		if ap.IsImage() {
+			return sdk.WrapError(sdk.ErrPluginInvalid, "downloadPluginHandler> %s is a docker plugin", ap.GetName())
+		}
+
+		f, err := objectstore.FetchPlugin(*ap)
 		if err != nil {
-			return sdk.WrapError(err, "downloadPluginHandler> Error while fetching plugin", name)
+			return sdk.WrapError(err, "downloadPluginHandler> Error while fetching plugin %s", ap.GetName())
 		}
 
 		w.Header().Add("Content-Type", "application/octet-stream")
@@ -268,3 +313,97 @@ func (api *API) downloadPluginHandler() Handler {
 		return nil
 	}
 }
+
+func (api *API) getPluginVersionsHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		name := mux.Vars(r)["name"]
+
+		versions, err := actionplugin.LoadVersions(api.mustDB(), name)
+		if err != nil {
+			return sdk.WrapError(err, "getPluginVersionsHandler>")
+		}
+		if len(versions) == 0 {
+			return sdk.WrapError(sdk.ErrNoAction, "getPluginVersionsHandler> plugin %s not found", name)
+		}
+
+		return WriteJSON(w, r, versions, http.StatusOK)
+	}
+}
+
+// getPluginVersionHandler resolves the version of a plugin used by a worker: the highest version matching a range,
+// the latest version without range
+func (api *API) getPluginVersionHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		name := mux.Vars(r)["name"]
+		versionRange := r.FormValue("range")
+
+		versions, err := actionplugin.LoadVersions(api.mustDB(), name)
+		if err != nil {
+			return sdk.WrapError(err, "getPluginVersionHandler>")
+		}
+
+		ap, err := sdk.ResolvePluginVersion(versions, versionRange)
+		if err != nil {
+			return sdk.WrapError(err, "getPluginVersionHandler> Unable to resolve %s", sdk.PluginRef(name, versionRange))
+		}
+
+		return WriteJSON(w, r, ap, http.StatusOK)
+	}
+}
+
+func (api *API) putPluginVersionHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		name := vars["name"]
+		version := vars["version"]
+
+		var ap sdk.ActionPlugin
+		if err := UnmarshalBody(r, &ap); err != nil {
+			return sdk.WrapError(err, "putPluginVersionHandler> cannot unmarshal request")
+		}
+
+		if err := actionplugin.Deprecate(api.mustDB(), name, version, ap.Deprecated); err != nil {
+			return sdk.WrapError(err, "putPluginVersionHandler> Unable to update %s", sdk.PluginRef(name, version))
+		}
+
+		updated, err := actionplugin.LoadVersion(api.mustDB(), name, version)
+		if err != nil {
+			return sdk.WrapError(err, "putPluginVersionHandler>")
+		}
+		return WriteJSON(w, r, updated, http.StatusOK)
+	}
+}
+
+func (api *API) deletePluginVersionHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		name := vars["name"]
+		version := vars["version"]
+
+		ap, err := actionplugin.LoadVersion(api.mustDB(), name, version)
+		if err != nil {
+			return sdk.WrapError(err, "deletePluginVersionHandler> Unable to load plugin %s", sdk.PluginRef(name, version))
+		}
+
+		tx, err := api.mustDB().Begin()
+		if err != nil {
+			return sdk.WrapError(err, "deletePluginVersionHandler> Cannot start transaction")
+		}
+		defer tx.Rollback()
+
+		if err := actionplugin.DeleteVersion(tx, name, version, getUser(ctx).ID); err != nil {
+			return sdk.WrapError(err, "deletePluginVersionHandler>")
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WrapError(err, "deletePluginVersionHandler> Cannot commit transaction")
+		}
+
+		if !ap.IsImage() {
+			if err := objectstore.DeletePlugin(*ap); err != nil {
+				return sdk.WrapError(err, "deletePluginVersionHandler> Error while deleting %s in objectstore", ap.GetName())
+			}
+		}
+		return nil
+	}
+}
@@ -5,12 +5,19 @@ import (
 	"io"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"path"
 	"runtime"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/action"
+	"github.com/ovh/cds/engine/api/actionplugin"
 	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/engine/api/test/assets"
+	"github.com/ovh/cds/sdk"
 )
 
 const dummyBinaryFile = "https://github.com/ovh/cds/releases/download/0.8.1/plugin-download-" + runtime.GOOS + "-amd64"
@@ -341,8 +348,13 @@ func TestUpdatePluginHandlerSuccess(t *testing.T) {
 			t.Fail()
 			return
 		}
-		//Then update the action
+		//An existing version cannot be replaced
 		postFile(t, db, path, "/plugin_test/TestUpdatePluginHandlerSuccess_PUT", "PUT", updatePluginHandler, func(t *testing.T, db *gorp.DbMap, resp *httptest.ResponseRecorder) {
+			t.Logf("Code status : %d", resp.Code)
+			assert.Equal(t, 409, resp.Code)
+		})
+		//Then update the action with a new version
+		postFile(t, db, path, "/plugin_test/TestUpdatePluginHandlerSuccess_PUT?version=99.0.0", "PUT", updatePluginHandler, func(t *testing.T, db *gorp.DbMap, resp *httptest.ResponseRecorder) {
 			t.Logf("Code status : %d", resp.Code)
 			assert.Equal(t, 200, resp.Code)
 			body, _ := ioutil.ReadAll(resp.Body)
@@ -435,3 +447,40 @@ func TestDeletePluginHandlerSuccess(t *testing.T) {
 	})
 }
 */
+
+func Test_deletePluginVersionHandler(t *testing.T) {
+	api, _, router := newTestAPI(t)
+
+	u, pass := assets.InsertAdminUser(api.mustDB())
+
+	name := "plugin-" + sdk.RandomString(10)
+	for _, ap := range []sdk.ActionPlugin{
+		{Name: name, Image: "ovh/" + name + ":1.0.0", Version: "1.0.0", Parameters: []sdk.Parameter{{Name: "foo", Type: sdk.StringParameter}}},
+		{Name: name, Image: "ovh/" + name + ":2.0.0", Version: "2.0.0", Parameters: []sdk.Parameter{{Name: "bar", Type: sdk.StringParameter}}},
+	} {
+		_, err := actionplugin.Insert(api.mustDB(), &ap, u.ID)
+		test.NoError(t, err)
+	}
+	defer actionplugin.Delete(api.mustDB(), name, u.ID)
+
+	a, err := action.LoadPublicAction(api.mustDB(), name)
+	test.NoError(t, err)
+	if assert.Len(t, a.Parameters, 1) {
+		assert.Equal(t, "bar", a.Parameters[0].Name)
+	}
+
+	//Delete the latest version
+	uri := router.GetRoute("DELETE", api.deletePluginVersionHandler, map[string]string{"name": name, "version": "2.0.0"})
+	test.NotEmpty(t, uri)
+	req := assets.NewAuthentifiedRequest(t, u, pass, "DELETE", uri, nil)
+	w := httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code)
+
+	//The action has the parameters of the new latest version
+	a, err = action.LoadPublicAction(api.mustDB(), name)
+	test.NoError(t, err)
+	if assert.Len(t, a.Parameters, 1) {
+		assert.Equal(t, "foo", a.Parameters[0].Name)
+	}
+}
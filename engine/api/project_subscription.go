@@ -0,0 +1,155 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/event"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/sdk"
+)
+
+func (api *API) getSubscriptionsInProjectHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		key := mux.Vars(r)["permProjectKey"]
+
+		subs, err := event.LoadSubscriptions(api.mustDB(), key)
+		if err != nil {
+			return sdk.WrapError(err, "getSubscriptionsInProjectHandler> Cannot load subscriptions")
+		}
+		return WriteJSON(w, r, subs, http.StatusOK)
+	}
+}
+
+func (api *API) addSubscriptionInProjectHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		key := mux.Vars(r)["permProjectKey"]
+
+		var s sdk.EventSubscription
+		if err := UnmarshalBody(r, &s); err != nil {
+			return err
+		}
+		if err := s.IsValid(); err != nil {
+			return sdk.WrapError(err, "addSubscriptionInProjectHandler> Invalid subscription %s", s.Name)
+		}
+
+		p, errP := project.Load(api.mustDB(), api.Cache, key, getUser(ctx))
+		if errP != nil {
+			return sdk.WrapError(errP, "addSubscriptionInProjectHandler> Cannot load project")
+		}
+
+		s.ProjectID = p.ID
+		s.ProjectKey = p.Key
+		if err := event.InsertSubscription(api.mustDB(), &s); err != nil {
+			return sdk.WrapError(err, "addSubscriptionInProjectHandler> Cannot insert subscription")
+		}
+		event.InvalidateSubscriptions(api.Cache, p.Key)
+		s.Secret = ""
+		return WriteJSON(w, r, s, http.StatusCreated)
+	}
+}
+
+func (api *API) getSubscriptionInProjectHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		s, err := api.loadSubscription(r)
+		if err != nil {
+			return sdk.WrapError(err, "getSubscriptionInProjectHandler> Cannot load subscription")
+		}
+		return WriteJSON(w, r, s, http.StatusOK)
+	}
+}
+
+func (api *API) updateSubscriptionInProjectHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		old, err := api.loadSubscription(r)
+		if err != nil {
+			return sdk.WrapError(err, "updateSubscriptionInProjectHandler> Cannot load subscription")
+		}
+
+		var s sdk.EventSubscription
+		if err := UnmarshalBody(r, &s); err != nil {
+			return err
+		}
+		if err := s.IsValid(); err != nil {
+			return sdk.WrapError(err, "updateSubscriptionInProjectHandler> Invalid subscription %s", s.Name)
+		}
+
+		s.ID = old.ID
+		s.ProjectID = old.ProjectID
+		s.ProjectKey = old.ProjectKey
+		s.Created = old.Created
+		if err := event.UpdateSubscription(api.mustDB(), &s); err != nil {
+			return sdk.WrapError(err, "updateSubscriptionInProjectHandler> Cannot update subscription %d", s.ID)
+		}
+		event.InvalidateSubscriptions(api.Cache, s.ProjectKey)
+		s.Secret = ""
+		return WriteJSON(w, r, s, http.StatusOK)
+	}
+}
+
+func (api *API) deleteSubscriptionInProjectHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		s, err := api.loadSubscription(r)
+		if err != nil {
+			return sdk.WrapError(err, "deleteSubscriptionInProjectHandler> Cannot load subscription")
+		}
+		if err := event.DeleteSubscription(api.mustDB(), s.ProjectID, s.ID); err != nil {
+			return sdk.WrapError(err, "deleteSubscriptionInProjectHandler> Cannot delete subscription %d", s.ID)
+		}
+		event.InvalidateSubscriptions(api.Cache, s.ProjectKey)
+		return nil
+	}
+}
+
+func (api *API) getSubscriptionDeliveriesHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		s, err := api.loadSubscription(r)
+		if err != nil {
+			return sdk.WrapError(err, "getSubscriptionDeliveriesHandler> Cannot load subscription")
+		}
+
+		limit := 50
+		if l := r.FormValue("limit"); l != "" {
+			limit, err = strconv.Atoi(l)
+			if err != nil || limit <= 0 {
+				return sdk.WrapError(sdk.ErrWrongRequest, "getSubscriptionDeliveriesHandler> Invalid limit %s", l)
+			}
+		}
+
+		deliveries, err := event.LoadDeliveries(api.mustDB(), s.ID, r.FormValue("status"), limit)
+		if err != nil {
+			return sdk.WrapError(err, "getSubscriptionDeliveriesHandler> Cannot load deliveries")
+		}
+		return WriteJSON(w, r, deliveries, http.StatusOK)
+	}
+}
+
+func (api *API) retrySubscriptionDeliveryHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		s, err := api.loadSubscription(r)
+		if err != nil {
+			return sdk.WrapError(err, "retrySubscriptionDeliveryHandler> Cannot load subscription")
+		}
+
+		deliveryID, err := strconv.ParseInt(mux.Vars(r)["deliveryID"], 10, 64)
+		if err != nil {
+			return sdk.WrapError(sdk.ErrWrongRequest, "retrySubscriptionDeliveryHandler> Invalid delivery id")
+		}
+		if err := event.RetryDelivery(api.mustDB(), s.ID, deliveryID); err != nil {
+			return sdk.WrapError(err, "retrySubscriptionDeliveryHandler> Cannot retry delivery %d", deliveryID)
+		}
+		return nil
+	}
+}
+
+func (api *API) loadSubscription(r *http.Request) (*sdk.EventSubscription, error) {
+	vars := mux.Vars(r)
+	id, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		return nil, sdk.ErrWrongRequest
+	}
+	return event.LoadSubscription(api.mustDB(), vars["permProjectKey"], id)
+}
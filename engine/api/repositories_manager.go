@@ -182,15 +182,18 @@ func (api *API) repositoriesManagerAuthorizeCallbackHandler() Handler {
 			return err
 		}
 
-		var token, verifier string
+		var token, verifier, sshKey string
 		if tv["request_token"] != nil {
 			token = tv["request_token"].(string)
 		}
 		if tv["verifier"] != nil {
 			verifier = tv["verifier"].(string)
 		}
+		if tv["ssh_key"] != nil {
+			sshKey = tv["ssh_key"].(string)
+		}
 
-		if token == "" || verifier == "" {
+		if token == "" || (verifier == "" && sshKey == "") {
 			return sdk.WrapError(sdk.ErrWrongRequest, "repositoriesManagerAuthorizeCallback> Cannot get token nor verifier from data")
 		}
 
@@ -199,6 +202,22 @@ func (api *API) repositoriesManagerAuthorizeCallbackHandler() Handler {
 			return sdk.WrapError(errP, "repositoriesManagerAuthorizeCallback> Cannot load project")
 		}
 
+		//Repositories managers without any API, such as plain git servers, are authorized with a SSH key of the project
+		if sshKey != "" {
+			if err := project.LoadAllKeys(api.mustDB(), proj); err != nil {
+				return sdk.WrapError(err, "repositoriesManagerAuthorizeCallback> Cannot load project keys")
+			}
+			for _, k := range proj.Keys {
+				if k.Name == sshKey && k.Type == sdk.KeyTypeSsh {
+					verifier = k.Private
+					break
+				}
+			}
+			if verifier == "" {
+				return sdk.WrapError(sdk.ErrKeyNotFound, "repositoriesManagerAuthorizeCallback> SSH key %s not found on project %s", sshKey, projectKey)
+			}
+		}
+
 		vcsServer, errVCSServer := repositoriesmanager.NewVCSServerConsumer(api.mustDB, api.Cache, rmName)
 		if errVCSServer != nil {
 			return sdk.WrapError(errVCSServer, "repositoriesManagerAuthorizeCallback> Cannot load project")
@@ -214,7 +233,7 @@ func (api *API) repositoriesManagerAuthorizeCallbackHandler() Handler {
 		if err != nil {
 			return sdk.WrapError(sdk.ErrNoReposManagerClientAuth, "repositoriesManagerAuthorizeCallback> Error with AuthorizeToken: %s", err)
 		}
-		log.Debug("repositoriesManagerAuthorizeCallback> [%s] AccessToken=%s; AccessTokenSecret=%s", projectKey, token, secret)
+		log.Debug("repositoriesManagerAuthorizeCallback> [%s] AccessToken=%s", projectKey, token)
 
 		vcsServerForProject := &sdk.ProjectVCSServer{
 			Name: rmName,
@@ -7,6 +7,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 
 	"github.com/go-gorp/gorp"
@@ -212,6 +213,19 @@ func (c *vcsClient) Commit(fullname, hash string) (sdk.VCSCommit, error) {
 	return commit, nil
 }
 
+func (c *vcsClient) Contents(fullname, filepath, ref string) (sdk.VCSContent, error) {
+	content := sdk.VCSContent{}
+	segments := strings.Split(strings.Trim(filepath, "/"), "/")
+	for i := range segments {
+		segments[i] = url.PathEscape(segments[i])
+	}
+	path := fmt.Sprintf("/vcs/%s/repos/%s/contents/%s?ref=%s", c.name, fullname, strings.Join(segments, "/"), url.QueryEscape(ref))
+	if _, err := c.doJSONRequest("GET", path, nil, &content); err != nil {
+		return content, err
+	}
+	return content, nil
+}
+
 func (c *vcsClient) PullRequests(fullname string) ([]sdk.VCSPullRequest, error) {
 	prs := []sdk.VCSPullRequest{}
 	path := fmt.Sprintf("/vcs/%s/repos/%s/pullrequests", c.name, fullname)
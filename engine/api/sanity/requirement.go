@@ -122,7 +122,7 @@ func checkNoWorkerModelMatchRequirement(proj string, pip string, a *sdk.Action,
 	var warns []sdk.Warning
 	areqs := a.Requirements
 
-	// Check all binary requirement are present in at least one model
+	// Check all binary, os/architecture, label, cpu and disk requirements are satisfied by at least one model
 	validModel := false
 	for _, wm := range wms {
 		ok := true
@@ -134,6 +134,15 @@ func checkNoWorkerModelMatchRequirement(proj string, pip string, a *sdk.Action,
 				break
 			}
 
+			// OS/architecture, label, cpu and disk requirements have to be advertised in model capabilities
+			if sdk.IsCapabilityRequirement(ar) {
+				if !sdk.SatisfyRequirement(wm.Capabilities, ar) {
+					ok = false
+					break
+				}
+				continue
+			}
+
 			// We are only checkins binary requirement matching with binary capabilities
 			// so let's skip this other types of requirements
 			if ar.Type != sdk.BinaryRequirement {
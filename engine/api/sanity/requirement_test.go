@@ -395,6 +395,104 @@ func Test_checkNoWorkerModelMatchRequirement(t *testing.T) {
 			want:    nil,
 			wantErr: false,
 		},
+		{
+			name: "With an os-architecture req not advertised by the model it should return 1 warning",
+			args: args{
+				proj: "proj",
+				pip:  "pipeline",
+				a: &sdk.Action{
+					ID:   1,
+					Name: "Action Name 1",
+					Requirements: []sdk.Requirement{
+						{
+							Name:  "os-architecture",
+							Type:  sdk.OSArchRequirement,
+							Value: "linux/arm64",
+						},
+					},
+				},
+				wms: []sdk.Model{
+					{
+						Capabilities: []sdk.Requirement{
+							{
+								Type:  sdk.OSArchRequirement,
+								Value: "linux/amd64",
+							},
+						},
+					},
+				},
+			},
+			want: []sdk.Warning{
+				{
+					Action: sdk.Action{
+						ID: 1,
+					},
+					ID: NoWorkerModelMatchRequirement,
+					MessageParam: map[string]string{
+						"ActionName":   "Action Name 1",
+						"PipelineName": "pipeline",
+						"ProjectKey":   "proj",
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "With os-architecture, label and cpu reqs satisfied by a model it should not return warning",
+			args: args{
+				proj: "proj",
+				pip:  "pipeline",
+				a: &sdk.Action{
+					ID:   1,
+					Name: "Action Name 1",
+					Requirements: []sdk.Requirement{
+						{
+							Name:  "os-architecture",
+							Type:  sdk.OSArchRequirement,
+							Value: "linux",
+						},
+						{
+							Name:  "gpu",
+							Type:  sdk.LabelRequirement,
+							Value: "gpu",
+						},
+						{
+							Name:  "cpu",
+							Type:  sdk.CPURequirement,
+							Value: "4",
+						},
+					},
+				},
+				wms: []sdk.Model{
+					{
+						Capabilities: []sdk.Requirement{
+							{
+								Type:  sdk.OSArchRequirement,
+								Value: "windows/amd64",
+							},
+						},
+					},
+					{
+						Capabilities: []sdk.Requirement{
+							{
+								Type:  sdk.OSArchRequirement,
+								Value: "linux/arm64",
+							},
+							{
+								Type:  sdk.LabelRequirement,
+								Value: "gpu",
+							},
+							{
+								Type:  sdk.CPURequirement,
+								Value: "8",
+							},
+						},
+					},
+				},
+			},
+			want:    nil,
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		got, err := checkNoWorkerModelMatchRequirement(tt.args.proj, tt.args.pip, tt.args.a, tt.args.wms, 0, 0)
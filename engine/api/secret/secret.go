@@ -32,6 +32,8 @@ var (
 type Secret struct {
 	Token  string
 	Client *vault.Client
+	// ProjectPolicy is the vault policy granted to a project to read its vault variables
+	ProjectPolicy string
 }
 
 // Init secrets: cipherKey
@@ -0,0 +1,147 @@
+package secret
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	vault "github.com/hashicorp/vault/api"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// DefaultProjectPolicy is the name of the vault policy used to read vault variables of a project
+const DefaultProjectPolicy = "cds-project-{{.ProjectKey}}"
+
+// VaultStore is a secret store able to read a secret on behalf of a project
+type VaultStore interface {
+	ReadForProject(projectKey, path string) (string, error)
+}
+
+var vaultStore VaultStore
+
+// InitVault sets the store used to resolve vault variables at job runtime
+func InitVault(s VaultStore) {
+	vaultStore = s
+}
+
+// ResolveVaultVariable replaces the vault path of a vault variable by the secret value.
+// The resolved variable becomes a password variable, so it is handled as any other secret.
+func ResolveVaultVariable(projectKey string, v *sdk.Variable) error {
+	if !sdk.IsVaultVariable(v.Type) {
+		return nil
+	}
+	if vaultStore == nil {
+		return sdk.WrapError(sdk.ErrSecretStoreUnreachable, "ResolveVaultVariable> vault is not configured, unable to resolve %s", v.Name)
+	}
+
+	value, err := vaultStore.ReadForProject(projectKey, v.Value)
+	if err != nil {
+		return sdk.WrapError(err, "ResolveVaultVariable> Unable to resolve %s", v.Name)
+	}
+
+	v.Value = value
+	v.Type = sdk.SecretVariable
+	return nil
+}
+
+func projectPolicy(format, projectKey string) string {
+	return strings.Replace(format, "{{.ProjectKey}}", strings.ToLower(projectKey), -1)
+}
+
+// ReadForProject reads a secret from vault with a single-use token restricted to the project policy
+func (secret *Secret) ReadForProject(projectKey, path string) (string, error) {
+	client := secret.Client
+	if secret.ProjectPolicy != "" {
+		t, err := secret.Client.Auth().Token().Create(&vault.TokenCreateRequest{
+			Policies:        []string{projectPolicy(secret.ProjectPolicy, projectKey)},
+			TTL:             "1m",
+			NumUses:         1,
+			NoDefaultPolicy: true,
+			DisplayName:     "cds-" + projectKey,
+		})
+		if err != nil {
+			return "", sdk.WrapError(sdk.ErrSecretStoreUnreachable, "ReadForProject> Unable to create vault token for project %s: %s", projectKey, err)
+		}
+		if t == nil || t.Auth == nil {
+			return "", sdk.WrapError(sdk.ErrSecretStoreUnreachable, "ReadForProject> Invalid vault token for project %s", projectKey)
+		}
+
+		client, err = vault.NewClient(vault.DefaultConfig())
+		if err != nil {
+			return "", err
+		}
+		client.SetAddress(secret.Client.Address())
+		client.SetToken(t.Auth.ClientToken)
+	}
+
+	conf, err := client.Logical().Read(path)
+	if err != nil {
+		return "", sdk.WrapError(sdk.ErrForbidden, "ReadForProject> Unable to read %s for project %s: %s", path, projectKey, err)
+	}
+	if conf == nil {
+		return "", sdk.ErrVaultSecretNotFound
+	}
+	value, exists := conf.Data["data"]
+	if !exists {
+		return "", sdk.ErrVaultSecretNotFound
+	}
+	return fmt.Sprintf("%v", value), nil
+}
+
+// LocalVault is an in-memory stand-in for vault, for dev mode and tests.
+// As with vault, a project can only read the paths granted to its policy.
+type LocalVault struct {
+	ProjectPolicy string
+	mutex         sync.RWMutex
+	data          map[string]string
+	policies      map[string][]string
+}
+
+// NewLocalVault returns an empty LocalVault
+func NewLocalVault(projectPolicy string) *LocalVault {
+	return &LocalVault{
+		ProjectPolicy: projectPolicy,
+		data:          map[string]string{},
+		policies:      map[string][]string{},
+	}
+}
+
+// Write stores a secret at the given path
+func (l *LocalVault) Write(path, value string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.data[path] = value
+}
+
+// Grant allows the policy to read all the paths starting with prefix
+func (l *LocalVault) Grant(policy, prefix string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.policies[policy] = append(l.policies[policy], prefix)
+}
+
+// ReadForProject reads a secret if the project policy grants it
+func (l *LocalVault) ReadForProject(projectKey, path string) (string, error) {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	if l.ProjectPolicy != "" {
+		var granted bool
+		for _, prefix := range l.policies[projectPolicy(l.ProjectPolicy, projectKey)] {
+			if strings.HasPrefix(path, prefix) {
+				granted = true
+				break
+			}
+		}
+		if !granted {
+			return "", sdk.WrapError(sdk.ErrForbidden, "ReadForProject> project %s is not allowed to read %s", projectKey, path)
+		}
+	}
+
+	value, ok := l.data[path]
+	if !ok {
+		return "", sdk.ErrVaultSecretNotFound
+	}
+	return value, nil
+}
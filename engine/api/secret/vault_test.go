@@ -0,0 +1,46 @@
+package secret
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestResolveVaultVariable(t *testing.T) {
+	v := NewLocalVault(DefaultProjectPolicy)
+	v.Write("secret/cds/key1/db", "my-db-password")
+	v.Write("secret/cds/key2/db", "another-db-password")
+	v.Grant("cds-project-key1", "secret/cds/key1/")
+	InitVault(v)
+	defer InitVault(nil)
+
+	variable := sdk.Variable{Name: "cds.proj.db", Type: sdk.VaultVariable, Value: "secret/cds/key1/db"}
+	assert.NoError(t, ResolveVaultVariable("KEY1", &variable))
+	assert.Equal(t, "my-db-password", variable.Value)
+	assert.Equal(t, sdk.SecretVariable, variable.Type)
+
+	// Project KEY1 is not allowed to read secrets of project KEY2
+	variable = sdk.Variable{Name: "cds.proj.db", Type: sdk.VaultVariable, Value: "secret/cds/key2/db"}
+	err := ResolveVaultVariable("KEY1", &variable)
+	assert.Error(t, err)
+	assert.Equal(t, sdk.ErrForbidden, errors.Cause(err))
+
+	variable = sdk.Variable{Name: "cds.proj.db", Type: sdk.VaultVariable, Value: "secret/cds/key1/unknown"}
+	err = ResolveVaultVariable("KEY1", &variable)
+	assert.Equal(t, sdk.ErrVaultSecretNotFound, errors.Cause(err))
+
+	// Other variables are left untouched
+	variable = sdk.Variable{Name: "cds.proj.foo", Type: sdk.StringVariable, Value: "secret/cds/key1/db"}
+	assert.NoError(t, ResolveVaultVariable("KEY1", &variable))
+	assert.Equal(t, "secret/cds/key1/db", variable.Value)
+}
+
+func TestResolveVaultVariableWithoutVault(t *testing.T) {
+	InitVault(nil)
+	variable := sdk.Variable{Name: "cds.proj.db", Type: sdk.VaultVariable, Value: "secret/cds/key1/db"}
+	err := ResolveVaultVariable("KEY1", &variable)
+	assert.Equal(t, sdk.ErrSecretStoreUnreachable, errors.Cause(err))
+}
@@ -0,0 +1,116 @@
+package token
+
+import (
+	"crypto/sha512"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk"
+)
+
+const accessTokenColumns = `id, user_id, name, scopes, project_key, created, expire_at, last_used`
+
+// hashToken returns the hash stored in database instead of the token value
+func hashToken(token string) string {
+	sum := sha512.Sum512([]byte(token))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// InsertAccessToken generates and inserts a new personal access token. The token value is only set on the returned struct.
+func InsertAccessToken(db gorp.SqlExecutor, t *sdk.AccessToken) error {
+	tk, err := GenerateToken()
+	if err != nil {
+		return sdk.WrapError(err, "InsertAccessToken> Unable to generate token")
+	}
+
+	scopes, err := json.Marshal(t.Scopes)
+	if err != nil {
+		return sdk.WrapError(err, "InsertAccessToken> Unable to marshal scopes")
+	}
+
+	t.Created = time.Now()
+	query := `INSERT INTO user_access_token (user_id, name, token_hash, scopes, project_key, created, expire_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`
+	if err := db.QueryRow(query, t.UserID, t.Name, hashToken(tk), scopes, t.ProjectKey, t.Created, t.ExpireAt).Scan(&t.ID); err != nil {
+		return sdk.WrapError(err, "InsertAccessToken> Unable to insert token %s for user %d", t.Name, t.UserID)
+	}
+	t.Token = tk
+	return nil
+}
+
+// LoadAccessTokens returns all the personal access tokens of a user, without their values
+func LoadAccessTokens(db gorp.SqlExecutor, userID int64) ([]sdk.AccessToken, error) {
+	query := `SELECT ` + accessTokenColumns + ` FROM user_access_token WHERE user_id = $1 ORDER BY created`
+	rows, err := db.Query(query, userID)
+	if err != nil {
+		return nil, sdk.WrapError(err, "LoadAccessTokens> Unable to load tokens for user %d", userID)
+	}
+	defer rows.Close()
+
+	tokens := []sdk.AccessToken{}
+	for rows.Next() {
+		t, err := scanAccessToken(rows)
+		if err != nil {
+			return nil, sdk.WrapError(err, "LoadAccessTokens> Unable to scan token")
+		}
+		tokens = append(tokens, *t)
+	}
+	return tokens, nil
+}
+
+// LoadAccessToken fetches a personal access token from its value
+func LoadAccessToken(db gorp.SqlExecutor, token string) (*sdk.AccessToken, error) {
+	query := `SELECT ` + accessTokenColumns + ` FROM user_access_token WHERE token_hash = $1`
+	t, err := scanAccessToken(db.QueryRow(query, hashToken(token)))
+	if err == sql.ErrNoRows {
+		return nil, sdk.ErrInvalidToken
+	}
+	if err != nil {
+		return nil, sdk.WrapError(err, "LoadAccessToken> Unable to load token")
+	}
+	return t, nil
+}
+
+// UpdateAccessTokenLastUsed records the last use of a personal access token
+func UpdateAccessTokenLastUsed(db gorp.SqlExecutor, id int64, lastUsed time.Time) error {
+	if _, err := db.Exec(`UPDATE user_access_token SET last_used = $2 WHERE id = $1`, id, lastUsed); err != nil {
+		return sdk.WrapError(err, "UpdateAccessTokenLastUsed> Unable to update token %d", id)
+	}
+	return nil
+}
+
+// DeleteAccessToken revokes a personal access token of a user
+func DeleteAccessToken(db gorp.SqlExecutor, userID, id int64) error {
+	res, err := db.Exec(`DELETE FROM user_access_token WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return sdk.WrapError(err, "DeleteAccessToken> Unable to delete token %d", id)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return sdk.WrapError(err, "DeleteAccessToken> Unable to delete token %d", id)
+	}
+	if n == 0 {
+		return sdk.ErrInvalidToken
+	}
+	return nil
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanAccessToken(s scanner) (*sdk.AccessToken, error) {
+	var t sdk.AccessToken
+	var scopes []byte
+	if err := s.Scan(&t.ID, &t.UserID, &t.Name, &scopes, &t.ProjectKey, &t.Created, &t.ExpireAt, &t.LastUsed); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(scopes, &t.Scopes); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
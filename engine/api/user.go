@@ -157,10 +157,13 @@ func (api *API) getUsersHandler() Handler {
 // AddUser creates a new user and generate verification email
 func (api *API) addUserHandler() Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
-		//returns forbidden if LDAP mode is activated
+		//returns forbidden if LDAP or OpenID Connect mode is activated, users are provisioned at login
 		if _, ldap := api.Router.AuthDriver.(*auth.LDAPClient); ldap {
 			return sdk.ErrForbidden
 		}
+		if _, oidc := api.Router.AuthDriver.(*auth.OIDCClient); oidc {
+			return sdk.ErrForbidden
+		}
 
 		createUserRequest := sdk.UserAPIRequest{}
 		if err := UnmarshalBody(r, &createUserRequest); err != nil {
@@ -274,6 +277,9 @@ func (api *API) authModeHandler() Handler {
 		if _, ldap := api.Router.AuthDriver.(*auth.LDAPClient); ldap {
 			mode = "ldap"
 		}
+		if _, oidc := api.Router.AuthDriver.(*auth.OIDCClient); oidc {
+			mode = "oidc"
+		}
 		res := map[string]string{
 			"auth_mode": mode,
 		}
@@ -0,0 +1,100 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/token"
+	"github.com/ovh/cds/engine/api/user"
+	"github.com/ovh/cds/sdk"
+)
+
+func (api *API) getUserAccessTokensHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		username := mux.Vars(r)["username"]
+
+		u, err := user.LoadUserWithoutAuth(api.mustDB(), username)
+		if err != nil {
+			return sdk.WrapError(err, "getUserAccessTokensHandler> Cannot load user %s", username)
+		}
+
+		tokens, err := token.LoadAccessTokens(api.mustDB(), u.ID)
+		if err != nil {
+			return sdk.WrapError(err, "getUserAccessTokensHandler> Cannot load tokens")
+		}
+		return WriteJSON(w, r, tokens, http.StatusOK)
+	}
+}
+
+func (api *API) postUserAccessTokenHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		username := mux.Vars(r)["username"]
+
+		var t sdk.AccessToken
+		if err := UnmarshalBody(r, &t); err != nil {
+			return err
+		}
+		if err := t.IsValid(); err != nil {
+			return sdk.WrapError(err, "postUserAccessTokenHandler> Invalid token %s: %v", t.Name, t.Scopes)
+		}
+
+		u, err := user.LoadUserWithoutAuth(api.mustDB(), username)
+		if err != nil {
+			return sdk.WrapError(err, "postUserAccessTokenHandler> Cannot load user %s", username)
+		}
+
+		if t.ProjectKey != "" {
+			exist, err := project.Exist(api.mustDB(), t.ProjectKey)
+			if err != nil {
+				return sdk.WrapError(err, "postUserAccessTokenHandler> Cannot check project %s", t.ProjectKey)
+			}
+			if !exist {
+				return sdk.WrapError(sdk.ErrNoProject, "postUserAccessTokenHandler> Project %s not found", t.ProjectKey)
+			}
+		}
+
+		// An access token can't create a token with more scopes than its own
+		if current := getAccessToken(ctx); current != nil {
+			for _, s := range t.Scopes {
+				if !current.HasScope(s) {
+					return sdk.WrapError(sdk.ErrForbidden, "postUserAccessTokenHandler> Scope %s not granted to token %d", s, current.ID)
+				}
+			}
+			if current.ProjectKey != "" && t.ProjectKey != current.ProjectKey {
+				return sdk.WrapError(sdk.ErrForbidden, "postUserAccessTokenHandler> Token %d is restricted to project %s", current.ID, current.ProjectKey)
+			}
+		}
+
+		t.UserID = u.ID
+		if err := token.InsertAccessToken(api.mustDB(), &t); err != nil {
+			return sdk.WrapError(err, "postUserAccessTokenHandler> Cannot insert token")
+		}
+		return WriteJSON(w, r, t, http.StatusCreated)
+	}
+}
+
+func (api *API) deleteUserAccessTokenHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		username := vars["username"]
+
+		id, err := strconv.ParseInt(vars["id"], 10, 64)
+		if err != nil {
+			return sdk.WrapError(sdk.ErrWrongRequest, "deleteUserAccessTokenHandler> Invalid id %s", vars["id"])
+		}
+
+		u, err := user.LoadUserWithoutAuth(api.mustDB(), username)
+		if err != nil {
+			return sdk.WrapError(err, "deleteUserAccessTokenHandler> Cannot load user %s", username)
+		}
+
+		if err := token.DeleteAccessToken(api.mustDB(), u.ID, id); err != nil {
+			return sdk.WrapError(err, "deleteUserAccessTokenHandler> Cannot revoke token %d", id)
+		}
+		return nil
+	}
+}
@@ -32,18 +32,18 @@ func (api *API) registerWorkerHandler() Handler {
 		}
 
 		// Try to register worker
-		worker, err := worker.RegisterWorker(api.mustDB(), params.Name, params.Token, params.ModelID, h, params.BinaryCapabilities)
+		wk, err := worker.RegisterWorker(api.mustDB(), params.Name, params.Token, params.ModelID, h, params.BinaryCapabilities, params.HostCapabilities)
 		if err != nil {
 			err = sdk.NewError(sdk.ErrUnauthorized, err)
 			return sdk.WrapError(err, "registerWorkerHandler> [%s] Registering failed", params.Name)
 		}
 
-		worker.Uptodate = params.Version == sdk.VERSION
+		wk.Uptodate = params.Version == sdk.VERSION
 
-		log.Debug("New worker: [%s] - %s", worker.ID, worker.Name)
+		log.Debug("New worker: [%s] - %s", wk.ID, wk.Name)
 
 		// Return worker info to worker itself
-		return WriteJSON(w, r, worker, http.StatusOK)
+		return WriteJSON(w, r, wk, http.StatusOK)
 	}
 }
 
@@ -183,9 +183,18 @@ func LoadWorkerModelCapabilities(db gorp.SqlExecutor, workerID int64) ([]sdk.Req
 // ComputeRegistrationNeeds checks if worker models need to be register
 // if requirements contains "binary" type: all workers model need to be registered again by
 // setting flag need_registration to true in DB.
+// if requirements contains "os-architecture", "cpu" or "disk" type: only the worker models without
+// this capability need to be registered again.
 func ComputeRegistrationNeeds(db gorp.SqlExecutor, allBinaryReqs []sdk.Requirement, reqs []sdk.Requirement) error {
 	log.Debug("ComputeRegistrationNeeds>")
 	for _, r := range reqs {
+		// os/architecture, cpu and disk are advertised by workers only when they register
+		if sdk.IsHostCapabilityRequirement(r) {
+			if err := updateToNeedRegistrationWithoutCapability(db, r.Type); err != nil {
+				return err
+			}
+			continue
+		}
 		if r.Type == sdk.BinaryRequirement {
 			exist := false
 			for _, e := range allBinaryReqs {
@@ -202,6 +211,54 @@ func ComputeRegistrationNeeds(db gorp.SqlExecutor, allBinaryReqs []sdk.Requireme
 	return nil
 }
 
+// UpdateModelHostCapabilities adds the os/architecture, cpu and disk capabilities advertised by a worker
+// at registration to its model. Capabilities already declared on the model are never overwritten.
+func UpdateModelHostCapabilities(db gorp.SqlExecutor, modelID int64, capabilities []sdk.Requirement) error {
+	existingCapas, err := LoadWorkerModelCapabilities(db, modelID)
+	if err != nil {
+		return sdk.WrapError(err, "UpdateModelHostCapabilities> Unable to load worker model capabilities")
+	}
+
+	for _, c := range capabilities {
+		if !sdk.IsHostCapabilityRequirement(c) {
+			continue
+		}
+		var found bool
+		for _, e := range existingCapas {
+			if e.Type == c.Type || e.Name == c.Name {
+				found = true
+				break
+			}
+		}
+		if found {
+			continue
+		}
+		query := `insert into worker_capability (worker_model_id, name, argument, type) values ($1, $2, $3, $4)`
+		if _, err := db.Exec(query, modelID, c.Name, c.Value, c.Type); err != nil {
+			return sdk.WrapError(err, "UpdateModelHostCapabilities> Unable to insert %s capability of model %d", c.Type, modelID)
+		}
+		existingCapas = append(existingCapas, c)
+	}
+	return nil
+}
+
+// updateToNeedRegistrationWithoutCapability flags the worker models without a capability of the given type
+func updateToNeedRegistrationWithoutCapability(db gorp.SqlExecutor, capaType string) error {
+	query := `UPDATE worker_model SET need_registration = $1
+	WHERE id NOT IN (SELECT worker_model_id FROM worker_capability WHERE type = $2)`
+	res, err := db.Exec(query, true, capaType)
+	if err != nil {
+		return sdk.WrapError(err, "updateToNeedRegistrationWithoutCapability>")
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return sdk.WrapError(err, "updateToNeedRegistrationWithoutCapability>")
+	}
+	log.Debug("updateToNeedRegistrationWithoutCapability> %d worker model(s) without %s capability need registration", rows, capaType)
+	return nil
+}
+
 func updateAllToNeedRegistration(db gorp.SqlExecutor) error {
 	query := `UPDATE worker_model SET need_registration = $1`
 	res, err := db.Exec(query, true)
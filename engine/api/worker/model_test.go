@@ -212,3 +212,35 @@ func TestUpdateWorkerModel(t *testing.T) {
 	assert.Equal(t, sdk.Docker, m3.Type)
 	assert.Equal(t, 2, len(m3.Capabilities))
 }
+
+func TestUpdateModelHostCapabilities(t *testing.T) {
+	db, _ := test.SetupPG(t, bootstrap.InitiliazeDB)
+	deleteAllWorkerModel(t, db)
+
+	g, err := group.LoadGroup(db, "shared.infra")
+	if err != nil {
+		t.Fatalf("Error : %s", err)
+	}
+	m := insertWorkerModel(t, db, "Foo", g.ID)
+
+	// cpu is declared by an administrator
+	if _, err := db.Exec("insert into worker_capability (worker_model_id, name, argument, type) values ($1, $2, $3, $4)", m.ID, "cpu", "16", sdk.CPURequirement); err != nil {
+		t.Fatalf("Cannot insert capability: %s", err)
+	}
+
+	host := []sdk.Requirement{
+		{Name: "cpu", Type: sdk.CPURequirement, Value: "4"},
+		{Name: "disk", Type: sdk.DiskRequirement, Value: "2048"},
+	}
+	assert.NoError(t, UpdateModelHostCapabilities(db, m.ID, host))
+
+	capa, err := LoadWorkerModelCapabilities(db, m.ID)
+	if err != nil {
+		t.Fatalf("Cannot load worker model capabilities: %s", err)
+	}
+	assert.EqualValues(t, []sdk.Requirement{
+		{Name: "capa_1", Type: sdk.BinaryRequirement, Value: "capa_1"},
+		{Name: "cpu", Type: sdk.CPURequirement, Value: "16"},
+		{Name: "disk", Type: sdk.DiskRequirement, Value: "2048"},
+	}, capa)
+}
@@ -269,6 +269,7 @@ type RegistrationForm struct {
 	Hatchery           int64
 	HatcheryName       string
 	BinaryCapabilities []string
+	HostCapabilities   []sdk.Requirement
 	Version            string
 	OS                 string
 	Arch               string
@@ -281,7 +282,7 @@ type TakeForm struct {
 }
 
 // RegisterWorker  Register new worker
-func RegisterWorker(db *gorp.DbMap, name string, key string, modelID int64, h *sdk.Hatchery, binaryCapabilities []string) (*sdk.Worker, error) {
+func RegisterWorker(db *gorp.DbMap, name string, key string, modelID int64, h *sdk.Hatchery, binaryCapabilities []string, hostCapabilities []sdk.Requirement) (*sdk.Worker, error) {
 	if name == "" {
 		return nil, fmt.Errorf("cannot register worker with empty name")
 	}
@@ -356,6 +357,13 @@ func RegisterWorker(db *gorp.DbMap, name string, key string, modelID int64, h *s
 		return nil, err
 	}
 
+	//During a registration run, the worker advertises the os/architecture, cpu and disk of its host
+	if m != nil && m.NeedRegistration && len(hostCapabilities) > 0 {
+		if err := UpdateModelHostCapabilities(tx, modelID, hostCapabilities); err != nil {
+			return nil, sdk.WrapError(err, "RegisterWorker> Unable to update model %d host capabilities", modelID)
+		}
+	}
+
 	//If the worker is registered for a model and it gave us BinaryCapabilities...
 	if len(binaryCapabilities) > 0 && modelID != 0 {
 		go func() {
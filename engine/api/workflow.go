@@ -169,6 +169,12 @@ func (api *API) putWorkflowHandler() Handler {
 		if err := UnmarshalBody(r, &wf); err != nil {
 			return sdk.WrapError(err, "Cannot read body")
 		}
+		//The secrets of the hooks are not sent to the users: the placeholders keep the stored values
+		if err := workflow.DecryptHookSecrets(api.mustDB(), oldW); err != nil {
+			return sdk.WrapError(err, "putWorkflowHandler> Cannot load hooks secrets")
+		}
+		workflow.RestoreHookSecrets(&wf, oldW)
+
 		wf.ID = oldW.ID
 		wf.RootID = oldW.RootID
 		wf.Root.ID = oldW.RootID
@@ -45,11 +45,12 @@ func UpdateLastModifiedDate(db gorp.SqlExecutor, store cache.Store, u *sdk.User,
 // PostGet is a db hook
 func (w *Workflow) PostGet(db gorp.SqlExecutor) error {
 	var res = struct {
-		Metadata  sql.NullString `db:"metadata"`
-		PurgeTags sql.NullString `db:"purge_tags"`
+		Metadata      sql.NullString `db:"metadata"`
+		PurgeTags     sql.NullString `db:"purge_tags"`
+		Notifications sql.NullString `db:"notifications"`
 	}{}
 
-	if err := db.SelectOne(&res, "SELECT metadata, purge_tags FROM workflow WHERE id = $1", w.ID); err != nil {
+	if err := db.SelectOne(&res, "SELECT metadata, purge_tags, notifications FROM workflow WHERE id = $1", w.ID); err != nil {
 		return sdk.WrapError(err, "PostGet> Unable to load marshalled workflow")
 	}
 
@@ -65,6 +66,12 @@ func (w *Workflow) PostGet(db gorp.SqlExecutor) error {
 	}
 	w.PurgeTags = purgeTags
 
+	notifications := []sdk.WorkflowNotification{}
+	if err := gorpmapping.JSONNullString(res.Notifications, &notifications); err != nil {
+		return err
+	}
+	w.Notifications = notifications
+
 	return nil
 }
 
@@ -86,6 +93,17 @@ func (w *Workflow) PostUpdate(db gorp.SqlExecutor) error {
 		return err
 	}
 
+	return updateNotifications(db, w.ID, w.Notifications)
+}
+
+func updateNotifications(db gorp.SqlExecutor, id int64, notifications []sdk.WorkflowNotification) error {
+	n, err := json.Marshal(notifications)
+	if err != nil {
+		return err
+	}
+	if _, err := db.Exec("update workflow set notifications = $1 where id = $2", n, id); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -339,6 +357,10 @@ func Insert(db gorp.SqlExecutor, store cache.Store, w *sdk.Workflow, p *sdk.Proj
 			return sdk.WrapError(err, "Insert> Unable to insert update workflow(%d) join (%#v)", w.ID, j)
 		}
 	}
+
+	if err := updateNotifications(db, w.ID, w.Notifications); err != nil {
+		return sdk.WrapError(err, "Insert> Unable to insert workflow(%d) notifications", w.ID)
+	}
 	return updateLastModified(db, store, w, u)
 }
 
@@ -559,6 +581,13 @@ func IsValid(w *sdk.Workflow, proj *sdk.Project) error {
 		}
 	}
 
+	//Check notifications
+	for _, n := range w.Notifications {
+		if err := n.IsValid(w); err != nil {
+			return err
+		}
+	}
+
 	//Checks application are in the current project
 	apps := w.InvolvedApplications()
 	for _, appID := range apps {
@@ -0,0 +1,43 @@
+package workflow
+
+import (
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+// loadReferenceAnalysis loads the findings of the latest previous run of the same node on the same branch
+func loadReferenceAnalysis(db gorp.SqlExecutor, n *sdk.WorkflowNodeRun) (int64, *sdk.AnalysisReport, error) {
+	id, findings, err := loadReferenceNodeRunColumn(db, n, "findings")
+	if err != nil {
+		return 0, nil, sdk.WrapError(err, "loadReferenceAnalysis>")
+	}
+	if id == 0 {
+		return 0, nil, nil
+	}
+
+	report := new(sdk.AnalysisReport)
+	if err := gorpmapping.JSONNullString(findings, report); err != nil {
+		return 0, nil, sdk.WrapError(err, "loadReferenceAnalysis> Unable to read findings of node run %d", id)
+	}
+	return id, report, nil
+}
+
+// AddNodeRunAnalysis merges an analysis report in the findings of a node run, and flags the findings
+// which were not reported by the latest previous run of the same node on the same branch.
+// The node run is not updated in database.
+func AddNodeRunAnalysis(db gorp.SqlExecutor, n *sdk.WorkflowNodeRun, report sdk.AnalysisReport) error {
+	if n.Findings == nil {
+		n.Findings = &sdk.AnalysisReport{}
+	}
+	n.Findings.Merge(report)
+
+	refID, ref, err := loadReferenceAnalysis(db, n)
+	if err != nil {
+		return sdk.WrapError(err, "AddNodeRunAnalysis>")
+	}
+	n.Findings.ReferenceNodeRunID = refID
+	n.Findings.FlagNew(ref)
+	return nil
+}
@@ -0,0 +1,107 @@
+package workflow
+
+import (
+	"database/sql"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+// CoverageFilter returns the build parameters used to look for the coverage history of a node on a branch
+func CoverageFilter(n *sdk.WorkflowNode, branch string) []sdk.Parameter {
+	params := []sdk.Parameter{}
+	if n.Pipeline.Name != "" {
+		params = append(params, sdk.Parameter{Name: "cds.pipeline", Value: n.Pipeline.Name})
+	}
+	if n.Context != nil && n.Context.Application != nil {
+		params = append(params, sdk.Parameter{Name: "cds.application", Value: n.Context.Application.Name})
+	}
+	if n.Context != nil && n.Context.Environment != nil {
+		params = append(params, sdk.Parameter{Name: "cds.environment", Value: n.Context.Environment.Name})
+	}
+	if branch != "" {
+		params = append(params, sdk.Parameter{Name: tagGitBranch, Value: branch})
+	}
+	return params
+}
+
+// LoadCoverageHistory loads the coverage of the node runs of a workflow, the most recent first.
+// Node runs can be filtered on their build parameters with CoverageFilter.
+func LoadCoverageHistory(db gorp.SqlExecutor, projectKey, workflowName string, filter []sdk.Parameter, limit int) ([]sdk.WorkflowNodeRunCoverage, error) {
+	contains, err := buildParametersContains(filter)
+	if err != nil {
+		return nil, sdk.WrapError(err, "LoadCoverageHistory> Unable to compute filter")
+	}
+
+	query := `select workflow_run.num, workflow_node_run.sub_num, workflow_node_run.id, workflow_node_run.workflow_node_id, workflow_node_run.start, workflow_node_run.coverage
+	from workflow_node_run
+	join workflow_run on workflow_run.id = workflow_node_run.workflow_run_id
+	join project on project.id = workflow_run.project_id
+	join workflow on workflow.id = workflow_run.workflow_id
+	where project.projectkey = $1
+	and workflow.name = $2
+	and workflow_node_run.coverage is not null
+	and workflow_node_run.build_parameters @> $3::jsonb
+	order by workflow_run.num desc, workflow_node_run.sub_num desc, workflow_node_run.id desc
+	limit $4`
+
+	rows, err := db.Query(query, projectKey, workflowName, contains, limit)
+	if err != nil {
+		return nil, sdk.WrapError(err, "LoadCoverageHistory> Unable to load coverage history")
+	}
+	defer rows.Close()
+
+	res := []sdk.WorkflowNodeRunCoverage{}
+	for rows.Next() {
+		var c sdk.WorkflowNodeRunCoverage
+		var cov sql.NullString
+		if err := rows.Scan(&c.WorkflowRunNumber, &c.SubNumber, &c.WorkflowNodeRunID, &c.WorkflowNodeID, &c.Start, &cov); err != nil {
+			return nil, sdk.WrapError(err, "LoadCoverageHistory> Unable to scan coverage")
+		}
+		if err := gorpmapping.JSONNullString(cov, &c.Coverage); err != nil {
+			return nil, sdk.WrapError(err, "LoadCoverageHistory> Unable to read coverage of node run %d", c.WorkflowNodeRunID)
+		}
+		res = append(res, c)
+	}
+	return res, nil
+}
+
+// loadReferenceCoverage loads the coverage of the latest previous run of the same node on the same branch
+func loadReferenceCoverage(db gorp.SqlExecutor, n *sdk.WorkflowNodeRun) (*sdk.WorkflowNodeRunCoverage, error) {
+	id, cov, err := loadReferenceNodeRunColumn(db, n, "coverage")
+	if err != nil {
+		return nil, sdk.WrapError(err, "loadReferenceCoverage>")
+	}
+	if id == 0 {
+		return nil, nil
+	}
+
+	c := sdk.WorkflowNodeRunCoverage{WorkflowNodeRunID: id}
+	if err := gorpmapping.JSONNullString(cov, &c.Coverage); err != nil {
+		return nil, sdk.WrapError(err, "loadReferenceCoverage> Unable to read coverage of node run %d", id)
+	}
+	return &c, nil
+}
+
+// AddNodeRunCoverage merges a coverage report in the coverage of a node run, and computes the line coverage delta
+// with the latest previous run of the same node on the same branch. The node run is not updated in database.
+func AddNodeRunCoverage(db gorp.SqlExecutor, n *sdk.WorkflowNodeRun, report sdk.Coverage) error {
+	if n.Coverage == nil {
+		n.Coverage = &sdk.Coverage{}
+	}
+	n.Coverage.Merge(report)
+
+	ref, err := loadReferenceCoverage(db, n)
+	if err != nil {
+		return sdk.WrapError(err, "AddNodeRunCoverage>")
+	}
+	n.Coverage.Delta = 0
+	n.Coverage.ReferenceNodeRunID = 0
+	if ref != nil {
+		n.Coverage.Delta = n.Coverage.Lines.Percent() - ref.Coverage.Lines.Percent()
+		n.Coverage.ReferenceNodeRunID = ref.WorkflowNodeRunID
+	}
+	return nil
+}
@@ -1,12 +1,15 @@
 package workflow
 
 import (
+	"bytes"
 	"database/sql"
+	"encoding/base64"
 	"fmt"
 
 	"github.com/go-gorp/gorp"
 
 	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/engine/api/secret"
 	"github.com/ovh/cds/engine/api/sessionstore"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
@@ -48,10 +51,19 @@ func insertHook(db gorp.SqlExecutor, node *sdk.WorkflowNode, hook *sdk.WorkflowN
 	}
 	hook.WorkflowHookModelID = hook.WorkflowHookModel.ID
 
+	if hook.Config == nil {
+		hook.Config = sdk.WorkflowNodeHookConfig{}
+	}
+
 	errmu := sdk.MultiError{}
 	// Check configuration of the hook vs the model
-	for k := range hook.WorkflowHookModel.DefaultConfig {
+	for k, v := range hook.WorkflowHookModel.DefaultConfig {
 		if _, ok := hook.Config[k]; !ok {
+			//Optional settings may have been added to the model after the creation of the hook
+			if v.Value == "" && v.Configurable {
+				hook.Config[k] = v
+				continue
+			}
 			errmu = append(errmu, fmt.Errorf("Missing configuration key: %s", k))
 		}
 	}
@@ -81,9 +93,29 @@ func insertHook(db gorp.SqlExecutor, node *sdk.WorkflowNode, hook *sdk.WorkflowN
 	return nil
 }
 
+// secretHookConfigKeys are the configuration keys of hooks which are encrypted in database
+var secretHookConfigKeys = []string{"secret", "password"}
+
 //PostInsert is a db hook
 func (r *NodeHook) PostInsert(db gorp.SqlExecutor) error {
-	sConfig, errgo := gorpmapping.JSONToNullString(r.Config)
+	config := make(sdk.WorkflowNodeHookConfig, len(r.Config))
+	for k, v := range r.Config {
+		config[k] = v
+	}
+	for _, k := range secretHookConfigKeys {
+		v, ok := config[k]
+		if !ok || v.Value == "" {
+			continue
+		}
+		encrypted, err := secret.Encrypt([]byte(v.Value))
+		if err != nil {
+			return sdk.WrapError(err, "PostInsert> Unable to encrypt %s", k)
+		}
+		v.Value = base64.StdEncoding.EncodeToString(encrypted)
+		config[k] = v
+	}
+
+	sConfig, errgo := gorpmapping.JSONToNullString(config)
 	if errgo != nil {
 		return errgo
 	}
@@ -94,21 +126,16 @@ func (r *NodeHook) PostInsert(db gorp.SqlExecutor) error {
 	return nil
 }
 
-//PostGet is a db hook
+//PostGet is a db hook. The secrets are replaced by a placeholder, they are only given in clear to the hooks µService
 func (r *NodeHook) PostGet(db gorp.SqlExecutor) error {
-	var res = struct {
-		Config sql.NullString `db:"config"`
-	}{}
-	if err := db.SelectOne(&res, "select config from workflow_node_hook where id = $1", r.ID); err != nil {
-		return err
-	}
-
-	conf := sdk.WorkflowNodeHookConfig{}
+	return r.postGet(db, false)
+}
 
-	if err := gorpmapping.JSONNullString(res.Config, &conf); err != nil {
+func (r *NodeHook) postGet(db gorp.SqlExecutor, clearSecrets bool) error {
+	conf, err := loadHookConfig(db, r.ID, clearSecrets)
+	if err != nil {
 		return err
 	}
-
 	r.Config = conf
 
 	//Load the model
@@ -122,7 +149,98 @@ func (r *NodeHook) PostGet(db gorp.SqlExecutor) error {
 	return nil
 }
 
-// LoadAllHooks returns all hooks
+// loadHookConfig loads the configuration of a hook, with its secrets in clear or replaced by a placeholder
+func loadHookConfig(db gorp.SqlExecutor, id int64, clearSecrets bool) (sdk.WorkflowNodeHookConfig, error) {
+	var res = struct {
+		Config sql.NullString `db:"config"`
+	}{}
+	if err := db.SelectOne(&res, "select config from workflow_node_hook where id = $1", id); err != nil {
+		return nil, err
+	}
+
+	conf := sdk.WorkflowNodeHookConfig{}
+
+	if err := gorpmapping.JSONNullString(res.Config, &conf); err != nil {
+		return nil, err
+	}
+	for _, k := range secretHookConfigKeys {
+		v, ok := conf[k]
+		if !ok || v.Value == "" {
+			continue
+		}
+		if !clearSecrets {
+			v.Value = sdk.PasswordPlaceholder
+			conf[k] = v
+			continue
+		}
+		clear, err := decryptHookConfigValue(v.Value)
+		if err != nil {
+			return nil, sdk.WrapError(err, "loadHookConfig> Unable to decrypt %s", k)
+		}
+		v.Value = clear
+		conf[k] = v
+	}
+	return conf, nil
+}
+
+// DecryptHookSecrets loads the secrets of the hooks of a workflow in clear
+func DecryptHookSecrets(db gorp.SqlExecutor, w *sdk.Workflow) error {
+	for _, h := range w.GetHooks() {
+		if h.ID == 0 {
+			continue
+		}
+		conf, err := loadHookConfig(db, h.ID, true)
+		if err != nil {
+			return sdk.WrapError(err, "DecryptHookSecrets> Unable to load hook %s", h.UUID)
+		}
+		for _, k := range secretHookConfigKeys {
+			if v, ok := conf[k]; ok {
+				h.Config[k] = v
+			}
+		}
+	}
+	return nil
+}
+
+// RestoreHookSecrets replaces the placeholders sent back by the users with the secrets of the same hooks in the old workflow,
+// whose secrets must have been loaded with DecryptHookSecrets
+func RestoreHookSecrets(w, oldW *sdk.Workflow) {
+	var oldHooks map[string]sdk.WorkflowNodeHook
+	if oldW != nil {
+		oldHooks = oldW.GetHooks()
+	}
+	for uuid, h := range w.GetHooks() {
+		for _, k := range secretHookConfigKeys {
+			v, ok := h.Config[k]
+			if !ok || v.Value != sdk.PasswordPlaceholder {
+				continue
+			}
+			v.Value = ""
+			if old, ok := oldHooks[uuid]; ok && uuid != "" {
+				v.Value = old.Config[k].Value
+			}
+			h.Config[k] = v
+		}
+	}
+}
+
+// decryptHookConfigValue decrypts a configuration value. Values saved before their encryption are returned as is
+func decryptHookConfigValue(v string) (string, error) {
+	encrypted, err := base64.StdEncoding.DecodeString(v)
+	if err != nil {
+		return v, nil
+	}
+	clear, err := secret.Decrypt(encrypted)
+	if err != nil {
+		return "", err
+	}
+	if bytes.Equal(clear, encrypted) {
+		return v, nil
+	}
+	return string(clear), nil
+}
+
+// LoadAllHooks returns all hooks, with their secrets in clear
 func LoadAllHooks(db gorp.SqlExecutor) ([]sdk.WorkflowNodeHook, error) {
 	res := []NodeHook{}
 	if _, err := db.Select(&res, "select id, uuid, workflow_hook_model_id, workflow_node_id from workflow_node_hook"); err != nil {
@@ -134,7 +252,8 @@ func LoadAllHooks(db gorp.SqlExecutor) ([]sdk.WorkflowNodeHook, error) {
 
 	nodes := []sdk.WorkflowNodeHook{}
 	for i := range res {
-		if err := res[i].PostGet(db); err != nil {
+		//The hooks µService needs the secrets of the hooks
+		if err := res[i].postGet(db, true); err != nil {
 			return nil, sdk.WrapError(err, "LoadAllHooks")
 		}
 		nodes = append(nodes, sdk.WorkflowNodeHook(res[i]))
@@ -145,6 +264,30 @@ func LoadAllHooks(db gorp.SqlExecutor) ([]sdk.WorkflowNodeHook, error) {
 	return nodes, nil
 }
 
+// LoadHooksByModelName returns all hooks of a model
+func LoadHooksByModelName(db gorp.SqlExecutor, name string) ([]sdk.WorkflowNodeHook, error) {
+	res := []NodeHook{}
+	query := `select workflow_node_hook.id, workflow_node_hook.uuid, workflow_node_hook.workflow_hook_model_id, workflow_node_hook.workflow_node_id
+		from workflow_node_hook
+		join workflow_hook_model on workflow_hook_model.id = workflow_node_hook.workflow_hook_model_id
+		where workflow_hook_model.name = $1`
+	if _, err := db.Select(&res, query, name); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, sdk.WrapError(err, "LoadHooksByModelName")
+	}
+
+	hooks := []sdk.WorkflowNodeHook{}
+	for i := range res {
+		if err := res[i].PostGet(db); err != nil {
+			return nil, sdk.WrapError(err, "LoadHooksByModelName")
+		}
+		hooks = append(hooks, sdk.WorkflowNodeHook(res[i]))
+	}
+	return hooks, nil
+}
+
 func loadHooks(db gorp.SqlExecutor, node *sdk.WorkflowNode) ([]sdk.WorkflowNodeHook, error) {
 	res := []NodeHook{}
 	if _, err := db.Select(&res, "select id, uuid, workflow_hook_model_id, workflow_node_id from workflow_node_hook where workflow_node_id = $1", node.ID); err != nil {
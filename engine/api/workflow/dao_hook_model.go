@@ -22,6 +22,14 @@ var (
 				Value:        "POST",
 				Configurable: true,
 			},
+			"secret": {
+				Value:        "",
+				Configurable: true,
+			},
+			"allowedIPs": {
+				Value:        "",
+				Configurable: true,
+			},
 		},
 	}
 
@@ -36,6 +44,14 @@ var (
 				Value:        "POST",
 				Configurable: false,
 			},
+			"secret": {
+				Value:        "",
+				Configurable: true,
+			},
+			"allowedIPs": {
+				Value:        "",
+				Configurable: true,
+			},
 		},
 	}
 
@@ -65,11 +81,117 @@ var (
 		},
 	}
 
+	KafkaHookModel = &sdk.WorkflowHookModel{
+		Author:     "CDS",
+		Type:       sdk.WorkflowHookModelBuiltin,
+		Identifier: "github.com/ovh/cds/hook/builtin/kafka",
+		Name:       "Kafka",
+		Icon:       "Linkify",
+		DefaultConfig: sdk.WorkflowNodeHookConfig{
+			"brokers": {
+				Value:        "localhost:9092",
+				Configurable: true,
+			},
+			"topic": {
+				Value:        "",
+				Configurable: true,
+			},
+			"consumerGroup": {
+				Value:        "",
+				Configurable: true,
+			},
+			"username": {
+				Value:        "",
+				Configurable: true,
+			},
+			"password": {
+				Value:        "",
+				Configurable: true,
+			},
+			"filter": {
+				Value:        "",
+				Configurable: true,
+			},
+		},
+	}
+
+	AMQPHookModel = &sdk.WorkflowHookModel{
+		Author:     "CDS",
+		Type:       sdk.WorkflowHookModelBuiltin,
+		Identifier: "github.com/ovh/cds/hook/builtin/amqp",
+		Name:       "AMQP",
+		Icon:       "Linkify",
+		DefaultConfig: sdk.WorkflowNodeHookConfig{
+			"url": {
+				Value:        "amqp://localhost:5672/",
+				Configurable: true,
+			},
+			"username": {
+				Value:        "",
+				Configurable: true,
+			},
+			"password": {
+				Value:        "",
+				Configurable: true,
+			},
+			"queue": {
+				Value:        "",
+				Configurable: true,
+			},
+			"exchange": {
+				Value:        "",
+				Configurable: true,
+			},
+			"bindingKey": {
+				Value:        "",
+				Configurable: true,
+			},
+			"filter": {
+				Value:        "",
+				Configurable: true,
+			},
+		},
+	}
+
+	WorkflowHookModel = &sdk.WorkflowHookModel{
+		Author:      "CDS",
+		Type:        sdk.WorkflowHookModelBuiltin,
+		Identifier:  "github.com/ovh/cds/hook/builtin/workflow",
+		Name:        "Workflow",
+		Description: "Run the workflow when a run of another workflow is done",
+		Icon:        "sitemap",
+		DefaultConfig: sdk.WorkflowNodeHookConfig{
+			"sourceProject": {
+				Value:        "",
+				Configurable: true,
+			},
+			"sourceWorkflow": {
+				Value:        "",
+				Configurable: true,
+			},
+			"sourceNode": {
+				Value:        "",
+				Configurable: true,
+			},
+			"status": {
+				Value:        sdk.StatusSuccess.String(),
+				Configurable: true,
+			},
+			"branch": {
+				Value:        "",
+				Configurable: true,
+			},
+		},
+	}
+
 	builtinModels = []*sdk.WorkflowHookModel{
 		WebHookModel,
 		RepositoryWebHookModel,
 		GitPollerModel,
 		SchedulerModel,
+		KafkaHookModel,
+		AMQPHookModel,
+		WorkflowHookModel,
 	}
 )
 
@@ -130,6 +252,12 @@ func CreateBuiltinWorkflowHookModels(db *gorp.DbMap) error {
 			if err := InsertHookModel(tx, h); err != nil {
 				return sdk.WrapError(err, "CreateBuiltinWorkflowHookModels")
 			}
+			continue
+		}
+
+		//Builtin models may have new configuration keys
+		if err := UpdateHookModel(tx, h); err != nil {
+			return sdk.WrapError(err, "CreateBuiltinWorkflowHookModels")
 		}
 	}
 	return tx.Commit()
@@ -116,6 +116,12 @@ func insertNode(db gorp.SqlExecutor, store cache.Store, w *sdk.Workflow, n *sdk.
 			}
 		}
 
+		if h.WorkflowHookModel.Name == WorkflowHookModel.Name {
+			if err := checkWorkflowHookSource(db, h, u); err != nil {
+				return sdk.WrapError(err, "InsertOrUpdateNode> Invalid workflow hook")
+			}
+		}
+
 		//Insert the hook
 		if err := insertHook(db, n, h); err != nil {
 			return sdk.WrapError(err, "InsertOrUpdateNode> Unable to insert workflow node hook")
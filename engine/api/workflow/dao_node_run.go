@@ -166,6 +166,18 @@ func fromDBNodeRun(rr NodeRun) (*sdk.WorkflowNodeRun, error) {
 			return nil, sdk.WrapError(err, "fromDBNodeRun>Error loading node run %d", r.ID)
 		}
 	}
+	if rr.Coverage.Valid {
+		r.Coverage = new(sdk.Coverage)
+		if err := gorpmapping.JSONNullString(rr.Coverage, r.Coverage); err != nil {
+			return nil, sdk.WrapError(err, "fromDBNodeRun>Error loading node run %d", r.ID)
+		}
+	}
+	if rr.Findings.Valid {
+		r.Findings = new(sdk.AnalysisReport)
+		if err := gorpmapping.JSONNullString(rr.Findings, r.Findings); err != nil {
+			return nil, sdk.WrapError(err, "fromDBNodeRun>Error loading node run %d", r.ID)
+		}
+	}
 
 	return r, nil
 }
@@ -245,6 +257,20 @@ func makeDBNodeRun(n sdk.WorkflowNodeRun) (*NodeRun, error) {
 		}
 		nodeRunDB.Tests = s
 	}
+	if n.Coverage != nil {
+		s, err := gorpmapping.JSONToNullString(n.Coverage)
+		if err != nil {
+			return nil, sdk.WrapError(err, "makeDBNodeRun> unable to get json from coverage")
+		}
+		nodeRunDB.Coverage = s
+	}
+	if n.Findings != nil {
+		s, err := gorpmapping.JSONToNullString(n.Findings)
+		if err != nil {
+			return nil, sdk.WrapError(err, "makeDBNodeRun> unable to get json from findings")
+		}
+		nodeRunDB.Findings = s
+	}
 	if n.Commits != nil {
 		s, err := gorpmapping.JSONToNullString(n.Commits)
 		if err != nil {
@@ -0,0 +1,68 @@
+package workflow
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// referenceParameters are the build parameters identifying a node across the runs of a workflow.
+// Node IDs can't be used since they change each time the workflow is updated.
+var referenceParameters = []string{"cds.pipeline", "cds.application", "cds.environment"}
+
+// nodeRunReferenceFilter returns the build parameters identifying the node and the branch of a node run
+func nodeRunReferenceFilter(n *sdk.WorkflowNodeRun) []sdk.Parameter {
+	params := []sdk.Parameter{}
+	for _, name := range append(referenceParameters, tagGitBranch) {
+		if p := sdk.ParameterFind(n.BuildParameters, name); p != nil && p.Value != "" {
+			params = append(params, sdk.Parameter{Name: name, Value: p.Value})
+		}
+	}
+	return params
+}
+
+// buildParametersContains returns the jsonb value to match node runs build parameters with the @> operator
+func buildParametersContains(params []sdk.Parameter) (string, error) {
+	filter := make([]map[string]string, len(params))
+	for i, p := range params {
+		filter[i] = map[string]string{"name": p.Name, "value": p.Value}
+	}
+	btes, err := json.Marshal(filter)
+	if err != nil {
+		return "", err
+	}
+	return string(btes), nil
+}
+
+// loadReferenceNodeRunColumn loads a column of the latest previous run of the same node on the same branch, with a non null value.
+// It returns a zero ID if there is no such node run.
+func loadReferenceNodeRunColumn(db gorp.SqlExecutor, n *sdk.WorkflowNodeRun, column string) (int64, sql.NullString, error) {
+	var value sql.NullString
+	contains, err := buildParametersContains(nodeRunReferenceFilter(n))
+	if err != nil {
+		return 0, value, sdk.WrapError(err, "loadReferenceNodeRunColumn> Unable to compute filter")
+	}
+
+	query := `select ref.id, ref.` + column + `
+	from workflow_node_run ref
+	join workflow_run ref_run on ref_run.id = ref.workflow_run_id
+	join workflow_run cur_run on cur_run.workflow_id = ref_run.workflow_id
+	where cur_run.id = $1
+	and ref.id < $2
+	and ref.` + column + ` is not null
+	and ref.build_parameters @> $3::jsonb
+	order by ref.id desc
+	limit 1`
+
+	var id int64
+	if err := db.QueryRow(query, n.WorkflowRunID, n.ID, contains).Scan(&id, &value); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, value, nil
+		}
+		return 0, value, sdk.WrapError(err, "loadReferenceNodeRunColumn> Unable to load reference %s of node run %d", column, n.ID)
+	}
+	return id, value, nil
+}
@@ -0,0 +1,93 @@
+package workflow
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/go-gorp/gorp"
+	"github.com/lib/pq"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+// LoadNodeRunsTests loads the test results of the node runs of the last runs of a workflow, the most recent first
+func LoadNodeRunsTests(db gorp.SqlExecutor, projectKey, workflowName string, limit int) ([]sdk.WorkflowNodeRunTests, error) {
+	query := `select workflow_run.num, workflow_node_run.sub_num, workflow_node_run.id, workflow_node_run.start,
+		coalesce((select p->>'value' from jsonb_array_elements(workflow_node_run.build_parameters) p where p->>'name' = $3 limit 1), ''),
+		workflow_node_run.tests
+	from workflow_node_run
+	join workflow_run on workflow_run.id = workflow_node_run.workflow_run_id
+	where workflow_node_run.tests is not null
+	and workflow_run.id in (
+		select workflow_run.id
+		from workflow_run
+		join project on project.id = workflow_run.project_id
+		join workflow on workflow.id = workflow_run.workflow_id
+		where project.projectkey = $1
+		and workflow.name = $2
+		order by workflow_run.num desc
+		limit $4
+	)
+	order by workflow_run.num desc, workflow_node_run.sub_num desc, workflow_node_run.id desc`
+
+	rows, err := db.Query(query, projectKey, workflowName, tagGitHash, limit)
+	if err != nil {
+		return nil, sdk.WrapError(err, "LoadNodeRunsTests> Unable to load tests")
+	}
+	defer rows.Close()
+
+	res := []sdk.WorkflowNodeRunTests{}
+	for rows.Next() {
+		var t sdk.WorkflowNodeRunTests
+		var tests sql.NullString
+		if err := rows.Scan(&t.WorkflowRunNumber, &t.SubNumber, &t.WorkflowNodeRunID, &t.Start, &t.Hash, &tests); err != nil {
+			return nil, sdk.WrapError(err, "LoadNodeRunsTests> Unable to scan tests")
+		}
+		if err := gorpmapping.JSONNullString(tests, &t.Tests); err != nil {
+			return nil, sdk.WrapError(err, "LoadNodeRunsTests> Unable to read tests of node run %d", t.WorkflowNodeRunID)
+		}
+		res = append(res, t)
+	}
+	return res, nil
+}
+
+// LoadTestsQuarantine loads the test cases in quarantine of a workflow
+func LoadTestsQuarantine(db gorp.SqlExecutor, workflowID int64) ([]sdk.WorkflowTestQuarantine, error) {
+	var qs []TestQuarantine
+	if _, err := db.Select(&qs, "SELECT * FROM workflow_test_quarantine WHERE workflow_id = $1 ORDER BY testsuite, testcase", workflowID); err != nil {
+		return nil, sdk.WrapError(err, "LoadTestsQuarantine> Unable to load quarantine of workflow %d", workflowID)
+	}
+
+	res := make([]sdk.WorkflowTestQuarantine, len(qs))
+	for i := range qs {
+		res[i] = sdk.WorkflowTestQuarantine(qs[i])
+	}
+	return res, nil
+}
+
+// InsertTestQuarantine puts a test case of a workflow in quarantine
+func InsertTestQuarantine(db gorp.SqlExecutor, q *sdk.WorkflowTestQuarantine) error {
+	q.Created = time.Now()
+	dbQ := TestQuarantine(*q)
+	if err := db.Insert(&dbQ); err != nil {
+		if errPG, ok := err.(*pq.Error); ok && errPG.Code == "23505" {
+			err = sdk.ErrConflict
+		}
+		return sdk.WrapError(err, "InsertTestQuarantine> Unable to insert quarantine of %s/%s", q.TestSuite, q.TestCase)
+	}
+	q.ID = dbQ.ID
+	return nil
+}
+
+// DeleteTestQuarantine removes a test case of a workflow from quarantine
+func DeleteTestQuarantine(db gorp.SqlExecutor, workflowID, id int64) error {
+	res, err := db.Exec("DELETE FROM workflow_test_quarantine WHERE workflow_id = $1 AND id = $2", workflowID, id)
+	if err != nil {
+		return sdk.WrapError(err, "DeleteTestQuarantine> Unable to delete quarantine %d", id)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sdk.WrapError(sdk.ErrNotFound, "DeleteTestQuarantine> Quarantine %d not found", id)
+	}
+	return nil
+}
@@ -55,6 +55,7 @@ func UpdateNodeJobRunStatus(db gorp.SqlExecutor, store cache.Store, p *sdk.Proje
 		}
 		job.Done = time.Now()
 		job.Status = status.String()
+		expireLogSecrets(store, job.ID)
 
 		wf, errLoadWf := LoadRunByID(db, node.WorkflowRunID, false)
 		if errLoadWf != nil {
@@ -256,7 +257,7 @@ func LoadNodeJobRunKeys(db gorp.SqlExecutor, store cache.Store, job *sdk.Workflo
 func LoadNodeJobRunSecrets(db gorp.SqlExecutor, store cache.Store, job *sdk.WorkflowNodeJobRun, nodeRun *sdk.WorkflowNodeRun, w *sdk.WorkflowRun, pv []sdk.Variable) ([]sdk.Variable, error) {
 	var secrets []sdk.Variable
 
-	pv = sdk.VariablesFilter(pv, sdk.SecretVariable, sdk.KeyVariable)
+	pv = sdk.VariablesFilter(pv, sdk.SecretVariable, sdk.KeyVariable, sdk.VaultVariable)
 	pv = sdk.VariablesPrefix(pv, "cds.proj.")
 	secrets = append(secrets, pv...)
 
@@ -273,7 +274,7 @@ func LoadNodeJobRunSecrets(db gorp.SqlExecutor, store cache.Store, job *sdk.Work
 		if errA != nil {
 			return nil, sdk.WrapError(errA, "LoadNodeJobRunSecrets> Cannot load application variables")
 		}
-		av = sdk.VariablesFilter(appv, sdk.SecretVariable, sdk.KeyVariable)
+		av = sdk.VariablesFilter(appv, sdk.SecretVariable, sdk.KeyVariable, sdk.VaultVariable)
 		av = sdk.VariablesPrefix(av, "cds.app.")
 	}
 	secrets = append(secrets, av...)
@@ -285,7 +286,7 @@ func LoadNodeJobRunSecrets(db gorp.SqlExecutor, store cache.Store, job *sdk.Work
 		if errE != nil {
 			return nil, sdk.WrapError(errE, "LoadNodeJobRunSecrets> Cannot load environment variables")
 		}
-		ev = sdk.VariablesFilter(envv, sdk.SecretVariable, sdk.KeyVariable)
+		ev = sdk.VariablesFilter(envv, sdk.SecretVariable, sdk.KeyVariable, sdk.VaultVariable)
 		ev = sdk.VariablesPrefix(ev, "cds.env.")
 	}
 	secrets = append(secrets, ev...)
@@ -297,6 +298,13 @@ func LoadNodeJobRunSecrets(db gorp.SqlExecutor, store cache.Store, job *sdk.Work
 			return nil, sdk.WrapError(err, "LoadNodeJobRunSecrets> Unable to decrypt variables")
 		}
 	}
+
+	//Resolve vault variables, their values are never stored
+	for i := range secrets {
+		if err := secret.ResolveVaultVariable(w.Workflow.ProjectKey, &secrets[i]); err != nil {
+			return nil, sdk.WrapError(err, "LoadNodeJobRunSecrets> Unable to resolve vault variables")
+		}
+	}
 	return secrets, nil
 }
 
@@ -312,13 +320,16 @@ func BookNodeJobRun(store cache.Store, id int64, hatchery *sdk.Hatchery) (*sdk.H
 	return &h, sdk.WrapError(sdk.ErrJobAlreadyBooked, "BookNodeJobRun> job %d already booked by %s (%d)", id, h.Name, h.ID)
 }
 
-//AddLog adds a build log
-func AddLog(db gorp.SqlExecutor, job *sdk.WorkflowNodeJobRun, logs *sdk.Log) error {
+//AddLog adds a build log, masking the secrets of the job
+func AddLog(db gorp.SqlExecutor, store cache.Store, job *sdk.WorkflowNodeJobRun, logs *sdk.Log) error {
 	if job != nil {
 		logs.PipelineBuildJobID = job.ID
 		logs.PipelineBuildID = job.WorkflowNodeRunID
 	}
 
+	// Worker should have masked secrets, this is a second line of defense
+	logs.Val = sdk.NewLogMasker(loadLogSecrets(store, logs.PipelineBuildJobID)).Mask(logs.Val)
+
 	existingLogs, errLog := LoadStepLogs(db, logs.PipelineBuildJobID, logs.StepOrder)
 	if errLog != nil && errLog != sql.ErrNoRows {
 		return sdk.WrapError(errLog, "AddLog> Cannot load existing logs")
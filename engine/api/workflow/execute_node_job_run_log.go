@@ -2,13 +2,77 @@ package workflow
 
 import (
 	"database/sql"
+	"encoding/json"
+	"strconv"
 	"time"
 
 	"github.com/go-gorp/gorp"
 	"github.com/golang/protobuf/ptypes"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/secret"
 	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+const (
+	// logSecretsTTL is the maximum duration of a job, secrets are kept to mask its logs
+	logSecretsTTL = 6 * 60 * 60
+	// logSecretsTTLAfterEnd lets the last logs be masked once the job is over
+	logSecretsTTLAfterEnd = 5 * 60
 )
 
+func keyLogSecrets(id int64) string {
+	return cache.Key("workflow", "job", "logsecrets", strconv.FormatInt(id, 10))
+}
+
+// SetLogSecrets keeps the secrets sent to the worker of a job run, ciphered, to mask them in the job logs
+func SetLogSecrets(store cache.Store, jobID int64, secrets []sdk.Variable) error {
+	return setLogSecretsWithTTL(store, jobID, secrets, logSecretsTTL)
+}
+
+func setLogSecretsWithTTL(store cache.Store, jobID int64, secrets []sdk.Variable, ttl int) error {
+	btes, err := json.Marshal(secrets)
+	if err != nil {
+		return sdk.WrapError(err, "SetLogSecrets> Unable to marshal secrets")
+	}
+	encrypted, err := secret.Encrypt(btes)
+	if err != nil {
+		return sdk.WrapError(err, "SetLogSecrets> Unable to encrypt secrets")
+	}
+	store.SetWithTTL(keyLogSecrets(jobID), encrypted, ttl)
+	return nil
+}
+
+func loadLogSecrets(store cache.Store, jobID int64) []sdk.Variable {
+	var encrypted []byte
+	if store == nil || !store.Get(keyLogSecrets(jobID), &encrypted) {
+		return nil
+	}
+	btes, err := secret.Decrypt(encrypted)
+	if err != nil {
+		log.Error("loadLogSecrets> Unable to decrypt secrets of job %d: %v", jobID, err)
+		return nil
+	}
+	var secrets []sdk.Variable
+	if err := json.Unmarshal(btes, &secrets); err != nil {
+		log.Error("loadLogSecrets> Unable to unmarshal secrets of job %d: %v", jobID, err)
+		return nil
+	}
+	return secrets
+}
+
+// expireLogSecrets keeps the secrets of a job only a few minutes more, to mask its last logs
+func expireLogSecrets(store cache.Store, jobID int64) {
+	secrets := loadLogSecrets(store, jobID)
+	if secrets == nil {
+		return
+	}
+	if err := setLogSecretsWithTTL(store, jobID, secrets, logSecretsTTLAfterEnd); err != nil {
+		log.Warning("expireLogSecrets> %v", err)
+	}
+}
+
 //LoadStepLogs load logs (workflow_node_run_job_logs) for a job (workflow_node_run_job) for a specific step_order
 func LoadStepLogs(db gorp.SqlExecutor, id int64, order int64) (*sdk.Log, error) {
 	query := `
@@ -48,6 +48,8 @@ type NodeRun struct {
 	PipelineParameters sql.NullString `db:"pipeline_parameters"`
 	BuildParameters    sql.NullString `db:"build_parameters"`
 	Tests              sql.NullString `db:"tests"`
+	Coverage           sql.NullString `db:"coverage"`
+	Findings           sql.NullString `db:"findings"`
 	Commits            sql.NullString `db:"commits"`
 	Stages             sql.NullString `db:"stages"`
 	TriggersRun        sql.NullString `db:"triggers_run"`
@@ -68,6 +70,9 @@ type NodeHook sdk.WorkflowNodeHook
 // NodeHookModel is a gorp wrapper around sdk.WorkflowHookModel
 type NodeHookModel sdk.WorkflowHookModel
 
+// TestQuarantine is a gorp wrapper around sdk.WorkflowTestQuarantine
+type TestQuarantine sdk.WorkflowTestQuarantine
+
 func init() {
 	gorpmapping.Register(gorpmapping.New(Workflow{}, "workflow", true, "id"))
 	gorpmapping.Register(gorpmapping.New(Node{}, "workflow_node", true, "id"))
@@ -83,4 +88,5 @@ func init() {
 	gorpmapping.Register(gorpmapping.New(NodeRunArtifact{}, "workflow_node_run_artifacts", true, "id"))
 	gorpmapping.Register(gorpmapping.New(RunTag{}, "workflow_run_tag", false, "workflow_run_id", "tag"))
 	gorpmapping.Register(gorpmapping.New(NodeHookModel{}, "workflow_hook_model", true, "id"))
+	gorpmapping.Register(gorpmapping.New(TestQuarantine{}, "workflow_test_quarantine", true, "id"))
 }
@@ -3,10 +3,12 @@ package workflow
 import (
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/go-gorp/gorp"
 
 	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/permission"
 	"github.com/ovh/cds/engine/api/repositoriesmanager"
 	"github.com/ovh/cds/engine/api/services"
 	"github.com/ovh/cds/sdk"
@@ -24,6 +26,13 @@ func HookRegistration(db gorp.SqlExecutor, store cache.Store, oldW *sdk.Workflow
 		hookToUpdate = wf.GetHooks()
 	}
 
+	//Workflow hooks are run by CDS API, not by the hooks µService
+	for uuid, h := range hookToUpdate {
+		if h.WorkflowHookModel.Name == WorkflowHookModel.Name {
+			delete(hookToUpdate, uuid)
+		}
+	}
+
 	if len(hookToUpdate) > 0 {
 		//Push the hook to hooks µService
 		dao := services.Querier(db, store)
@@ -142,6 +151,7 @@ func createVCSConfiguration(db gorp.SqlExecutor, store cache.Store, p *sdk.Proje
 		Method:   "POST",
 		URL:      h.Config["webHookURL"].Value,
 		Workflow: true,
+		Secret:   h.Config["secret"].Value,
 	}
 	if err := client.CreateHook(h.Config["repoFullName"].Value, &vcsHook); err != nil {
 		return sdk.WrapError(err, "createVCSConfiguration> Cannot create hook on repository: %+v", vcsHook)
@@ -184,3 +194,78 @@ func diffHook(oldHooks map[string]sdk.WorkflowNodeHook, newHooks map[string]sdk.
 	}
 	return
 }
+
+// WorkflowHookEvent returns the event of a Workflow hook for a node run of its source workflow, or nil if the hook doesn't match it.
+// The node name is empty for the whole workflow run, then nr is the last run of the root node.
+// The payload contains the build parameters and the tags of the source run, prefixed by "parent.".
+func WorkflowHookEvent(h sdk.WorkflowNodeHook, projectKey, workflowName, nodeName, status string, nr sdk.WorkflowNodeRun, tags []sdk.WorkflowRunTag) *sdk.WorkflowNodeRunHookEvent {
+	if !IsWorkflowHookSource(h, projectKey, workflowName, nodeName) {
+		return nil
+	}
+
+	statuses := h.Config["status"].Value
+	if statuses == "" {
+		statuses = sdk.StatusSuccess.String()
+	}
+	var statusMatches bool
+	for _, s := range strings.Split(statuses, ",") {
+		if strings.TrimSpace(s) == status {
+			statusMatches = true
+			break
+		}
+	}
+	if !statusMatches {
+		return nil
+	}
+
+	if branch := h.Config["branch"].Value; branch != "" && sdk.ParameterValue(nr.BuildParameters, tagGitBranch) != branch {
+		return nil
+	}
+
+	payload := map[string]string{
+		"parent.project":  projectKey,
+		"parent.workflow": workflowName,
+		"parent.node":     nodeName,
+		"parent.number":   fmt.Sprintf("%d", nr.Number),
+		"parent.status":   status,
+	}
+	for _, p := range nr.BuildParameters {
+		payload["parent."+p.Name] = p.Value
+	}
+	for _, t := range tags {
+		payload["parent.tag."+t.Tag] = t.Value
+	}
+
+	return &sdk.WorkflowNodeRunHookEvent{
+		WorkflowNodeHookUUID: h.UUID,
+		Payload:              payload,
+	}
+}
+
+// IsWorkflowHookSource returns true if the node, or the workflow if nodeName is empty, is the source of a Workflow hook
+func IsWorkflowHookSource(h sdk.WorkflowNodeHook, projectKey, workflowName, nodeName string) bool {
+	return h.Config["sourceProject"].Value == projectKey && h.Config["sourceWorkflow"].Value == workflowName && h.Config["sourceNode"].Value == nodeName
+}
+
+// checkWorkflowHookSource checks the source workflow of a Workflow hook, which must be readable by the user
+func checkWorkflowHookSource(db gorp.SqlExecutor, h *sdk.WorkflowNodeHook, u *sdk.User) error {
+	key, name := h.Config["sourceProject"].Value, h.Config["sourceWorkflow"].Value
+	if key == "" || name == "" {
+		return sdk.WrapError(sdk.ErrWrongRequest, "checkWorkflowHookSource> Missing source project or workflow")
+	}
+
+	id, err := db.SelectInt(`select workflow.id from workflow
+		join project on project.id = workflow.project_id
+		where project.projectkey = $1 and workflow.name = $2`, key, name)
+	if err != nil {
+		return sdk.WrapError(err, "checkWorkflowHookSource> Unable to load workflow %s/%s", key, name)
+	}
+	if id == 0 {
+		return sdk.WrapError(sdk.ErrWorkflowNotFound, "checkWorkflowHookSource> Unknown workflow %s/%s", key, name)
+	}
+
+	if u != nil && permission.WorkflowPermission(id, u) < permission.PermissionRead {
+		return sdk.WrapError(sdk.ErrForbidden, "checkWorkflowHookSource> Not enough right on workflow %s/%s", key, name)
+	}
+	return nil
+}
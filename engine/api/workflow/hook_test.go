@@ -0,0 +1,99 @@
+package workflow
+
+import (
+	"testing"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkflowHookEvent(t *testing.T) {
+	h := sdk.WorkflowNodeHook{
+		UUID: "uuid",
+		Config: sdk.WorkflowNodeHookConfig{
+			"sourceProject":  sdk.WorkflowNodeHookConfigValue{Value: "PROJ"},
+			"sourceWorkflow": sdk.WorkflowNodeHookConfigValue{Value: "build"},
+			"sourceNode":     sdk.WorkflowNodeHookConfigValue{Value: ""},
+			"status":         sdk.WorkflowNodeHookConfigValue{Value: "Success, Fail"},
+			"branch":         sdk.WorkflowNodeHookConfigValue{Value: "master"},
+		},
+	}
+	nr := sdk.WorkflowNodeRun{
+		Number: 12,
+		BuildParameters: []sdk.Parameter{
+			{Name: "git.branch", Type: sdk.StringParameter, Value: "master"},
+		},
+	}
+	tags := []sdk.WorkflowRunTag{{Tag: "version", Value: "1.0.0"}}
+
+	e := WorkflowHookEvent(h, "PROJ", "build", "", sdk.StatusFail.String(), nr, tags)
+	assert.NotNil(t, e)
+	assert.Equal(t, "uuid", e.WorkflowNodeHookUUID)
+	assert.Equal(t, "12", e.Payload["parent.number"])
+	assert.Equal(t, "Fail", e.Payload["parent.status"])
+	assert.Equal(t, "master", e.Payload["parent.git.branch"])
+	assert.Equal(t, "1.0.0", e.Payload["parent.tag.version"])
+
+	assert.Nil(t, WorkflowHookEvent(h, "PROJ", "build", "", sdk.StatusStopped.String(), nr, tags))
+	assert.Nil(t, WorkflowHookEvent(h, "PROJ", "build", "deploy", sdk.StatusSuccess.String(), nr, tags))
+	assert.Nil(t, WorkflowHookEvent(h, "PROJ", "other", "", sdk.StatusSuccess.String(), nr, tags))
+
+	nr.BuildParameters[0].Value = "feat/foo"
+	assert.Nil(t, WorkflowHookEvent(h, "PROJ", "build", "", sdk.StatusSuccess.String(), nr, tags))
+
+	assert.True(t, IsWorkflowHookSource(h, "PROJ", "build", ""))
+	assert.False(t, IsWorkflowHookSource(h, "OTHER", "build", ""))
+	assert.False(t, IsWorkflowHookSource(h, "PROJ", "build", "deploy"))
+}
+
+func TestRestoreHookSecrets(t *testing.T) {
+	oldW := &sdk.Workflow{
+		Root: &sdk.WorkflowNode{
+			Hooks: []sdk.WorkflowNodeHook{
+				{
+					UUID: "kafka",
+					Config: sdk.WorkflowNodeHookConfig{
+						"topic":    sdk.WorkflowNodeHookConfigValue{Value: "events"},
+						"password": sdk.WorkflowNodeHookConfigValue{Value: "kafka-password"},
+					},
+				},
+				{
+					UUID: "webhook",
+					Config: sdk.WorkflowNodeHookConfig{
+						"secret": sdk.WorkflowNodeHookConfigValue{Value: "webhook-secret"},
+					},
+				},
+			},
+		},
+	}
+
+	w := &sdk.Workflow{
+		Root: &sdk.WorkflowNode{
+			Hooks: []sdk.WorkflowNodeHook{
+				{
+					UUID: "kafka",
+					Config: sdk.WorkflowNodeHookConfig{
+						"topic":    sdk.WorkflowNodeHookConfigValue{Value: "events"},
+						"password": sdk.WorkflowNodeHookConfigValue{Value: sdk.PasswordPlaceholder},
+					},
+				},
+				{
+					UUID: "webhook",
+					Config: sdk.WorkflowNodeHookConfig{
+						"secret": sdk.WorkflowNodeHookConfigValue{Value: "new-secret"},
+					},
+				},
+				{
+					Config: sdk.WorkflowNodeHookConfig{
+						"secret": sdk.WorkflowNodeHookConfigValue{Value: sdk.PasswordPlaceholder},
+					},
+				},
+			},
+		},
+	}
+
+	RestoreHookSecrets(w, oldW)
+	assert.Equal(t, "kafka-password", w.Root.Hooks[0].Config["password"].Value)
+	assert.Equal(t, "new-secret", w.Root.Hooks[1].Config["secret"].Value)
+	assert.Equal(t, "", w.Root.Hooks[2].Config["secret"].Value)
+}
@@ -3,6 +3,7 @@ package workflow
 import (
 	"bytes"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/fsamin/go-dump"
@@ -320,6 +321,7 @@ func processWorkflowNodeRun(db gorp.SqlExecutor, store cache.Store, p *sdk.Proje
 	runPayload := map[string]string{}
 
 	parentStatus := sdk.StatusSuccess.String()
+	var parentCoverage *sdk.Coverage
 	run.SourceNodeRuns = sourceNodeRuns
 	if sourceNodeRuns != nil {
 		//Get all the nodeRun from the sources
@@ -332,6 +334,10 @@ func processWorkflowNodeRun(db gorp.SqlExecutor, store cache.Store, p *sdk.Proje
 						if run.Status == sdk.StatusFail.String() {
 							parentStatus = sdk.StatusFail.String()
 						}
+						//Keep the worst coverage of the parents
+						if run.Coverage != nil && (parentCoverage == nil || run.Coverage.Delta < parentCoverage.Delta) {
+							parentCoverage = run.Coverage
+						}
 					}
 				}
 			}
@@ -405,10 +411,22 @@ func processWorkflowNodeRun(db gorp.SqlExecutor, store cache.Store, p *sdk.Proje
 		Type:  sdk.StringParameter,
 		Value: parentStatus,
 	}
+	cdsParams := []sdk.Parameter{cdsStatusParam}
+	if parentCoverage != nil {
+		cdsParams = append(cdsParams, sdk.Parameter{
+			Name:  "cds.coverage",
+			Type:  sdk.StringParameter,
+			Value: strconv.FormatFloat(parentCoverage.Lines.Percent(), 'f', 2, 64),
+		}, sdk.Parameter{
+			Name:  "cds.coverage.delta",
+			Type:  sdk.StringParameter,
+			Value: strconv.FormatFloat(parentCoverage.Delta, 'f', 2, 64),
+		})
+	}
 	run.BuildParameters = sdk.ParametersFromMap(
 		sdk.ParametersMapMerge(
 			sdk.ParametersToMap(run.BuildParameters),
-			sdk.ParametersToMap([]sdk.Parameter{cdsStatusParam}),
+			sdk.ParametersToMap(cdsParams),
 		),
 	)
 
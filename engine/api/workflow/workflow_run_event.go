@@ -53,9 +53,54 @@ func SendEvent(db gorp.SqlExecutor, wrs []sdk.WorkflowRun, wnrs []sdk.WorkflowNo
 			log.Warning("SendEvent> Cannot load workflow run %d: %s", wnr.WorkflowRunID, errWR)
 			continue
 		}
-		event.PublishWorkflowNodeRun(wnr, *wr, key)
+		var previous *sdk.WorkflowNodeRun
+		if len(wr.Workflow.Notifications) > 0 {
+			previous = previousNodeRun(db, *wr, wnr, key)
+		}
+		event.PublishWorkflowNodeRun(db, wnr, *wr, previous, key)
 	}
 	for _, wnjr := range wnjrs {
-		event.PublishWorkflowNodeJobRun(wnjr)
+		name, errN := db.SelectStr(`select workflow.name from workflow_node_run
+			join workflow_run on workflow_run.id = workflow_node_run.workflow_run_id
+			join workflow on workflow.id = workflow_run.workflow_id
+			where workflow_node_run.id = $1`, wnjr.WorkflowNodeRunID)
+		if errN != nil {
+			log.Warning("SendEvent> Cannot load workflow name of node run %d: %s", wnjr.WorkflowNodeRunID, errN)
+		}
+		event.PublishWorkflowNodeJobRun(wnjr, key, name)
+	}
+}
+
+// previousNodeRun returns the previous execution of the node: the previous subnumber in the same run,
+// else the last execution of the node with the same name in the previous run
+func previousNodeRun(db gorp.SqlExecutor, wr sdk.WorkflowRun, wnr sdk.WorkflowNodeRun, key string) *sdk.WorkflowNodeRun {
+	var previous *sdk.WorkflowNodeRun
+	for i, nr := range wr.WorkflowNodeRuns[wnr.WorkflowNodeID] {
+		if nr.SubNumber < wnr.SubNumber && (previous == nil || nr.SubNumber > previous.SubNumber) {
+			previous = &wr.WorkflowNodeRuns[wnr.WorkflowNodeID][i]
+		}
+	}
+	if previous != nil || wr.Number <= 1 {
+		return previous
+	}
+
+	node := wr.Workflow.GetNode(wnr.WorkflowNodeID)
+	if node == nil {
+		return nil
+	}
+	prev, err := LoadRun(db, key, wr.Workflow.Name, wr.Number-1, false)
+	if err != nil {
+		log.Debug("previousNodeRun> Cannot load previous run %d of workflow %s: %s", wr.Number-1, wr.Workflow.Name, err)
+		return nil
+	}
+	prevNode := prev.Workflow.GetNodeByName(node.Name)
+	if prevNode == nil {
+		return nil
+	}
+	for i, nr := range prev.WorkflowNodeRuns[prevNode.ID] {
+		if previous == nil || nr.SubNumber > previous.SubNumber {
+			previous = &prev.WorkflowNodeRuns[prevNode.ID][i]
+		}
 	}
+	return previous
 }
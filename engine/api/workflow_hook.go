@@ -2,14 +2,20 @@ package api
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 
+	"github.com/go-gorp/gorp"
 	"github.com/gorilla/mux"
+	"github.com/mitchellh/mapstructure"
 
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/permission"
 	"github.com/ovh/cds/engine/api/project"
 	"github.com/ovh/cds/engine/api/repositoriesmanager"
 	"github.com/ovh/cds/engine/api/workflow"
 	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
 )
 
 func (api *API) getWorkflowHooksHandler() Handler {
@@ -19,7 +25,15 @@ func (api *API) getWorkflowHooksHandler() Handler {
 			return sdk.WrapError(err, "getWorkflowHooksHandler")
 		}
 
-		return WriteJSON(w, r, hooks, http.StatusOK)
+		//Workflow hooks are run by CDS API, not by the hooks µService
+		res := make([]sdk.WorkflowNodeHook, 0, len(hooks))
+		for _, h := range hooks {
+			if h.WorkflowHookModel.Name != workflow.WorkflowHookModel.Name {
+				res = append(res, h)
+			}
+		}
+
+		return WriteJSON(w, r, res, http.StatusOK)
 	}
 }
 
@@ -159,3 +173,147 @@ func (api *API) putWorkflowHookModelHandler() Handler {
 		return WriteJSON(w, r, m, http.StatusOK)
 	}
 }
+
+// workflowHookReceiver has to be launched as a goroutine. It runs the workflows having a Workflow hook on the done runs of their source workflow.
+func workflowHookReceiver(c context.Context, DBFunc func() *gorp.DbMap, store cache.Store) {
+	for {
+		e := sdk.Event{}
+		store.DequeueWithContext(c, "events_workflowhooks", &e)
+		if err := c.Err(); err != nil {
+			log.Error("Exiting workflowHookReceiver: %v", err)
+			return
+		}
+
+		db := DBFunc()
+		if db == nil {
+			log.Error("workflowHookReceiver> database not available, event %s skipped", e.ID)
+			continue
+		}
+		if err := processWorkflowHookEvent(db, store, e); err != nil {
+			log.Error("workflowHookReceiver> Unable to process event %s: %v", e.ID, err)
+		}
+	}
+}
+
+// processWorkflowHookEvent runs the workflows having a Workflow hook matching a workflow run or workflow node run event
+func processWorkflowHookEvent(db *gorp.DbMap, store cache.Store, e sdk.Event) error {
+	var src struct {
+		ID               int64
+		WorkflowRunID    int64
+		ProjectKey       string
+		WorkflowName     string
+		WorkflowNodeName string
+		Status           string
+	}
+	if err := mapstructure.Decode(e.Payload, &src); err != nil {
+		return sdk.WrapError(err, "processWorkflowHookEvent> Unable to read event")
+	}
+	if !sdk.StatusIsTerminated(src.Status) {
+		return nil
+	}
+
+	var runID int64
+	switch e.EventType {
+	case fmt.Sprintf("%T", sdk.EventWorkflowRun{}):
+		runID = src.ID
+		src.WorkflowNodeName = ""
+	case fmt.Sprintf("%T", sdk.EventWorkflowNodeRun{}):
+		runID = src.WorkflowRunID
+	default:
+		return nil
+	}
+
+	//Only the hooks on the source of the event are processed
+	all, err := workflow.LoadHooksByModelName(db, workflow.WorkflowHookModel.Name)
+	if err != nil {
+		return sdk.WrapError(err, "processWorkflowHookEvent> Unable to load workflow hooks")
+	}
+	hooks := []sdk.WorkflowNodeHook{}
+	for _, h := range all {
+		if workflow.IsWorkflowHookSource(h, src.ProjectKey, src.WorkflowName, src.WorkflowNodeName) {
+			hooks = append(hooks, h)
+		}
+	}
+	if len(hooks) == 0 {
+		return nil
+	}
+
+	//Load the source node run: the last run of the root node for a workflow run
+	wr, err := workflow.LoadRunByID(db, runID, false)
+	if err != nil {
+		return sdk.WrapError(err, "processWorkflowHookEvent> Unable to load workflow run %d", runID)
+	}
+	var nr *sdk.WorkflowNodeRun
+	for _, nodeRuns := range wr.WorkflowNodeRuns {
+		for i := range nodeRuns {
+			r := &nodeRuns[i]
+			if src.WorkflowNodeName != "" && r.ID == src.ID {
+				nr = r
+			}
+			if src.WorkflowNodeName == "" && r.WorkflowNodeID == wr.Workflow.RootID && (nr == nil || r.SubNumber > nr.SubNumber) {
+				nr = r
+			}
+		}
+	}
+	if nr == nil {
+		return nil
+	}
+
+	for _, h := range hooks {
+		hookEvent := workflow.WorkflowHookEvent(h, src.ProjectKey, src.WorkflowName, src.WorkflowNodeName, src.Status, *nr, wr.Tags)
+		if hookEvent == nil {
+			continue
+		}
+
+		//Events may be sent several times for the same status. The key is removed if the workflow is not run,
+		//so that it can be run by the next event
+		key := cache.Key("workflowhooks", h.UUID, fmt.Sprintf("%d", nr.ID), src.Status)
+		var done bool
+		if store.Get(key, &done) {
+			continue
+		}
+		store.SetWithTTL(key, true, 24*3600)
+
+		if err := runWorkflowHook(db, store, h, wr.WorkflowID, hookEvent); err != nil {
+			store.Delete(key)
+			log.Error("processWorkflowHookEvent> Unable to run workflow hook %s: %v", h.UUID, err)
+		}
+	}
+	return nil
+}
+
+// runWorkflowHook runs the workflow of a Workflow hook, if a group can read the source workflow and execute the workflow
+func runWorkflowHook(db *gorp.DbMap, store cache.Store, h sdk.WorkflowNodeHook, srcWorkflowID int64, e *sdk.WorkflowNodeRunHookEvent) error {
+	key, name := h.Config["project"].Value, h.Config["workflow"].Value
+	u := &sdk.User{Username: "cds.workflow.hook"}
+
+	wf, err := workflow.Load(db, store, key, name, u)
+	if err != nil {
+		return sdk.WrapError(err, "runWorkflowHook> Unable to load workflow %s/%s", key, name)
+	}
+
+	ok, err := permission.WorkflowsGroupAccess(db, srcWorkflowID, permission.PermissionRead, wf.ID, permission.PermissionReadExecute)
+	if err != nil {
+		return sdk.WrapError(err, "runWorkflowHook> Unable to check permissions")
+	}
+	if !ok {
+		return sdk.WrapError(sdk.ErrForbidden, "runWorkflowHook> No group can read the source workflow and execute the workflow %s/%s", key, name)
+	}
+
+	p, err := project.Load(db, store, key, u, project.LoadOptions.WithVariables)
+	if err != nil {
+		return sdk.WrapError(err, "runWorkflowHook> Unable to load project %s", key)
+	}
+
+	chanEvent := make(chan interface{}, 1)
+	chanError := make(chan error, 1)
+
+	go startWorkflowRun(chanEvent, chanError, db, store, p, wf, nil, &sdk.WorkflowRunPostHandlerOption{Hook: e}, u)
+
+	workflowRuns, workflowNodeRuns, workflowNodeJobRuns, err := workflow.GetWorkflowRunEventData(chanError, chanEvent)
+	if err != nil {
+		return err
+	}
+	go workflow.SendEvent(db, workflowRuns, workflowNodeRuns, workflowNodeJobRuns, p.Key)
+	return nil
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/ovh/venom"
 
+	"github.com/ovh/cds/engine/api/actionplugin"
 	"github.com/ovh/cds/engine/api/artifact"
 	"github.com/ovh/cds/engine/api/cache"
 	"github.com/ovh/cds/engine/api/objectstore"
@@ -147,6 +148,10 @@ func (api *API) postTakeWorkflowJobHandler() Handler {
 		pbji.Secrets = append(pbji.Secrets, secretsKeys...)
 		pbji.NodeJobRun.Parameters = append(pbji.NodeJobRun.Parameters, params...)
 
+		if err := workflow.SetLogSecrets(api.Cache, job.ID, pbji.Secrets); err != nil {
+			return sdk.WrapError(err, "postTakeWorkflowJobHandler> Cannot keep secrets to mask logs")
+		}
+
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "postTakeWorkflowJobHandler> Cannot commit transaction")
 		}
@@ -332,7 +337,7 @@ func (api *API) postWorkflowJobLogsHandler() AsynchronousHandler {
 			return sdk.WrapError(err, "postWorkflowJobLogsHandler> Unable to parse body")
 		}
 
-		if err := workflow.AddLog(api.mustDB(), pbJob, &logs); err != nil {
+		if err := workflow.AddLog(api.mustDB(), api.Cache, pbJob, &logs); err != nil {
 			return sdk.WrapError(err, "postWorkflowJobLogsHandler")
 		}
 
@@ -357,6 +362,13 @@ func (api *API) postWorkflowJobStepStatusHandler() Handler {
 			return sdk.WrapError(err, "postWorkflowJobStepStatusHandler> Error while unmarshal job")
 		}
 
+		// A step is building once per run
+		if step.Status == sdk.StatusBuilding.String() {
+			if err := actionplugin.IncStepUsage(api.mustDB(), &nodeJobRun.Job.Action, step.StepOrder); err != nil {
+				log.Warning("postWorkflowJobStepStatusHandler> Unable to count plugin usage: %s", err)
+			}
+		}
+
 		found := false
 		for i := range nodeJobRun.Job.StepStatus {
 			jobStep := &nodeJobRun.Job.StepStatus[i]
@@ -504,6 +516,94 @@ func (api *API) postWorkflowJobTestsResultsHandler() Handler {
 	}
 }
 
+func (api *API) postWorkflowJobCoverageHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		var report sdk.Coverage
+		if err := UnmarshalBody(r, &report); err != nil {
+			return sdk.WrapError(err, "postWorkflowJobCoverageHandler> cannot unmarshal request")
+		}
+
+		id, errI := requestVarInt(r, "permID")
+		if errI != nil {
+			return sdk.WrapError(errI, "postWorkflowJobCoverageHandler> Invalid node job run ID")
+		}
+
+		nodeRunJob, errJobRun := workflow.LoadNodeJobRun(api.mustDB(), api.Cache, id)
+		if errJobRun != nil {
+			return sdk.WrapError(errJobRun, "postWorkflowJobCoverageHandler> Cannot load node run job")
+		}
+
+		tx, errB := api.mustDB().Begin()
+		if errB != nil {
+			return sdk.WrapError(errB, "postWorkflowJobCoverageHandler> Cannot start transaction")
+		}
+		defer tx.Rollback()
+
+		wnjr, err := workflow.LoadAndLockNodeRunByID(tx, nodeRunJob.WorkflowNodeRunID, false)
+		if err != nil {
+			return sdk.WrapError(err, "postWorkflowJobCoverageHandler> Cannot load node job")
+		}
+
+		if err := workflow.AddNodeRunCoverage(tx, wnjr, report); err != nil {
+			return sdk.WrapError(err, "postWorkflowJobCoverageHandler> Cannot compute coverage")
+		}
+
+		if err := workflow.UpdateNodeRun(tx, wnjr); err != nil {
+			return sdk.WrapError(err, "postWorkflowJobCoverageHandler> Cannot update node run")
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WrapError(err, "postWorkflowJobCoverageHandler> Cannot update node run")
+		}
+
+		return WriteJSON(w, r, wnjr.Coverage, http.StatusOK)
+	}
+}
+
+func (api *API) postWorkflowJobFindingsHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		var report sdk.AnalysisReport
+		if err := UnmarshalBody(r, &report); err != nil {
+			return sdk.WrapError(err, "postWorkflowJobFindingsHandler> cannot unmarshal request")
+		}
+
+		id, errI := requestVarInt(r, "permID")
+		if errI != nil {
+			return sdk.WrapError(errI, "postWorkflowJobFindingsHandler> Invalid node job run ID")
+		}
+
+		nodeRunJob, errJobRun := workflow.LoadNodeJobRun(api.mustDB(), api.Cache, id)
+		if errJobRun != nil {
+			return sdk.WrapError(errJobRun, "postWorkflowJobFindingsHandler> Cannot load node run job")
+		}
+
+		tx, errB := api.mustDB().Begin()
+		if errB != nil {
+			return sdk.WrapError(errB, "postWorkflowJobFindingsHandler> Cannot start transaction")
+		}
+		defer tx.Rollback()
+
+		wnjr, err := workflow.LoadAndLockNodeRunByID(tx, nodeRunJob.WorkflowNodeRunID, false)
+		if err != nil {
+			return sdk.WrapError(err, "postWorkflowJobFindingsHandler> Cannot load node job")
+		}
+
+		if err := workflow.AddNodeRunAnalysis(tx, wnjr, report); err != nil {
+			return sdk.WrapError(err, "postWorkflowJobFindingsHandler> Cannot compute findings")
+		}
+
+		if err := workflow.UpdateNodeRun(tx, wnjr); err != nil {
+			return sdk.WrapError(err, "postWorkflowJobFindingsHandler> Cannot update node run")
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WrapError(err, "postWorkflowJobFindingsHandler> Cannot update node run")
+		}
+
+		return WriteJSON(w, r, wnjr.Findings, http.StatusOK)
+	}
+}
+
 func (api *API) postWorkflowJobTagsHandler() Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		id, errr := requestVarInt(r, "permID")
@@ -619,6 +719,50 @@ func (api *API) postWorkflowJobVariableHandler() Handler {
 	}
 }
 
+func (api *API) postWorkflowJobOutputHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		id, errr := requestVarInt(r, "permID")
+		if errr != nil {
+			return sdk.WrapError(errr, "postWorkflowJobOutputHandler> Invalid id")
+		}
+
+		var o sdk.WorkflowNodeOutput
+		if err := UnmarshalBody(r, &o); err != nil {
+			return sdk.WrapError(err, "postWorkflowJobOutputHandler")
+		}
+
+		job, errj := workflow.LoadNodeJobRun(api.mustDB(), api.Cache, id)
+		if errj != nil {
+			return sdk.WrapError(errj, "postWorkflowJobOutputHandler> Unable to load job %d", id)
+		}
+
+		tx, errb := api.mustDB().Begin()
+		if errb != nil {
+			return sdk.WrapError(errb, "postWorkflowJobOutputHandler> Unable to start tx")
+		}
+		defer tx.Rollback()
+
+		node, errn := workflow.LoadAndLockNodeRunByID(tx, job.WorkflowNodeRunID, true)
+		if errn != nil {
+			return sdk.WrapError(errn, "postWorkflowJobOutputHandler> Unable to load node %d", job.WorkflowNodeRunID)
+		}
+
+		if err := sdk.AddNodeOutput(&node.BuildParameters, o); err != nil {
+			return sdk.WrapError(err, "postWorkflowJobOutputHandler> Invalid output %s", o.Name)
+		}
+
+		if err := workflow.UpdateNodeRun(tx, node); err != nil {
+			return sdk.WrapError(err, "postWorkflowJobOutputHandler> Unable to update node run %d", node.ID)
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WrapError(err, "postWorkflowJobOutputHandler> Unable to commit tx")
+		}
+
+		return nil
+	}
+}
+
 func (api *API) postWorkflowJobArtifactHandler() Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		// Load and lock Existing workflow Run Job
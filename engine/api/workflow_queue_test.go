@@ -156,7 +156,7 @@ func testRegisterWorker(t *testing.T, api *API, router *Router, ctx *testRunWork
 		Name:  sdk.RandomString(10),
 		Token: ctx.workerToken,
 	}
-	ctx.worker, err = worker.RegisterWorker(api.mustDB(), params.Name, params.Token, params.ModelID, nil, params.BinaryCapabilities)
+	ctx.worker, err = worker.RegisterWorker(api.mustDB(), params.Name, params.Token, params.ModelID, nil, params.BinaryCapabilities, nil)
 	test.NoError(t, err)
 }
 
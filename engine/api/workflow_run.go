@@ -362,6 +362,48 @@ func (api *API) getWorkflowNodeRunHistoryHandler() Handler {
 	}
 }
 
+func (api *API) getWorkflowCoverageHistoryHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		name := vars["permWorkflowName"]
+
+		limit := defaultLimit
+		if limitS := r.FormValue("limit"); limitS != "" {
+			var errAtoi error
+			limit, errAtoi = strconv.Atoi(limitS)
+			if errAtoi != nil || limit <= 0 {
+				return sdk.ErrWrongRequest
+			}
+		}
+		if limit > rangeMax {
+			return sdk.WrapError(sdk.ErrWrongRequest, "getWorkflowCoverageHistoryHandler> Requested range %d not allowed", limit)
+		}
+
+		branch := r.FormValue("branch")
+		filter := []sdk.Parameter{}
+		if nodeName := r.FormValue("node"); nodeName != "" {
+			wf, errW := workflow.Load(api.mustDB(), api.Cache, key, name, getUser(ctx))
+			if errW != nil {
+				return sdk.WrapError(errW, "getWorkflowCoverageHistoryHandler> Unable to load workflow %s", name)
+			}
+			node := wf.GetNodeByName(nodeName)
+			if node == nil {
+				return sdk.WrapError(sdk.ErrWorkflowNodeNotFound, "getWorkflowCoverageHistoryHandler> Unable to find node %s", nodeName)
+			}
+			filter = workflow.CoverageFilter(node, branch)
+		} else if branch != "" {
+			filter = append(filter, sdk.Parameter{Name: "git.branch", Value: branch})
+		}
+
+		history, err := workflow.LoadCoverageHistory(api.mustDB(), key, name, filter, limit)
+		if err != nil {
+			return sdk.WrapError(err, "getWorkflowCoverageHistoryHandler> Unable to load coverage history")
+		}
+		return WriteJSON(w, r, history, http.StatusOK)
+	}
+}
+
 func (api *API) stopWorkflowNodeRunHandler() Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		vars := mux.Vars(r)
@@ -462,6 +504,39 @@ func (api *API) getWorkflowNodeRunHandler() Handler {
 	}
 }
 
+func (api *API) getWorkflowNodeRunFindingsHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		name := vars["permWorkflowName"]
+		number, err := requestVarInt(r, "number")
+		if err != nil {
+			return err
+		}
+		id, err := requestVarInt(r, "nodeRunID")
+		if err != nil {
+			return err
+		}
+
+		severity := r.FormValue("severity")
+		if severity != "" && sdk.SeverityLevel(severity) < 0 {
+			return sdk.WrapError(sdk.ErrWrongRequest, "getWorkflowNodeRunFindingsHandler> Invalid severity %s", severity)
+		}
+		onlyNew := FormBool(r, "new")
+
+		run, err := workflow.LoadNodeRun(api.mustDB(), key, name, number, id, false)
+		if err != nil {
+			return sdk.WrapError(err, "getWorkflowNodeRunFindingsHandler> Unable to load node run")
+		}
+
+		findings := []sdk.Finding{}
+		if run.Findings != nil {
+			findings = run.Findings.Filter(severity, onlyNew)
+		}
+		return WriteJSON(w, r, findings, http.StatusOK)
+	}
+}
+
 func (api *API) postWorkflowRunHandler() Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		vars := mux.Vars(r)
@@ -0,0 +1,160 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+)
+
+func (api *API) getWorkflowTestsHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		name := vars["permWorkflowName"]
+
+		limit := defaultLimit
+		if limitS := r.FormValue("limit"); limitS != "" {
+			var errAtoi error
+			limit, errAtoi = strconv.Atoi(limitS)
+			if errAtoi != nil || limit <= 0 {
+				return sdk.ErrWrongRequest
+			}
+		}
+		if limit > rangeMax {
+			return sdk.WrapError(sdk.ErrWrongRequest, "getWorkflowTestsHandler> Requested range %d not allowed", limit)
+		}
+
+		wf, errW := workflow.Load(api.mustDB(), api.Cache, key, name, getUser(ctx))
+		if errW != nil {
+			return sdk.WrapError(errW, "getWorkflowTestsHandler> Unable to load workflow %s", name)
+		}
+
+		quarantine, errQ := workflow.LoadTestsQuarantine(api.mustDB(), wf.ID)
+		if errQ != nil {
+			return sdk.WrapError(errQ, "getWorkflowTestsHandler>")
+		}
+
+		nodeRuns, errT := workflow.LoadNodeRunsTests(api.mustDB(), key, name, limit)
+		if errT != nil {
+			return sdk.WrapError(errT, "getWorkflowTestsHandler>")
+		}
+
+		tests := sdk.ComputeTestsHistory(nodeRuns, quarantine)
+		if FormBool(r, "flaky") {
+			flaky := []sdk.WorkflowTestCase{}
+			for _, t := range tests {
+				if t.IsFlaky() {
+					flaky = append(flaky, t)
+				}
+			}
+			tests = flaky
+		}
+
+		return WriteJSON(w, r, tests, http.StatusOK)
+	}
+}
+
+func (api *API) getWorkflowTestsQuarantineHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		name := vars["permWorkflowName"]
+
+		wf, errW := workflow.Load(api.mustDB(), api.Cache, key, name, getUser(ctx))
+		if errW != nil {
+			return sdk.WrapError(errW, "getWorkflowTestsQuarantineHandler> Unable to load workflow %s", name)
+		}
+
+		quarantine, errQ := workflow.LoadTestsQuarantine(api.mustDB(), wf.ID)
+		if errQ != nil {
+			return sdk.WrapError(errQ, "getWorkflowTestsQuarantineHandler>")
+		}
+		return WriteJSON(w, r, quarantine, http.StatusOK)
+	}
+}
+
+func (api *API) postWorkflowTestQuarantineHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		name := vars["permWorkflowName"]
+
+		var q sdk.WorkflowTestQuarantine
+		if err := UnmarshalBody(r, &q); err != nil {
+			return sdk.WrapError(err, "postWorkflowTestQuarantineHandler> cannot unmarshal request")
+		}
+		if q.TestSuite == "" || q.TestCase == "" {
+			return sdk.WrapError(sdk.ErrWrongRequest, "postWorkflowTestQuarantineHandler> testsuite and testcase are mandatory")
+		}
+
+		wf, errW := workflow.Load(api.mustDB(), api.Cache, key, name, getUser(ctx))
+		if errW != nil {
+			return sdk.WrapError(errW, "postWorkflowTestQuarantineHandler> Unable to load workflow %s", name)
+		}
+
+		q.WorkflowID = wf.ID
+		q.Author = getUser(ctx).Username
+		if err := workflow.InsertTestQuarantine(api.mustDB(), &q); err != nil {
+			return sdk.WrapError(err, "postWorkflowTestQuarantineHandler>")
+		}
+		return WriteJSON(w, r, q, http.StatusCreated)
+	}
+}
+
+func (api *API) deleteWorkflowTestQuarantineHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		name := vars["permWorkflowName"]
+
+		id, errI := requestVarInt(r, "id")
+		if errI != nil {
+			return sdk.WrapError(errI, "deleteWorkflowTestQuarantineHandler> Invalid id")
+		}
+
+		wf, errW := workflow.Load(api.mustDB(), api.Cache, key, name, getUser(ctx))
+		if errW != nil {
+			return sdk.WrapError(errW, "deleteWorkflowTestQuarantineHandler> Unable to load workflow %s", name)
+		}
+
+		if err := workflow.DeleteTestQuarantine(api.mustDB(), wf.ID, id); err != nil {
+			return sdk.WrapError(err, "deleteWorkflowTestQuarantineHandler>")
+		}
+		return nil
+	}
+}
+
+func (api *API) getWorkflowJobTestsQuarantineHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		id, errI := requestVarInt(r, "permID")
+		if errI != nil {
+			return sdk.WrapError(errI, "getWorkflowJobTestsQuarantineHandler> Invalid node job run ID")
+		}
+
+		nodeRunJob, errJobRun := workflow.LoadNodeJobRun(api.mustDB(), api.Cache, id)
+		if errJobRun != nil {
+			return sdk.WrapError(errJobRun, "getWorkflowJobTestsQuarantineHandler> Cannot load node run job")
+		}
+
+		nodeRun, errNR := workflow.LoadNodeRunByID(api.mustDB(), nodeRunJob.WorkflowNodeRunID, false)
+		if errNR != nil {
+			return sdk.WrapError(errNR, "getWorkflowJobTestsQuarantineHandler> Cannot load node run")
+		}
+
+		run, errR := workflow.LoadRunByID(api.mustDB(), nodeRun.WorkflowRunID, false)
+		if errR != nil {
+			return sdk.WrapError(errR, "getWorkflowJobTestsQuarantineHandler> Cannot load workflow run")
+		}
+
+		quarantine, errQ := workflow.LoadTestsQuarantine(api.mustDB(), run.WorkflowID)
+		if errQ != nil {
+			return sdk.WrapError(errQ, "getWorkflowJobTestsQuarantineHandler>")
+		}
+		return WriteJSON(w, r, quarantine, http.StatusOK)
+	}
+}
@@ -54,16 +54,16 @@ func (api *API) getWorkflowTriggerConditionHandler() Handler {
 				return sdk.WrapError(errp, "getWorkflowTriggerConditionHandler> Unable to load build parameters")
 			}
 
-			var statusParamFound bool
+			paramsFound := map[string]bool{}
 			for _, p := range params {
-				if p.Name == "cds.status" {
-					statusParamFound = true
-				}
+				paramsFound[p.Name] = true
 				data.ConditionNames = append(data.ConditionNames, p.Name)
 			}
 
-			if !statusParamFound {
-				data.ConditionNames = append(data.ConditionNames, "cds.status")
+			for _, n := range []string{"cds.status", "cds.coverage", "cds.coverage.delta"} {
+				if !paramsFound[n] {
+					data.ConditionNames = append(data.ConditionNames, n)
+				}
 			}
 		}
 
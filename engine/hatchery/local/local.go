@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"runtime"
+	"strconv"
 	"strings"
 	"time"
 
@@ -118,6 +120,10 @@ func (h *HatcheryLocal) CanSpawn(model *sdk.Model, jobID int64, requirements []s
 			return false
 		}
 	}
+	if !model.SatisfyRequirements(requirements) {
+		log.Debug("CanSpawn false model %s does not satisfy requirements of job %d", model.Name, jobID)
+		return false
+	}
 	log.Debug("CanSpawn true for job %d", jobID)
 	return true
 }
@@ -285,6 +291,11 @@ func (h *HatcheryLocal) Init() error {
 	if err != nil {
 		return fmt.Errorf("Cannot check local capabilities: %s", err)
 	}
+	// workers are spawned on the current host
+	capa = append(capa,
+		sdk.Requirement{Name: "os-architecture", Type: sdk.OSArchRequirement, Value: runtime.GOOS + "/" + runtime.GOARCH},
+		sdk.Requirement{Name: "cpu", Type: sdk.CPURequirement, Value: strconv.Itoa(runtime.NumCPU())},
+	)
 
 	h.hatch = &sdk.Hatchery{
 		Name: genname,
@@ -161,6 +161,11 @@ func (h *HatcheryMarathon) CanSpawn(model *sdk.Model, jobID int64, requirements
 		}
 	}
 
+	if !model.SatisfyRequirements(requirements) {
+		log.Debug("CanSpawn> Model %s does not satisfy requirements of job %d", model.Name, jobID)
+		return false
+	}
+
 	deployments, errd := h.marathonClient.Deployments()
 	if errd != nil {
 		log.Info("CanSpawn> Error on h.marathonClient.Deployments() : %s", errd)
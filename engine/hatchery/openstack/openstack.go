@@ -144,7 +144,7 @@ func (h *HatcheryOpenstack) CanSpawn(model *sdk.Model, jobID int64, requirements
 			return false
 		}
 	}
-	return true
+	return model.SatisfyRequirements(requirements)
 }
 
 func (h *HatcheryOpenstack) main() {
@@ -620,6 +620,11 @@ func (*HatcherySwarm) ModelType() string {
 
 // CanSpawn checks if the model can be spawned by this hatchery
 func (h *HatcherySwarm) CanSpawn(model *sdk.Model, jobID int64, requirements []sdk.Requirement) bool {
+	if !model.SatisfyRequirements(requirements) {
+		log.Debug("CanSpawn> Model %s does not satisfy requirements of job %d", model.Name, jobID)
+		return false
+	}
+
 	//List all containers to check if we can spawn a new one
 	cs, errList := h.getContainers()
 	if errList != nil {
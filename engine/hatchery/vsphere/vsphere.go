@@ -87,7 +87,7 @@ func (h *HatcheryVSphere) CanSpawn(model *sdk.Model, jobID int64, requirements [
 			return false
 		}
 	}
-	return true
+	return model.SatisfyRequirements(requirements)
 }
 
 //Client returns cdsclient instance
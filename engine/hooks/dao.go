@@ -1,7 +1,7 @@
 package hooks
 
 import (
-	"fmt"
+	"time"
 
 	"github.com/ovh/cds/engine/api/cache"
 	"github.com/ovh/cds/sdk"
@@ -52,21 +52,69 @@ func (d *dao) DeleteTask(r *Task) {
 
 func (d *dao) SaveTaskExecution(r *TaskExecution) {
 	setKey := cache.Key(executionRootKey, r.Type, r.UUID)
-	execKey := fmt.Sprintf("%d", r.Timestamp)
-	d.store.SetAdd(setKey, execKey, r)
+	d.store.SetAdd(setKey, r.Key(), r)
 }
 
 func (d *dao) DeleteTaskExecution(r *TaskExecution) {
 	setKey := cache.Key(executionRootKey, r.Type, r.UUID)
-	execKey := fmt.Sprintf("%d", r.Timestamp)
-	d.store.SetRemove(setKey, execKey, r)
+	d.store.SetRemove(setKey, r.Key(), r)
+}
+
+func (d *dao) FindTaskExecution(r *TaskExecution) *TaskExecution {
+	k := cache.Key(executionRootKey, r.Type, r.UUID, r.Key())
+	t := &TaskExecution{}
+	if d.store.Get(k, t) {
+		return t
+	}
+	return nil
 }
 
 func (d *dao) EnqueueTaskExecution(r *TaskExecution) {
-	k := cache.Key(executionRootKey, r.Type, r.UUID, fmt.Sprintf("%d", r.Timestamp))
+	k := cache.Key(executionRootKey, r.Type, r.UUID, r.Key())
 	d.store.Enqueue(schedulerQueueKey, k)
 }
 
+func (d *dao) LockTaskExecution(r *TaskExecution, owner string, lease time.Duration) bool {
+	k := cache.Key(executionLocksRootKey, r.Type, r.UUID, r.Key())
+	return d.store.Lock(k, owner, lease)
+}
+
+func (d *dao) ExtendTaskExecutionLock(r *TaskExecution, owner string, lease time.Duration) bool {
+	k := cache.Key(executionLocksRootKey, r.Type, r.UUID, r.Key())
+	return d.store.ExtendLock(k, owner, lease)
+}
+
+func (d *dao) UnlockTaskExecution(r *TaskExecution, owner string) {
+	k := cache.Key(executionLocksRootKey, r.Type, r.UUID, r.Key())
+	d.store.Unlock(k, owner)
+}
+
+func (d *dao) FindAllMembers() ([]Member, error) {
+	nbMembers := d.store.SetCard(membersKey)
+	members := make([]*Member, nbMembers, nbMembers)
+	for i := 0; i < nbMembers; i++ {
+		members[i] = &Member{}
+	}
+	if err := d.store.SetScan(membersKey, sdk.InterfaceSlice(members)...); err != nil {
+		return nil, sdk.WrapError(err, "hooks>FindAllMembers> Unable to scan %s", membersKey)
+	}
+
+	allmembers := make([]Member, nbMembers)
+	for i := 0; i < nbMembers; i++ {
+		allmembers[i] = *members[i]
+	}
+
+	return allmembers, nil
+}
+
+func (d *dao) SaveMember(m *Member) {
+	d.store.SetAdd(membersKey, m.ID, m)
+}
+
+func (d *dao) DeleteMember(id string) {
+	d.store.SetRemove(membersKey, id, nil)
+}
+
 func (d *dao) FindAllTaskExecutions(t *Task) ([]TaskExecution, error) {
 	nbExecutions := d.store.SetCard(cache.Key(executionRootKey, t.Type, t.UUID))
 	execs := make([]*TaskExecution, nbExecutions, nbExecutions)
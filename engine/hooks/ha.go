@@ -0,0 +1,177 @@
+package hooks
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/satori/go.uuid"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/sdk/log"
+)
+
+const defaultLeaseDuration = 30
+
+var (
+	membersKey            = cache.Key("hooks", "members")
+	leaderKey             = cache.Key("hooks", "leader")
+	executionLocksRootKey = cache.Key("hooks", "locks", "executions")
+)
+
+// Member is an instance of the hooks µService. All the instances sharing the same cache are members of the same cluster.
+type Member struct {
+	ID        string    `json:"id"`
+	Hostname  string    `json:"hostname"`
+	Leader    bool      `json:"leader"`
+	StartedAt time.Time `json:"started_at"`
+	LastSeen  time.Time `json:"last_seen"`
+}
+
+// Status is the status of an instance of the hooks µService, with all the members of its cluster
+type Status struct {
+	Version string   `json:"version"`
+	Uptime  string   `json:"uptime"`
+	Member  string   `json:"member"`
+	Leader  bool     `json:"leader"`
+	Cache   string   `json:"cache"`
+	Queue   int      `json:"queue"`
+	Members []Member `json:"members"`
+}
+
+// newMemberID returns an unique identifier for this instance
+func newMemberID() string {
+	hostname, _ := os.Hostname()
+	return fmt.Sprintf("%s/%s", hostname, uuid.NewV4().String())
+}
+
+// leaseDuration returns the duration of the locks held by this instance
+func (s *Service) leaseDuration() time.Duration {
+	if s.Cfg.LeaseDuration <= 0 {
+		return defaultLeaseDuration * time.Second
+	}
+	return time.Duration(s.Cfg.LeaseDuration) * time.Second
+}
+
+// isLeader returns true if this instance is currently the leader of the cluster
+func (s *Service) isLeader() bool {
+	return atomic.LoadInt32(&s.leader) == 1
+}
+
+// runMembership should run as a long-running goroutine. It registers this instance as a member of the cluster
+// and tries to take (or keep) the leadership, until the context is done.
+func (s *Service) runMembership(c context.Context) error {
+	lease := s.leaseDuration()
+	tick := time.NewTicker(lease / 3)
+	defer tick.Stop()
+
+	s.doMembership()
+	for {
+		select {
+		case <-c.Done():
+			s.Cache.Unlock(leaderKey, s.memberID)
+			atomic.StoreInt32(&s.leader, 0)
+			s.Dao.DeleteMember(s.memberID)
+			return c.Err()
+		case <-tick.C:
+			s.doMembership()
+		}
+	}
+}
+
+func (s *Service) doMembership() {
+	lease := s.leaseDuration()
+
+	//Extend the leadership, or take it if there is no leader
+	var leader int32
+	if s.Cache.ExtendLock(leaderKey, s.memberID, lease) || s.Cache.Lock(leaderKey, s.memberID, lease) {
+		leader = 1
+	}
+	if old := atomic.SwapInt32(&s.leader, leader); old != leader {
+		if leader == 1 {
+			log.Info("Hooks> %s is now the leader", s.memberID)
+		} else {
+			log.Info("Hooks> %s is no longer the leader", s.memberID)
+		}
+	}
+
+	hostname, _ := os.Hostname()
+	s.Dao.SaveMember(&Member{
+		ID:        s.memberID,
+		Hostname:  hostname,
+		Leader:    leader == 1,
+		StartedAt: s.startedAt,
+		LastSeen:  time.Now(),
+	})
+
+	//Forget the members which have not been seen for a long time
+	members, err := s.Dao.FindAllMembers()
+	if err != nil {
+		log.Error("Hooks> doMembership> Unable to find all members: %v", err)
+		return
+	}
+	for _, m := range members {
+		if time.Since(m.LastSeen) > 3*lease {
+			log.Info("Hooks> member %s has left", m.ID)
+			s.Dao.DeleteMember(m.ID)
+		}
+	}
+}
+
+// holdTaskExecution locks a task execution, so no other instance processes it, and extends the lock until the
+// returned function is called. It returns false if the execution is locked by another instance.
+func (s *Service) holdTaskExecution(c context.Context, e *TaskExecution) (func(), bool) {
+	lease := s.leaseDuration()
+	exec := *e
+	if !s.Dao.LockTaskExecution(&exec, s.memberID, lease) {
+		return nil, false
+	}
+
+	ctx, cancel := context.WithCancel(c)
+	go func() {
+		tick := time.NewTicker(lease / 3)
+		defer tick.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-tick.C:
+				if !s.Dao.ExtendTaskExecutionLock(&exec, s.memberID, lease) {
+					log.Warning("Hooks> Unable to extend the lock on execution %s of task %s", exec.Key(), exec.UUID)
+				}
+			}
+		}
+	}()
+
+	return func() {
+		cancel()
+		s.Dao.UnlockTaskExecution(&exec, s.memberID)
+	}, true
+}
+
+// reclaimTaskExecution marks as failed an execution which is still DOING whereas its lock has expired: the instance
+// which was processing it has been stopped. Webhooks and scheduled executions will be retried by the scheduler,
+// messages are redelivered by their broker.
+// The lock is taken with a reclaim owner: an execution locked by any instance, including this one, is not reclaimed.
+func (s *Service) reclaimTaskExecution(e *TaskExecution) {
+	owner := s.memberID + "/reclaim"
+	if !s.Dao.LockTaskExecution(e, owner, s.leaseDuration()) {
+		return
+	}
+	defer s.Dao.UnlockTaskExecution(e, owner)
+
+	//The execution may have been processed since it was loaded
+	t := s.Dao.FindTaskExecution(e)
+	if t == nil || t.Status != TaskExecutionDoing {
+		return
+	}
+
+	log.Warning("Hooks> Reclaiming execution %s of task %s", t.Key(), t.UUID)
+	t.Status = TaskExecutionDone
+	t.LastError = "Execution lease expired: the instance processing it has been stopped"
+	t.NbErrors++
+	t.ProcessingTimestamp = time.Now().UnixNano()
+	s.Dao.SaveTaskExecution(t)
+}
@@ -21,6 +21,9 @@ func New() *Service {
 	s.Router = &api.Router{
 		Mux: mux.NewRouter(),
 	}
+	s.consumers = map[string]context.CancelFunc{}
+	s.memberID = newMemberID()
+	s.startedAt = time.Now()
 	return s
 }
 
@@ -81,6 +84,16 @@ func (s *Service) Serve(c context.Context) error {
 	//Init the DAO
 	s.Dao = dao{s.Cache}
 
+	//Consumers of Kafka and AMQP tasks live as long as the service
+	s.consumersCtx = ctx
+
+	//Join the other instances, and try to be their leader
+	go func() {
+		if err := s.runMembership(ctx); err != nil {
+			log.Info("Hooks> %s has left the cluster: %v", s.memberID, err)
+		}
+	}()
+
 	//Start the heartbeat gorourine
 	go func() {
 		if err := s.heartbeat(ctx); err != nil {
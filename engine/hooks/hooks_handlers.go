@@ -4,6 +4,7 @@ import (
 	"context"
 	"io/ioutil"
 	"net/http"
+	"sort"
 	"time"
 
 	"github.com/gorilla/mux"
@@ -38,12 +39,16 @@ func (s *Service) webhookHandler() api.Handler {
 			return sdk.WrapError(err, "Hook> webhookHandler> unable to read request")
 		}
 
+		//Check the caller and the signature
+		errCheck := s.checkWebHookRequest(webHook, r, req)
+		r.Header.Del(GitlabTokenHeader)
+
 		//Prepare a web hook execution
 		exec := &TaskExecution{
 			Timestamp: time.Now().UnixNano(),
 			Type:      webHook.Type,
 			UUID:      webHook.UUID,
-			Config:    webHook.Config,
+			Config:    executionConfig(webHook.Config),
 			WebHook: &WebHookExecution{
 				RequestBody:   req,
 				RequestHeader: r.Header,
@@ -51,6 +56,15 @@ func (s *Service) webhookHandler() api.Handler {
 			},
 		}
 
+		//Keep the rejected call in the execution history, without executing it
+		if errCheck != nil {
+			exec.Status = TaskExecutionRejected
+			exec.LastError = errCheck.Error()
+			exec.ProcessingTimestamp = exec.Timestamp
+			s.Dao.SaveTaskExecution(exec)
+			return sdk.WrapError(sdk.ErrForbidden, "Hook> webhookHandler> call on %s rejected: %v", uuid, errCheck)
+		}
+
 		//Save the web hook execution
 		s.Dao.SaveTaskExecution(exec)
 
@@ -156,8 +170,8 @@ func (s *Service) getTaskExecutionsHandler() api.Handler {
 
 		//Load the task
 		t := s.Dao.FindTask(uuid)
-		if t != nil {
-			return api.WriteJSON(w, r, t, http.StatusOK)
+		if t == nil {
+			return sdk.WrapError(sdk.ErrNotFound, "Hook> getTaskExecutionsHandler> unknown uuid")
 		}
 
 		//Load the executions
@@ -223,9 +237,37 @@ func (s *Service) addTask(ctx context.Context, h *sdk.WorkflowNodeHook) error {
 	//Save the task
 	s.Dao.SaveTask(t)
 
+	//Restart the consumer with the new configuration
+	if t.Type == TypeKafka || t.Type == TypeAMQP {
+		s.stopConsumer(t.UUID)
+	}
+
 	//Start the task
 	if err := s.startTask(ctx, t); err != nil {
 		return sdk.WrapError(err, "Hooks> addTask> Unable start task %+v", t)
 	}
 	return nil
 }
+
+func (s *Service) statusHandler() api.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		members, err := s.Dao.FindAllMembers()
+		if err != nil {
+			return sdk.WrapError(err, "Hook> statusHandler> Unable to find all members")
+		}
+		sort.Slice(members, func(i, j int) bool {
+			return members[i].ID < members[j].ID
+		})
+
+		status := Status{
+			Version: sdk.VERSION,
+			Uptime:  time.Since(s.startedAt).String(),
+			Member:  s.memberID,
+			Leader:  s.isLeader(),
+			Cache:   s.Cache.Status(),
+			Queue:   s.Cache.QueueLen(schedulerQueueKey),
+			Members: members,
+		}
+		return api.WriteJSON(w, r, status, http.StatusOK)
+	}
+}
@@ -14,6 +14,7 @@ func (s *Service) initRouter(ctx context.Context) {
 	r.Middlewares = append(r.Middlewares, s.authMiddleware)
 
 	r.Handle("/mon/version", r.GET(api.VersionHandler, api.Auth(false)))
+	r.Handle("/mon/status", r.GET(s.statusHandler, api.Auth(false)))
 
 	r.Handle("/webhook/{uuid}", r.POST(s.webhookHandler, api.Auth(false)), r.GET(s.webhookHandler, api.Auth(false)), r.DELETE(s.webhookHandler, api.Auth(false)), r.PUT(s.webhookHandler, api.Auth(false)))
 
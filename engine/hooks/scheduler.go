@@ -47,6 +47,10 @@ func (s *Service) retryTaskExecutionsRoutine(c context.Context) error {
 			tick.Stop()
 			return c.Err()
 		case <-tick.C:
+			//Only the leader schedules the executions
+			if !s.isLeader() {
+				continue
+			}
 			tasks, err := s.Dao.FindAllTasks()
 			if err != nil {
 				log.Error("Hooks> retryTaskExecutionsRoutine > Unable to find all tasks: %v", err)
@@ -59,7 +63,16 @@ func (s *Service) retryTaskExecutionsRoutine(c context.Context) error {
 					continue
 				}
 				for _, e := range execs {
+					//An execution is DOING as long as an instance holds its lock
 					if e.Status == TaskExecutionDoing {
+						s.reclaimTaskExecution(&e)
+						continue
+					}
+					if e.Status == TaskExecutionRejected {
+						continue
+					}
+					//Messages are retried by their consumer
+					if e.Kafka != nil || e.AMQP != nil {
 						continue
 					}
 					if e.ProcessingTimestamp == 0 && e.Timestamp <= time.Now().UnixNano() {
@@ -87,6 +100,9 @@ func (s *Service) deleteTaskExecutionsRoutine(c context.Context) error {
 			tick.Stop()
 			return c.Err()
 		case <-tick.C:
+			if !s.isLeader() {
+				continue
+			}
 			tasks, err := s.Dao.FindAllTasks()
 			if err != nil {
 				log.Error("Hooks> deleteTaskExecutionsRoutine > Unable to find all tasks: %v", err)
@@ -128,6 +144,20 @@ func (s *Service) dequeueTaskExecutions(c context.Context) error {
 		if !s.Cache.Get(taskKey, &t) {
 			continue
 		}
+
+		// Lock the task execution, it may be processed by another instance
+		release, ok := s.holdTaskExecution(c, &t)
+		if !ok {
+			log.Debug("Hooks> dequeueTaskExecutions> execution %s of task %s is processed by another instance", t.Key(), t.UUID)
+			continue
+		}
+
+		// Reload the task execution, it may have been processed since it has been enqueued
+		if !s.Cache.Get(taskKey, &t) || !s.isTaskExecutionToProcess(&t) {
+			release()
+			continue
+		}
+
 		t.ProcessingTimestamp = time.Now().UnixNano()
 		t.LastError = ""
 		t.Status = TaskExecutionDoing
@@ -151,6 +181,7 @@ func (s *Service) dequeueTaskExecutions(c context.Context) error {
 		t.Status = TaskExecutionDone
 		t.ProcessingTimestamp = time.Now().UnixNano()
 		s.Dao.SaveTaskExecution(&t)
+		release()
 
 		//Start (or restart) the task
 		s.startTask(c, task)
@@ -158,3 +189,16 @@ func (s *Service) dequeueTaskExecutions(c context.Context) error {
 		continue
 	}
 }
+
+// isTaskExecutionToProcess checks if a dequeued task execution has still to be processed
+func (s *Service) isTaskExecutionToProcess(t *TaskExecution) bool {
+	switch {
+	case t.Status == TaskExecutionRejected, t.Kafka != nil, t.AMQP != nil:
+		return false
+	case t.Status == TaskExecutionDoing, t.ProcessingTimestamp == 0:
+		//Executions which are DOING without lock have been abandoned by a stopped instance
+		return true
+	default:
+		return t.LastError != "" && t.NbErrors < s.Cfg.RetryError
+	}
+}
@@ -0,0 +1,31 @@
+package hooks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_isTaskExecutionToProcess(t *testing.T) {
+	s := &Service{}
+	s.Cfg.RetryError = 3
+
+	tests := []struct {
+		name string
+		exec TaskExecution
+		want bool
+	}{
+		{name: "new", exec: TaskExecution{}, want: true},
+		{name: "done", exec: TaskExecution{Status: TaskExecutionDone, ProcessingTimestamp: 1}, want: false},
+		{name: "in error", exec: TaskExecution{Status: TaskExecutionDone, ProcessingTimestamp: 1, LastError: "error", NbErrors: 1}, want: true},
+		{name: "too many errors", exec: TaskExecution{Status: TaskExecutionDone, ProcessingTimestamp: 1, LastError: "error", NbErrors: 3}, want: false},
+		{name: "abandoned", exec: TaskExecution{Status: TaskExecutionDoing, ProcessingTimestamp: 1}, want: true},
+		{name: "rejected", exec: TaskExecution{Status: TaskExecutionRejected, ProcessingTimestamp: 1, LastError: "forbidden"}, want: false},
+		{name: "message", exec: TaskExecution{Kafka: &KafkaTaskExecution{}}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, s.isTaskExecutionToProcess(&tt.exec))
+		})
+	}
+}
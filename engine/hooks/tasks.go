@@ -25,10 +25,13 @@ const (
 	TypeRepoManagerWebHook = "RepoWebHook"
 	TypeWebHook            = "Webhook"
 	TypeScheduler          = "Scheduler"
+	TypeKafka              = "Kafka"
+	TypeAMQP               = "AMQP"
 
 	GithubHeader    = "X-Github-Event"
 	GitlabHeader    = "X-Gitlab-Event"
 	BitbucketHeader = "X-Event-Key"
+	GiteaHeader     = "X-Gitea-Event"
 )
 
 var (
@@ -136,6 +139,24 @@ func (s *Service) hookToTask(h *sdk.WorkflowNodeHook) (*Task, error) {
 			Type:   TypeScheduler,
 			Config: h.Config,
 		}, nil
+	case workflow.KafkaHookModel.Name:
+		if err := checkConsumerConfig(h.Config, "brokers", "topic", "consumerGroup"); err != nil {
+			return nil, err
+		}
+		return &Task{
+			UUID:   h.UUID,
+			Type:   TypeKafka,
+			Config: h.Config,
+		}, nil
+	case workflow.AMQPHookModel.Name:
+		if err := checkConsumerConfig(h.Config, "url", "queue"); err != nil {
+			return nil, err
+		}
+		return &Task{
+			UUID:   h.UUID,
+			Type:   TypeAMQP,
+			Config: h.Config,
+		}, nil
 	}
 
 	return nil, fmt.Errorf("Unsupported hook: %s", h.WorkflowHookModel.Name)
@@ -171,6 +192,8 @@ func (s *Service) startTask(ctx context.Context, t *Task) error {
 		return nil
 	case TypeScheduler:
 		return s.prepareNextScheduledTaskExecution(t)
+	case TypeKafka, TypeAMQP:
+		return s.startConsumer(t)
 	default:
 		return fmt.Errorf("Unsupported task type %s", t.Type)
 	}
@@ -239,6 +262,10 @@ func (s *Service) stopTask(ctx context.Context, t *Task) error {
 	case TypeWebHook, TypeScheduler, TypeRepoManagerWebHook:
 		log.Debug("Hooks> Tasks %s has been stopped", t.UUID)
 		return nil
+	case TypeKafka, TypeAMQP:
+		s.stopConsumer(t.UUID)
+		log.Debug("Hooks> Tasks %s has been stopped", t.UUID)
+		return nil
 	default:
 		return fmt.Errorf("Unsupported task type %s", t.Type)
 	}
@@ -257,6 +284,10 @@ func (s *Service) doTask(ctx context.Context, t *Task, e *TaskExecution) error {
 		h, err = s.doWebHookExecution(e)
 	case e.ScheduledTask != nil:
 		h, err = s.doScheduledTaskExecution(e)
+	case e.Kafka != nil:
+		h, err = executeMessage(e, e.Kafka.Message)
+	case e.AMQP != nil:
+		h, err = executeMessage(e, e.AMQP.Message)
 	default:
 		err = fmt.Errorf("Unsupported task type %s", e.Type)
 	}
@@ -283,6 +314,33 @@ func (s *Service) doTask(ctx context.Context, t *Task, e *TaskExecution) error {
 	return nil
 }
 
+// executionConfig returns the configuration of a task to save in its executions history, without its secrets
+func executionConfig(config sdk.WorkflowNodeHookConfig) sdk.WorkflowNodeHookConfig {
+	c := make(sdk.WorkflowNodeHookConfig, len(config))
+	for k, v := range config {
+		if k == "password" || k == "secret" {
+			continue
+		}
+		c[k] = v
+	}
+	return c
+}
+
+// checkConsumerConfig checks the mandatory settings and the filter of a Kafka or AMQP hook
+func checkConsumerConfig(config sdk.WorkflowNodeHookConfig, keys ...string) error {
+	for _, k := range keys {
+		if config[k].Value == "" {
+			return fmt.Errorf("Missing configuration value: %s", k)
+		}
+	}
+	if filter := config["filter"].Value; filter != "" {
+		if _, err := filterValues(filter); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (s *Service) doScheduledTaskExecution(t *TaskExecution) (*sdk.WorkflowNodeRunHookEvent, error) {
 	log.Debug("Hooks> Processing scheduled task %s", t.UUID)
 
@@ -316,7 +374,10 @@ func (s *Service) doWebHookExecution(t *TaskExecution) (*sdk.WorkflowNodeRunHook
 }
 
 func getRepositoryHeader(whe *WebHookExecution) string {
-	if v, ok := whe.RequestHeader[GithubHeader]; ok && v[0] == "push" {
+	//Gitea also sends the Github header, with a different payload
+	if v, ok := whe.RequestHeader[GiteaHeader]; ok && v[0] == "push" {
+		return GiteaHeader
+	} else if v, ok := whe.RequestHeader[GithubHeader]; ok && v[0] == "push" {
 		return GithubHeader
 	} else if v, ok := whe.RequestHeader[GitlabHeader]; ok && v[0] == "Push Hook" {
 		return GitlabHeader
@@ -384,6 +445,24 @@ func executeRepositoryWebHook(t *TaskExecution) (*sdk.WorkflowNodeRunHookEvent,
 		payload["git.hash.before"] = pushEvent.Changes[0].FromHash
 		payload["git.hash"] = pushEvent.Changes[0].ToHash
 
+	case GiteaHeader:
+		var pushEvent GiteaPushEvent
+		if err := json.Unmarshal(t.WebHook.RequestBody, &pushEvent); err != nil {
+			return nil, sdk.WrapError(err, "Hook> webhookHandler> unable ro read gitea request: %s", string(t.WebHook.RequestBody))
+		}
+		// Branch deletion
+		if pushEvent.After == "0000000000000000000000000000000000000000" {
+			return nil, nil
+		}
+		payload["git.author"] = pushEvent.Pusher.Login
+		payload["git.branch"] = strings.TrimPrefix(pushEvent.Ref, "refs/heads/")
+		payload["git.hash.before"] = pushEvent.Before
+		payload["git.hash"] = pushEvent.After
+		payload["git.nb.commits"] = len(pushEvent.Commits)
+		payload["git.commits"] = pushEvent.GetCommits()
+		if len(pushEvent.Commits) > 0 {
+			payload["git.message"] = pushEvent.Commits[0].Message
+		}
 	default:
 		values, err := url.ParseQuery(t.WebHook.RequestURL)
 		if err != nil {
@@ -0,0 +1,104 @@
+package hooks
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/streadway/amqp"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// consumeAMQP consumes the messages of the queue of an AMQP task. If an exchange is given, the queue is declared and bound to it.
+// Messages are acknowledged once the workflow has been triggered. A message abandoned by processMessage is rejected, so it is
+// dead-lettered if the queue has a dead letter exchange; it is requeued if the consumer stops while processing it.
+func (s *Service) consumeAMQP(ctx context.Context, t *Task) error {
+	config := amqp.Config{}
+	if user := t.Config["username"].Value; user != "" {
+		config.SASL = []amqp.Authentication{&amqp.PlainAuth{Username: user, Password: t.Config["password"].Value}}
+	}
+	conn, err := amqp.DialConfig(t.Config["url"].Value, config)
+	if err != nil {
+		return sdk.WrapError(err, "Hooks> consumeAMQP> Unable to connect")
+	}
+	defer conn.Close()
+	closed := conn.NotifyClose(make(chan *amqp.Error, 1))
+
+	channel, err := conn.Channel()
+	if err != nil {
+		return sdk.WrapError(err, "Hooks> consumeAMQP> Unable to open channel")
+	}
+
+	queue := t.Config["queue"].Value
+	if exchange := t.Config["exchange"].Value; exchange != "" {
+		if _, err := channel.QueueDeclare(queue, true, false, false, false, nil); err != nil {
+			return sdk.WrapError(err, "Hooks> consumeAMQP> Unable to declare queue %s", queue)
+		}
+		if err := channel.QueueBind(queue, t.Config["bindingKey"].Value, exchange, false, nil); err != nil {
+			return sdk.WrapError(err, "Hooks> consumeAMQP> Unable to bind queue %s to exchange %s", queue, exchange)
+		}
+	}
+	//Messages are processed one by one
+	if err := channel.Qos(1, 0, false); err != nil {
+		return sdk.WrapError(err, "Hooks> consumeAMQP> Unable to set QoS")
+	}
+	deliveries, err := channel.Consume(queue, "", false, false, false, false, nil)
+	if err != nil {
+		return sdk.WrapError(err, "Hooks> consumeAMQP> Unable to consume queue %s", queue)
+	}
+
+	log.Info("Hooks> Consuming AMQP queue %s for task %s", queue, t.UUID)
+	filter := t.Config["filter"].Value
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-closed:
+			return fmt.Errorf("Connection closed: %v", err)
+		case msg, ok := <-deliveries:
+			if !ok {
+				return fmt.Errorf("Consumer of queue %s has been closed", queue)
+			}
+
+			payload, _ := messagePayload(msg.Body)
+			match, err := matchFilter(filter, payload)
+			if err != nil {
+				// the filter will fail again on this message
+				msg.Nack(false, false)
+				return err
+			}
+			if match {
+				exec := &TaskExecution{
+					Timestamp: time.Now().UnixNano(),
+					Type:      t.Type,
+					UUID:      t.UUID,
+					Config:    executionConfig(t.Config),
+					AMQP: &AMQPTaskExecution{
+						Queue:      queue,
+						Exchange:   msg.Exchange,
+						RoutingKey: msg.RoutingKey,
+						MessageID:  msg.MessageId,
+						Message:    msg.Body,
+					},
+				}
+				err := s.processMessage(ctx, t, exec)
+				if err == errMessageAbandoned {
+					if err := msg.Reject(false); err != nil {
+						log.Warning("Hooks> consumeAMQP> Unable to reject message of queue %s: %v", queue, err)
+					}
+					continue
+				}
+				if err != nil {
+					msg.Nack(false, true)
+					return err
+				}
+			}
+
+			if err := msg.Ack(false); err != nil {
+				log.Warning("Hooks> consumeAMQP> Unable to acknowledge message of queue %s: %v", queue, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/fsamin/go-dump"
+	"github.com/pkg/errors"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+const (
+	consumerReconnectDelay = 10 * time.Second
+	consumerMaxRetryDelay  = 5 * time.Minute
+)
+
+// startConsumer starts to consume the messages of a Kafka or AMQP task, if it's not already running
+func (s *Service) startConsumer(t *Task) error {
+	s.consumersMutex.Lock()
+	defer s.consumersMutex.Unlock()
+
+	if _, ok := s.consumers[t.UUID]; ok {
+		return nil
+	}
+	if s.consumersCtx == nil {
+		return fmt.Errorf("Unable to start consumer of task %s: service is not started", t.UUID)
+	}
+
+	ctx, cancel := context.WithCancel(s.consumersCtx)
+	s.consumers[t.UUID] = cancel
+
+	task := *t
+	go func() {
+		for ctx.Err() == nil {
+			var err error
+			switch task.Type {
+			case TypeKafka:
+				err = s.consumeKafka(ctx, &task)
+			case TypeAMQP:
+				err = s.consumeAMQP(ctx, &task)
+			}
+			if err != nil && ctx.Err() == nil {
+				log.Error("Hooks> consumer of task %s stopped: %v", task.UUID, err)
+			}
+
+			//Reconnect later
+			select {
+			case <-ctx.Done():
+			case <-time.After(consumerReconnectDelay):
+			}
+		}
+		log.Debug("Hooks> consumer of task %s has been stopped", task.UUID)
+	}()
+
+	return nil
+}
+
+// stopConsumer stops the consumption of the messages of a task
+func (s *Service) stopConsumer(uuid string) {
+	s.consumersMutex.Lock()
+	defer s.consumersMutex.Unlock()
+
+	if cancel, ok := s.consumers[uuid]; ok {
+		cancel()
+		delete(s.consumers, uuid)
+	}
+}
+
+// errMessageAbandoned is returned by processMessage when a message has not triggered the workflow after all the attempts,
+// or with an error which can't be retried: the message must not be consumed again.
+var errMessageAbandoned = fmt.Errorf("message abandoned")
+
+// processMessage triggers the workflow for a message. It is retried while the number of errors is lower than RetryError:
+// the message must be committed only if processMessage returns no error, rejected if it returns errMessageAbandoned,
+// else it will be consumed again.
+func (s *Service) processMessage(ctx context.Context, t *Task, e *TaskExecution) error {
+	release, ok := s.holdTaskExecution(ctx, e)
+	if !ok {
+		return fmt.Errorf("Unable to lock execution %s of task %s", e.Key(), t.UUID)
+	}
+	defer release()
+
+	for {
+		e.ProcessingTimestamp = time.Now().UnixNano()
+		e.Status = TaskExecutionDoing
+		s.Dao.SaveTaskExecution(e)
+
+		err := s.doTask(ctx, t, e)
+		if err == nil {
+			break
+		}
+
+		log.Error("Hooks> processMessage> task %s failed [%d]: %v", t.UUID, e.NbErrors, err)
+		e.LastError = err.Error()
+		e.NbErrors++
+		if !isRetryableError(err) || e.NbErrors >= s.Cfg.RetryError {
+			log.Error("Hooks> processMessage> message of task %s abandoned after %d errors", t.UUID, e.NbErrors)
+			e.Status = TaskExecutionDone
+			e.ProcessingTimestamp = time.Now().UnixNano()
+			s.Dao.SaveTaskExecution(e)
+			return errMessageAbandoned
+		}
+		s.Dao.SaveTaskExecution(e)
+
+		delay := time.Duration(s.Cfg.RetryDelay) * time.Second << uint(e.NbErrors)
+		if delay <= 0 || delay > consumerMaxRetryDelay {
+			delay = consumerMaxRetryDelay
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+
+	e.Status = TaskExecutionDone
+	e.LastError = ""
+	e.ProcessingTimestamp = time.Now().UnixNano()
+	s.Dao.SaveTaskExecution(e)
+	return nil
+}
+
+// isRetryableError returns false for the errors which will happen again on the same message:
+// invalid message, or call rejected by CDS API (deleted workflow, invalid payload...)
+func isRetryableError(err error) bool {
+	e, ok := errors.Cause(err).(sdk.Error)
+	if !ok {
+		return true
+	}
+	switch {
+	case e.Status == http.StatusRequestTimeout, e.Status == http.StatusTooManyRequests:
+		return true
+	case e.Status >= 400 && e.Status < 500:
+		return false
+	}
+	return true
+}
+
+// executeMessage computes the hook event of a message
+func executeMessage(t *TaskExecution, message []byte) (*sdk.WorkflowNodeRunHookEvent, error) {
+	payload, err := messagePayload(message)
+	if err != nil {
+		return nil, sdk.WrapError(sdk.NewError(sdk.ErrWrongRequest, err), "Hooks> executeMessage> Unable to read message")
+	}
+	return &sdk.WorkflowNodeRunHookEvent{
+		WorkflowNodeHookUUID: t.UUID,
+		Payload:              payload,
+	}, nil
+}
+
+// messagePayload flattens a JSON message. Other messages are given as is in the "message" key.
+func messagePayload(message []byte) (map[string]string, error) {
+	var body interface{}
+	if err := json.Unmarshal(message, &body); err != nil {
+		return map[string]string{"message": string(message)}, nil
+	}
+	if _, ok := body.(map[string]interface{}); !ok {
+		return map[string]string{"message": string(message)}, nil
+	}
+
+	e := dump.NewDefaultEncoder(new(bytes.Buffer))
+	e.Formatters = []dump.KeyFormatterFunc{dump.WithDefaultLowerCaseFormatter()}
+	e.ExtraFields.DetailedMap = false
+	e.ExtraFields.DetailedStruct = false
+	e.ExtraFields.Len = false
+	e.ExtraFields.Type = false
+	return e.ToStringMap(body)
+}
+
+// matchFilter checks that the payload of a message contains all the values of a JSON object
+func matchFilter(filter string, payload map[string]string) (bool, error) {
+	if filter == "" {
+		return true, nil
+	}
+	values, err := filterValues(filter)
+	if err != nil {
+		return false, err
+	}
+	for k, v := range values {
+		if payload[k] != v {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// filterValues flattens a filter, which must be a JSON object
+func filterValues(filter string) (map[string]string, error) {
+	var f map[string]interface{}
+	if err := json.Unmarshal([]byte(filter), &f); err != nil {
+		return nil, fmt.Errorf("Invalid filter %s: it must be a JSON object", filter)
+	}
+	return messagePayload([]byte(filter))
+}
@@ -0,0 +1,82 @@
+package hooks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"gopkg.in/bsm/sarama-cluster.v2"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// consumeKafka consumes the messages of the topic of a Kafka task. Offsets are committed once the workflow has been triggered.
+func (s *Service) consumeKafka(ctx context.Context, t *Task) error {
+	var config = sarama.NewConfig()
+	if user := t.Config["username"].Value; user != "" {
+		config.Net.TLS.Enable = true
+		config.Net.SASL.Enable = true
+		config.Net.SASL.User = user
+		config.Net.SASL.Password = t.Config["password"].Value
+		config.ClientID = user
+	}
+	config.Version = sarama.V0_10_0_1
+
+	clusterConfig := cluster.NewConfig()
+	clusterConfig.Config = *config
+	clusterConfig.Consumer.Return.Errors = true
+
+	topic := t.Config["topic"].Value
+	consumer, err := cluster.NewConsumer(strings.Split(t.Config["brokers"].Value, ","), t.Config["consumerGroup"].Value, []string{topic}, clusterConfig)
+	if err != nil {
+		return sdk.WrapError(err, "Hooks> consumeKafka> Unable to create consumer on topic %s", topic)
+	}
+	defer consumer.Close()
+
+	log.Info("Hooks> Consuming kafka topic %s for task %s", topic, t.UUID)
+	filter := t.Config["filter"].Value
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-consumer.Errors():
+			log.Warning("Hooks> consumeKafka> Error during consumption of topic %s: %v", topic, err)
+		case msg, ok := <-consumer.Messages():
+			if !ok {
+				return fmt.Errorf("Consumer of topic %s has been closed", topic)
+			}
+
+			payload, _ := messagePayload(msg.Value)
+			match, err := matchFilter(filter, payload)
+			if err != nil {
+				return err
+			}
+			if match {
+				exec := &TaskExecution{
+					Timestamp: time.Now().UnixNano(),
+					Type:      t.Type,
+					UUID:      t.UUID,
+					Config:    executionConfig(t.Config),
+					Kafka: &KafkaTaskExecution{
+						Topic:     msg.Topic,
+						Partition: msg.Partition,
+						Offset:    msg.Offset,
+						Message:   msg.Value,
+					},
+				}
+				//An abandoned message is skipped, its execution is kept in error in the history
+				if err := s.processMessage(ctx, t, exec); err != nil && err != errMessageAbandoned {
+					return err
+				}
+			}
+
+			consumer.MarkOffset(msg, "")
+			if err := consumer.CommitOffsets(); err != nil {
+				log.Warning("Hooks> consumeKafka> Unable to commit offset %d of topic %s: %v", msg.Offset, topic, err)
+			}
+		}
+	}
+}
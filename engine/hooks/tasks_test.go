@@ -1,6 +1,9 @@
 package hooks
 
 import (
+	"fmt"
+	"net/http"
+
 	"github.com/ovh/cds/engine/api/test"
 	"github.com/ovh/cds/sdk"
 	"github.com/stretchr/testify/assert"
@@ -92,6 +95,77 @@ func Test_doWebHookExecutionBitbucket(t *testing.T) {
 	assert.Equal(t, "9f4fac7ec5642099982a86f584f2c4a362adb670", h.Payload["git.hash"])
 }
 
+func Test_doWebHookExecutionGitea(t *testing.T) {
+	s := Service{}
+	task := &TaskExecution{
+		UUID: sdk.RandomString(10),
+		Type: TypeRepoManagerWebHook,
+		WebHook: &WebHookExecution{
+			RequestBody: []byte(giteaPushEvent),
+			RequestHeader: map[string][]string{
+				GiteaHeader:  {"push"},
+				GithubHeader: {"push"},
+			},
+			RequestURL: "",
+		},
+	}
+	h, err := s.doWebHookExecution(task)
+	test.NoError(t, err)
+
+	assert.Equal(t, "develop", h.Payload["git.branch"])
+	assert.Equal(t, "gitea", h.Payload["git.author"])
+	assert.Equal(t, "bump version", h.Payload["git.message"])
+	assert.Equal(t, "bffeb74224043ba2feb48d137756c8a9331c449a", h.Payload["git.hash"])
+	assert.Equal(t, "1", h.Payload["git.nb.commits"])
+}
+
+func Test_executeMessage(t *testing.T) {
+	task := &TaskExecution{
+		UUID: sdk.RandomString(10),
+		Type: TypeKafka,
+		Kafka: &KafkaTaskExecution{
+			Topic:   "deployments",
+			Message: []byte(`{"application": {"name": "my-app"}, "version": "1.2.0", "env": "prod"}`),
+		},
+	}
+	h, err := executeMessage(task, task.Kafka.Message)
+	test.NoError(t, err)
+	assert.Equal(t, task.UUID, h.WorkflowNodeHookUUID)
+	assert.Equal(t, "my-app", h.Payload["application.name"])
+	assert.Equal(t, "1.2.0", h.Payload["version"])
+
+	match, err := matchFilter(`{"env": "prod", "application": {"name": "my-app"}}`, h.Payload)
+	test.NoError(t, err)
+	assert.True(t, match)
+
+	match, err = matchFilter(`{"env": "dev"}`, h.Payload)
+	test.NoError(t, err)
+	assert.False(t, match)
+
+	_, err = matchFilter(`env=dev`, h.Payload)
+	assert.Error(t, err)
+
+	h, err = executeMessage(task, []byte("deploy my-app"))
+	test.NoError(t, err)
+	assert.Equal(t, "deploy my-app", h.Payload["message"])
+}
+
+func Test_checkConsumerConfig(t *testing.T) {
+	config := sdk.WorkflowNodeHookConfig{
+		"brokers":       {Value: "localhost:9092"},
+		"topic":         {Value: "deployments"},
+		"consumerGroup": {Value: ""},
+		"filter":        {Value: `{"env": "prod"}`},
+	}
+	assert.Error(t, checkConsumerConfig(config, "brokers", "topic", "consumerGroup"))
+
+	config["consumerGroup"] = sdk.WorkflowNodeHookConfigValue{Value: "cds"}
+	test.NoError(t, checkConsumerConfig(config, "brokers", "topic", "consumerGroup"))
+
+	config["filter"] = sdk.WorkflowNodeHookConfigValue{Value: `["prod"]`}
+	assert.Error(t, checkConsumerConfig(config, "brokers", "topic", "consumerGroup"))
+}
+
 var bitbucketPushEvent = `
 	{
     "eventKey": "repo:refs_changed",
@@ -382,3 +456,89 @@ var githubPushEvent = `
   }
 }
 `
+
+var giteaPushEvent = `
+{
+  "secret": "",
+  "ref": "refs/heads/develop",
+  "before": "28e1879d029cb852e4844d9c718537df08844e03",
+  "after": "bffeb74224043ba2feb48d137756c8a9331c449a",
+  "compare_url": "http://localhost:3000/gitea/webhooks/compare/28e1879d029cb852e4844d9c718537df08844e03...bffeb74224043ba2feb48d137756c8a9331c449a",
+  "commits": [
+    {
+      "id": "bffeb74224043ba2feb48d137756c8a9331c449a",
+      "message": "bump version",
+      "url": "http://localhost:3000/gitea/webhooks/commit/bffeb74224043ba2feb48d137756c8a9331c449a",
+      "author": {
+        "name": "Gitea",
+        "email": "someone@gitea.io",
+        "username": "gitea"
+      },
+      "committer": {
+        "name": "Gitea",
+        "email": "someone@gitea.io",
+        "username": "gitea"
+      },
+      "timestamp": "2017-03-13T13:52:11-04:00"
+    }
+  ],
+  "repository": {
+    "id": 140,
+    "owner": {
+      "id": 1,
+      "login": "gitea",
+      "full_name": "Gitea",
+      "email": "someone@gitea.io",
+      "avatar_url": "https://localhost:3000/avatars/1",
+      "username": "gitea"
+    },
+    "name": "webhooks",
+    "full_name": "gitea/webhooks",
+    "html_url": "http://localhost:3000/gitea/webhooks",
+    "ssh_url": "ssh://gitea@localhost:2222/gitea/webhooks.git",
+    "clone_url": "http://localhost:3000/gitea/webhooks.git",
+    "default_branch": "master"
+  },
+  "pusher": {
+    "id": 1,
+    "login": "gitea",
+    "full_name": "Gitea",
+    "email": "someone@gitea.io",
+    "avatar_url": "https://localhost:3000/avatars/1",
+    "username": "gitea"
+  },
+  "sender": {
+    "id": 1,
+    "login": "gitea",
+    "full_name": "Gitea",
+    "email": "someone@gitea.io",
+    "avatar_url": "https://localhost:3000/avatars/1",
+    "username": "gitea"
+  }
+}
+`
+
+func Test_isRetryableError(t *testing.T) {
+	assert.True(t, isRetryableError(fmt.Errorf("connection refused")))
+	assert.True(t, isRetryableError(sdk.WrapError(sdk.Error{Message: "internal", Status: http.StatusInternalServerError}, "Hooks> Unable to run workflow")))
+	assert.True(t, isRetryableError(sdk.Error{Message: "slow down", Status: http.StatusTooManyRequests}))
+	assert.False(t, isRetryableError(sdk.WrapError(sdk.Error{Message: "workflow does not exist", Status: http.StatusNotFound}, "Hooks> Unable to run workflow")))
+	assert.False(t, isRetryableError(sdk.NewError(sdk.ErrWrongRequest, fmt.Errorf("invalid message"))))
+}
+
+func Test_executionConfig(t *testing.T) {
+	config := sdk.WorkflowNodeHookConfig{
+		"queue":    sdk.WorkflowNodeHookConfigValue{Value: "builds"},
+		"username": sdk.WorkflowNodeHookConfigValue{Value: "cds"},
+		"password": sdk.WorkflowNodeHookConfigValue{Value: "s3cr3t"},
+		"secret":   sdk.WorkflowNodeHookConfigValue{Value: "s3cr3t"},
+	}
+	c := executionConfig(config)
+	assert.Equal(t, "builds", c["queue"].Value)
+	assert.Equal(t, "cds", c["username"].Value)
+	_, ok := c["password"]
+	assert.False(t, ok)
+	_, ok = c["secret"]
+	assert.False(t, ok)
+	assert.Equal(t, "s3cr3t", config["password"].Value)
+}
@@ -0,0 +1,62 @@
+package hooks
+
+import (
+	"time"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// GiteaPushEvent represents payload send by gitea (or gogs) on a push event
+type GiteaPushEvent struct {
+	Ref        string `json:"ref"`
+	Before     string `json:"before"`
+	After      string `json:"after"`
+	CompareURL string `json:"compare_url"`
+	Commits    []struct {
+		ID      string `json:"id"`
+		Message string `json:"message"`
+		URL     string `json:"url"`
+		Author  struct {
+			Name     string `json:"name"`
+			Email    string `json:"email"`
+			Username string `json:"username"`
+		} `json:"author"`
+		Timestamp time.Time `json:"timestamp"`
+	} `json:"commits"`
+	Repository struct {
+		ID       int64  `json:"id"`
+		Name     string `json:"name"`
+		FullName string `json:"full_name"`
+		HTMLURL  string `json:"html_url"`
+		CloneURL string `json:"clone_url"`
+		SSHURL   string `json:"ssh_url"`
+	} `json:"repository"`
+	Pusher struct {
+		ID        int64  `json:"id"`
+		Login     string `json:"login"`
+		Username  string `json:"username"`
+		FullName  string `json:"full_name"`
+		Email     string `json:"email"`
+		AvatarURL string `json:"avatar_url"`
+	} `json:"pusher"`
+}
+
+// GetCommits returns the commits of the push event
+func (g *GiteaPushEvent) GetCommits() []sdk.VCSCommit {
+	commits := []sdk.VCSCommit{}
+	for _, c := range g.Commits {
+		commit := sdk.VCSCommit{
+			Hash: c.ID,
+			Author: sdk.VCSAuthor{
+				Name:        c.Author.Username,
+				DisplayName: c.Author.Name,
+				Email:       c.Author.Email,
+			},
+			Message:   c.Message,
+			URL:       c.URL,
+			Timestamp: c.Timestamp.Unix(),
+		}
+		commits = append(commits, commit)
+	}
+	return commits
+}
@@ -1,6 +1,11 @@
 package hooks
 
 import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
 	"github.com/ovh/cds/engine/api"
 	"github.com/ovh/cds/engine/api/cache"
 	"github.com/ovh/cds/sdk"
@@ -8,8 +13,9 @@ import (
 )
 
 const (
-	TaskExecutionDoing = "DOING"
-	TaskExecutionDone  = "DONE"
+	TaskExecutionDoing    = "DOING"
+	TaskExecutionDone     = "DONE"
+	TaskExecutionRejected = "REJECTED"
 )
 
 // Service is the stuct representing a hooks µService
@@ -20,6 +26,16 @@ type Service struct {
 	cds    cdsclient.Interface
 	Dao    dao
 	hash   string
+
+	//Kafka and AMQP tasks consume messages in goroutines, which are stopped with the tasks
+	consumersCtx   context.Context
+	consumers      map[string]context.CancelFunc
+	consumersMutex sync.Mutex
+
+	//Instances sharing the same cache elect a leader to run the scheduling routines
+	memberID  string
+	startedAt time.Time
+	leader    int32
 }
 
 // Configuration is the hooks configuration structure
@@ -34,6 +50,7 @@ type Configuration struct {
 	RetryDelay       int64  `toml:"retryDelay" default:"1" comment:"Execution retry delay in seconds"`
 	RetryError       int64  `toml:"retryError" default:"3" comment:"Retry execution while this number of error is not reached"`
 	ExecutionHistory int    `toml:"executionHistory" default:"10" comment:"Number of execution to keep"`
+	LeaseDuration    int    `toml:"leaseDuration" default:"30" comment:"Duration in seconds of the locks held by an instance on the leadership and on the task executions it processes. If an instance stops, another one takes over after this delay"`
 	API              struct {
 		HTTP struct {
 			URL      string `toml:"url" default:"http://localhost:8081"`
@@ -47,6 +64,10 @@ type Configuration struct {
 		RequestTimeout       int    `toml:"requestTimeout" default:"10"`
 		MaxHeartbeatFailures int    `toml:"maxHeartbeatFailures" default:"10"`
 	} `toml:"api" comment:"######################\n CDS API Settings \n######################`
+	Webhooks struct {
+		ReplayWindow int  `toml:"replayWindow" default:"300" comment:"Webhook calls signed with X-Cds-Hook-Signature are rejected if they are older than this number of seconds, and authenticated webhook calls if the same call has already been received during this delay"`
+		TrustProxy   bool `toml:"trustProxy" commented:"true" comment:"Read the caller IP from the X-Forwarded-For header set by your reverse proxy to check the allowed IPs of the webhooks"`
+	} `toml:"webhooks" comment:"######################\n CDS Hooks Webhooks Settings \n######################"`
 	Cache struct {
 		TTL   int `toml:"ttl" default:"60"`
 		Redis struct {
@@ -76,9 +97,16 @@ type TaskExecution struct {
 	Config              sdk.WorkflowNodeHookConfig
 	WebHook             *WebHookExecution
 	ScheduledTask       *ScheduledTaskExecution
+	Kafka               *KafkaTaskExecution
+	AMQP                *AMQPTaskExecution
 	Status              string
 }
 
+// Key returns the key of the execution in the executions of its task
+func (e *TaskExecution) Key() string {
+	return fmt.Sprintf("%d", e.Timestamp)
+}
+
 // WebHookExecution contains specific data for a webhook execution
 type WebHookExecution struct {
 	RequestURL    string
@@ -90,3 +118,20 @@ type WebHookExecution struct {
 type ScheduledTaskExecution struct {
 	DateScheduledExecution string
 }
+
+// KafkaTaskExecution contains specific data for a kafka message
+type KafkaTaskExecution struct {
+	Topic     string
+	Partition int32
+	Offset    int64
+	Message   []byte
+}
+
+// AMQPTaskExecution contains specific data for an AMQP message
+type AMQPTaskExecution struct {
+	Queue      string
+	Exchange   string
+	RoutingKey string
+	MessageID  string
+	Message    []byte
+}
@@ -0,0 +1,194 @@
+package hooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ovh/cds/engine/api/cache"
+)
+
+//This are the headers used to authenticate webhook calls
+const (
+	HubSignatureHeader    = "X-Hub-Signature"
+	HubSignature256Header = "X-Hub-Signature-256"
+	GitlabTokenHeader     = "X-Gitlab-Token"
+	GiteaSignatureHeader  = "X-Gitea-Signature"
+	HookSignatureHeader   = "X-Cds-Hook-Signature"
+	HookTimestampHeader   = "X-Cds-Hook-Timestamp"
+)
+
+const defaultReplayWindow = 300
+
+var deliveriesRootKey = cache.Key("hooks", "deliveries")
+
+// checkWebHookRequest checks the caller IP against the allowed IPs of the task, and the signature of the request if the task has a secret
+func (s *Service) checkWebHookRequest(t *Task, r *http.Request, body []byte) error {
+	if allowed := t.Config["allowedIPs"].Value; allowed != "" {
+		ip := s.remoteIP(r)
+		ok, err := isIPAllowed(ip, allowed)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("IP %s is not allowed", ip)
+		}
+	}
+
+	secret := t.Config["secret"].Value
+	if secret == "" {
+		return nil
+	}
+
+	//Configuration files written before the replay window was introduced don't have it
+	replayWindow := s.Cfg.Webhooks.ReplayWindow
+	if replayWindow <= 0 {
+		replayWindow = defaultReplayWindow
+	}
+	window := time.Duration(replayWindow) * time.Second
+	if err := verifyWebHookSignature(secret, r.Header, body, time.Now(), window); err != nil {
+		return err
+	}
+
+	//A signed call can be sent only once during the replay window
+	deliveryKey := webHookDeliveryKey(r.Header, body)
+	key := cache.Key(deliveriesRootKey, t.UUID, deliveryKey)
+	var received bool
+	if s.Cache.Get(key, &received) {
+		return fmt.Errorf("Delivery %s has already been received", deliveryKey)
+	}
+	s.Cache.SetWithTTL(key, true, replayWindow)
+	return nil
+}
+
+// remoteIP returns the IP of the caller, from the last proxy if the service is behind a trusted reverse proxy
+func (s *Service) remoteIP(r *http.Request) string {
+	if s.Cfg.Webhooks.TrustProxy {
+		forwarded := strings.Split(r.Header.Get("X-Forwarded-For"), ",")
+		if ip := strings.TrimSpace(forwarded[len(forwarded)-1]); ip != "" {
+			return ip
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// isIPAllowed checks an IP against a comma separated list of IPs and CIDRs
+func isIPAllowed(ip, allowed string) (bool, error) {
+	remote := net.ParseIP(ip)
+	if remote == nil {
+		return false, fmt.Errorf("Invalid IP %s", ip)
+	}
+	for _, a := range strings.Split(allowed, ",") {
+		a = strings.TrimSpace(a)
+		if a == "" {
+			continue
+		}
+		if strings.Contains(a, "/") {
+			_, ipnet, err := net.ParseCIDR(a)
+			if err != nil {
+				return false, fmt.Errorf("Invalid allowed IP range %s", a)
+			}
+			if ipnet.Contains(remote) {
+				return true, nil
+			}
+			continue
+		}
+		allowedIP := net.ParseIP(a)
+		if allowedIP == nil {
+			return false, fmt.Errorf("Invalid allowed IP %s", a)
+		}
+		if allowedIP.Equal(remote) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// verifyWebHookSignature checks the signature of a webhook call:
+// - CDS: X-Cds-Hook-Signature is sha256=HMAC-SHA256(secret, timestamp.body) with the unix timestamp sent in X-Cds-Hook-Timestamp
+// - Github and Bitbucket Server: X-Hub-Signature-256 or X-Hub-Signature is sha256= or sha1= HMAC(secret, body)
+// - Gitlab: X-Gitlab-Token is the secret
+func verifyWebHookSignature(secret string, header http.Header, body []byte, now time.Time, window time.Duration) error {
+	if sig := header.Get(HookSignatureHeader); sig != "" {
+		ts, err := strconv.ParseInt(header.Get(HookTimestampHeader), 10, 64)
+		if err != nil {
+			return fmt.Errorf("Invalid %s header", HookTimestampHeader)
+		}
+		if d := now.Sub(time.Unix(ts, 0)); d > window || d < -window {
+			return fmt.Errorf("Signature timestamp is outside of the replay window")
+		}
+		return checkHMAC(secret, sig, append([]byte(fmt.Sprintf("%d.", ts)), body...))
+	}
+
+	if sig := header.Get(HubSignature256Header); sig != "" {
+		return checkHMAC(secret, sig, body)
+	}
+	if sig := header.Get(HubSignatureHeader); sig != "" {
+		return checkHMAC(secret, sig, body)
+	}
+	//Gitea sends the hexadecimal HMAC-SHA256 without the algorithm
+	if sig := header.Get(GiteaSignatureHeader); sig != "" {
+		return checkHMAC(secret, "sha256="+sig, body)
+	}
+
+	if token := header.Get(GitlabTokenHeader); token != "" {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(secret)) != 1 {
+			return fmt.Errorf("Invalid %s header", GitlabTokenHeader)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("Missing signature")
+}
+
+// checkHMAC checks a signature formatted as <algorithm>=<hexadecimal HMAC>
+func checkHMAC(secret, signature string, content []byte) error {
+	tuple := strings.SplitN(signature, "=", 2)
+	if len(tuple) != 2 {
+		return fmt.Errorf("Invalid signature format")
+	}
+
+	var h func() hash.Hash
+	switch tuple[0] {
+	case "sha1":
+		h = sha1.New
+	case "sha256":
+		h = sha256.New
+	default:
+		return fmt.Errorf("Unsupported signature algorithm %s", tuple[0])
+	}
+
+	received, err := hex.DecodeString(tuple[1])
+	if err != nil {
+		return fmt.Errorf("Invalid signature format")
+	}
+	mac := hmac.New(h, []byte(secret))
+	mac.Write(content)
+	if !hmac.Equal(received, mac.Sum(nil)) {
+		return fmt.Errorf("Invalid signature")
+	}
+	return nil
+}
+
+// webHookDeliveryKey identifies a call by the signed content: the delivery identifiers sent by the callers
+// are not signed, anyone could choose them to bypass the replay protection
+func webHookDeliveryKey(header http.Header, body []byte) string {
+	h := sha256.New()
+	//Signatures of CDS calls contain a timestamp
+	h.Write([]byte(header.Get(HookTimestampHeader) + "."))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,99 @@
+package hooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_verifyWebHookSignature(t *testing.T) {
+	body := []byte(`{"ref": "refs/heads/master"}`)
+	now := time.Now()
+	window := 5 * time.Minute
+
+	sign := func(secret string, content []byte) string {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(content)
+		return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	}
+
+	//Github and Bitbucket Server
+	h := http.Header{}
+	h.Set(HubSignature256Header, sign("mysecret", body))
+	assert.NoError(t, verifyWebHookSignature("mysecret", h, body, now, window))
+	assert.Error(t, verifyWebHookSignature("othersecret", h, body, now, window))
+
+	mac := hmac.New(sha1.New, []byte("mysecret"))
+	mac.Write(body)
+	h = http.Header{}
+	h.Set(HubSignatureHeader, "sha1="+hex.EncodeToString(mac.Sum(nil)))
+	assert.NoError(t, verifyWebHookSignature("mysecret", h, body, now, window))
+	assert.Error(t, verifyWebHookSignature("mysecret", h, []byte("{}"), now, window))
+
+	//Gitea
+	h = http.Header{}
+	h.Set(GiteaSignatureHeader, strings.TrimPrefix(sign("mysecret", body), "sha256="))
+	assert.NoError(t, verifyWebHookSignature("mysecret", h, body, now, window))
+	assert.Error(t, verifyWebHookSignature("othersecret", h, body, now, window))
+
+	//Gitlab
+	h = http.Header{}
+	h.Set(GitlabTokenHeader, "mysecret")
+	assert.NoError(t, verifyWebHookSignature("mysecret", h, body, now, window))
+	assert.Error(t, verifyWebHookSignature("othersecret", h, body, now, window))
+
+	//CDS
+	ts := fmt.Sprintf("%d", now.Unix())
+	h = http.Header{}
+	h.Set(HookTimestampHeader, ts)
+	h.Set(HookSignatureHeader, sign("mysecret", append([]byte(ts+"."), body...)))
+	assert.NoError(t, verifyWebHookSignature("mysecret", h, body, now, window))
+	assert.Error(t, verifyWebHookSignature("mysecret", h, body, now.Add(10*time.Minute), window))
+	h.Set(HookTimestampHeader, fmt.Sprintf("%d", now.Unix()+1))
+	assert.Error(t, verifyWebHookSignature("mysecret", h, body, now, window))
+
+	//Unsigned
+	assert.Error(t, verifyWebHookSignature("mysecret", http.Header{}, body, now, window))
+}
+
+func Test_isIPAllowed(t *testing.T) {
+	ok, err := isIPAllowed("10.0.12.4", "192.168.1.1, 10.0.0.0/16")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = isIPAllowed("192.168.1.1", "192.168.1.1")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = isIPAllowed("10.1.12.4", "192.168.1.1,10.0.0.0/16")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	_, err = isIPAllowed("10.1.12.4", "10.0.0.0/33")
+	assert.Error(t, err)
+}
+
+func Test_webHookDeliveryKey(t *testing.T) {
+	body := []byte(`{"ref": "refs/heads/master"}`)
+
+	//Unsigned delivery identifiers are ignored
+	h1 := http.Header{}
+	h1.Set("X-Github-Delivery", "1")
+	h2 := http.Header{}
+	h2.Set("X-Github-Delivery", "2")
+	assert.Equal(t, webHookDeliveryKey(h1, body), webHookDeliveryKey(h2, body))
+	assert.NotEqual(t, webHookDeliveryKey(h1, body), webHookDeliveryKey(h1, []byte("{}")))
+
+	//CDS calls are signed with a timestamp
+	h1.Set(HookTimestampHeader, "1500000000")
+	h2.Set(HookTimestampHeader, "1500000001")
+	assert.NotEqual(t, webHookDeliveryKey(h1, body), webHookDeliveryKey(h2, body))
+}
@@ -0,0 +1,18 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "user_access_token" (
+  id BIGSERIAL PRIMARY KEY,
+  user_id BIGINT NOT NULL,
+  name VARCHAR(256) NOT NULL,
+  token_hash VARCHAR(256) NOT NULL,
+  scopes JSONB,
+  project_key VARCHAR(256) NOT NULL DEFAULT '',
+  created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP,
+  expire_at TIMESTAMP WITH TIME ZONE,
+  last_used TIMESTAMP WITH TIME ZONE
+);
+
+SELECT create_unique_index('user_access_token', 'IDX_USER_ACCESS_TOKEN_TOKEN_HASH', 'token_hash');
+SELECT create_foreign_key_idx_cascade('FK_USER_ACCESS_TOKEN_USER', 'user_access_token', 'user', 'user_id', 'id');
+
+-- +migrate Down
+DROP TABLE user_access_token;
@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE workflow ADD COLUMN notifications JSONB;
+
+-- +migrate Down
+ALTER TABLE workflow DROP COLUMN notifications;
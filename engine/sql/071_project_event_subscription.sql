@@ -0,0 +1,37 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "project_event_subscription" (
+  id BIGSERIAL PRIMARY KEY,
+  project_id BIGINT NOT NULL,
+  name VARCHAR(256) NOT NULL,
+  url TEXT NOT NULL,
+  secret BYTEA,
+  event_types JSONB,
+  workflows JSONB,
+  statuses JSONB,
+  enabled BOOLEAN NOT NULL DEFAULT true,
+  created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP
+);
+
+SELECT create_unique_index('project_event_subscription', 'IDX_PROJECT_EVENT_SUBSCRIPTION_NAME', 'project_id,name');
+SELECT create_foreign_key_idx_cascade('FK_PROJECT_EVENT_SUBSCRIPTION_PROJECT', 'project_event_subscription', 'project', 'project_id', 'id');
+
+CREATE TABLE IF NOT EXISTS "project_event_delivery" (
+  id BIGSERIAL PRIMARY KEY,
+  subscription_id BIGINT NOT NULL,
+  event_type VARCHAR(256) NOT NULL,
+  payload JSONB,
+  status VARCHAR(32) NOT NULL,
+  attempts INT NOT NULL DEFAULT 0,
+  response_code INT NOT NULL DEFAULT 0,
+  error TEXT NOT NULL DEFAULT '',
+  created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP,
+  last_attempt TIMESTAMP WITH TIME ZONE,
+  next_attempt TIMESTAMP WITH TIME ZONE
+);
+
+SELECT create_foreign_key_idx_cascade('FK_PROJECT_EVENT_DELIVERY_SUBSCRIPTION', 'project_event_delivery', 'project_event_subscription', 'subscription_id', 'id');
+select create_index('project_event_delivery', 'IDX_PROJECT_EVENT_DELIVERY_STATUS', 'status,next_attempt');
+
+-- +migrate Down
+DROP TABLE project_event_delivery;
+DROP TABLE project_event_subscription;
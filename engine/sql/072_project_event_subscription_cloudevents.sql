@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE project_event_subscription ADD COLUMN cloud_events VARCHAR(32) NOT NULL DEFAULT '';
+
+-- +migrate Down
+ALTER TABLE project_event_subscription DROP COLUMN cloud_events;
@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE workflow_node_run ADD COLUMN coverage JSONB;
+
+-- +migrate Down
+ALTER TABLE workflow_node_run DROP COLUMN coverage;
@@ -0,0 +1,15 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "workflow_test_quarantine" (
+    id BIGSERIAL PRIMARY KEY,
+    workflow_id BIGINT NOT NULL,
+    testsuite VARCHAR(256) NOT NULL,
+    testcase VARCHAR(256) NOT NULL,
+    author VARCHAR(256) NOT NULL DEFAULT '',
+    created TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+SELECT create_foreign_key_idx_cascade('FK_WORKFLOW_TEST_QUARANTINE_WORKFLOW', 'workflow_test_quarantine', 'workflow', 'workflow_id', 'id');
+SELECT create_unique_index('workflow_test_quarantine', 'IDX_WORKFLOW_TEST_QUARANTINE_UNIQ', 'workflow_id,testsuite,testcase');
+
+-- +migrate Down
+DROP TABLE workflow_test_quarantine;
@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE action_edge ADD COLUMN parallel BOOLEAN NOT NULL DEFAULT false;
+
+-- +migrate Down
+ALTER TABLE action_edge DROP COLUMN parallel;
@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE action_edge ADD COLUMN step_name VARCHAR(256) NOT NULL DEFAULT '';
+ALTER TABLE action_edge ADD COLUMN condition TEXT NOT NULL DEFAULT '';
+
+-- +migrate Down
+ALTER TABLE action_edge DROP COLUMN step_name;
+ALTER TABLE action_edge DROP COLUMN condition;
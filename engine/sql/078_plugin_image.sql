@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE plugin ADD COLUMN image TEXT NOT NULL DEFAULT '';
+
+-- +migrate Down
+ALTER TABLE plugin DROP COLUMN image;
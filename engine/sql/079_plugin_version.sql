@@ -0,0 +1,22 @@
+-- +migrate Up
+ALTER TABLE plugin ADD COLUMN version TEXT NOT NULL DEFAULT '';
+ALTER TABLE plugin ADD COLUMN deprecated BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE plugin ADD COLUMN usage BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE plugin ADD COLUMN created TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now();
+ALTER TABLE plugin ADD COLUMN parameters JSONB;
+DROP INDEX IDX_PLUGIN_NAME;
+SELECT create_unique_index('plugin', 'IDX_PLUGIN_NAME_VERSION', 'name,version');
+
+ALTER TABLE action_edge ADD COLUMN plugin_version TEXT NOT NULL DEFAULT '';
+
+-- +migrate Down
+ALTER TABLE action_edge DROP COLUMN plugin_version;
+
+DROP INDEX IDX_PLUGIN_NAME_VERSION;
+DELETE FROM plugin WHERE id NOT IN (SELECT MAX(id) FROM plugin GROUP BY name);
+SELECT create_unique_index('plugin', 'IDX_PLUGIN_NAME', 'name');
+ALTER TABLE plugin DROP COLUMN version;
+ALTER TABLE plugin DROP COLUMN deprecated;
+ALTER TABLE plugin DROP COLUMN usage;
+ALTER TABLE plugin DROP COLUMN created;
+ALTER TABLE plugin DROP COLUMN parameters;
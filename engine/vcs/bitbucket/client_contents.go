@@ -0,0 +1,85 @@
+package bitbucket
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/ovh/cds/sdk"
+)
+
+//Contents returns a file with its content, or a directory with its entries
+func (b *bitbucketClient) Contents(repo, path, ref string) (sdk.VCSContent, error) {
+	project, slug, err := getRepo(repo)
+	if err != nil {
+		return sdk.VCSContent{}, sdk.WrapError(err, "vcs> bitbucket> contents>")
+	}
+
+	path = strings.Trim(path, "/")
+	segments := strings.Split(path, "/")
+	for i := range segments {
+		segments[i] = url.PathEscape(segments[i])
+	}
+	browsePath := fmt.Sprintf("/projects/%s/repos/%s/browse/%s", project, slug, strings.Join(segments, "/"))
+
+	params := url.Values{}
+	if ref != "" {
+		params.Set("at", ref)
+	}
+
+	content := sdk.VCSContent{
+		Name: path[strings.LastIndex(path, "/")+1:],
+		Path: path,
+	}
+	var lines []string
+	for {
+		var response BrowseResponse
+		if err := b.do("GET", "core", browsePath, params, nil, &response); err != nil {
+			if err == ErrNotFound {
+				return sdk.VCSContent{}, sdk.WrapError(sdk.ErrNotFound, "vcs> bitbucket> contents> %s not found on %s at %s", path, repo, ref)
+			}
+			return sdk.VCSContent{}, sdk.WrapError(err, "vcs> bitbucket> contents> Unable to browse %s", browsePath)
+		}
+
+		//Directories are returned with their children, files with their lines
+		if response.Children != nil {
+			content.Type = sdk.VCSContentTypeDirectory
+			for _, c := range response.Children.Values {
+				t := sdk.VCSContentTypeFile
+				if c.Type == "DIRECTORY" {
+					t = sdk.VCSContentTypeDirectory
+				}
+				childPath := c.Path.ToString
+				if path != "" {
+					childPath = path + "/" + childPath
+				}
+				content.Entries = append(content.Entries, sdk.VCSContent{
+					Name: c.Path.Name,
+					Path: childPath,
+					Type: t,
+					Size: c.Size,
+				})
+			}
+			if response.Children.IsLastPage {
+				break
+			}
+			params.Set("start", fmt.Sprintf("%d", response.Children.NextPageStart))
+			continue
+		}
+
+		content.Type = sdk.VCSContentTypeFile
+		for _, l := range response.Lines {
+			lines = append(lines, l.Text)
+		}
+		if response.IsLastPage {
+			break
+		}
+		params.Set("start", fmt.Sprintf("%d", response.NextPageStart))
+	}
+
+	if content.Type == sdk.VCSContentTypeFile && len(lines) > 0 {
+		content.Content = []byte(strings.Join(lines, "\n") + "\n")
+		content.Size = int64(len(content.Content))
+	}
+	return content, nil
+}
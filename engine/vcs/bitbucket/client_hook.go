@@ -100,6 +100,9 @@ func (b *bitbucketClient) CreateHook(repo string, hook *sdk.VCSHook) error {
 		Name:          repo,
 		Configuration: make(map[string]string),
 	}
+	if hook.Secret != "" {
+		request.Configuration["secret"] = hook.Secret
+	}
 
 	values, err := json.Marshal(&request)
 	if err != nil {
@@ -215,3 +215,31 @@ type User struct {
 	DisplayName  string `json:"displayName"`
 	Slug         string `json:"slug"`
 }
+
+type BrowsePath struct {
+	Components []string `json:"components"`
+	Name       string   `json:"name"`
+	ToString   string   `json:"toString"`
+}
+
+type BrowseChild struct {
+	Path BrowsePath `json:"path"`
+	Type string     `json:"type"`
+	Size int64      `json:"size"`
+}
+
+type BrowseLine struct {
+	Text string `json:"text"`
+}
+
+type BrowseResponse struct {
+	Path     BrowsePath `json:"path"`
+	Children *struct {
+		Values        []BrowseChild `json:"values"`
+		IsLastPage    bool          `json:"isLastPage"`
+		NextPageStart int           `json:"nextPageStart"`
+	} `json:"children"`
+	Lines         []BrowseLine `json:"lines"`
+	IsLastPage    bool         `json:"isLastPage"`
+	NextPageStart int          `json:"nextPageStart"`
+}
@@ -0,0 +1,46 @@
+package git
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// Branches returns list of branches for a repo
+func (c *gitClient) Branches(fullname string) ([]sdk.VCSBranch, error) {
+	refs, head, err := c.lsRemote(fullname)
+	if err != nil {
+		return nil, err
+	}
+
+	branches := []sdk.VCSBranch{}
+	for ref, hash := range refs {
+		if !strings.HasPrefix(ref, "refs/heads/") {
+			continue
+		}
+		name := strings.TrimPrefix(ref, "refs/heads/")
+		branches = append(branches, sdk.VCSBranch{
+			ID:           name,
+			DisplayID:    name,
+			LatestCommit: hash,
+			Default:      name == head,
+		})
+	}
+	sort.Slice(branches, func(i, j int) bool { return branches[i].ID < branches[j].ID })
+	return branches, nil
+}
+
+// Branch returns only detail of a branch
+func (c *gitClient) Branch(fullname, theBranch string) (*sdk.VCSBranch, error) {
+	branches, err := c.Branches(fullname)
+	if err != nil {
+		return nil, err
+	}
+	for i := range branches {
+		if branches[i].ID == theBranch {
+			return &branches[i], nil
+		}
+	}
+	return nil, sdk.WrapError(sdk.ErrNoBranch, "gitClient.Branch> Branch %s not found on %s", theBranch, fullname)
+}
@@ -0,0 +1,95 @@
+package git
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// logFormat separates the fields of a commit with NUL characters, and the commits with RS characters
+const logFormat = "--format=%H%x00%an%x00%ae%x00%at%x00%B%x1e"
+
+// Commits returns the commits reachable from until (or the head of the branch), and not reachable from since.
+// If since is empty, only the last commit is returned
+func (c *gitClient) Commits(fullname, branch, since, until string) ([]sdk.VCSCommit, error) {
+	if until == "" {
+		until = "refs/heads/" + branch
+	}
+	if err := checkRevisions(since, until); err != nil {
+		return nil, err
+	}
+
+	path, unlock, err := c.mirror(fullname)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	args := []string{"log", logFormat}
+	if since == "" {
+		args = append(args, "-n", "1", until)
+	} else {
+		args = append(args, since+".."+until)
+	}
+	out, err := gitLocal(path, append(args, "--")...)
+	if err != nil {
+		return nil, sdk.WrapError(err, "gitClient.Commits> Unable to get commits of %s", fullname)
+	}
+	return parseLog(out), nil
+}
+
+// Commit returns the details of a commit
+func (c *gitClient) Commit(fullname, hash string) (sdk.VCSCommit, error) {
+	if err := checkRevisions(hash); err != nil {
+		return sdk.VCSCommit{}, err
+	}
+
+	path, unlock, err := c.mirror(fullname)
+	if err != nil {
+		return sdk.VCSCommit{}, err
+	}
+	defer unlock()
+
+	out, err := gitLocal(path, "log", logFormat, "-n", "1", hash, "--")
+	if err != nil {
+		return sdk.VCSCommit{}, sdk.WrapError(sdk.ErrNotFound, "gitClient.Commit> Unable to get commit %s of %s: %s", hash, fullname, err)
+	}
+	commits := parseLog(out)
+	if len(commits) == 0 {
+		return sdk.VCSCommit{}, sdk.WrapError(sdk.ErrNotFound, "gitClient.Commit> Commit %s not found on %s", hash, fullname)
+	}
+	return commits[0], nil
+}
+
+// checkRevisions prevents revisions to be read as options by git
+func checkRevisions(revs ...string) error {
+	for _, r := range revs {
+		if strings.HasPrefix(r, "-") || strings.Contains(r, "..") {
+			return sdk.WrapError(sdk.ErrWrongRequest, "checkRevisions> Invalid revision %s", r)
+		}
+	}
+	return nil
+}
+
+func parseLog(out []byte) []sdk.VCSCommit {
+	commits := []sdk.VCSCommit{}
+	for _, record := range strings.Split(string(out), "\x1e") {
+		fields := strings.SplitN(strings.TrimLeft(record, "\n"), "\x00", 5)
+		if len(fields) != 5 {
+			continue
+		}
+		timestamp, _ := strconv.ParseInt(fields[3], 10, 64)
+		commits = append(commits, sdk.VCSCommit{
+			Hash: fields[0],
+			Author: sdk.VCSAuthor{
+				Name:        fields[1],
+				DisplayName: fields[1],
+				Email:       fields[2],
+			},
+			Timestamp: timestamp * 1000,
+			Message:   strings.TrimSpace(fields[4]),
+		})
+	}
+	return commits
+}
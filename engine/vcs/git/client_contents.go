@@ -0,0 +1,87 @@
+package git
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// Contents returns a file with its content, or a directory with its entries. The default ref is HEAD
+func (c *gitClient) Contents(fullname, path, ref string) (sdk.VCSContent, error) {
+	if ref == "" {
+		ref = "HEAD"
+	}
+	if err := checkRevisions(ref); err != nil {
+		return sdk.VCSContent{}, err
+	}
+	path = strings.Trim(path, "/")
+	object := ref + ":" + path
+
+	mirror, unlock, err := c.mirror(fullname)
+	if err != nil {
+		return sdk.VCSContent{}, err
+	}
+	defer unlock()
+
+	t, err := gitLocal(mirror, "cat-file", "-t", object)
+	if err != nil {
+		return sdk.VCSContent{}, sdk.WrapError(sdk.ErrNotFound, "gitClient.Contents> %s not found on %s at %s: %s", path, fullname, ref, err)
+	}
+
+	content := sdk.VCSContent{
+		Name: path[strings.LastIndex(path, "/")+1:],
+		Path: path,
+	}
+
+	switch strings.TrimSpace(string(t)) {
+	case "blob":
+		b, err := gitLocal(mirror, "cat-file", "blob", object)
+		if err != nil {
+			return sdk.VCSContent{}, sdk.WrapError(err, "gitClient.Contents> Unable to read %s on %s", path, fullname)
+		}
+		content.Type = sdk.VCSContentTypeFile
+		content.Size = int64(len(b))
+		content.Content = b
+	case "tree":
+		out, err := gitLocal(mirror, "ls-tree", "-l", "-z", object)
+		if err != nil {
+			return sdk.VCSContent{}, sdk.WrapError(err, "gitClient.Contents> Unable to list %s on %s", path, fullname)
+		}
+		content.Type = sdk.VCSContentTypeDirectory
+		content.Entries = parseTree(path, out)
+	default:
+		return sdk.VCSContent{}, sdk.WrapError(sdk.ErrNotFound, "gitClient.Contents> %s is not a file nor a directory", path)
+	}
+	return content, nil
+}
+
+// parseTree parses the output of git ls-tree -l -z: <mode> SP <type> SP <object> SP+ <size> TAB <name> NUL
+func parseTree(dir string, out []byte) []sdk.VCSContent {
+	entries := []sdk.VCSContent{}
+	for _, line := range bytes.Split(out, []byte{0}) {
+		fields := strings.SplitN(string(line), "\t", 2)
+		if len(fields) != 2 {
+			continue
+		}
+		infos := strings.Fields(fields[0])
+		if len(infos) != 4 {
+			continue
+		}
+		entry := sdk.VCSContent{
+			Name: fields[1],
+			Path: fields[1],
+			Type: sdk.VCSContentTypeFile,
+		}
+		if dir != "" {
+			entry.Path = dir + "/" + fields[1]
+		}
+		if infos[1] == "tree" {
+			entry.Type = sdk.VCSContentTypeDirectory
+		}
+		entry.Size, _ = strconv.ParseInt(infos[3], 10, 64)
+		entries = append(entries, entry)
+	}
+	return entries
+}
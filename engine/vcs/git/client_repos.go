@@ -0,0 +1,38 @@
+package git
+
+import (
+	"strings"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// Repos can't be listed on a plain git server
+func (c *gitClient) Repos() ([]sdk.VCSRepo, error) {
+	return nil, sdk.WrapError(sdk.ErrNotImplemented, "gitClient.Repos> Repositories can't be listed on a plain git server")
+}
+
+// RepoByFullname checks that the repository is reachable and returns its details
+func (c *gitClient) RepoByFullname(fullname string) (sdk.VCSRepo, error) {
+	if _, _, err := c.lsRemote(fullname); err != nil {
+		return sdk.VCSRepo{}, err
+	}
+
+	cloneURL, err := c.cloneURL(fullname)
+	if err != nil {
+		return sdk.VCSRepo{}, err
+	}
+
+	fullname = strings.TrimSuffix(fullname, ".git")
+	repo := sdk.VCSRepo{
+		ID:          fullname,
+		Name:        fullname,
+		Slug:        fullname,
+		Fullname:    fullname,
+		SSHCloneURL: cloneURL,
+	}
+	if i := strings.LastIndex(fullname, "/"); i >= 0 {
+		repo.Name = fullname[i+1:]
+		repo.Slug = fullname[:i]
+	}
+	return repo, nil
+}
@@ -0,0 +1,62 @@
+package git
+
+import (
+	"io"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// A plain git server has no pull requests, webhooks, events, statuses nor releases
+
+func (c *gitClient) PullRequests(string) ([]sdk.VCSPullRequest, error) {
+	return nil, sdk.WrapError(sdk.ErrNotImplemented, "gitClient.PullRequests> Not supported on a plain git server")
+}
+
+func (c *gitClient) CreateHook(repo string, hook *sdk.VCSHook) error {
+	return sdk.WrapError(sdk.ErrNotImplemented, "gitClient.CreateHook> Not supported on a plain git server")
+}
+
+func (c *gitClient) GetHook(repo, url string) (sdk.VCSHook, error) {
+	return sdk.VCSHook{}, sdk.WrapError(sdk.ErrNotImplemented, "gitClient.GetHook> Not supported on a plain git server")
+}
+
+func (c *gitClient) UpdateHook(repo, url string, hook sdk.VCSHook) error {
+	return sdk.WrapError(sdk.ErrNotImplemented, "gitClient.UpdateHook> Not supported on a plain git server")
+}
+
+func (c *gitClient) DeleteHook(repo string, hook sdk.VCSHook) error {
+	return sdk.WrapError(sdk.ErrNotImplemented, "gitClient.DeleteHook> Not supported on a plain git server")
+}
+
+func (c *gitClient) GetEvents(repo string, dateRef time.Time) ([]interface{}, time.Duration, error) {
+	return nil, 0, sdk.WrapError(sdk.ErrNotImplemented, "gitClient.GetEvents> Not supported on a plain git server")
+}
+
+func (c *gitClient) PushEvents(string, []interface{}) ([]sdk.VCSPushEvent, error) {
+	return nil, sdk.WrapError(sdk.ErrNotImplemented, "gitClient.PushEvents> Not supported on a plain git server")
+}
+
+func (c *gitClient) CreateEvents(string, []interface{}) ([]sdk.VCSCreateEvent, error) {
+	return nil, sdk.WrapError(sdk.ErrNotImplemented, "gitClient.CreateEvents> Not supported on a plain git server")
+}
+
+func (c *gitClient) DeleteEvents(string, []interface{}) ([]sdk.VCSDeleteEvent, error) {
+	return nil, sdk.WrapError(sdk.ErrNotImplemented, "gitClient.DeleteEvents> Not supported on a plain git server")
+}
+
+func (c *gitClient) PullRequestEvents(string, []interface{}) ([]sdk.VCSPullRequestEvent, error) {
+	return nil, sdk.WrapError(sdk.ErrNotImplemented, "gitClient.PullRequestEvents> Not supported on a plain git server")
+}
+
+func (c *gitClient) SetStatus(event sdk.Event) error {
+	return sdk.WrapError(sdk.ErrNotImplemented, "gitClient.SetStatus> Not supported on a plain git server")
+}
+
+func (c *gitClient) Release(repo, tagName, releaseTitle, releaseDescription string) (*sdk.VCSRelease, error) {
+	return nil, sdk.WrapError(sdk.ErrNotImplemented, "gitClient.Release> Not supported on a plain git server")
+}
+
+func (c *gitClient) UploadReleaseFile(repo string, releaseName string, uploadURL string, artifactName string, r io.ReadCloser) error {
+	return sdk.WrapError(sdk.ErrNotImplemented, "gitClient.UploadReleaseFile> Not supported on a plain git server")
+}
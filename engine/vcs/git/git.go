@@ -0,0 +1,47 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// gitClient is a plain git wrapper for CDS vcs. interface. It runs git commands
+// over SSH, authenticated with a project SSH key
+type gitClient struct {
+	URL        string
+	PrivateKey string
+	mirrors    *mirrors
+}
+
+// gitConsumer implements vcs.Server and it's used to instanciate a gitClient
+type gitConsumer struct {
+	URL     string `json:"url"`
+	mirrors *mirrors
+}
+
+// New creates a new GitConsumer. URL is the base URL of the repositories on the git server,
+// such as ssh://git@mygitserver:2222/srv/git or git@mygitserver:
+func New(URL, mirrorsDirectory string) sdk.VCSServer {
+	if mirrorsDirectory == "" {
+		mirrorsDirectory = filepath.Join(os.TempDir(), "cds-vcs-mirrors")
+	}
+	return &gitConsumer{
+		URL:     URL,
+		mirrors: newMirrors(mirrorsDirectory),
+	}
+}
+
+// cloneURL returns the URL of a repository from its fullname
+func (c *gitClient) cloneURL(fullname string) (string, error) {
+	if fullname == "" || strings.HasPrefix(fullname, "-") || strings.HasPrefix(fullname, "/") || strings.Contains(fullname, "..") {
+		return "", sdk.WrapError(sdk.ErrWrongRequest, "gitClient.cloneURL> Invalid repository name %s", fullname)
+	}
+	fullname = strings.TrimSuffix(fullname, ".git")
+	if strings.HasSuffix(c.URL, ":") {
+		return c.URL + fullname + ".git", nil
+	}
+	return strings.TrimSuffix(c.URL, "/") + "/" + fullname + ".git", nil
+}
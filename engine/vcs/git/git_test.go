@@ -0,0 +1,238 @@
+package git
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/sdk"
+)
+
+func newPrivateKey(t *testing.T) string {
+	k, err := rsa.GenerateKey(rand.Reader, 2048)
+	test.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(k)}))
+}
+
+func runGit(t *testing.T, dir string, args ...string) string {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=John Doe", "GIT_AUTHOR_EMAIL=john@localhost",
+		"GIT_COMMITTER_NAME=John Doe", "GIT_COMMITTER_EMAIL=john@localhost",
+	)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v: %s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// newTestServer creates a bare repository cds/myrepo.git in a temporary directory, which is used as the git server.
+// The repository has 3 commits on master, and a branch feat/foo with one more commit. The second commit adds a .cds directory
+func newTestServer(t *testing.T) (string, []string) {
+	dir, err := ioutil.TempDir("", "cds-vcs-git-test")
+	test.NoError(t, err)
+
+	server := filepath.Join(dir, "server")
+	bare := filepath.Join(server, "cds", "myrepo.git")
+	work := filepath.Join(dir, "work")
+	test.NoError(t, os.MkdirAll(bare, os.FileMode(0755)))
+	test.NoError(t, os.MkdirAll(work, os.FileMode(0755)))
+
+	runGit(t, bare, "init", "--bare", "--quiet")
+	runGit(t, bare, "symbolic-ref", "HEAD", "refs/heads/master")
+	runGit(t, work, "init", "--quiet")
+	runGit(t, work, "checkout", "--quiet", "-b", "master")
+
+	hashes := []string{}
+	for i := 1; i <= 4; i++ {
+		if i == 4 {
+			runGit(t, work, "checkout", "--quiet", "-b", "feat/foo")
+		}
+		if i == 2 {
+			test.NoError(t, os.MkdirAll(filepath.Join(work, ".cds", "pipelines"), os.FileMode(0755)))
+			test.NoError(t, ioutil.WriteFile(filepath.Join(work, ".cds", "workflow.yml"), []byte("name: myworkflow\n"), os.FileMode(0644)))
+			test.NoError(t, ioutil.WriteFile(filepath.Join(work, ".cds", "pipelines", "build.yml"), []byte("name: build\n"), os.FileMode(0644)))
+			runGit(t, work, "add", ".cds")
+		}
+		runGit(t, work, "commit", "--quiet", "--allow-empty", "-m", fmt.Sprintf("commit %d", i))
+		hashes = append(hashes, runGit(t, work, "rev-parse", "HEAD"))
+	}
+	runGit(t, work, "push", "--quiet", bare, "master", "feat/foo")
+	return dir, hashes
+}
+
+func newTestClient(t *testing.T) (sdk.VCSAuthorizedClient, []string, func()) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir, hashes := newTestServer(t)
+	consumer := New(filepath.Join(dir, "server"), filepath.Join(dir, "mirrors"))
+	client, err := consumer.GetAuthorizedClient("", newPrivateKey(t))
+	test.NoError(t, err)
+	return client, hashes, func() { os.RemoveAll(dir) }
+}
+
+func TestAuthorize(t *testing.T) {
+	consumer := New("ssh://git@localhost/srv/git", "")
+
+	token, url, err := consumer.AuthorizeRedirect()
+	test.NoError(t, err)
+	assert.NotEmpty(t, token)
+	assert.Empty(t, url)
+
+	privateKey := newPrivateKey(t)
+	accessToken, secret, err := consumer.AuthorizeToken(token, privateKey)
+	test.NoError(t, err)
+	assert.True(t, strings.HasPrefix(accessToken, "SHA256:"))
+	assert.Equal(t, privateKey, secret)
+
+	_, _, err = consumer.AuthorizeToken(token, "not a key")
+	assert.Error(t, err)
+
+	_, err = consumer.GetAuthorizedClient(accessToken, "")
+	assert.Error(t, err)
+}
+
+func TestCloneURL(t *testing.T) {
+	c := &gitClient{URL: "ssh://git@localhost:2222/srv/git/"}
+	u, err := c.cloneURL("cds/myrepo")
+	test.NoError(t, err)
+	assert.Equal(t, "ssh://git@localhost:2222/srv/git/cds/myrepo.git", u)
+
+	c = &gitClient{URL: "git@localhost:"}
+	u, err = c.cloneURL("cds/myrepo.git")
+	test.NoError(t, err)
+	assert.Equal(t, "git@localhost:cds/myrepo.git", u)
+
+	for _, fullname := range []string{"", "../etc/passwd", "-oProxyCommand=foo", "/cds/myrepo"} {
+		_, err := c.cloneURL(fullname)
+		assert.Error(t, err, fullname)
+	}
+}
+
+func TestRepoAndBranches(t *testing.T) {
+	client, hashes, cleanup := newTestClient(t)
+	defer cleanup()
+
+	repo, err := client.RepoByFullname("cds/myrepo")
+	test.NoError(t, err)
+	assert.Equal(t, "myrepo", repo.Name)
+	assert.Equal(t, "cds", repo.Slug)
+	assert.Equal(t, "cds/myrepo", repo.Fullname)
+	assert.True(t, strings.HasSuffix(repo.SSHCloneURL, "/server/cds/myrepo.git"))
+
+	_, err = client.RepoByFullname("cds/unknown")
+	assert.Error(t, err)
+
+	branches, err := client.Branches("cds/myrepo")
+	test.NoError(t, err)
+	assert.Len(t, branches, 2)
+	assert.Equal(t, "feat/foo", branches[0].ID)
+	assert.Equal(t, hashes[3], branches[0].LatestCommit)
+	assert.False(t, branches[0].Default)
+	assert.Equal(t, "master", branches[1].ID)
+	assert.Equal(t, hashes[2], branches[1].LatestCommit)
+	assert.True(t, branches[1].Default)
+
+	branch, err := client.Branch("cds/myrepo", "feat/foo")
+	test.NoError(t, err)
+	assert.Equal(t, hashes[3], branch.LatestCommit)
+
+	_, err = client.Branch("cds/myrepo", "unknown")
+	assert.Error(t, err)
+}
+
+func TestCommits(t *testing.T) {
+	client, hashes, cleanup := newTestClient(t)
+	defer cleanup()
+
+	commits, err := client.Commits("cds/myrepo", "feat/foo", hashes[0], "")
+	test.NoError(t, err)
+	assert.Len(t, commits, 3)
+	assert.Equal(t, hashes[3], commits[0].Hash)
+	assert.Equal(t, hashes[1], commits[2].Hash)
+
+	commits, err = client.Commits("cds/myrepo", "master", hashes[0], hashes[1])
+	test.NoError(t, err)
+	assert.Len(t, commits, 1)
+	assert.Equal(t, hashes[1], commits[0].Hash)
+
+	commits, err = client.Commits("cds/myrepo", "master", "", "")
+	test.NoError(t, err)
+	assert.Len(t, commits, 1)
+	assert.Equal(t, hashes[2], commits[0].Hash)
+
+	_, err = client.Commits("cds/myrepo", "master", "--output=/tmp/foo", "")
+	assert.Error(t, err)
+
+	commit, err := client.Commit("cds/myrepo", hashes[1])
+	test.NoError(t, err)
+	assert.Equal(t, "commit 2", commit.Message)
+	assert.Equal(t, "John Doe", commit.Author.Name)
+	assert.Equal(t, "john@localhost", commit.Author.Email)
+	assert.NotZero(t, commit.Timestamp)
+
+	_, err = client.Commit("cds/myrepo", "0000000000000000000000000000000000000000")
+	assert.Error(t, err)
+}
+
+func TestContents(t *testing.T) {
+	client, hashes, cleanup := newTestClient(t)
+	defer cleanup()
+
+	root, err := client.Contents("cds/myrepo", "", "")
+	test.NoError(t, err)
+	assert.Equal(t, sdk.VCSContentTypeDirectory, root.Type)
+	assert.Len(t, root.Entries, 1)
+	assert.Equal(t, ".cds", root.Entries[0].Path)
+
+	dir, err := client.Contents("cds/myrepo", "/.cds/", hashes[1])
+	test.NoError(t, err)
+	assert.Equal(t, sdk.VCSContentTypeDirectory, dir.Type)
+	assert.Equal(t, ".cds", dir.Name)
+	assert.Len(t, dir.Entries, 2)
+	assert.Equal(t, ".cds/pipelines", dir.Entries[0].Path)
+	assert.Equal(t, sdk.VCSContentTypeDirectory, dir.Entries[0].Type)
+	assert.Equal(t, ".cds/workflow.yml", dir.Entries[1].Path)
+	assert.Equal(t, sdk.VCSContentTypeFile, dir.Entries[1].Type)
+	assert.Equal(t, int64(17), dir.Entries[1].Size)
+
+	file, err := client.Contents("cds/myrepo", ".cds/workflow.yml", "feat/foo")
+	test.NoError(t, err)
+	assert.Equal(t, sdk.VCSContentTypeFile, file.Type)
+	assert.Equal(t, "workflow.yml", file.Name)
+	assert.Equal(t, "name: myworkflow\n", string(file.Content))
+
+	_, err = client.Contents("cds/myrepo", ".cds/workflow.yml", hashes[0])
+	assert.Error(t, err)
+	_, err = client.Contents("cds/myrepo", ".cds/workflow.yml", "--output=/tmp/foo")
+	assert.Error(t, err)
+}
+
+func TestUnsupported(t *testing.T) {
+	client, err := New("git@localhost:", "").GetAuthorizedClient("", newPrivateKey(t))
+	test.NoError(t, err)
+
+	_, err = client.Repos()
+	assert.Equal(t, sdk.ErrNotImplemented, errors.Cause(err))
+	_, err = client.PullRequests("cds/myrepo")
+	assert.Equal(t, sdk.ErrNotImplemented, errors.Cause(err))
+	assert.Equal(t, sdk.ErrNotImplemented, errors.Cause(client.SetStatus(sdk.Event{})))
+	_, err = client.Release("cds/myrepo", "v1.0.0", "v1.0.0", "")
+	assert.Equal(t, sdk.ErrNotImplemented, errors.Cause(err))
+	assert.Equal(t, sdk.ErrNotImplemented, errors.Cause(client.CreateHook("cds/myrepo", &sdk.VCSHook{})))
+}
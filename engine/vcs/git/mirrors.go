@@ -0,0 +1,148 @@
+package git
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/vcs"
+	vcsgit "github.com/ovh/cds/sdk/vcs/git"
+)
+
+// mirrors manages the local bare mirrors of the repositories. Git commands on a mirror are serialized
+type mirrors struct {
+	directory string
+	mutex     sync.Mutex
+	locks     map[string]*sync.Mutex
+}
+
+func newMirrors(directory string) *mirrors {
+	return &mirrors{
+		directory: directory,
+		locks:     map[string]*sync.Mutex{},
+	}
+}
+
+func (m *mirrors) path(cloneURL string) string {
+	h := sha1.Sum([]byte(cloneURL))
+	return filepath.Join(m.directory, hex.EncodeToString(h[:]))
+}
+
+func (m *mirrors) lock(path string) func() {
+	m.mutex.Lock()
+	l, ok := m.locks[path]
+	if !ok {
+		l = new(sync.Mutex)
+		m.locks[path] = l
+	}
+	m.mutex.Unlock()
+
+	l.Lock()
+	return l.Unlock
+}
+
+// withPrivateKey writes the private key in a temporary directory for the time of f
+func withPrivateKey(privateKey string, f func(auth *vcsgit.AuthOpts) error) error {
+	dir, err := ioutil.TempDir("", "cds-vcs-git")
+	if err != nil {
+		return sdk.WrapError(err, "withPrivateKey> Unable to create temporary directory")
+	}
+	defer os.RemoveAll(dir)
+
+	if !strings.HasSuffix(privateKey, "\n") {
+		privateKey += "\n"
+	}
+	keyPath := filepath.Join(dir, "id_rsa")
+	if err := ioutil.WriteFile(keyPath, []byte(privateKey), os.FileMode(0600)); err != nil {
+		return sdk.WrapError(err, "withPrivateKey> Unable to write private key")
+	}
+
+	return f(&vcsgit.AuthOpts{PrivateKey: vcs.SSHKey{Filename: keyPath, Content: []byte(privateKey)}})
+}
+
+// lsRemote returns the references of a repository, and the branch pointed by HEAD
+func (c *gitClient) lsRemote(fullname string) (map[string]string, string, error) {
+	cloneURL, err := c.cloneURL(fullname)
+	if err != nil {
+		return nil, "", err
+	}
+
+	stdout := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+	if err := withPrivateKey(c.PrivateKey, func(auth *vcsgit.AuthOpts) error {
+		return vcsgit.LsRemote(cloneURL, auth, &vcsgit.OutputOpts{Stdout: stdout, Stderr: stderr})
+	}); err != nil {
+		return nil, "", gitError(err, stderr, "gitClient.lsRemote> Unable to list references of %s", fullname)
+	}
+
+	refs := map[string]string{}
+	var head string
+	for _, line := range strings.Split(stdout.String(), "\n") {
+		fields := strings.Split(line, "\t")
+		if len(fields) != 2 {
+			continue
+		}
+		if strings.HasPrefix(fields[0], "ref: ") {
+			if fields[1] == "HEAD" {
+				head = strings.TrimPrefix(strings.TrimPrefix(fields[0], "ref: "), "refs/heads/")
+			}
+			continue
+		}
+		refs[fields[1]] = fields[0]
+	}
+	return refs, head, nil
+}
+
+// mirror updates the mirror of a repository with the private key of the client, and locks it.
+// The mirror is always fetched, so that the access of the client to the repository is checked
+func (c *gitClient) mirror(fullname string) (string, func(), error) {
+	cloneURL, err := c.cloneURL(fullname)
+	if err != nil {
+		return "", nil, err
+	}
+
+	path := c.mirrors.path(cloneURL)
+	unlock := c.mirrors.lock(path)
+
+	if err := os.MkdirAll(c.mirrors.directory, os.FileMode(0700)); err != nil {
+		unlock()
+		return "", nil, sdk.WrapError(err, "gitClient.mirror> Unable to create mirrors directory")
+	}
+
+	stderr := new(bytes.Buffer)
+	if err := withPrivateKey(c.PrivateKey, func(auth *vcsgit.AuthOpts) error {
+		return vcsgit.Mirror(cloneURL, path, auth, &vcsgit.OutputOpts{Stdout: ioutil.Discard, Stderr: stderr})
+	}); err != nil {
+		unlock()
+		return "", nil, gitError(err, stderr, "gitClient.mirror> Unable to fetch %s", fullname)
+	}
+	return path, unlock, nil
+}
+
+// gitLocal runs a git command on a local repository
+func gitLocal(dir string, args ...string) ([]byte, error) {
+	stderr := new(bytes.Buffer)
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Stderr = stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, gitError(err, stderr, "gitLocal> git %s failed", args[0])
+	}
+	return out, nil
+}
+
+func gitError(err error, stderr *bytes.Buffer, format string, args ...interface{}) error {
+	if msg := strings.TrimSpace(stderr.String()); msg != "" {
+		err = fmt.Errorf("%v: %s", err, msg)
+	}
+	return sdk.WrapError(err, format, args...)
+}
@@ -0,0 +1,39 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/satori/go.uuid"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/ovh/cds/sdk"
+)
+
+//AuthorizeRedirect returns a request token. There is nothing to authorize on a plain git server,
+//so there is no URL to redirect to
+func (g *gitConsumer) AuthorizeRedirect() (string, string, error) {
+	return uuid.NewV4().String(), "", nil
+}
+
+//AuthorizeToken takes the private SSH key of the project as verifier. It returns the fingerprint of the key as access token,
+//and the private key as access token secret
+func (g *gitConsumer) AuthorizeToken(state, privateKey string) (string, string, error) {
+	signer, err := ssh.ParsePrivateKey([]byte(privateKey))
+	if err != nil {
+		return "", "", sdk.WrapError(sdk.ErrWrongRequest, "gitConsumer.AuthorizeToken> Invalid private key: %s", err)
+	}
+	return ssh.FingerprintSHA256(signer.PublicKey()), privateKey, nil
+}
+
+//GetAuthorizedClient returns an authorized client
+func (g *gitConsumer) GetAuthorizedClient(accessToken, accessTokenSecret string) (sdk.VCSAuthorizedClient, error) {
+	if strings.TrimSpace(accessTokenSecret) == "" {
+		return nil, fmt.Errorf("gitConsumer.GetAuthorizedClient> no private key")
+	}
+	return &gitClient{
+		URL:        g.URL,
+		PrivateKey: accessTokenSecret,
+		mirrors:    g.mirrors,
+	}, nil
+}
@@ -0,0 +1,65 @@
+package gitea
+
+import (
+	"github.com/ovh/cds/sdk"
+)
+
+// Branches returns list of branches for a repo
+// https://try.gitea.io/api/swagger#/repository/repoListBranches
+func (c *giteaClient) Branches(fullname string) ([]sdk.VCSBranch, error) {
+	repo, err := c.repoByFullname(fullname)
+	if err != nil {
+		return nil, err
+	}
+
+	branches, err := c.branches(fullname)
+	if err != nil {
+		return nil, err
+	}
+
+	branchesResult := make([]sdk.VCSBranch, 0, len(branches))
+	for _, b := range branches {
+		branchesResult = append(branchesResult, b.toVCSBranch(repo.DefaultBranch))
+	}
+	return branchesResult, nil
+}
+
+// Branch returns only detail of a branch
+// https://try.gitea.io/api/swagger#/repository/repoGetBranch
+func (c *giteaClient) Branch(fullname, theBranch string) (*sdk.VCSBranch, error) {
+	repo, err := c.repoByFullname(fullname)
+	if err != nil {
+		return nil, err
+	}
+
+	branch := Branch{}
+	if err := c.get("/repos/"+fullname+"/branches/"+theBranch, &branch); err != nil {
+		return nil, sdk.WrapError(err, "giteaClient.Branch> Unable to get branch %s of %s", theBranch, fullname)
+	}
+
+	b := branch.toVCSBranch(repo.DefaultBranch)
+	return &b, nil
+}
+
+func (c *giteaClient) branches(fullname string) ([]Branch, error) {
+	var branches []Branch
+	for page := 1; ; page++ {
+		next := []Branch{}
+		if err := c.getPage("/repos/"+fullname+"/branches", nil, page, &next); err != nil {
+			return nil, sdk.WrapError(err, "giteaClient.Branches> Unable to list branches of %s", fullname)
+		}
+		branches = append(branches, next...)
+		if len(next) < pageLimit {
+			return branches, nil
+		}
+	}
+}
+
+func (b Branch) toVCSBranch(defaultBranch string) sdk.VCSBranch {
+	return sdk.VCSBranch{
+		DisplayID:    b.Name,
+		ID:           b.Name,
+		LatestCommit: b.Commit.ID,
+		Default:      b.Name == defaultBranch,
+	}
+}
@@ -0,0 +1,75 @@
+package gitea
+
+import (
+	"net/url"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+//Commits between since and until are looked for in this maximum number of pages
+const maxCommitsPages = 20
+
+// Commits returns the commits list on a branch between a commit SHA (since) until another commit SHA (until).
+// If until is empty, the commits are listed from the head of the branch. If since is empty, only the last commit is returned.
+// https://try.gitea.io/api/swagger#/repository/repoGetAllCommits
+func (c *giteaClient) Commits(repo, theBranch, since, until string) ([]sdk.VCSCommit, error) {
+	log.Debug("Looking for commits on repo %s since = %s until = %s", repo, since, until)
+
+	ref := until
+	if ref == "" {
+		ref = theBranch
+	}
+	values := url.Values{}
+	values.Set("sha", ref)
+
+	commitsResult := []sdk.VCSCommit{}
+	for page := 1; page <= maxCommitsPages; page++ {
+		next := []Commit{}
+		if err := c.getPage("/repos/"+repo+"/commits", values, page, &next); err != nil {
+			return nil, sdk.WrapError(err, "giteaClient.Commits> Unable to list commits of %s", repo)
+		}
+		for _, commit := range next {
+			if since != "" && commit.SHA == since {
+				return commitsResult, nil
+			}
+			commitsResult = append(commitsResult, commit.toVCSCommit())
+			if since == "" {
+				return commitsResult, nil
+			}
+		}
+		if len(next) < pageLimit {
+			break
+		}
+	}
+	return commitsResult, nil
+}
+
+// Commit Get a single commit
+// https://try.gitea.io/api/swagger#/repository/repoGetSingleCommit
+func (c *giteaClient) Commit(repo, hash string) (sdk.VCSCommit, error) {
+	commit := Commit{}
+	if err := c.get("/repos/"+repo+"/git/commits/"+hash, &commit); err != nil {
+		return sdk.VCSCommit{}, sdk.WrapError(err, "giteaClient.Commit> Unable to get commit %s of %s", hash, repo)
+	}
+	return commit.toVCSCommit(), nil
+}
+
+func (c Commit) toVCSCommit() sdk.VCSCommit {
+	commit := sdk.VCSCommit{
+		Timestamp: c.Commit.Author.Date.Unix() * 1000,
+		Message:   c.Commit.Message,
+		Hash:      c.SHA,
+		URL:       c.HTMLURL,
+		Author: sdk.VCSAuthor{
+			DisplayName: c.Commit.Author.Name,
+			Email:       c.Commit.Author.Email,
+			Name:        c.Commit.Author.Name,
+		},
+	}
+	if c.Author != nil {
+		commit.Author.Name = c.Author.Login
+		commit.Author.Avatar = c.Author.AvatarURL
+	}
+	return commit
+}
@@ -0,0 +1,75 @@
+package gitea
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"net/url"
+	"strings"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// Contents returns a file with its content, or a directory with its entries
+// https://try.gitea.io/api/swagger#/repository/repoGetContents
+func (c *giteaClient) Contents(fullname, path, ref string) (sdk.VCSContent, error) {
+	path = strings.Trim(path, "/")
+	segments := strings.Split(path, "/")
+	for i := range segments {
+		segments[i] = url.PathEscape(segments[i])
+	}
+	u := "/repos/" + fullname + "/contents/" + strings.Join(segments, "/")
+	if ref != "" {
+		u += "?ref=" + url.QueryEscape(ref)
+	}
+
+	var raw json.RawMessage
+	if err := c.get(u, &raw); err != nil {
+		return sdk.VCSContent{}, sdk.WrapError(err, "giteaClient.Contents> Unable to get %s on %s at %s", path, fullname, ref)
+	}
+
+	//Gitea returns an array for directories
+	if bytes.HasPrefix(bytes.TrimSpace(raw), []byte("[")) {
+		entries := []Content{}
+		if err := json.Unmarshal(raw, &entries); err != nil {
+			return sdk.VCSContent{}, sdk.WrapError(err, "giteaClient.Contents> Unable to parse directory %s", path)
+		}
+		dir := sdk.VCSContent{
+			Name:    path[strings.LastIndex(path, "/")+1:],
+			Path:    path,
+			Type:    sdk.VCSContentTypeDirectory,
+			Entries: make([]sdk.VCSContent, 0, len(entries)),
+		}
+		for _, e := range entries {
+			dir.Entries = append(dir.Entries, e.toVCSContent())
+		}
+		return dir, nil
+	}
+
+	file := Content{}
+	if err := json.Unmarshal(raw, &file); err != nil {
+		return sdk.VCSContent{}, sdk.WrapError(err, "giteaClient.Contents> Unable to parse file %s", path)
+	}
+	content := file.toVCSContent()
+	if file.Encoding == "base64" {
+		b, err := base64.StdEncoding.DecodeString(strings.Replace(file.Content, "\n", "", -1))
+		if err != nil {
+			return sdk.VCSContent{}, sdk.WrapError(err, "giteaClient.Contents> Unable to decode file %s", path)
+		}
+		content.Content = b
+	}
+	return content, nil
+}
+
+func (c Content) toVCSContent() sdk.VCSContent {
+	t := sdk.VCSContentTypeFile
+	if c.Type == "dir" {
+		t = sdk.VCSContentTypeDirectory
+	}
+	return sdk.VCSContent{
+		Name: c.Name,
+		Path: c.Path,
+		Type: t,
+		Size: c.Size,
+	}
+}
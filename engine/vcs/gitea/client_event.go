@@ -0,0 +1,147 @@
+package gitea
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+//Gitea doesn't give the events of a repository, they are computed by polling every minute
+const pollingInterval = 60 * time.Second
+
+// GetEvents returns a push event for each branch which has been updated after the reference date, and a pull
+// request event for each open pull request which has been updated after this date.
+func (c *giteaClient) GetEvents(fullname string, dateRef time.Time) ([]interface{}, time.Duration, error) {
+	log.Debug("giteaClient.GetEvents> loading events for %s after %v", fullname, dateRef)
+
+	branches, err := c.branches(fullname)
+	if err != nil {
+		return nil, pollingInterval, err
+	}
+
+	events := []interface{}{}
+	for _, b := range branches {
+		if !b.Commit.Timestamp.After(dateRef) {
+			continue
+		}
+		events = append(events, Event{
+			Type:      PushEvent,
+			Timestamp: b.Commit.Timestamp.Unix() * 1000,
+			Ref:       b.Name,
+			Sha:       b.Commit.ID,
+		})
+	}
+
+	pullRequests, err := c.openPullRequests(fullname)
+	if err != nil {
+		return nil, pollingInterval, err
+	}
+	for _, pr := range pullRequests {
+		if !pr.Updated.After(dateRef) {
+			continue
+		}
+		action := "edited"
+		if pr.Created.After(dateRef) {
+			action = "opened"
+		}
+		events = append(events, Event{
+			Type:        PullRequestEvent,
+			Timestamp:   pr.Updated.Unix() * 1000,
+			Ref:         pr.Head.Ref,
+			Sha:         pr.Head.Sha,
+			PullRequest: pr.Number,
+			Action:      action,
+		})
+	}
+
+	return events, pollingInterval, nil
+}
+
+//PushEvents returns push events as commits
+func (c *giteaClient) PushEvents(fullname string, iEvents []interface{}) ([]sdk.VCSPushEvent, error) {
+	events, err := decodeEvents(iEvents, PushEvent)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []sdk.VCSPushEvent{}
+	for _, e := range events {
+		branch, err := c.Branch(fullname, e.Ref)
+		if err != nil {
+			log.Warning("giteaClient.PushEvents> Unable to find branch %s in %s : %s", e.Ref, fullname, err)
+			continue
+		}
+		commit, err := c.Commit(fullname, e.Sha)
+		if err != nil {
+			log.Warning("giteaClient.PushEvents> Unable to find commit %s in %s : %s", e.Sha, fullname, err)
+			continue
+		}
+		res = append(res, sdk.VCSPushEvent{
+			Branch: *branch,
+			Commit: commit,
+			Repo:   fullname,
+		})
+	}
+	return res, nil
+}
+
+//CreateEvents returns no event: new branches are given as push events
+func (c *giteaClient) CreateEvents(fullname string, iEvents []interface{}) ([]sdk.VCSCreateEvent, error) {
+	return []sdk.VCSCreateEvent{}, nil
+}
+
+//DeleteEvents returns no event: deleted branches can't be detected by polling
+func (c *giteaClient) DeleteEvents(fullname string, iEvents []interface{}) ([]sdk.VCSDeleteEvent, error) {
+	return []sdk.VCSDeleteEvent{}, nil
+}
+
+//PullRequestEvents returns the pull request events
+func (c *giteaClient) PullRequestEvents(fullname string, iEvents []interface{}) ([]sdk.VCSPullRequestEvent, error) {
+	events, err := decodeEvents(iEvents, PullRequestEvent)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []sdk.VCSPullRequestEvent{}
+	for _, e := range events {
+		pr, err := c.pullRequest(fullname, e.PullRequest)
+		if err != nil {
+			log.Warning("giteaClient.PullRequestEvents> %s", err)
+			continue
+		}
+		if pr.State != "open" {
+			continue
+		}
+		vcsPR := pr.toVCSPullRequest()
+		res = append(res, sdk.VCSPullRequestEvent{
+			Action: e.Action,
+			URL:    vcsPR.URL,
+			Repo:   vcsPR.Head.Repo,
+			User:   vcsPR.User,
+			Head:   vcsPR.Head,
+			Base:   vcsPR.Base,
+		})
+	}
+	return res, nil
+}
+
+// decodeEvents decodes the events of a type. Events are given as maps when they are sent back by CDS API.
+func decodeEvents(iEvents []interface{}, eventType string) ([]Event, error) {
+	b, err := json.Marshal(iEvents)
+	if err != nil {
+		return nil, sdk.WrapError(err, "giteaClient.decodeEvents> Unable to read events")
+	}
+	all := []Event{}
+	if err := json.Unmarshal(b, &all); err != nil {
+		return nil, sdk.WrapError(err, "giteaClient.decodeEvents> Unable to read events")
+	}
+	events := []Event{}
+	for _, e := range all {
+		if e.Type == eventType {
+			events = append(events, e)
+		}
+	}
+	return events, nil
+}
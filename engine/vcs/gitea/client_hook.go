@@ -0,0 +1,105 @@
+package gitea
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// CreateHook creates a webhook sending the push events of a repository
+// https://try.gitea.io/api/swagger#/repository/repoCreateHook
+func (c *giteaClient) CreateHook(repo string, hook *sdk.VCSHook) error {
+	h := newHook(*hook)
+	if err := c.send(http.MethodPost, "/repos/"+repo+"/hooks", h, &h); err != nil {
+		return sdk.WrapError(err, "giteaClient.CreateHook> Unable to create webhook on %s", repo)
+	}
+	hook.ID = strconv.FormatInt(h.ID, 10)
+	return nil
+}
+
+// GetHook returns the webhook of a repository calling an URL
+func (c *giteaClient) GetHook(repo, url string) (sdk.VCSHook, error) {
+	h, err := c.hookByURL(repo, url)
+	if err != nil {
+		return sdk.VCSHook{}, err
+	}
+	return sdk.VCSHook{
+		ID:          strconv.FormatInt(h.ID, 10),
+		Name:        h.Type,
+		Disable:     !h.Active,
+		Events:      h.Events,
+		Method:      http.MethodPost,
+		URL:         h.Config["url"],
+		ContentType: h.Config["content_type"],
+	}, nil
+}
+
+// UpdateHook updates the webhook of a repository calling an URL
+// https://try.gitea.io/api/swagger#/repository/repoEditHook
+func (c *giteaClient) UpdateHook(repo, url string, hook sdk.VCSHook) error {
+	h, err := c.hookByURL(repo, url)
+	if err != nil {
+		return err
+	}
+	update := newHook(hook)
+	if err := c.send(http.MethodPatch, fmt.Sprintf("/repos/%s/hooks/%d", repo, h.ID), update, nil); err != nil {
+		return sdk.WrapError(err, "giteaClient.UpdateHook> Unable to update webhook %d on %s", h.ID, repo)
+	}
+	return nil
+}
+
+// DeleteHook deletes a webhook, found by its ID or else by its URL
+// https://try.gitea.io/api/swagger#/repository/repoDeleteHook
+func (c *giteaClient) DeleteHook(repo string, hook sdk.VCSHook) error {
+	id := hook.ID
+	if id == "" {
+		h, err := c.hookByURL(repo, hook.URL)
+		if err != nil {
+			return err
+		}
+		id = strconv.FormatInt(h.ID, 10)
+	}
+	if err := c.delete("/repos/" + repo + "/hooks/" + id); err != nil {
+		return sdk.WrapError(err, "giteaClient.DeleteHook> Unable to delete webhook %s on %s", id, repo)
+	}
+	return nil
+}
+
+func (c *giteaClient) hookByURL(repo, url string) (Hook, error) {
+	for page := 1; ; page++ {
+		hooks := []Hook{}
+		if err := c.getPage("/repos/"+repo+"/hooks", nil, page, &hooks); err != nil {
+			return Hook{}, sdk.WrapError(err, "giteaClient.hookByURL> Unable to list webhooks of %s", repo)
+		}
+		for _, h := range hooks {
+			if h.Config["url"] == url {
+				return h, nil
+			}
+		}
+		if len(hooks) < pageLimit {
+			return Hook{}, sdk.WrapError(sdk.ErrNotFound, "giteaClient.hookByURL> No webhook calling %s on %s", url, repo)
+		}
+	}
+}
+
+func newHook(hook sdk.VCSHook) Hook {
+	events := hook.Events
+	if len(events) == 0 {
+		events = []string{"push"}
+	}
+	h := Hook{
+		Type:   "gitea",
+		Active: !hook.Disable,
+		Events: events,
+		Config: map[string]string{
+			"url":          hook.URL,
+			"content_type": "json",
+		},
+	}
+	if hook.Secret != "" {
+		h.Config["secret"] = hook.Secret
+	}
+	return h
+}
@@ -0,0 +1,84 @@
+package gitea
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// PullRequests fetch all the open pull request for a repository
+// https://try.gitea.io/api/swagger#/repository/repoListPullRequests
+func (c *giteaClient) PullRequests(fullname string) ([]sdk.VCSPullRequest, error) {
+	pullRequests, err := c.openPullRequests(fullname)
+	if err != nil {
+		return nil, err
+	}
+
+	prResults := make([]sdk.VCSPullRequest, 0, len(pullRequests))
+	for _, pr := range pullRequests {
+		prResults = append(prResults, pr.toVCSPullRequest())
+	}
+	return prResults, nil
+}
+
+func (c *giteaClient) openPullRequests(fullname string) ([]PullRequest, error) {
+	values := url.Values{}
+	values.Set("state", "open")
+
+	var pullRequests []PullRequest
+	for page := 1; ; page++ {
+		next := []PullRequest{}
+		if err := c.getPage("/repos/"+fullname+"/pulls", values, page, &next); err != nil {
+			return nil, sdk.WrapError(err, "giteaClient.PullRequests> Unable to list pull requests of %s", fullname)
+		}
+		pullRequests = append(pullRequests, next...)
+		if len(next) < pageLimit {
+			return pullRequests, nil
+		}
+	}
+}
+
+func (c *giteaClient) pullRequest(fullname string, number int64) (PullRequest, error) {
+	pr := PullRequest{}
+	if err := c.get(fmt.Sprintf("/repos/%s/pulls/%d", fullname, number), &pr); err != nil {
+		return pr, sdk.WrapError(err, "giteaClient.pullRequest> Unable to get pull request %d of %s", number, fullname)
+	}
+	return pr, nil
+}
+
+func (pr PullRequest) toVCSPullRequest() sdk.VCSPullRequest {
+	return sdk.VCSPullRequest{
+		Base: pr.Base.toVCSPushEvent(pr),
+		Head: pr.Head.toVCSPushEvent(pr),
+		URL:  pr.HTMLURL,
+		User: sdk.VCSAuthor{
+			Avatar:      pr.User.AvatarURL,
+			DisplayName: pr.User.Login,
+			Name:        pr.User.FullName,
+			Email:       pr.User.Email,
+		},
+	}
+}
+
+func (b PRBranchInfo) toVCSPushEvent(pr PullRequest) sdk.VCSPushEvent {
+	return sdk.VCSPushEvent{
+		Repo: b.Repo.FullName,
+		Branch: sdk.VCSBranch{
+			ID:           b.Ref,
+			DisplayID:    b.Ref,
+			LatestCommit: b.Sha,
+		},
+		CloneURL: b.Repo.CloneURL,
+		Commit: sdk.VCSCommit{
+			Author: sdk.VCSAuthor{
+				Avatar:      pr.User.AvatarURL,
+				DisplayName: pr.User.Login,
+				Name:        pr.User.FullName,
+			},
+			Hash:      b.Sha,
+			Message:   b.Label,
+			Timestamp: pr.Updated.Unix() * 1000,
+		},
+	}
+}
@@ -0,0 +1,57 @@
+package gitea
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// Release creates a release on Gitea
+// https://try.gitea.io/api/swagger#/repository/repoCreateRelease
+func (c *giteaClient) Release(fullname string, tagName string, title string, releaseNote string) (*sdk.VCSRelease, error) {
+	req := ReleaseRequest{
+		TagName: tagName,
+		Name:    title,
+		Body:    releaseNote,
+	}
+	var release Release
+	if err := c.send(http.MethodPost, "/repos/"+fullname+"/releases", req, &release); err != nil {
+		return nil, sdk.WrapError(err, "giteaClient.Release> Unable to create release %s on %s", tagName, fullname)
+	}
+
+	return &sdk.VCSRelease{
+		ID:        release.ID,
+		UploadURL: fmt.Sprintf("%s/repos/%s/releases/%d/assets", c.apiURL, fullname, release.ID),
+	}, nil
+}
+
+// UploadReleaseFile attaches a file to a release
+// https://try.gitea.io/api/swagger#/repository/repoCreateReleaseAttachment
+func (c *giteaClient) UploadReleaseFile(repo string, releaseName string, uploadURL string, artifactName string, r io.ReadCloser) error {
+	defer r.Close()
+
+	body := new(bytes.Buffer)
+	w := multipart.NewWriter(body)
+	part, err := w.CreateFormFile("attachment", artifactName)
+	if err != nil {
+		return sdk.WrapError(err, "giteaClient.UploadReleaseFile> Unable to create form")
+	}
+	if _, err := io.Copy(part, r); err != nil {
+		return sdk.WrapError(err, "giteaClient.UploadReleaseFile> Unable to read file %s", artifactName)
+	}
+	if err := w.Close(); err != nil {
+		return sdk.WrapError(err, "giteaClient.UploadReleaseFile> Unable to create form")
+	}
+
+	path := strings.Split(uploadURL, "?")[0] + "?name=" + url.QueryEscape(artifactName)
+	if _, err := c.do(http.MethodPost, path, w.FormDataContentType(), body, nil); err != nil {
+		return sdk.WrapError(err, "giteaClient.UploadReleaseFile> Unable to upload file %s on release %s of %s", artifactName, releaseName, repo)
+	}
+	return nil
+}
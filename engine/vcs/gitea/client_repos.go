@@ -0,0 +1,59 @@
+package gitea
+
+import (
+	"strconv"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// Repos list repositories that are accessible to the authenticated user
+// https://try.gitea.io/api/swagger#/user/userCurrentListRepos
+func (c *giteaClient) Repos() ([]sdk.VCSRepo, error) {
+	var repos []Repository
+	for page := 1; ; page++ {
+		next := []Repository{}
+		if err := c.getPage("/user/repos", nil, page, &next); err != nil {
+			return nil, sdk.WrapError(err, "giteaClient.Repos> Unable to list repositories")
+		}
+		repos = append(repos, next...)
+		if len(next) < pageLimit {
+			break
+		}
+	}
+
+	responseRepos := make([]sdk.VCSRepo, 0, len(repos))
+	for _, repo := range repos {
+		responseRepos = append(responseRepos, repo.toVCSRepo())
+	}
+	return responseRepos, nil
+}
+
+// RepoByFullname Get only one repo
+// https://try.gitea.io/api/swagger#/repository/repoGet
+func (c *giteaClient) RepoByFullname(fullname string) (sdk.VCSRepo, error) {
+	repo, err := c.repoByFullname(fullname)
+	if err != nil {
+		return sdk.VCSRepo{}, err
+	}
+	return repo.toVCSRepo(), nil
+}
+
+func (c *giteaClient) repoByFullname(fullname string) (Repository, error) {
+	repo := Repository{}
+	if err := c.get("/repos/"+fullname, &repo); err != nil {
+		return repo, sdk.WrapError(err, "giteaClient.RepoByFullname> Unable to get repository %s", fullname)
+	}
+	return repo, nil
+}
+
+func (r Repository) toVCSRepo() sdk.VCSRepo {
+	return sdk.VCSRepo{
+		ID:           strconv.FormatInt(r.ID, 10),
+		Name:         r.Name,
+		Slug:         r.Owner.Login,
+		Fullname:     r.FullName,
+		URL:          r.HTMLURL,
+		HTTPCloneURL: r.CloneURL,
+		SSHCloneURL:  r.SSHURL,
+	}
+}
@@ -0,0 +1,80 @@
+package gitea
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+func getGiteaStateFromStatus(s sdk.Status) string {
+	switch s {
+	case sdk.StatusWaiting, sdk.StatusChecking, sdk.StatusBuilding:
+		return "pending"
+	case sdk.StatusSuccess:
+		return "success"
+	case sdk.StatusFail:
+		return "failure"
+	case sdk.StatusUnknown:
+		return "error"
+	}
+	return "warning"
+}
+
+//SetStatus creates a commit status
+//https://try.gitea.io/api/swagger#/repository/repoCreateStatus
+func (c *giteaClient) SetStatus(event sdk.Event) error {
+	var eventpb sdk.EventPipelineBuild
+	if event.EventType != fmt.Sprintf("%T", sdk.EventPipelineBuild{}) {
+		return nil
+	}
+
+	if c.DisableStatus {
+		log.Warning("⚠ Gitea statuses are disabled")
+		return nil
+	}
+
+	if err := mapstructure.Decode(event.Payload, &eventpb); err != nil {
+		return err
+	}
+
+	log.Debug("Process event:%+v", event)
+
+	//These statuses are not sent
+	if eventpb.Status == sdk.StatusDisabled ||
+		eventpb.Status == sdk.StatusNeverBuilt ||
+		eventpb.Status == sdk.StatusSkipped {
+		return nil
+	}
+
+	targetURL := fmt.Sprintf("%s/project/%s/application/%s/pipeline/%s/build/%d?envName=%s",
+		c.uiURL,
+		eventpb.ProjectKey,
+		eventpb.ApplicationName,
+		eventpb.PipelineName,
+		eventpb.BuildNumber,
+		url.QueryEscape(eventpb.EnvironmentName),
+	)
+
+	//CDS can avoid sending the target url in status, if it's disable
+	if c.DisableStatusDetail {
+		targetURL = ""
+	}
+
+	status := CreateStatus{
+		State:       getGiteaStateFromStatus(eventpb.Status),
+		TargetURL:   targetURL,
+		Description: fmt.Sprintf("Build #%d %s-%s-%s: %s", eventpb.BuildNumber, eventpb.ProjectKey, eventpb.ApplicationName, eventpb.PipelineName, eventpb.Status.String()),
+		Context:     fmt.Sprintf("continuous-delivery/CDS/%s", eventpb.PipelineName),
+	}
+
+	path := fmt.Sprintf("/repos/%s/statuses/%s", eventpb.RepositoryFullname, eventpb.Hash)
+	if err := c.send(http.MethodPost, path, status, nil); err != nil {
+		return sdk.WrapError(err, "giteaClient.SetStatus> Unable to create status on %s", path)
+	}
+	return nil
+}
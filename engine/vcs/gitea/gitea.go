@@ -0,0 +1,45 @@
+package gitea
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// giteaClient is a Gitea (or Gogs) wrapper for CDS vcs. interface
+type giteaClient struct {
+	OAuthToken          string
+	DisableStatus       bool
+	DisableStatusDetail bool
+	apiURL              string
+	uiURL               string
+	consumer            *giteaConsumer
+	tokenMutex          sync.Mutex
+	refreshToken        string
+	expiresAt           time.Time
+}
+
+// giteaConsumer implements vcs.Server and it's used to instanciate a giteaClient
+type giteaConsumer struct {
+	URL                      string `json:"url"`
+	ClientID                 string `json:"client-id"`
+	ClientSecret             string `json:"-"`
+	AuthorizationCallbackURL string
+	uiURL                    string
+	disableStatus            bool
+	disableStatusDetail      bool
+}
+
+// New creates a new GiteaConsumer
+func New(ClientID, ClientSecret, URL, callbackURL, uiURL string, disableStatus, disableStatusDetail bool) sdk.VCSServer {
+	return &giteaConsumer{
+		URL:                      URL,
+		ClientID:                 ClientID,
+		ClientSecret:             ClientSecret,
+		AuthorizationCallbackURL: callbackURL,
+		uiURL:                    uiURL,
+		disableStatus:            disableStatus,
+		disableStatusDetail:      disableStatusDetail,
+	}
+}
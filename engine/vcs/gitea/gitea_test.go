@@ -0,0 +1,482 @@
+package gitea
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/sdk"
+)
+
+// newTestServer returns a fake Gitea server. Its API routes check the access token of the client.
+func newTestServer(t *testing.T, routes map[string]http.HandlerFunc) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/api/v1/") && r.Header.Get("Authorization") != "token mytoken" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		h, ok := routes[r.Method+" "+r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprintf(w, `{"message": "%s %s not found"}`, r.Method, r.URL.Path)
+			return
+		}
+		h(w, r)
+	}))
+}
+
+func writeJSON(t *testing.T, w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	test.NoError(t, json.NewEncoder(w).Encode(v))
+}
+
+func newTestClient(t *testing.T, srv *httptest.Server) sdk.VCSAuthorizedClient {
+	consumer := New("myclient", "mysecret", srv.URL, "http://cds/repositories_manager/oauth2/callback", "http://cds-ui", false, false)
+	client, err := consumer.GetAuthorizedClient("mytoken", "")
+	test.NoError(t, err)
+	return client
+}
+
+var testRepo = Repository{
+	ID:            42,
+	Owner:         User{Login: "cds"},
+	Name:          "myrepo",
+	FullName:      "cds/myrepo",
+	HTMLURL:       "https://gitea.local/cds/myrepo",
+	CloneURL:      "https://gitea.local/cds/myrepo.git",
+	SSHURL:        "git@gitea.local:cds/myrepo.git",
+	DefaultBranch: "master",
+}
+
+func TestAuthorize(t *testing.T) {
+	srv := newTestServer(t, map[string]http.HandlerFunc{
+		"POST /login/oauth/access_token": func(w http.ResponseWriter, r *http.Request) {
+			test.NoError(t, r.ParseForm())
+			assert.Equal(t, "myclient", r.Form.Get("client_id"))
+			assert.Equal(t, "mysecret", r.Form.Get("client_secret"))
+			assert.Equal(t, "authorization_code", r.Form.Get("grant_type"))
+			if r.Form.Get("code") != "mycode" {
+				writeJSON(t, w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+				return
+			}
+			writeJSON(t, w, http.StatusOK, authorizeResponse{AccessToken: "mytoken", TokenType: "bearer", ExpiresIn: 3600, RefreshToken: "myrefreshtoken"})
+		},
+	})
+	defer srv.Close()
+	consumer := New("myclient", "mysecret", srv.URL, "http://cds/repositories_manager/oauth2/callback", "http://cds-ui", false, false)
+
+	state, authorizeURL, err := consumer.AuthorizeRedirect()
+	test.NoError(t, err)
+	u, err := url.Parse(authorizeURL)
+	test.NoError(t, err)
+	assert.Equal(t, "/login/oauth/authorize", u.Path)
+	assert.Equal(t, "myclient", u.Query().Get("client_id"))
+	assert.Equal(t, "code", u.Query().Get("response_type"))
+	assert.Equal(t, state, u.Query().Get("state"))
+
+	token, secret, err := consumer.AuthorizeToken(state, "mycode")
+	test.NoError(t, err)
+	assert.Equal(t, "mytoken", token)
+	var saved giteaToken
+	test.NoError(t, json.Unmarshal([]byte(secret), &saved))
+	assert.Equal(t, "myrefreshtoken", saved.RefreshToken)
+	assert.InDelta(t, time.Now().Add(time.Hour).Unix(), saved.ExpiresAt, 5)
+
+	_, _, err = consumer.AuthorizeToken(state, "badcode")
+	assert.Error(t, err)
+}
+
+func TestRefreshToken(t *testing.T) {
+	var refreshed int
+	srv := newTestServer(t, map[string]http.HandlerFunc{
+		"POST /login/oauth/access_token": func(w http.ResponseWriter, r *http.Request) {
+			test.NoError(t, r.ParseForm())
+			assert.Equal(t, "myclient", r.Form.Get("client_id"))
+			assert.Equal(t, "refresh_token", r.Form.Get("grant_type"))
+			assert.Equal(t, "myrefreshtoken", r.Form.Get("refresh_token"))
+			refreshed++
+			writeJSON(t, w, http.StatusOK, authorizeResponse{AccessToken: "mytoken", TokenType: "bearer", ExpiresIn: 3600, RefreshToken: "newrefreshtoken"})
+		},
+		"GET /api/v1/repos/cds/myrepo": func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(t, w, http.StatusOK, testRepo)
+		},
+	})
+	defer srv.Close()
+	consumer := New("myclient", "mysecret", srv.URL, "http://cds/repositories_manager/oauth2/callback", "http://cds-ui", false, false)
+
+	secret, err := json.Marshal(giteaToken{RefreshToken: "myrefreshtoken", ExpiresAt: time.Now().Add(-time.Minute).Unix()})
+	test.NoError(t, err)
+	client, err := consumer.GetAuthorizedClient("expiredtoken", string(secret))
+	test.NoError(t, err)
+
+	//The expired token is refreshed once
+	for i := 0; i < 2; i++ {
+		repo, err := client.RepoByFullname("cds/myrepo")
+		test.NoError(t, err)
+		assert.Equal(t, "42", repo.ID)
+	}
+	assert.Equal(t, 1, refreshed)
+}
+
+func TestRepos(t *testing.T) {
+	srv := newTestServer(t, map[string]http.HandlerFunc{
+		"GET /api/v1/user/repos": func(w http.ResponseWriter, r *http.Request) {
+			//The first page is full, the second one is the last
+			repos := []Repository{}
+			if r.URL.Query().Get("page") == "1" {
+				for i := 0; i < pageLimit; i++ {
+					repos = append(repos, Repository{ID: int64(i + 100), FullName: fmt.Sprintf("cds/repo%d", i)})
+				}
+			} else {
+				repos = append(repos, testRepo)
+			}
+			writeJSON(t, w, http.StatusOK, repos)
+		},
+		"GET /api/v1/repos/cds/myrepo": func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(t, w, http.StatusOK, testRepo)
+		},
+	})
+	defer srv.Close()
+	client := newTestClient(t, srv)
+
+	repos, err := client.Repos()
+	test.NoError(t, err)
+	assert.Len(t, repos, pageLimit+1)
+	assert.Equal(t, "cds/myrepo", repos[pageLimit].Fullname)
+
+	repo, err := client.RepoByFullname("cds/myrepo")
+	test.NoError(t, err)
+	assert.Equal(t, "42", repo.ID)
+	assert.Equal(t, "cds", repo.Slug)
+	assert.Equal(t, "https://gitea.local/cds/myrepo.git", repo.HTTPCloneURL)
+	assert.Equal(t, "git@gitea.local:cds/myrepo.git", repo.SSHCloneURL)
+
+	_, err = client.RepoByFullname("cds/unknown")
+	assert.Error(t, err)
+}
+
+func TestBranchesAndCommits(t *testing.T) {
+	commits := []Commit{}
+	for i := 5; i > 0; i-- {
+		c := Commit{SHA: fmt.Sprintf("sha%d", i), HTMLURL: fmt.Sprintf("https://gitea.local/cds/myrepo/commit/sha%d", i)}
+		c.Commit.Message = fmt.Sprintf("commit %d", i)
+		c.Commit.Author = CommitUser{Name: "John Doe", Email: "john@gitea.local", Date: time.Unix(int64(1500000000+i), 0)}
+		c.Author = &User{Login: "john"}
+		commits = append(commits, c)
+	}
+
+	srv := newTestServer(t, map[string]http.HandlerFunc{
+		"GET /api/v1/repos/cds/myrepo": func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(t, w, http.StatusOK, testRepo)
+		},
+		"GET /api/v1/repos/cds/myrepo/branches": func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(t, w, http.StatusOK, []Branch{
+				{Name: "master", Commit: PayloadCommit{ID: "sha5"}},
+				{Name: "feat/foo", Commit: PayloadCommit{ID: "sha3"}},
+			})
+		},
+		"GET /api/v1/repos/cds/myrepo/branches/feat/foo": func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(t, w, http.StatusOK, Branch{Name: "feat/foo", Commit: PayloadCommit{ID: "sha3"}})
+		},
+		"GET /api/v1/repos/cds/myrepo/commits": func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "master", r.URL.Query().Get("sha"))
+			writeJSON(t, w, http.StatusOK, commits)
+		},
+		"GET /api/v1/repos/cds/myrepo/git/commits/sha4": func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(t, w, http.StatusOK, commits[1])
+		},
+	})
+	defer srv.Close()
+	client := newTestClient(t, srv)
+
+	branches, err := client.Branches("cds/myrepo")
+	test.NoError(t, err)
+	assert.Len(t, branches, 2)
+	assert.True(t, branches[0].Default)
+	assert.Equal(t, "sha5", branches[0].LatestCommit)
+	assert.False(t, branches[1].Default)
+
+	branch, err := client.Branch("cds/myrepo", "feat/foo")
+	test.NoError(t, err)
+	assert.Equal(t, "sha3", branch.LatestCommit)
+
+	_, err = client.Branch("cds/myrepo", "unknown")
+	assert.Error(t, err)
+
+	res, err := client.Commits("cds/myrepo", "master", "sha2", "")
+	test.NoError(t, err)
+	assert.Len(t, res, 3)
+	assert.Equal(t, "sha5", res[0].Hash)
+	assert.Equal(t, "sha3", res[2].Hash)
+
+	res, err = client.Commits("cds/myrepo", "master", "", "")
+	test.NoError(t, err)
+	assert.Len(t, res, 1)
+
+	commit, err := client.Commit("cds/myrepo", "sha4")
+	test.NoError(t, err)
+	assert.Equal(t, "commit 4", commit.Message)
+	assert.Equal(t, "john", commit.Author.Name)
+	assert.Equal(t, "John Doe", commit.Author.DisplayName)
+	assert.Equal(t, int64(1500000004000), commit.Timestamp)
+}
+
+func TestPullRequests(t *testing.T) {
+	pr := PullRequest{
+		ID:      1,
+		Number:  7,
+		HTMLURL: "https://gitea.local/cds/myrepo/pulls/7",
+		State:   "open",
+		User:    User{Login: "john"},
+		Head:    PRBranchInfo{Ref: "feat/foo", Sha: "sha3", Repo: testRepo},
+		Base:    PRBranchInfo{Ref: "master", Sha: "sha5", Repo: testRepo},
+	}
+	srv := newTestServer(t, map[string]http.HandlerFunc{
+		"GET /api/v1/repos/cds/myrepo/pulls": func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "open", r.URL.Query().Get("state"))
+			writeJSON(t, w, http.StatusOK, []PullRequest{pr})
+		},
+	})
+	defer srv.Close()
+	client := newTestClient(t, srv)
+
+	prs, err := client.PullRequests("cds/myrepo")
+	test.NoError(t, err)
+	assert.Len(t, prs, 1)
+	assert.Equal(t, "https://gitea.local/cds/myrepo/pulls/7", prs[0].URL)
+	assert.Equal(t, "feat/foo", prs[0].Head.Branch.ID)
+	assert.Equal(t, "sha5", prs[0].Base.Branch.LatestCommit)
+	assert.Equal(t, "john", prs[0].User.DisplayName)
+}
+
+func TestHooks(t *testing.T) {
+	hooks := map[int64]Hook{}
+	srv := newTestServer(t, map[string]http.HandlerFunc{
+		"POST /api/v1/repos/cds/myrepo/hooks": func(w http.ResponseWriter, r *http.Request) {
+			h := Hook{}
+			test.NoError(t, json.NewDecoder(r.Body).Decode(&h))
+			h.ID = int64(len(hooks) + 1)
+			hooks[h.ID] = h
+			writeJSON(t, w, http.StatusCreated, h)
+		},
+		"GET /api/v1/repos/cds/myrepo/hooks": func(w http.ResponseWriter, r *http.Request) {
+			res := []Hook{}
+			for _, h := range hooks {
+				res = append(res, h)
+			}
+			writeJSON(t, w, http.StatusOK, res)
+		},
+		"PATCH /api/v1/repos/cds/myrepo/hooks/1": func(w http.ResponseWriter, r *http.Request) {
+			h := Hook{}
+			test.NoError(t, json.NewDecoder(r.Body).Decode(&h))
+			h.ID = 1
+			hooks[1] = h
+			writeJSON(t, w, http.StatusOK, h)
+		},
+		"DELETE /api/v1/repos/cds/myrepo/hooks/1": func(w http.ResponseWriter, r *http.Request) {
+			delete(hooks, 1)
+			w.WriteHeader(http.StatusNoContent)
+		},
+	})
+	defer srv.Close()
+	client := newTestClient(t, srv)
+
+	hook := sdk.VCSHook{URL: "http://cds-hooks/webhook/uuid", Secret: "hooksecret"}
+	test.NoError(t, client.CreateHook("cds/myrepo", &hook))
+	assert.Equal(t, "1", hook.ID)
+	assert.Equal(t, "gitea", hooks[1].Type)
+	assert.Equal(t, "json", hooks[1].Config["content_type"])
+	assert.Equal(t, "hooksecret", hooks[1].Config["secret"])
+	assert.Equal(t, []string{"push"}, hooks[1].Events)
+	assert.True(t, hooks[1].Active)
+
+	h, err := client.GetHook("cds/myrepo", "http://cds-hooks/webhook/uuid")
+	test.NoError(t, err)
+	assert.Equal(t, "1", h.ID)
+	assert.False(t, h.Disable)
+
+	_, err = client.GetHook("cds/myrepo", "http://unknown")
+	assert.Error(t, err)
+
+	hook.Disable = true
+	test.NoError(t, client.UpdateHook("cds/myrepo", "http://cds-hooks/webhook/uuid", hook))
+	assert.False(t, hooks[1].Active)
+
+	test.NoError(t, client.DeleteHook("cds/myrepo", sdk.VCSHook{URL: "http://cds-hooks/webhook/uuid"}))
+	assert.Len(t, hooks, 0)
+}
+
+func TestEvents(t *testing.T) {
+	dateRef := time.Unix(1500000000, 0)
+	pr := PullRequest{
+		Number:  7,
+		State:   "open",
+		HTMLURL: "https://gitea.local/cds/myrepo/pulls/7",
+		Head:    PRBranchInfo{Ref: "feat/foo", Sha: "sha3", Repo: testRepo},
+		Base:    PRBranchInfo{Ref: "master", Sha: "sha2", Repo: testRepo},
+		Created: dateRef.Add(time.Minute),
+		Updated: dateRef.Add(time.Minute),
+	}
+	commit := Commit{SHA: "sha3"}
+	commit.Commit.Message = "my commit"
+
+	srv := newTestServer(t, map[string]http.HandlerFunc{
+		"GET /api/v1/repos/cds/myrepo": func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(t, w, http.StatusOK, testRepo)
+		},
+		"GET /api/v1/repos/cds/myrepo/branches": func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(t, w, http.StatusOK, []Branch{
+				{Name: "master", Commit: PayloadCommit{ID: "sha2", Timestamp: dateRef.Add(-time.Minute)}},
+				{Name: "feat/foo", Commit: PayloadCommit{ID: "sha3", Timestamp: dateRef.Add(time.Minute)}},
+			})
+		},
+		"GET /api/v1/repos/cds/myrepo/branches/feat/foo": func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(t, w, http.StatusOK, Branch{Name: "feat/foo", Commit: PayloadCommit{ID: "sha3"}})
+		},
+		"GET /api/v1/repos/cds/myrepo/git/commits/sha3": func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(t, w, http.StatusOK, commit)
+		},
+		"GET /api/v1/repos/cds/myrepo/pulls": func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(t, w, http.StatusOK, []PullRequest{pr})
+		},
+		"GET /api/v1/repos/cds/myrepo/pulls/7": func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(t, w, http.StatusOK, pr)
+		},
+	})
+	defer srv.Close()
+	client := newTestClient(t, srv)
+
+	events, interval, err := client.GetEvents("cds/myrepo", dateRef)
+	test.NoError(t, err)
+	assert.Equal(t, pollingInterval, interval)
+	assert.Len(t, events, 2)
+
+	//Events are sent to CDS API, and given back as maps
+	b, err := json.Marshal(events)
+	test.NoError(t, err)
+	events = []interface{}{}
+	test.NoError(t, json.Unmarshal(b, &events))
+
+	pushEvents, err := client.PushEvents("cds/myrepo", events)
+	test.NoError(t, err)
+	assert.Len(t, pushEvents, 1)
+	assert.Equal(t, "feat/foo", pushEvents[0].Branch.ID)
+	assert.Equal(t, "my commit", pushEvents[0].Commit.Message)
+
+	prEvents, err := client.PullRequestEvents("cds/myrepo", events)
+	test.NoError(t, err)
+	assert.Len(t, prEvents, 1)
+	assert.Equal(t, "opened", prEvents[0].Action)
+	assert.Equal(t, "feat/foo", prEvents[0].Head.Branch.ID)
+	assert.Equal(t, "master", prEvents[0].Base.Branch.ID)
+}
+
+func TestSetStatus(t *testing.T) {
+	var status CreateStatus
+	srv := newTestServer(t, map[string]http.HandlerFunc{
+		"POST /api/v1/repos/cds/myrepo/statuses/sha5": func(w http.ResponseWriter, r *http.Request) {
+			test.NoError(t, json.NewDecoder(r.Body).Decode(&status))
+			writeJSON(t, w, http.StatusCreated, status)
+		},
+	})
+	defer srv.Close()
+	client := newTestClient(t, srv)
+
+	evt := sdk.Event{
+		EventType: fmt.Sprintf("%T", sdk.EventPipelineBuild{}),
+		Payload: map[string]interface{}{
+			"ProjectKey":         "PROJ",
+			"ApplicationName":    "app",
+			"PipelineName":       "build",
+			"BuildNumber":        12,
+			"Status":             sdk.StatusSuccess,
+			"RepositoryFullname": "cds/myrepo",
+			"Hash":               "sha5",
+		},
+	}
+	test.NoError(t, client.SetStatus(evt))
+	assert.Equal(t, "success", status.State)
+	assert.Equal(t, "continuous-delivery/CDS/build", status.Context)
+	assert.Equal(t, "http://cds-ui/project/PROJ/application/app/pipeline/build/build/12?envName=", status.TargetURL)
+}
+
+func TestRelease(t *testing.T) {
+	var uploaded string
+	srv := newTestServer(t, map[string]http.HandlerFunc{
+		"POST /api/v1/repos/cds/myrepo/releases": func(w http.ResponseWriter, r *http.Request) {
+			req := ReleaseRequest{}
+			test.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			assert.Equal(t, "v1.0.0", req.TagName)
+			writeJSON(t, w, http.StatusCreated, Release{ID: 3, TagName: req.TagName, Name: req.Name})
+		},
+		"POST /api/v1/repos/cds/myrepo/releases/3/assets": func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "myfile.txt", r.URL.Query().Get("name"))
+			f, _, err := r.FormFile("attachment")
+			test.NoError(t, err)
+			b, err := ioutil.ReadAll(f)
+			test.NoError(t, err)
+			uploaded = string(b)
+			writeJSON(t, w, http.StatusCreated, map[string]interface{}{"id": 1, "name": "myfile.txt"})
+		},
+	})
+	defer srv.Close()
+	client := newTestClient(t, srv)
+
+	release, err := client.Release("cds/myrepo", "v1.0.0", "Release 1.0.0", "First release")
+	test.NoError(t, err)
+	assert.Equal(t, int64(3), release.ID)
+
+	err = client.UploadReleaseFile("cds/myrepo", "Release 1.0.0", release.UploadURL, "myfile.txt", ioutil.NopCloser(strings.NewReader("my content")))
+	test.NoError(t, err)
+	assert.Equal(t, "my content", uploaded)
+}
+
+func TestContents(t *testing.T) {
+	srv := newTestServer(t, map[string]http.HandlerFunc{
+		"GET /api/v1/repos/cds/myrepo/contents/.cds": func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "sha5", r.URL.Query().Get("ref"))
+			writeJSON(t, w, http.StatusOK, []Content{
+				{Type: "file", Name: "workflow.yml", Path: ".cds/workflow.yml", Size: 12},
+				{Type: "dir", Name: "pipelines", Path: ".cds/pipelines"},
+			})
+		},
+		"GET /api/v1/repos/cds/myrepo/contents/.cds/workflow.yml": func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(t, w, http.StatusOK, Content{
+				Type:     "file",
+				Name:     "workflow.yml",
+				Path:     ".cds/workflow.yml",
+				Size:     12,
+				Encoding: "base64",
+				Content:  "bmFtZTogbXl3\nb3JrZmxvdwo=",
+			})
+		},
+	})
+	defer srv.Close()
+	client := newTestClient(t, srv)
+
+	dir, err := client.Contents("cds/myrepo", "/.cds/", "sha5")
+	test.NoError(t, err)
+	assert.Equal(t, sdk.VCSContentTypeDirectory, dir.Type)
+	assert.Equal(t, ".cds", dir.Name)
+	assert.Len(t, dir.Entries, 2)
+	assert.Equal(t, sdk.VCSContentTypeFile, dir.Entries[0].Type)
+	assert.Equal(t, sdk.VCSContentTypeDirectory, dir.Entries[1].Type)
+
+	file, err := client.Contents("cds/myrepo", ".cds/workflow.yml", "sha5")
+	test.NoError(t, err)
+	assert.Equal(t, sdk.VCSContentTypeFile, file.Type)
+	assert.Equal(t, "name: myworkflow\n", string(file.Content))
+
+	_, err = client.Contents("cds/myrepo", "unknown", "sha5")
+	assert.Error(t, err)
+}
@@ -0,0 +1,146 @@
+package gitea
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/facebookgo/httpcontrol"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+//Number of items asked on each page of lists
+const pageLimit = 50
+
+var httpClient = &http.Client{
+	Transport: &httpcontrol.Transport{
+		RequestTimeout: time.Second * 30,
+		MaxTries:       5,
+	},
+}
+
+func (g *giteaConsumer) postForm(path string, data url.Values, headers map[string][]string) (int, []byte, error) {
+	body := strings.NewReader(data.Encode())
+
+	req, err := http.NewRequest(http.MethodPost, g.URL+path, body)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("User-Agent", "CDS-gitea_client_id="+g.ClientID)
+	for k, h := range headers {
+		for i := range h {
+			req.Header.Add(k, h[i])
+		}
+	}
+
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer res.Body.Close()
+	resBody, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return res.StatusCode, nil, err
+	}
+
+	return res.StatusCode, resBody, nil
+}
+
+// do performs a request on the Gitea API. The JSON response is unmarshalled in out, if it's not nil.
+func (c *giteaClient) do(method, path, contentType string, body io.Reader, out interface{}) (http.Header, error) {
+	if !strings.HasPrefix(path, c.apiURL) {
+		path = c.apiURL + path
+	}
+
+	token, err := c.accessToken()
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(method, path, body)
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("token %s", token))
+
+	log.Debug("Gitea API>> Request %s %s", method, req.URL.String())
+
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return nil, sdk.WrapError(err, "giteaClient.do> Cannot do request %s %s", method, path)
+	}
+	defer res.Body.Close()
+
+	resBody, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, sdk.WrapError(err, "giteaClient.do> Cannot read response of %s %s", method, path)
+	}
+
+	switch {
+	case res.StatusCode == http.StatusNotFound:
+		return nil, sdk.NewError(sdk.ErrNotFound, errorAPI(res.StatusCode, resBody))
+	case res.StatusCode == http.StatusUnauthorized, res.StatusCode == http.StatusForbidden:
+		return nil, sdk.NewError(sdk.ErrForbidden, errorAPI(res.StatusCode, resBody))
+	case res.StatusCode >= 400:
+		return nil, sdk.NewError(sdk.ErrUnknownError, errorAPI(res.StatusCode, resBody))
+	}
+
+	if out != nil && len(resBody) > 0 {
+		if err := json.Unmarshal(resBody, out); err != nil {
+			return nil, sdk.WrapError(err, "giteaClient.do> Unable to parse response of %s %s: %s", method, path, string(resBody))
+		}
+	}
+	return res.Header, nil
+}
+
+func (c *giteaClient) get(path string, out interface{}) error {
+	_, err := c.do(http.MethodGet, path, "", nil, out)
+	return err
+}
+
+func (c *giteaClient) send(method, path string, in, out interface{}) error {
+	b, err := json.Marshal(in)
+	if err != nil {
+		return sdk.WrapError(err, "giteaClient.send> Cannot marshal body %+v", in)
+	}
+	_, err = c.do(method, path, "application/json", bytes.NewBuffer(b), out)
+	return err
+}
+
+func (c *giteaClient) delete(path string) error {
+	_, err := c.do(http.MethodDelete, path, "", nil, nil)
+	return err
+}
+
+// getPage loads a page of a list, pages are numbered from 1
+func (c *giteaClient) getPage(path string, values url.Values, page int, out interface{}) error {
+	v := url.Values{}
+	for k := range values {
+		v[k] = values[k]
+	}
+	v.Set("limit", fmt.Sprintf("%d", pageLimit))
+	v.Set("page", fmt.Sprintf("%d", page))
+	return c.get(path+"?"+v.Encode(), out)
+}
+
+func errorAPI(status int, body []byte) error {
+	e := Error{}
+	if err := json.Unmarshal(body, &e); err == nil && e.Message != "" {
+		return fmt.Errorf("Gitea error (%d): %s", status, e.Message)
+	}
+	return fmt.Errorf("Gitea error (%d): %s", status, string(body))
+}
@@ -0,0 +1,179 @@
+package gitea
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+type authorizeResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+func generateHash() (string, error) {
+	bs := make([]byte, 64)
+	if _, err := rand.Read(bs); err != nil {
+		log.Error("generateID: rand.Read failed: %s\n", err)
+		return "", err
+	}
+	return hex.EncodeToString(bs), nil
+}
+
+//AuthorizeRedirect returns the request token, the Authorize URL
+//doc: https://docs.gitea.io/en-us/oauth2-provider/
+func (g *giteaConsumer) AuthorizeRedirect() (string, string, error) {
+	requestToken, err := generateHash()
+	if err != nil {
+		return "", "", err
+	}
+
+	val := url.Values{}
+	val.Add("client_id", g.ClientID)
+	val.Add("redirect_uri", g.AuthorizationCallbackURL)
+	val.Add("response_type", "code")
+	val.Add("state", requestToken)
+
+	authorizeURL := fmt.Sprintf("%s/login/oauth/authorize?%s", g.URL, val.Encode())
+	return requestToken, authorizeURL, nil
+}
+
+// giteaToken is saved by CDS as the secret of the access token. Gitea access tokens expire, they are refreshed with the refresh token.
+type giteaToken struct {
+	RefreshToken string `json:"refresh_token"`
+	ExpiresAt    int64  `json:"expires_at"`
+}
+
+//AuthorizeToken returns the authorized token (and its secret)
+//from the request token and the verifier got on authorize url
+func (g *giteaConsumer) AuthorizeToken(state, code string) (string, string, error) {
+	log.Debug("AuthorizeToken> Gitea send code %s for state %s", code, state)
+
+	params := url.Values{}
+	params.Add("code", code)
+	params.Add("grant_type", "authorization_code")
+	params.Add("redirect_uri", g.AuthorizationCallbackURL)
+
+	giteaResponse, err := g.requestToken(params)
+	if err != nil {
+		return "", "", err
+	}
+
+	secret, err := json.Marshal(giteaToken{
+		RefreshToken: giteaResponse.RefreshToken,
+		ExpiresAt:    tokenExpiration(giteaResponse.ExpiresIn).Unix(),
+	})
+	if err != nil {
+		return "", "", err
+	}
+	return giteaResponse.AccessToken, string(secret), nil
+}
+
+// refreshToken gets a new access token from a refresh token
+func (g *giteaConsumer) refreshToken(refreshToken string) (*authorizeResponse, error) {
+	params := url.Values{}
+	params.Add("refresh_token", refreshToken)
+	params.Add("grant_type", "refresh_token")
+	return g.requestToken(params)
+}
+
+func (g *giteaConsumer) requestToken(params url.Values) (*authorizeResponse, error) {
+	params.Add("client_id", g.ClientID)
+	params.Add("client_secret", g.ClientSecret)
+
+	headers := map[string][]string{}
+	headers["Accept"] = []string{"application/json"}
+
+	status, res, err := g.postForm("/login/oauth/access_token", params, headers)
+	if err != nil {
+		return nil, err
+	}
+
+	if status < 200 || status >= 400 {
+		return nil, fmt.Errorf("Gitea error (%d) %s ", status, string(res))
+	}
+
+	giteaResponse := authorizeResponse{}
+	if err := json.Unmarshal(res, &giteaResponse); err != nil {
+		return nil, fmt.Errorf("Unable to parse gitea response (%d) %s ", status, string(res))
+	}
+	if giteaResponse.AccessToken == "" {
+		return nil, fmt.Errorf("Gitea returned no access token (%d) %s ", status, string(res))
+	}
+	return &giteaResponse, nil
+}
+
+// tokenExpiration returns the expiration date of a token, zero if it doesn't expire
+func tokenExpiration(expiresIn int64) time.Time {
+	if expiresIn <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(time.Duration(expiresIn) * time.Second)
+}
+
+//keep client in memory
+var (
+	instancesAuthorizedClient      = map[string]*giteaClient{}
+	instancesAuthorizedClientMutex sync.Mutex
+)
+
+//GetAuthorizedClient returns an authorized client.
+//The secret contains the refresh token of the access token, links created before the refresh tokens were saved are not refreshed.
+//A refreshed access token is kept by the client in memory: the refresh token saved by CDS is used again after a restart.
+func (g *giteaConsumer) GetAuthorizedClient(accessToken, accessTokenSecret string) (sdk.VCSAuthorizedClient, error) {
+	instancesAuthorizedClientMutex.Lock()
+	defer instancesAuthorizedClientMutex.Unlock()
+
+	key := g.URL + "/" + accessToken
+	c, ok := instancesAuthorizedClient[key]
+	if !ok {
+		c = &giteaClient{
+			OAuthToken:          accessToken,
+			DisableStatus:       g.disableStatus,
+			DisableStatusDetail: g.disableStatusDetail,
+			apiURL:              strings.TrimSuffix(g.URL, "/") + "/api/v1",
+			uiURL:               g.uiURL,
+			consumer:            g,
+		}
+		var token giteaToken
+		if err := json.Unmarshal([]byte(accessTokenSecret), &token); err == nil {
+			c.refreshToken = token.RefreshToken
+			if token.ExpiresAt > 0 {
+				c.expiresAt = time.Unix(token.ExpiresAt, 0)
+			}
+		}
+		instancesAuthorizedClient[key] = c
+	}
+	return c, nil
+}
+
+// accessToken returns the access token of the client, refreshed if it has expired
+func (c *giteaClient) accessToken() (string, error) {
+	c.tokenMutex.Lock()
+	defer c.tokenMutex.Unlock()
+
+	if c.refreshToken == "" || c.expiresAt.IsZero() || time.Now().Add(time.Minute).Before(c.expiresAt) {
+		return c.OAuthToken, nil
+	}
+
+	res, err := c.consumer.refreshToken(c.refreshToken)
+	if err != nil {
+		return "", sdk.WrapError(err, "giteaClient.accessToken> Unable to refresh access token")
+	}
+	c.OAuthToken = res.AccessToken
+	if res.RefreshToken != "" {
+		c.refreshToken = res.RefreshToken
+	}
+	c.expiresAt = tokenExpiration(res.ExpiresIn)
+	return c.OAuthToken, nil
+}
@@ -0,0 +1,167 @@
+package gitea
+
+import (
+	"time"
+)
+
+// User represents a Gitea user
+type User struct {
+	ID        int64  `json:"id"`
+	Login     string `json:"login"`
+	FullName  string `json:"full_name"`
+	Email     string `json:"email"`
+	AvatarURL string `json:"avatar_url"`
+}
+
+// Repository represents a Gitea repository
+type Repository struct {
+	ID            int64  `json:"id"`
+	Owner         User   `json:"owner"`
+	Name          string `json:"name"`
+	FullName      string `json:"full_name"`
+	HTMLURL       string `json:"html_url"`
+	CloneURL      string `json:"clone_url"`
+	SSHURL        string `json:"ssh_url"`
+	DefaultBranch string `json:"default_branch"`
+}
+
+// PayloadUser represents the author or the committer of a commit
+type PayloadUser struct {
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	UserName string `json:"username"`
+}
+
+// PayloadCommit represents the last commit of a branch
+type PayloadCommit struct {
+	ID        string      `json:"id"`
+	Message   string      `json:"message"`
+	URL       string      `json:"url"`
+	Author    PayloadUser `json:"author"`
+	Committer PayloadUser `json:"committer"`
+	Timestamp time.Time   `json:"timestamp"`
+}
+
+// Branch represents a branch of a Gitea repository
+type Branch struct {
+	Name   string        `json:"name"`
+	Commit PayloadCommit `json:"commit"`
+}
+
+// CommitUser represents the author or the committer of a git commit
+type CommitUser struct {
+	Name  string    `json:"name"`
+	Email string    `json:"email"`
+	Date  time.Time `json:"date"`
+}
+
+// CommitMeta references a commit
+type CommitMeta struct {
+	URL string `json:"url"`
+	SHA string `json:"sha"`
+}
+
+// RepoCommit contains the git data of a commit
+type RepoCommit struct {
+	Message   string     `json:"message"`
+	Author    CommitUser `json:"author"`
+	Committer CommitUser `json:"committer"`
+}
+
+// Commit represents a commit of a Gitea repository
+type Commit struct {
+	SHA     string       `json:"sha"`
+	HTMLURL string       `json:"html_url"`
+	Commit  RepoCommit   `json:"commit"`
+	Author  *User        `json:"author"`
+	Parents []CommitMeta `json:"parents"`
+}
+
+// PRBranchInfo represents the head or the base of a pull request
+type PRBranchInfo struct {
+	Label string     `json:"label"`
+	Ref   string     `json:"ref"`
+	Sha   string     `json:"sha"`
+	Repo  Repository `json:"repo"`
+}
+
+// PullRequest represents a pull request of a Gitea repository
+type PullRequest struct {
+	ID      int64        `json:"id"`
+	Number  int64        `json:"number"`
+	HTMLURL string       `json:"html_url"`
+	State   string       `json:"state"`
+	Title   string       `json:"title"`
+	User    User         `json:"user"`
+	Head    PRBranchInfo `json:"head"`
+	Base    PRBranchInfo `json:"base"`
+	Created time.Time    `json:"created_at"`
+	Updated time.Time    `json:"updated_at"`
+}
+
+// Hook represents a webhook of a Gitea repository
+type Hook struct {
+	ID     int64             `json:"id,omitempty"`
+	Type   string            `json:"type"`
+	Config map[string]string `json:"config"`
+	Events []string          `json:"events"`
+	Active bool              `json:"active"`
+}
+
+// CreateStatus represents a commit status to create
+type CreateStatus struct {
+	State       string `json:"state"`
+	TargetURL   string `json:"target_url"`
+	Description string `json:"description"`
+	Context     string `json:"context"`
+}
+
+// ReleaseRequest represents a release to create
+type ReleaseRequest struct {
+	TagName string `json:"tag_name"`
+	Name    string `json:"name"`
+	Body    string `json:"body"`
+}
+
+// Release represents a release of a Gitea repository
+type Release struct {
+	ID      int64  `json:"id"`
+	TagName string `json:"tag_name"`
+	Name    string `json:"name"`
+	Body    string `json:"body"`
+	URL     string `json:"url"`
+}
+
+// Error represents an error returned by the Gitea API
+type Error struct {
+	Message string `json:"message"`
+	URL     string `json:"url"`
+}
+
+// Event is computed by polling the repository, as Gitea doesn't have an events API.
+// Its fields are scalars, as events are sent to CDS API and decoded from maps.
+type Event struct {
+	Type        string `json:"type"`
+	Timestamp   int64  `json:"timestamp"`
+	Ref         string `json:"ref"`
+	Sha         string `json:"sha"`
+	PullRequest int64  `json:"pull_request"`
+	Action      string `json:"action"`
+}
+
+// The types of the events computed by polling
+const (
+	PushEvent        = "push"
+	PullRequestEvent = "pull_request"
+)
+
+// Content represents a file or an entry of a directory returned by the contents API
+type Content struct {
+	Type     string `json:"type"`
+	Encoding string `json:"encoding"`
+	Size     int64  `json:"size"`
+	Name     string `json:"name"`
+	Path     string `json:"path"`
+	Content  string `json:"content"`
+	SHA      string `json:"sha"`
+}
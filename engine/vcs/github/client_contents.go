@@ -0,0 +1,85 @@
+package github
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// Contents returns a file with its content, or a directory with its entries
+// https://developer.github.com/v3/repos/contents/#get-contents
+func (g *githubClient) Contents(repo, path, ref string) (sdk.VCSContent, error) {
+	path = strings.Trim(path, "/")
+	u := "/repos/" + repo + "/contents/" + escapePath(path)
+	if ref != "" {
+		u += "?ref=" + url.QueryEscape(ref)
+	}
+
+	status, body, _, err := g.get(u, withoutETag)
+	if err != nil {
+		return sdk.VCSContent{}, sdk.WrapError(err, "githubClient.Contents> Unable to get %s on %s", path, repo)
+	}
+	if status == http.StatusNotFound {
+		return sdk.VCSContent{}, sdk.WrapError(sdk.ErrNotFound, "githubClient.Contents> %s not found on %s at %s", path, repo, ref)
+	}
+	if status >= 400 {
+		return sdk.VCSContent{}, sdk.NewError(sdk.ErrUnknownError, errorAPI(body))
+	}
+
+	//Github returns an array for directories
+	if strings.HasPrefix(strings.TrimSpace(string(body)), "[") {
+		entries := []Content{}
+		if err := json.Unmarshal(body, &entries); err != nil {
+			return sdk.VCSContent{}, sdk.WrapError(err, "githubClient.Contents> Unable to parse directory %s", path)
+		}
+		dir := sdk.VCSContent{
+			Name:    path[strings.LastIndex(path, "/")+1:],
+			Path:    path,
+			Type:    sdk.VCSContentTypeDirectory,
+			Entries: make([]sdk.VCSContent, 0, len(entries)),
+		}
+		for _, e := range entries {
+			dir.Entries = append(dir.Entries, e.toVCSContent())
+		}
+		return dir, nil
+	}
+
+	file := Content{}
+	if err := json.Unmarshal(body, &file); err != nil {
+		return sdk.VCSContent{}, sdk.WrapError(err, "githubClient.Contents> Unable to parse file %s", path)
+	}
+	content := file.toVCSContent()
+	if file.Encoding == "base64" {
+		b, err := base64.StdEncoding.DecodeString(strings.Replace(file.Content, "\n", "", -1))
+		if err != nil {
+			return sdk.VCSContent{}, sdk.WrapError(err, "githubClient.Contents> Unable to decode file %s", path)
+		}
+		content.Content = b
+	}
+	return content, nil
+}
+
+func (c Content) toVCSContent() sdk.VCSContent {
+	t := sdk.VCSContentTypeFile
+	if c.Type == "dir" {
+		t = sdk.VCSContentTypeDirectory
+	}
+	return sdk.VCSContent{
+		Name: c.Name,
+		Path: c.Path,
+		Type: t,
+		Size: c.Size,
+	}
+}
+
+func escapePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i := range segments {
+		segments[i] = url.PathEscape(segments[i])
+	}
+	return strings.Join(segments, "/")
+}
@@ -19,6 +19,7 @@ func (g *githubClient) CreateHook(repo string, hook *sdk.VCSHook) error {
 		Config: WebHookConfig{
 			URL:         hook.URL,
 			ContentType: "json",
+			Secret:      hook.Secret,
 		},
 	}
 	b, err := json.Marshal(r)
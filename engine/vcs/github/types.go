@@ -49,6 +49,7 @@ type WebhookCreate struct {
 type WebHookConfig struct {
 	URL         string `json:"url"`
 	ContentType string `json:"content_type"`
+	Secret      string `json:"secret,omitempty"`
 }
 
 // User represents a GitHub user.
@@ -499,3 +500,14 @@ type ReleaseResponse struct {
 	ID        int64  `json:"id"`
 	UploadURL string `json:"upload_url"`
 }
+
+// Content represents a file or an entry of a directory returned by the contents API
+type Content struct {
+	Type     string `json:"type"`
+	Encoding string `json:"encoding"`
+	Size     int64  `json:"size"`
+	Name     string `json:"name"`
+	Path     string `json:"path"`
+	Content  string `json:"content"`
+	Sha      string `json:"sha"`
+}
@@ -0,0 +1,87 @@
+package gitlab
+
+import (
+	"encoding/base64"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/xanzy/go-gitlab"
+
+	"github.com/ovh/cds/sdk"
+)
+
+//Contents returns a file with its content, or a directory with its entries
+func (c *gitlabClient) Contents(repo, path, ref string) (sdk.VCSContent, error) {
+	path = strings.Trim(path, "/")
+
+	//Gitlab needs a ref to get a file
+	if ref == "" {
+		p, _, err := c.client.Projects.GetProject(repo)
+		if err != nil {
+			return sdk.VCSContent{}, sdk.WrapError(err, "gitlabClient.Contents> Unable to get project %s", repo)
+		}
+		ref = p.DefaultBranch
+	}
+
+	if path != "" {
+		f, resp, err := c.client.RepositoryFiles.GetFile(repo, path, &gitlab.GetFileOptions{Ref: &ref})
+		if err == nil {
+			b, err := base64.StdEncoding.DecodeString(f.Content)
+			if err != nil {
+				return sdk.VCSContent{}, sdk.WrapError(err, "gitlabClient.Contents> Unable to decode file %s", path)
+			}
+			return sdk.VCSContent{
+				Name:    f.FileName,
+				Path:    f.FilePath,
+				Type:    sdk.VCSContentTypeFile,
+				Size:    int64(f.Size),
+				Content: b,
+			}, nil
+		}
+		//The path may be a directory
+		if resp == nil || resp.StatusCode != http.StatusNotFound {
+			return sdk.VCSContent{}, sdk.WrapError(err, "gitlabClient.Contents> Unable to get file %s on %s", path, repo)
+		}
+	}
+
+	dir := sdk.VCSContent{
+		Name:    path[strings.LastIndex(path, "/")+1:],
+		Path:    path,
+		Type:    sdk.VCSContentTypeDirectory,
+		Entries: []sdk.VCSContent{},
+	}
+	for page := 1; page != 0; {
+		nodes, resp, err := c.client.Repositories.ListTree(repo, &gitlab.ListTreeOptions{Path: &path, Ref: &ref}, withPage(page))
+		if err != nil {
+			if resp != nil && resp.StatusCode == http.StatusNotFound {
+				return sdk.VCSContent{}, sdk.WrapError(sdk.ErrNotFound, "gitlabClient.Contents> %s not found on %s at %s", path, repo, ref)
+			}
+			return sdk.VCSContent{}, sdk.WrapError(err, "gitlabClient.Contents> Unable to get directory %s on %s", path, repo)
+		}
+		for _, n := range nodes {
+			t := sdk.VCSContentTypeFile
+			if n.Type == "tree" {
+				t = sdk.VCSContentTypeDirectory
+			}
+			dir.Entries = append(dir.Entries, sdk.VCSContent{Name: n.Name, Path: n.Path, Type: t})
+		}
+		page = resp.NextPage
+	}
+
+	//Old Gitlab versions return an empty tree for unknown paths
+	if path != "" && len(dir.Entries) == 0 {
+		return sdk.VCSContent{}, sdk.WrapError(sdk.ErrNotFound, "gitlabClient.Contents> %s not found on %s at %s", path, repo, ref)
+	}
+	return dir, nil
+}
+
+func withPage(page int) gitlab.OptionFunc {
+	return func(req *http.Request) error {
+		q := req.URL.Query()
+		q.Set("page", strconv.Itoa(page))
+		q.Set("per_page", "100")
+		req.URL.RawQuery = q.Encode()
+		return nil
+	}
+}
@@ -42,6 +42,9 @@ func (c *gitlabClient) CreateHook(repo string, hook *sdk.VCSHook) error {
 		TagPushEvents:         &f,
 		EnableSSLVerification: &f,
 	}
+	if hook.Secret != "" {
+		opt.Token = &hook.Secret
+	}
 
 	log.Debug("GitlabClient.CreateHook: %s %s\n", repo, *opt.URL)
 	ph, resp, err := c.client.Projects.AddProjectHook(repo, &opt)
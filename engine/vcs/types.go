@@ -61,6 +61,8 @@ type ServerConfiguration struct {
 	Github    *GithubServerConfiguration    `toml:"github" json:"github,omitempty"`
 	Gitlab    *GitlabServerConfiguration    `toml:"gitlab" json:"gitlab,omitempty"`
 	Bitbucket *BitbucketServerConfiguration `toml:"bitbucket" json:"bitbucket,omitempty"`
+	Gitea     *GiteaServerConfiguration     `toml:"gitea" json:"gitea,omitempty"`
+	Git       *GitServerConfiguration       `toml:"git" json:"git,omitempty"`
 }
 
 // GithubServerConfiguration represents the github configuration
@@ -119,6 +121,36 @@ func (s BitbucketServerConfiguration) check() error {
 	return nil
 }
 
+// GiteaServerConfiguration represents the gitea (or gogs) configuration
+type GiteaServerConfiguration struct {
+	ClientID     string `toml:"clientId" json:"-" comment:"Gitea OAuth2 Application Client ID"`
+	ClientSecret string `toml:"clientSecret" json:"-" comment:"Gitea OAuth2 Application Client Secret"`
+	Status       struct {
+		Disable    bool `toml:"disable" default:"false" commented:"true" comment:"Set to true if you don't want CDS to push statuses on the VCS server" json:"disable"`
+		ShowDetail bool `toml:"showDetail" default:"false" commented:"true" comment:"Set to true if you don't want CDS to push CDS URL in statuses on the VCS server" json:"show_detail"`
+	}
+	DisableWebHooks bool `toml:"disableWebHooks" comment:"Does webhooks are supported by VCS Server" json:"disable_web_hook"`
+	DisablePolling  bool `toml:"disablePolling" comment:"Does polling is supported by VCS Server" json:"disable_polling"`
+}
+
+func (s GiteaServerConfiguration) check() error {
+	if s.ClientID == "" || s.ClientSecret == "" {
+		return errGiteaConfigurationError
+	}
+	return nil
+}
+
+var errGiteaConfigurationError = fmt.Errorf("Gitea configuration Error")
+
+// GitServerConfiguration represents the configuration of a plain git server, reached over SSH without any API
+type GitServerConfiguration struct {
+	MirrorsDirectory string `toml:"mirrorsDirectory" json:"-" comment:"Directory where the mirrors of the repositories are cached. Default is a directory in the temporary directory of the system"`
+}
+
+func (s GitServerConfiguration) check() error {
+	return nil
+}
+
 func (s *Service) addServerConfiguration(name string, c ServerConfiguration) error {
 	if name == "" {
 		return fmt.Errorf("Invalid VCS server name")
@@ -162,5 +194,17 @@ func (s ServerConfiguration) check() error {
 		}
 	}
 
+	if s.Gitea != nil {
+		if err := s.Gitea.check(); err != nil {
+			return err
+		}
+	}
+
+	if s.Git != nil {
+		if err := s.Git.check(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
@@ -11,6 +11,8 @@ import (
 	"github.com/ovh/cds/engine/api"
 	"github.com/ovh/cds/engine/api/cache"
 	"github.com/ovh/cds/engine/vcs/bitbucket"
+	"github.com/ovh/cds/engine/vcs/git"
+	"github.com/ovh/cds/engine/vcs/gitea"
 	"github.com/ovh/cds/engine/vcs/github"
 	"github.com/ovh/cds/engine/vcs/gitlab"
 	"github.com/ovh/cds/sdk"
@@ -68,6 +70,12 @@ func (s *Service) getConsumer(name string) (sdk.VCSServer, error) {
 	if serverCfg.Gitlab != nil {
 		return gitlab.New(serverCfg.Gitlab.AppID, serverCfg.Gitlab.Secret, serverCfg.URL, s.Cfg.API.HTTP.URL+"/repositories_manager/oauth2/callback", s.Cfg.UI.HTTP.URL, s.Cache), nil
 	}
+	if serverCfg.Gitea != nil {
+		return gitea.New(serverCfg.Gitea.ClientID, serverCfg.Gitea.ClientSecret, serverCfg.URL, s.Cfg.API.HTTP.URL+"/repositories_manager/oauth2/callback", s.Cfg.UI.HTTP.URL, serverCfg.Gitea.Status.Disable, !serverCfg.Gitea.Status.ShowDetail), nil
+	}
+	if serverCfg.Git != nil {
+		return git.New(serverCfg.URL, serverCfg.Git.MirrorsDirectory), nil
+	}
 	return nil, sdk.ErrNotFound
 }
 
@@ -2,15 +2,20 @@ package vcs
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"net/http"
 	"net/url"
+	"regexp"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gorilla/mux"
 
 	"github.com/ovh/cds/engine/api"
+	"github.com/ovh/cds/engine/api/cache"
 	"github.com/ovh/cds/engine/vcs/github"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
@@ -64,6 +69,12 @@ func (s *Service) getVCSServersHooksHandler() api.Handler {
 			res.WebhooksSupported = true
 			res.WebhooksDisabled = cfg.Gitlab.DisableWebHooks
 			res.WebhooksIcon = sdk.GitlabIcon
+		case cfg.Gitea != nil:
+			res.WebhooksSupported = true
+			res.WebhooksDisabled = cfg.Gitea.DisableWebHooks
+			res.WebhooksIcon = sdk.GiteaIcon
+		case cfg.Git != nil:
+			res.WebhooksSupported = false
 		}
 
 		return api.WriteJSON(w, r, res, http.StatusOK)
@@ -92,6 +103,11 @@ func (s *Service) getVCSServersPollingHandler() api.Handler {
 		case cfg.Gitlab != nil:
 			res.PollingSupported = false
 			res.PollingDisabled = cfg.Gitlab.DisablePolling
+		case cfg.Gitea != nil:
+			res.PollingSupported = true
+			res.PollingDisabled = cfg.Gitea.DisablePolling
+		case cfg.Git != nil:
+			res.PollingSupported = false
 		}
 
 		return api.WriteJSON(w, r, res, http.StatusOK)
@@ -683,3 +699,89 @@ func (s *Service) deleteHookHandler() api.Handler {
 		return client.DeleteHook(fmt.Sprintf("%s/%s", owner, repo), hook)
 	}
 }
+
+// contentsCacheTTL is the time contents are kept in cache. Contents are only cached when their ref is resolved to a commit hash
+const contentsCacheTTL = 60 * 60
+
+var commitHashRegexp = regexp.MustCompile("^[0-9a-f]{40}$")
+
+func (s *Service) getContentsHandler() api.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		name := muxVar(r, "name")
+		owner := muxVar(r, "owner")
+		repo := muxVar(r, "repo")
+		path := strings.Trim(muxVar(r, "path"), "/")
+		ref := r.URL.Query().Get("ref")
+
+		accessToken, accessTokenSecret, ok := getAccessTokens(ctx)
+		if !ok {
+			return sdk.WrapError(sdk.ErrUnauthorized, "VCS> getContentsHandler> Unable to get access token headers")
+		}
+
+		consumer, err := s.getConsumer(name)
+		if err != nil {
+			return sdk.WrapError(err, "VCS> getContentsHandler> VCS server unavailable")
+		}
+
+		client, err := consumer.GetAuthorizedClient(accessToken, accessTokenSecret)
+		if err != nil {
+			return sdk.WrapError(err, "VCS> getContentsHandler> Unable to get authorized client")
+		}
+
+		fullname := fmt.Sprintf("%s/%s", owner, repo)
+		hash := resolveCommitHash(client, fullname, ref)
+		if hash != "" {
+			ref = hash
+		}
+
+		//Contents at a given commit never change. The hash of the access token is part of the key, so that the cache doesn't
+		//bypass the permissions on the repository, without storing the token in the cache
+		tokenHash := sha256.Sum256([]byte(accessToken))
+		cacheKey := cache.Key("vcs", name, "contents", hex.EncodeToString(tokenHash[:]), fullname, hash, path)
+		var content sdk.VCSContent
+		if hash != "" && s.Cache.Get(cacheKey, &content) {
+			return api.WriteJSON(w, r, content, http.StatusOK)
+		}
+
+		content, err = client.Contents(fullname, path, ref)
+		if err != nil {
+			return sdk.WrapError(err, "VCS> getContentsHandler> Unable to get %s on repo %s at %s", path, fullname, ref)
+		}
+
+		if hash != "" {
+			s.Cache.SetWithTTL(cacheKey, content, contentsCacheTTL)
+		}
+		return api.WriteJSON(w, r, content, http.StatusOK)
+	}
+}
+
+// resolveCommitHash returns the commit hash of a ref: the ref itself if it's a commit hash, the latest commit of the branch,
+// or of the default branch if ref is empty. It returns an empty string if the ref can't be resolved, as tags
+func resolveCommitHash(client sdk.VCSAuthorizedClient, fullname, ref string) string {
+	if commitHashRegexp.MatchString(ref) {
+		return ref
+	}
+
+	if ref == "" {
+		branches, err := client.Branches(fullname)
+		if err != nil {
+			return ""
+		}
+		for _, b := range branches {
+			if b.Default {
+				return b.LatestCommit
+			}
+		}
+		return ""
+	}
+
+	b, err := client.Branch(fullname, ref)
+	if err != nil || b == nil {
+		return ""
+	}
+	//Some repositories managers filter branches by prefix
+	if b.ID != ref && b.DisplayID != ref {
+		return ""
+	}
+	return b.LatestCommit
+}
@@ -19,6 +19,8 @@ func init() {
 	mapBuiltinActions[sdk.ArtifactDownload] = runArtifactDownload
 	mapBuiltinActions[sdk.ScriptAction] = runScriptAction
 	mapBuiltinActions[sdk.JUnitAction] = runParseJunitTestResultAction
+	mapBuiltinActions[sdk.CoverageAction] = runParseCoverageResultAction
+	mapBuiltinActions[sdk.AnalysisAction] = runParseAnalysisResultAction
 	mapBuiltinActions[sdk.GitCloneAction] = runGitClone
 	mapBuiltinActions[sdk.GitTagAction] = runGitTag
 	mapBuiltinActions[sdk.ReleaseAction] = runRelease
@@ -70,15 +72,34 @@ func (w *currentWorker) runPlugin(ctx context.Context, a *sdk.Action, buildID in
 		log.Info("runPlugin> End %p (%s)", ctx, ctx.Err())
 	}()
 
+	//The binary of a plugin run by worker exec is in the basedir, otherwise the version checked by the requirements of the job is run
+	pluginBinary := path.Join(w.basedir, a.Name)
+	if !w.local.enabled {
+		ap, binary, err := w.installPlugin(a.Name, a.PluginVersion)
+		if err != nil {
+			res := sdk.Result{
+				Status: sdk.StatusFail.String(),
+				Reason: fmt.Sprintf("Unable to install plugin %s: %s\n", sdk.PluginRef(a.Name, a.PluginVersion), err),
+			}
+			sendLog(res.Reason)
+			return res
+		}
+		if ap.Deprecated {
+			sendLog(fmt.Sprintf("Warning: version %s of plugin %s is deprecated\n", ap.Version, a.Name))
+		}
+		if ap.IsImage() {
+			return w.runPluginImage(ctx, a, ap.Image, *params, sendLog)
+		}
+		pluginBinary = binary
+	}
+
 	chanRes := make(chan sdk.Result, 1)
 
 	go func(buildID int64, params []sdk.Parameter) {
 		res := sdk.Result{Status: sdk.StatusFail.String()}
 
-		//For the moment we consider that plugin name = action name = plugin binary file name
+		//For the moment we consider that plugin name = action name
 		pluginName := a.Name
-		//The binary file has been downloaded during requirement check in /tmp
-		pluginBinary := path.Join(w.basedir, a.Name)
 
 		var tlsskipverify bool
 		if os.Getenv("CDS_SKIP_VERIFY") != "" {
@@ -97,7 +118,7 @@ func (w *currentWorker) runPlugin(ctx context.Context, a *sdk.Action, buildID in
 			env = append(env, fmt.Sprintf("%s=%s", envName, p.Value))
 		}
 
-		for _, p := range w.currentJob.buildVariables {
+		for _, p := range w.getBuildVariables() {
 			envName := strings.Replace(p.Name, ".", "_", -1)
 			envName = strings.ToUpper(envName)
 			env = append(env, fmt.Sprintf("%s=%s", envName, p.Value))
@@ -137,7 +158,7 @@ func (w *currentWorker) runPlugin(ctx context.Context, a *sdk.Action, buildID in
 				pluginSecrets.Data[p.Name] = p.Value
 			}
 		}
-		for _, v := range w.currentJob.buildVariables {
+		for _, v := range w.getBuildVariables() {
 			pluginArgs.Data[v.Name] = v.Value
 		}
 
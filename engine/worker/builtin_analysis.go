@@ -0,0 +1,351 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func runParseAnalysisResultAction(w *currentWorker) BuiltInAction {
+	return func(ctx context.Context, a *sdk.Action, buildID int64, params *[]sdk.Parameter, sendLog LoggerFunc) sdk.Result {
+		var res sdk.Result
+		res.Status = sdk.StatusFail.String()
+
+		p := sdk.ParameterValue(a.Parameters, "path")
+		if p == "" {
+			res.Reason = fmt.Sprintf("Static analysis parser: path not provided")
+			sendLog(res.Reason)
+			return res
+		}
+		format := strings.ToLower(strings.TrimSpace(sdk.ParameterValue(a.Parameters, "format")))
+
+		failOn := strings.ToLower(strings.TrimSpace(sdk.ParameterValue(a.Parameters, "failOn")))
+		if failOn != "" && sdk.SeverityLevel(failOn) < 0 {
+			res.Reason = fmt.Sprintf("Static analysis parser: invalid failOn severity %s", failOn)
+			sendLog(res.Reason)
+			return res
+		}
+		onlyNew := sdk.ParameterValue(a.Parameters, "onlyNew") != "false"
+
+		files, errg := filepath.Glob(p)
+		if errg != nil {
+			res.Reason = fmt.Sprintf("Static analysis parser: Cannot find requested files, invalid pattern")
+			sendLog(res.Reason)
+			return res
+		}
+		if len(files) == 0 {
+			res.Reason = fmt.Sprintf("Static analysis parser: no file matches %s", p)
+			sendLog(res.Reason)
+			return res
+		}
+
+		sendLog(fmt.Sprintf("%d", len(files)) + " file(s) to analyze")
+
+		// the paths of the findings are made relative to the workspace, which changes at each run
+		workspace, errw := os.Getwd()
+		if errw != nil {
+			res.Reason = fmt.Sprintf("Static analysis parser: cannot get workspace (%s)", errw)
+			sendLog(res.Reason)
+			return res
+		}
+
+		var report sdk.AnalysisReport
+		for _, f := range files {
+			data, errRead := ioutil.ReadFile(f)
+			if errRead != nil {
+				res.Reason = fmt.Sprintf("Static analysis parser: cannot read file %s (%s)", f, errRead)
+				sendLog(res.Reason)
+				return res
+			}
+
+			fileReport, errP := parseAnalysis(format, data, workspace)
+			if errP != nil {
+				res.Reason = fmt.Sprintf("Static analysis parser: cannot parse file %s (%s)", f, errP)
+				sendLog(res.Reason)
+				return res
+			}
+			report.Merge(fileReport)
+		}
+
+		if w.currentJob.wJob != nil {
+			data, err := json.Marshal(report)
+			if err != nil {
+				res.Reason = fmt.Sprintf("Static analysis parser: failed to send findings: %s", err)
+				sendLog(res.Reason)
+				return res
+			}
+
+			uri := fmt.Sprintf("/queue/workflows/%d/findings", w.currentJob.wJob.ID)
+			btes, code, err := sdk.Request("POST", uri, data)
+			if err == nil && code >= 300 {
+				err = fmt.Errorf("HTTP %d", code)
+			}
+			if err == nil {
+				err = json.Unmarshal(btes, &report)
+			}
+			if err != nil {
+				res.Reason = fmt.Sprintf("Static analysis parser: failed to send findings: %s", err)
+				sendLog(res.Reason)
+				return res
+			}
+		} else {
+			// without reference run, no finding is new
+			report.FlagNew(nil)
+		}
+
+		var nbNew int
+		for _, f := range report.Findings {
+			if f.New {
+				nbNew++
+			}
+		}
+		sendLog(fmt.Sprintf("Static analysis parser: %d finding(s), %d new", len(report.Findings), nbNew))
+
+		if failOn == "" {
+			res.Status = sdk.StatusSuccess.String()
+			return res
+		}
+
+		failures := report.Filter(failOn, onlyNew)
+		for _, f := range failures {
+			sendLog(fmt.Sprintf("Static analysis parser: [%s] %s %s:%d %s (%s)", f.Severity, f.Tool, f.File, f.Line, f.Message, f.RuleID))
+		}
+		if len(failures) > 0 {
+			qualifier := ""
+			if onlyNew {
+				qualifier = "new "
+			}
+			res.Reason = fmt.Sprintf("Static analysis parser: %d %sfinding(s) with severity %s or higher", len(failures), qualifier, failOn)
+			sendLog(res.Reason)
+			return res
+		}
+
+		res.Status = sdk.StatusSuccess.String()
+		return res
+	}
+}
+
+// parseAnalysis parses a report in the given format, the format is detected from the content if empty.
+// The paths of the findings are relative to the workspace.
+func parseAnalysis(format string, data []byte, workspace string) (sdk.AnalysisReport, error) {
+	if format == "" {
+		format = detectAnalysisFormat(data)
+	}
+	switch format {
+	case sdk.AnalysisFormatSARIF:
+		return parseSARIF(data, workspace)
+	case sdk.AnalysisFormatCheckstyle:
+		return parseCheckstyle(data, workspace)
+	case "":
+		return sdk.AnalysisReport{}, fmt.Errorf("unknown report format")
+	}
+	return sdk.AnalysisReport{}, fmt.Errorf("unsupported report format %s", format)
+}
+
+func detectAnalysisFormat(data []byte) string {
+	content := bytes.TrimSpace(data)
+	switch {
+	case bytes.HasPrefix(content, []byte("{")):
+		return sdk.AnalysisFormatSARIF
+	case bytes.HasPrefix(content, []byte("<")):
+		return sdk.AnalysisFormatCheckstyle
+	}
+	return ""
+}
+
+type sarifLog struct {
+	Runs []struct {
+		Tool struct {
+			Driver struct {
+				Name  string      `json:"name"`
+				Rules []sarifRule `json:"rules"`
+			} `json:"driver"`
+		} `json:"tool"`
+		Results []struct {
+			RuleID    string `json:"ruleId"`
+			RuleIndex *int   `json:"ruleIndex"`
+			Level     string `json:"level"`
+			Message   struct {
+				Text string `json:"text"`
+			} `json:"message"`
+			Locations []struct {
+				PhysicalLocation struct {
+					ArtifactLocation struct {
+						URI string `json:"uri"`
+					} `json:"artifactLocation"`
+					Region struct {
+						StartLine int `json:"startLine"`
+					} `json:"region"`
+				} `json:"physicalLocation"`
+			} `json:"locations"`
+			Properties map[string]interface{} `json:"properties"`
+		} `json:"results"`
+	} `json:"runs"`
+}
+
+type sarifRule struct {
+	ID                   string `json:"id"`
+	DefaultConfiguration struct {
+		Level string `json:"level"`
+	} `json:"defaultConfiguration"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+func parseSARIF(data []byte, workspace string) (sdk.AnalysisReport, error) {
+	var sarif sarifLog
+	if err := json.Unmarshal(data, &sarif); err != nil {
+		return sdk.AnalysisReport{}, err
+	}
+
+	var report sdk.AnalysisReport
+	for _, run := range sarif.Runs {
+		rules := map[string]sarifRule{}
+		for _, r := range run.Tool.Driver.Rules {
+			rules[r.ID] = r
+		}
+		for _, r := range run.Results {
+			var rule sarifRule
+			if r.RuleIndex != nil && *r.RuleIndex >= 0 && *r.RuleIndex < len(run.Tool.Driver.Rules) {
+				rule = run.Tool.Driver.Rules[*r.RuleIndex]
+			} else {
+				rule = rules[r.RuleID]
+			}
+
+			f := sdk.Finding{
+				Tool:    run.Tool.Driver.Name,
+				RuleID:  r.RuleID,
+				Message: r.Message.Text,
+			}
+			if f.RuleID == "" {
+				f.RuleID = rule.ID
+			}
+			if len(r.Locations) > 0 {
+				f.File = findingPath(r.Locations[0].PhysicalLocation.ArtifactLocation.URI, workspace)
+				f.Line = r.Locations[0].PhysicalLocation.Region.StartLine
+			}
+
+			// security-severity is a CVSS like score set by security scanners, the level is used otherwise
+			if score, ok := securitySeverity(r.Properties); ok {
+				f.Severity = severityFromScore(score)
+			} else if score, ok := securitySeverity(rule.Properties); ok {
+				f.Severity = severityFromScore(score)
+			} else {
+				level := r.Level
+				if level == "" {
+					level = rule.DefaultConfiguration.Level
+				}
+				f.Severity = severityFromLevel(level)
+			}
+
+			f.ComputeFingerprint()
+			report.Findings = append(report.Findings, f)
+		}
+	}
+	return report, nil
+}
+
+// findingPath returns the path of a finding relative to the workspace, so that its fingerprint doesn't change between runs.
+// Reports may contain absolute paths or file:// URIs.
+func findingPath(file, workspace string) string {
+	if strings.HasPrefix(file, "file://") {
+		if u, err := url.Parse(file); err == nil {
+			file = u.Path
+		} else {
+			file = strings.TrimPrefix(file, "file://")
+		}
+	}
+	if workspace != "" && filepath.IsAbs(file) {
+		if rel, err := filepath.Rel(workspace, file); err == nil && !strings.HasPrefix(rel, "..") {
+			file = rel
+		}
+	}
+	return filepath.ToSlash(file)
+}
+
+func securitySeverity(properties map[string]interface{}) (float64, bool) {
+	v, ok := properties["security-severity"]
+	if !ok {
+		return 0, false
+	}
+	switch s := v.(type) {
+	case string:
+		f, err := strconv.ParseFloat(s, 64)
+		return f, err == nil
+	case float64:
+		return s, true
+	}
+	return 0, false
+}
+
+func severityFromScore(score float64) string {
+	switch {
+	case score >= 9:
+		return sdk.SeverityCritical
+	case score >= 7:
+		return sdk.SeverityHigh
+	case score >= 4:
+		return sdk.SeverityMedium
+	case score > 0:
+		return sdk.SeverityLow
+	}
+	return sdk.SeverityInfo
+}
+
+// severityFromLevel converts SARIF levels and Checkstyle severities
+func severityFromLevel(level string) string {
+	switch strings.ToLower(level) {
+	case "error":
+		return sdk.SeverityHigh
+	case "warning", "":
+		return sdk.SeverityMedium
+	case "note":
+		return sdk.SeverityLow
+	}
+	return sdk.SeverityInfo
+}
+
+type checkstyleReport struct {
+	Files []struct {
+		Name   string `xml:"name,attr"`
+		Errors []struct {
+			Line     int    `xml:"line,attr"`
+			Severity string `xml:"severity,attr"`
+			Message  string `xml:"message,attr"`
+			Source   string `xml:"source,attr"`
+		} `xml:"error"`
+	} `xml:"file"`
+}
+
+func parseCheckstyle(data []byte, workspace string) (sdk.AnalysisReport, error) {
+	var cs checkstyleReport
+	if err := xml.Unmarshal(data, &cs); err != nil {
+		return sdk.AnalysisReport{}, err
+	}
+
+	var report sdk.AnalysisReport
+	for _, file := range cs.Files {
+		for _, e := range file.Errors {
+			f := sdk.Finding{
+				Tool:     sdk.AnalysisFormatCheckstyle,
+				RuleID:   e.Source,
+				Severity: severityFromLevel(e.Severity),
+				Message:  e.Message,
+				File:     findingPath(file.Name, workspace),
+				Line:     e.Line,
+			}
+			f.ComputeFingerprint()
+			report.Findings = append(report.Findings, f)
+		}
+	}
+	return report, nil
+}
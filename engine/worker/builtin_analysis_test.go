@@ -0,0 +1,106 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_parseAnalysisSARIF(t *testing.T) {
+	report := `{
+  "version": "2.1.0",
+  "runs": [{
+    "tool": {"driver": {"name": "gosec", "rules": [
+      {"id": "G101", "properties": {"security-severity": "7.5"}},
+      {"id": "G104", "defaultConfiguration": {"level": "note"}}
+    ]}},
+    "results": [
+      {"ruleId": "G101", "ruleIndex": 0, "level": "warning", "message": {"text": "Potential hardcoded credentials"},
+       "locations": [{"physicalLocation": {"artifactLocation": {"uri": "main.go"}, "region": {"startLine": 12}}}]},
+      {"ruleId": "G104", "message": {"text": "Errors unhandled"},
+       "locations": [{"physicalLocation": {"artifactLocation": {"uri": "util.go"}, "region": {"startLine": 3}}}]},
+      {"ruleId": "G999", "level": "error", "message": {"text": "Unknown rule"}}
+    ]
+  }]
+}`
+
+	r, err := parseAnalysis("", []byte(report), "/tmp/workspace")
+	assert.NoError(t, err)
+	if !assert.Len(t, r.Findings, 3) {
+		t.FailNow()
+	}
+	assert.Equal(t, "gosec", r.Findings[0].Tool)
+	assert.Equal(t, "G101", r.Findings[0].RuleID)
+	assert.Equal(t, sdk.SeverityHigh, r.Findings[0].Severity)
+	assert.Equal(t, "main.go", r.Findings[0].File)
+	assert.Equal(t, 12, r.Findings[0].Line)
+	assert.NotEmpty(t, r.Findings[0].Fingerprint)
+	assert.Equal(t, sdk.SeverityLow, r.Findings[1].Severity)
+	assert.Equal(t, sdk.SeverityHigh, r.Findings[2].Severity)
+}
+
+func Test_parseAnalysisCheckstyle(t *testing.T) {
+	report := `<?xml version="1.0" encoding="UTF-8"?>
+<checkstyle version="5.0">
+  <file name="engine/main.go">
+    <error column="2" line="10" message="Error return value is not checked" severity="error" source="errcheck"></error>
+    <error column="1" line="20" message="exported function should have comment" severity="warning" source="golint"></error>
+  </file>
+  <file name="sdk/log.go">
+    <error line="3" message="line is too long" severity="info" source="lll"></error>
+  </file>
+</checkstyle>`
+
+	r, err := parseAnalysis("", []byte(report), "/tmp/workspace")
+	assert.NoError(t, err)
+	if !assert.Len(t, r.Findings, 3) {
+		t.FailNow()
+	}
+	assert.Equal(t, sdk.Finding{
+		Tool:        sdk.AnalysisFormatCheckstyle,
+		RuleID:      "errcheck",
+		Severity:    sdk.SeverityHigh,
+		Message:     "Error return value is not checked",
+		File:        "engine/main.go",
+		Line:        10,
+		Fingerprint: r.Findings[0].Fingerprint,
+	}, r.Findings[0])
+	assert.Equal(t, sdk.SeverityMedium, r.Findings[1].Severity)
+	assert.Equal(t, sdk.SeverityInfo, r.Findings[2].Severity)
+
+	_, err = parseAnalysis("", []byte("foo"), "/tmp/workspace")
+	assert.Error(t, err)
+}
+
+func Test_parseAnalysisAbsolutePaths(t *testing.T) {
+	sarif := func(uri string) string {
+		return `{"version": "2.1.0", "runs": [{"tool": {"driver": {"name": "gosec"}}, "results": [
+  {"ruleId": "G101", "message": {"text": "Potential hardcoded credentials"},
+   "locations": [{"physicalLocation": {"artifactLocation": {"uri": "` + uri + `"}, "region": {"startLine": 12}}}]}
+]}]}`
+	}
+
+	// each run has its own workspace: the findings must keep the same fingerprint
+	r1, err := parseAnalysis("", []byte(sarif("file:///tmp/run1/engine/main.go")), "/tmp/run1")
+	assert.NoError(t, err)
+	r2, err := parseAnalysis("", []byte(sarif("/tmp/run2/engine/main.go")), "/tmp/run2")
+	assert.NoError(t, err)
+	r3, err := parseAnalysis("", []byte(sarif("engine/main.go")), "/tmp/run3")
+	assert.NoError(t, err)
+	assert.Equal(t, "engine/main.go", r1.Findings[0].File)
+	assert.Equal(t, "engine/main.go", r2.Findings[0].File)
+	assert.Equal(t, r1.Findings[0].Fingerprint, r2.Findings[0].Fingerprint)
+	assert.Equal(t, r1.Findings[0].Fingerprint, r3.Findings[0].Fingerprint)
+
+	checkstyle := `<checkstyle version="5.0"><file name="/tmp/run1/sdk/log.go"><error line="3" message="line is too long" severity="info" source="lll"></error></file></checkstyle>`
+	r, err := parseAnalysis("", []byte(checkstyle), "/tmp/run1")
+	assert.NoError(t, err)
+	assert.Equal(t, "sdk/log.go", r.Findings[0].File)
+
+	// files outside of the workspace keep their path
+	r, err = parseAnalysis("", []byte(checkstyle), "/tmp/run2")
+	assert.NoError(t, err)
+	assert.Equal(t, "/tmp/run1/sdk/log.go", r.Findings[0].File)
+}
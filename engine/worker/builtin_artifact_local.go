@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// runLocalArtifactUpload stores the artifacts in the artifacts directory of worker exec, in a sub directory per tag
+func runLocalArtifactUpload(w *currentWorker) BuiltInAction {
+	return func(ctx context.Context, a *sdk.Action, buildID int64, params *[]sdk.Parameter, sendLog LoggerFunc) sdk.Result {
+		res := sdk.Result{Status: sdk.StatusSuccess.String()}
+
+		filePattern := sdk.ParameterValue(a.Parameters, "path")
+		if filePattern == "" {
+			filePattern = "."
+		}
+
+		tag := sdk.ParameterValue(a.Parameters, "tag")
+		if tag == "" {
+			res.Status = sdk.StatusFail.String()
+			res.Reason = fmt.Sprintf("tag variable is empty. aborting")
+			sendLog(res.Reason)
+			return res
+		}
+		tag = url.QueryEscape(strings.Replace(tag, "/", "-", -1))
+
+		filesPath, err := filepath.Glob(filePattern)
+		if err != nil {
+			res.Status = sdk.StatusFail.String()
+			res.Reason = fmt.Sprintf("cannot perform globbing of pattern '%s': %s", filePattern, err)
+			sendLog(res.Reason)
+			return res
+		}
+
+		if len(filesPath) == 0 {
+			res.Status = sdk.StatusFail.String()
+			res.Reason = fmt.Sprintf("Pattern '%s' matched no file", filePattern)
+			sendLog(res.Reason)
+			return res
+		}
+
+		destDir := path.Join(w.local.artifactsDir, tag)
+		if err := os.MkdirAll(destDir, os.FileMode(0755)); err != nil {
+			res.Status = sdk.StatusFail.String()
+			res.Reason = fmt.Sprintf("Unable to create %s: %v", destDir, err)
+			sendLog(res.Reason)
+			return res
+		}
+
+		for _, filePath := range filesPath {
+			destFile := path.Join(destDir, filepath.Base(filePath))
+			sendLog(fmt.Sprintf("Uploading '%s' into '%s'", filepath.Base(filePath), destDir))
+			if err := copyFile(filePath, destFile); err != nil {
+				res.Status = sdk.StatusFail.String()
+				res.Reason = fmt.Sprintf("Error while uploading artefact: %v\n", err)
+				sendLog(res.Reason)
+				return res
+			}
+		}
+
+		return res
+	}
+}
+
+// runLocalArtifactDownload gets the artifacts uploaded by the previous jobs of worker exec
+func runLocalArtifactDownload(w *currentWorker) BuiltInAction {
+	return func(ctx context.Context, a *sdk.Action, buildID int64, params *[]sdk.Parameter, sendLog LoggerFunc) sdk.Result {
+		res := sdk.Result{Status: sdk.StatusSuccess.String()}
+
+		enabled := sdk.ParameterValue(*params, "enabled") != "false"
+		destPath := sdk.ParameterValue(a.Parameters, "path")
+		tag := sdk.ParameterValue(a.Parameters, "tag")
+		pattern := sdk.ParameterValue(a.Parameters, "pattern")
+
+		if !enabled {
+			sendLog("Artifact Download is disabled.")
+			return res
+		}
+
+		if destPath == "" {
+			destPath = "."
+		}
+
+		reg, errR := regexp.Compile(pattern)
+		if errR != nil {
+			res.Status = sdk.StatusFail.String()
+			res.Reason = fmt.Sprintf("Invalid pattern %s: %v", pattern, errR)
+			sendLog(res.Reason)
+			return res
+		}
+
+		// without tag, artifacts of all tags are downloaded
+		tags := []string{}
+		if tag != "" {
+			tags = append(tags, url.QueryEscape(strings.Replace(tag, "/", "-", -1)))
+		} else {
+			dirs, err := ioutil.ReadDir(w.local.artifactsDir)
+			if err != nil && !os.IsNotExist(err) {
+				res.Status = sdk.StatusFail.String()
+				res.Reason = fmt.Sprintf("Unable to list artifacts: %v", err)
+				sendLog(res.Reason)
+				return res
+			}
+			for _, d := range dirs {
+				if d.IsDir() {
+					tags = append(tags, d.Name())
+				}
+			}
+		}
+
+		if err := os.MkdirAll(destPath, os.FileMode(0744)); err != nil {
+			res.Status = sdk.StatusFail.String()
+			res.Reason = fmt.Sprintf("Unable to create %s: %v", destPath, err)
+			sendLog(res.Reason)
+			return res
+		}
+
+		sendLog(fmt.Sprintf("Downloading artifacts from '%s' into '%s'...", w.local.artifactsDir, destPath))
+		for _, t := range tags {
+			files, err := ioutil.ReadDir(path.Join(w.local.artifactsDir, t))
+			if err != nil {
+				if os.IsNotExist(err) {
+					sendLog(fmt.Sprintf("No artifact with tag %s", t))
+					continue
+				}
+				res.Status = sdk.StatusFail.String()
+				res.Reason = fmt.Sprintf("Unable to list artifacts: %v", err)
+				sendLog(res.Reason)
+				return res
+			}
+			for _, f := range files {
+				if f.IsDir() {
+					continue
+				}
+				if pattern != "" && !reg.MatchString(f.Name()) {
+					sendLog(fmt.Sprintf("%s does not match pattern %s - skipped", f.Name(), pattern))
+					continue
+				}
+				sendLog(fmt.Sprintf("downloading artifact %s (tag %s)...", f.Name(), t))
+				if err := copyFile(path.Join(w.local.artifactsDir, t, f.Name()), path.Join(destPath, f.Name())); err != nil {
+					res.Status = sdk.StatusFail.String()
+					res.Reason = fmt.Sprintf("Cannot download artifact %s: %v", f.Name(), err)
+					sendLog(res.Reason)
+					return res
+				}
+			}
+		}
+
+		return res
+	}
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	stat, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	if stat.IsDir() {
+		return fmt.Errorf("%s is a directory", src)
+	}
+
+	out, err := os.OpenFile(dst, os.O_RDWR|os.O_CREATE|os.O_TRUNC, stat.Mode())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -12,6 +12,9 @@ import (
 )
 
 func runArtifactUpload(w *currentWorker) BuiltInAction {
+	if w.local.enabled {
+		return runLocalArtifactUpload(w)
+	}
 	if w.currentJob.wJob == nil {
 		return func(ctx context.Context, a *sdk.Action, buildID int64, params *[]sdk.Parameter, sendLog LoggerFunc) sdk.Result {
 			res := sdk.Result{Status: sdk.StatusSuccess.String()}
@@ -0,0 +1,344 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func runParseCoverageResultAction(w *currentWorker) BuiltInAction {
+	return func(ctx context.Context, a *sdk.Action, buildID int64, params *[]sdk.Parameter, sendLog LoggerFunc) sdk.Result {
+		var res sdk.Result
+		res.Status = sdk.StatusFail.String()
+
+		p := sdk.ParameterValue(a.Parameters, "path")
+		if p == "" {
+			res.Reason = fmt.Sprintf("Coverage parser: path not provided")
+			sendLog(res.Reason)
+			return res
+		}
+		format := strings.ToLower(strings.TrimSpace(sdk.ParameterValue(a.Parameters, "format")))
+
+		minimum, errM := parseCoverageThreshold(sdk.ParameterValue(a.Parameters, "minimum"))
+		if errM != nil {
+			res.Reason = fmt.Sprintf("Coverage parser: invalid minimum: %s", errM)
+			sendLog(res.Reason)
+			return res
+		}
+		maxDecrease, errD := parseCoverageThreshold(sdk.ParameterValue(a.Parameters, "maxDecrease"))
+		if errD != nil {
+			res.Reason = fmt.Sprintf("Coverage parser: invalid maxDecrease: %s", errD)
+			sendLog(res.Reason)
+			return res
+		}
+
+		files, errg := filepath.Glob(p)
+		if errg != nil {
+			res.Reason = fmt.Sprintf("Coverage parser: Cannot find requested files, invalid pattern")
+			sendLog(res.Reason)
+			return res
+		}
+		if len(files) == 0 {
+			res.Reason = fmt.Sprintf("Coverage parser: no file matches %s", p)
+			sendLog(res.Reason)
+			return res
+		}
+
+		sendLog(fmt.Sprintf("%d", len(files)) + " file(s) to analyze")
+
+		var coverage sdk.Coverage
+		for _, f := range files {
+			data, errRead := ioutil.ReadFile(f)
+			if errRead != nil {
+				res.Reason = fmt.Sprintf("Coverage parser: cannot read file %s (%s)", f, errRead)
+				sendLog(res.Reason)
+				return res
+			}
+
+			fileCoverage, errP := parseCoverage(format, data)
+			if errP != nil {
+				res.Reason = fmt.Sprintf("Coverage parser: cannot parse file %s (%s)", f, errP)
+				sendLog(res.Reason)
+				return res
+			}
+			coverage.Merge(fileCoverage)
+		}
+
+		if w.currentJob.wJob != nil {
+			data, err := json.Marshal(coverage)
+			if err != nil {
+				res.Reason = fmt.Sprintf("Coverage parser: failed to send coverage details: %s", err)
+				sendLog(res.Reason)
+				return res
+			}
+
+			uri := fmt.Sprintf("/queue/workflows/%d/coverage", w.currentJob.wJob.ID)
+			btes, code, err := sdk.Request("POST", uri, data)
+			if err == nil && code >= 300 {
+				err = fmt.Errorf("HTTP %d", code)
+			}
+			if err == nil {
+				err = json.Unmarshal(btes, &coverage)
+			}
+			if err != nil {
+				res.Reason = fmt.Sprintf("Coverage parser: failed to send coverage details: %s", err)
+				sendLog(res.Reason)
+				return res
+			}
+		}
+
+		for _, pkg := range coverage.Packages {
+			sendLog(fmt.Sprintf("Coverage parser: package %s: %.2f%% lines", pkg.Name, pkg.Lines.Percent()))
+		}
+		sendLog(fmt.Sprintf("Coverage parser: %.2f%% lines (%d/%d), %.2f%% branches (%d/%d)",
+			coverage.Lines.Percent(), coverage.Lines.Covered, coverage.Lines.Total,
+			coverage.Branches.Percent(), coverage.Branches.Covered, coverage.Branches.Total))
+		if coverage.ReferenceNodeRunID != 0 {
+			sendLog(fmt.Sprintf("Coverage parser: delta %+.2f%% since previous run", coverage.Delta))
+		}
+
+		if reason := checkCoverageThresholds(coverage, minimum, maxDecrease); reason != "" {
+			res.Reason = reason
+			sendLog(res.Reason)
+			return res
+		}
+
+		res.Status = sdk.StatusSuccess.String()
+		return res
+	}
+}
+
+func parseCoverageThreshold(s string) (*float64, error) {
+	s = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(s), "%"))
+	if s == "" {
+		return nil, nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+// checkCoverageThresholds returns the reason of the failure if the coverage is under minimum or decreased more than maxDecrease
+func checkCoverageThresholds(c sdk.Coverage, minimum, maxDecrease *float64) string {
+	if minimum != nil && c.Lines.Percent() < *minimum {
+		return fmt.Sprintf("Coverage parser: line coverage %.2f%% is under the minimum %.2f%%", c.Lines.Percent(), *minimum)
+	}
+	if maxDecrease != nil && c.ReferenceNodeRunID != 0 && -c.Delta > *maxDecrease {
+		return fmt.Sprintf("Coverage parser: line coverage decreased by %.2f%%, more than %.2f%%", -c.Delta, *maxDecrease)
+	}
+	return ""
+}
+
+// parseCoverage parses a report in the given format, the format is detected from the content if empty
+func parseCoverage(format string, data []byte) (sdk.Coverage, error) {
+	if format == "" {
+		format = detectCoverageFormat(data)
+	}
+	switch format {
+	case sdk.CoverageFormatCobertura:
+		return parseCobertura(data)
+	case sdk.CoverageFormatLCOV:
+		return parseLCOV(data)
+	case sdk.CoverageFormatGo:
+		return parseGoCoverProfile(data)
+	case "":
+		return sdk.Coverage{}, fmt.Errorf("unknown report format")
+	}
+	return sdk.Coverage{}, fmt.Errorf("unsupported report format %s", format)
+}
+
+func detectCoverageFormat(data []byte) string {
+	content := bytes.TrimSpace(data)
+	switch {
+	case bytes.HasPrefix(content, []byte("mode:")):
+		return sdk.CoverageFormatGo
+	case bytes.HasPrefix(content, []byte("<")):
+		return sdk.CoverageFormatCobertura
+	case bytes.HasPrefix(content, []byte("TN:")), bytes.HasPrefix(content, []byte("SF:")):
+		return sdk.CoverageFormatLCOV
+	}
+	return ""
+}
+
+type coberturaReport struct {
+	Packages []struct {
+		Name    string `xml:"name,attr"`
+		Classes []struct {
+			Filename string `xml:"filename,attr"`
+			Lines    []struct {
+				Number            int    `xml:"number,attr"`
+				Hits              int64  `xml:"hits,attr"`
+				Branch            bool   `xml:"branch,attr"`
+				ConditionCoverage string `xml:"condition-coverage,attr"`
+			} `xml:"lines>line"`
+		} `xml:"classes>class"`
+	} `xml:"packages>package"`
+}
+
+var coberturaConditionRegexp = regexp.MustCompile(`\((\d+)/(\d+)\)`)
+
+func parseCobertura(data []byte) (sdk.Coverage, error) {
+	var report coberturaReport
+	if err := xml.Unmarshal(data, &report); err != nil {
+		return sdk.Coverage{}, err
+	}
+
+	var c sdk.Coverage
+	for _, p := range report.Packages {
+		pkg := sdk.CoveragePackage{Name: p.Name}
+		// the same line can be reported by several classes of a file (inner classes)
+		seen := map[string]bool{}
+		for _, class := range p.Classes {
+			for _, l := range class.Lines {
+				key := fmt.Sprintf("%s:%d", class.Filename, l.Number)
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+				pkg.Lines.Total++
+				if l.Hits > 0 {
+					pkg.Lines.Covered++
+				}
+				if !l.Branch {
+					continue
+				}
+				m := coberturaConditionRegexp.FindStringSubmatch(l.ConditionCoverage)
+				if m == nil {
+					continue
+				}
+				covered, _ := strconv.ParseInt(m[1], 10, 64)
+				total, _ := strconv.ParseInt(m[2], 10, 64)
+				pkg.Branches.Add(sdk.CoverageMetric{Covered: covered, Total: total})
+			}
+		}
+		c.Packages = append(c.Packages, pkg)
+	}
+	c.Compute()
+	return c, nil
+}
+
+func parseLCOV(data []byte) (sdk.Coverage, error) {
+	packages := map[string]*sdk.CoveragePackage{}
+	var pkg *sdk.CoveragePackage
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		i := strings.Index(line, ":")
+		if i < 0 {
+			continue
+		}
+		key, value := line[:i], line[i+1:]
+		if key == "SF" {
+			dir := filepath.ToSlash(filepath.Dir(value))
+			if _, ok := packages[dir]; !ok {
+				packages[dir] = &sdk.CoveragePackage{Name: dir}
+			}
+			pkg = packages[dir]
+			continue
+		}
+		if pkg == nil {
+			continue
+		}
+		fields := strings.Split(value, ",")
+		switch key {
+		case "DA":
+			if len(fields) < 2 {
+				return sdk.Coverage{}, fmt.Errorf("malformatted line %s", line)
+			}
+			pkg.Lines.Total++
+			if hits, _ := strconv.ParseInt(fields[1], 10, 64); hits > 0 {
+				pkg.Lines.Covered++
+			}
+		case "BRDA":
+			if len(fields) < 4 {
+				return sdk.Coverage{}, fmt.Errorf("malformatted line %s", line)
+			}
+			pkg.Branches.Total++
+			if taken, _ := strconv.ParseInt(fields[3], 10, 64); taken > 0 {
+				pkg.Branches.Covered++
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return sdk.Coverage{}, err
+	}
+
+	var c sdk.Coverage
+	for _, p := range packages {
+		c.Packages = append(c.Packages, *p)
+	}
+	c.Compute()
+	return c, nil
+}
+
+// parseGoCoverProfile parses a go coverprofile. Go coverage counts statements, reported as lines.
+func parseGoCoverProfile(data []byte) (sdk.Coverage, error) {
+	type block struct {
+		pkg        string
+		statements int64
+		count      int64
+	}
+	blocks := map[string]*block{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "mode:") {
+			continue
+		}
+		// name.go:line.column,line.column numberOfStatements count
+		fields := strings.Fields(line)
+		if len(fields) != 3 || !strings.Contains(fields[0], ":") {
+			return sdk.Coverage{}, fmt.Errorf("malformatted line %s", line)
+		}
+		statements, errS := strconv.ParseInt(fields[1], 10, 64)
+		count, errC := strconv.ParseInt(fields[2], 10, 64)
+		if errS != nil || errC != nil {
+			return sdk.Coverage{}, fmt.Errorf("malformatted line %s", line)
+		}
+		// the same block is reported once per test binary when profiles are concatenated
+		b, ok := blocks[fields[0]]
+		if !ok {
+			b = &block{pkg: path.Dir(fields[0][:strings.LastIndex(fields[0], ":")]), statements: statements}
+			blocks[fields[0]] = b
+		}
+		b.count += count
+	}
+	if err := scanner.Err(); err != nil {
+		return sdk.Coverage{}, err
+	}
+
+	packages := map[string]*sdk.CoveragePackage{}
+	for _, b := range blocks {
+		pkg, ok := packages[b.pkg]
+		if !ok {
+			pkg = &sdk.CoveragePackage{Name: b.pkg}
+			packages[b.pkg] = pkg
+		}
+		pkg.Lines.Total += b.statements
+		if b.count > 0 {
+			pkg.Lines.Covered += b.statements
+		}
+	}
+
+	var c sdk.Coverage
+	for _, p := range packages {
+		c.Packages = append(c.Packages, *p)
+	}
+	c.Compute()
+	return c, nil
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_parseCoverageCobertura(t *testing.T) {
+	report := `<?xml version="1.0" ?>
+<!DOCTYPE coverage SYSTEM 'http://cobertura.sourceforge.net/xml/coverage-04.dtd'>
+<coverage line-rate="0.75" branch-rate="0.5" version="1.9">
+	<packages>
+		<package name="com.example" line-rate="0.75" branch-rate="0.5">
+			<classes>
+				<class name="com.example.Main" filename="com/example/Main.java" line-rate="0.75">
+					<methods>
+						<method name="main" signature="()V">
+							<lines>
+								<line number="3" hits="1" branch="false"/>
+							</lines>
+						</method>
+					</methods>
+					<lines>
+						<line number="3" hits="1" branch="false"/>
+						<line number="4" hits="2" branch="true" condition-coverage="50% (1/2)"/>
+						<line number="5" hits="1" branch="false"/>
+						<line number="6" hits="0" branch="false"/>
+					</lines>
+				</class>
+				<class name="com.example.Main$Inner" filename="com/example/Main.java" line-rate="1">
+					<lines>
+						<line number="6" hits="0" branch="false"/>
+					</lines>
+				</class>
+			</classes>
+		</package>
+	</packages>
+</coverage>`
+
+	c, err := parseCoverage("", []byte(report))
+	assert.NoError(t, err)
+	if !assert.Len(t, c.Packages, 1) {
+		t.FailNow()
+	}
+	assert.Equal(t, "com.example", c.Packages[0].Name)
+	assert.Equal(t, sdk.CoverageMetric{Covered: 3, Total: 4}, c.Lines)
+	assert.Equal(t, sdk.CoverageMetric{Covered: 1, Total: 2}, c.Branches)
+	assert.Equal(t, 75.0, c.Lines.Percent())
+}
+
+func Test_parseCoverageLCOV(t *testing.T) {
+	report := `TN:
+SF:/src/lib/a.js
+DA:1,1
+DA:2,0
+BRDA:2,0,0,1
+BRDA:2,0,1,-
+LF:2
+LH:1
+end_of_record
+SF:/src/lib/b.js
+DA:1,3
+end_of_record
+SF:/src/index.js
+DA:1,0
+end_of_record
+`
+
+	c, err := parseCoverage("", []byte(report))
+	assert.NoError(t, err)
+	if !assert.Len(t, c.Packages, 2) {
+		t.FailNow()
+	}
+	assert.Equal(t, "/src", c.Packages[0].Name)
+	assert.Equal(t, sdk.CoverageMetric{Covered: 0, Total: 1}, c.Packages[0].Lines)
+	assert.Equal(t, "/src/lib", c.Packages[1].Name)
+	assert.Equal(t, sdk.CoverageMetric{Covered: 2, Total: 3}, c.Packages[1].Lines)
+	assert.Equal(t, sdk.CoverageMetric{Covered: 1, Total: 2}, c.Packages[1].Branches)
+	assert.Equal(t, sdk.CoverageMetric{Covered: 2, Total: 4}, c.Lines)
+}
+
+func Test_parseCoverageGo(t *testing.T) {
+	report := `mode: set
+github.com/ovh/cds/sdk/coverage.go:47.42,50.2 2 1
+github.com/ovh/cds/sdk/coverage.go:53.40,54.19 1 0
+github.com/ovh/cds/sdk/coverage.go:53.40,54.19 1 1
+github.com/ovh/cds/sdk/log/log.go:10.2,12.3 3 0
+`
+
+	c, err := parseCoverage(sdk.CoverageFormatGo, []byte(report))
+	assert.NoError(t, err)
+	if !assert.Len(t, c.Packages, 2) {
+		t.FailNow()
+	}
+	assert.Equal(t, "github.com/ovh/cds/sdk", c.Packages[0].Name)
+	assert.Equal(t, sdk.CoverageMetric{Covered: 3, Total: 3}, c.Packages[0].Lines)
+	assert.Equal(t, "github.com/ovh/cds/sdk/log", c.Packages[1].Name)
+	assert.Equal(t, sdk.CoverageMetric{Covered: 0, Total: 3}, c.Packages[1].Lines)
+	assert.Equal(t, sdk.CoverageMetric{Covered: 3, Total: 6}, c.Lines)
+
+	_, err = parseCoverage(sdk.CoverageFormatGo, []byte("mode: set\nfoo 1"))
+	assert.Error(t, err)
+	_, err = parseCoverage("", []byte("foo"))
+	assert.Error(t, err)
+}
+
+func Test_checkCoverageThresholds(t *testing.T) {
+	minimum, err := parseCoverageThreshold("80%")
+	assert.NoError(t, err)
+	maxDecrease, err := parseCoverageThreshold("1")
+	assert.NoError(t, err)
+
+	c := sdk.Coverage{Lines: sdk.CoverageMetric{Covered: 85, Total: 100}, Delta: -2}
+	assert.Empty(t, checkCoverageThresholds(c, minimum, maxDecrease), "no reference run, delta must be ignored")
+
+	c.ReferenceNodeRunID = 1
+	assert.NotEmpty(t, checkCoverageThresholds(c, minimum, maxDecrease))
+
+	c.Delta = -0.5
+	assert.Empty(t, checkCoverageThresholds(c, minimum, maxDecrease))
+
+	c.Lines.Covered = 79
+	assert.NotEmpty(t, checkCoverageThresholds(c, minimum, maxDecrease))
+	assert.Empty(t, checkCoverageThresholds(c, nil, nil))
+}
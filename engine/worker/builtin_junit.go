@@ -69,6 +69,26 @@ func runParseJunitTestResultAction(w *currentWorker) BuiltInAction {
 			sendLog(r)
 		}
 
+		// Failures of the tests in quarantine don't fail the step
+		if res.Status == sdk.StatusFail.String() && w.currentJob.wJob != nil {
+			var quarantine []sdk.WorkflowTestQuarantine
+			uri := fmt.Sprintf("/queue/workflows/%d/test/quarantine", w.currentJob.wJob.ID)
+			btes, code, err := sdk.Request("GET", uri, nil)
+			if err == nil && code >= 300 {
+				err = fmt.Errorf("HTTP %d", code)
+			}
+			if err == nil {
+				err = json.Unmarshal(btes, &quarantine)
+			}
+			if err != nil {
+				sendLog(fmt.Sprintf("JUnit parser: unable to get tests in quarantine: %s", err))
+			} else {
+				for _, r := range applyTestsQuarantine(&res, &tests, quarantine) {
+					sendLog(r)
+				}
+			}
+		}
+
 		data, err := json.Marshal(tests)
 		if err != nil {
 			res.Reason = fmt.Sprintf("JUnit parse: failed to send tests details: %s", err)
@@ -77,6 +97,11 @@ func runParseJunitTestResultAction(w *currentWorker) BuiltInAction {
 			return res
 		}
 
+		// without API, tests results are only logged
+		if w.local.enabled {
+			return res
+		}
+
 		var uri string
 		if w.currentJob.wJob != nil {
 			uri = fmt.Sprintf("/queue/workflows/%d/test", w.currentJob.wJob.ID)
@@ -85,7 +110,7 @@ func runParseJunitTestResultAction(w *currentWorker) BuiltInAction {
 		}
 
 		_, code, err := sdk.Request("POST", uri, data)
-		if err == nil && code > 300 {
+		if err == nil && code >= 300 {
 			err = fmt.Errorf("HTTP %d", code)
 		}
 
@@ -199,6 +224,35 @@ func computeStats(res *sdk.Result, v *venom.Tests) []string {
 	return reasons
 }
 
+// applyTestsQuarantine sets result.Status to success if all the failed tests are in quarantine,
+// and returns a list of log to send to API
+func applyTestsQuarantine(res *sdk.Result, v *venom.Tests, quarantine []sdk.WorkflowTestQuarantine) []string {
+	inQuarantine := map[string]bool{}
+	for _, q := range quarantine {
+		inQuarantine[q.TestSuite+"/"+q.TestCase] = true
+	}
+
+	reasons := []string{}
+	var nbFailed int
+	for _, ts := range v.TestSuites {
+		for _, tc := range ts.TestCases {
+			if sdk.TestCaseStatus(tc) != sdk.TestStatusFail {
+				continue
+			}
+			if inQuarantine[ts.Name+"/"+tc.Name] {
+				reasons = append(reasons, fmt.Sprintf("JUnit parser: testcase %s of testsuite %s is in quarantine, its failure is ignored", tc.Name, ts.Name))
+				continue
+			}
+			nbFailed++
+		}
+	}
+
+	if nbFailed == 0 && len(reasons) > 0 {
+		res.Status = sdk.StatusSuccess.String()
+	}
+	return reasons
+}
+
 func parseTestsuiteAlone(data []byte) (venom.TestSuite, bool) {
 	var s venom.TestSuite
 	err := xml.Unmarshal([]byte(data), &s)
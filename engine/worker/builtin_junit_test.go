@@ -216,3 +216,34 @@ func Test_computeStats(t *testing.T) {
 		})
 	}
 }
+
+func Test_applyTestsQuarantine(t *testing.T) {
+	tests := &venom.Tests{
+		TestSuites: []venom.TestSuite{
+			{
+				Name: "myTestSuite",
+				TestCases: []venom.TestCase{
+					{Name: "ok"},
+					{Name: "flaky", Failures: []venom.Failure{{Value: "timeout"}}},
+				},
+			},
+		},
+	}
+	quarantine := []sdk.WorkflowTestQuarantine{{TestSuite: "myTestSuite", TestCase: "flaky"}}
+
+	res := &sdk.Result{Status: sdk.StatusFail.String()}
+	reasons := applyTestsQuarantine(res, tests, quarantine)
+	if len(reasons) != 1 {
+		t.Errorf("expected 1 reason, got %v", reasons)
+	}
+	if res.Status != sdk.StatusSuccess.String() {
+		t.Errorf("expected status %s, got %s", sdk.StatusSuccess, res.Status)
+	}
+
+	tests.TestSuites[0].TestCases[0].Errors = []venom.Failure{{Value: "error"}}
+	res = &sdk.Result{Status: sdk.StatusFail.String()}
+	applyTestsQuarantine(res, tests, quarantine)
+	if res.Status != sdk.StatusFail.String() {
+		t.Errorf("expected status %s, got %s", sdk.StatusFail, res.Status)
+	}
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+	"github.com/ovh/cds/sdk/plugin"
+)
+
+// Mount points and files of the container of a docker action plugin
+const (
+	pluginImageWorkspace      = "/cds/workspace"
+	pluginImageDir            = "/cds/plugin"
+	pluginImageParametersFile = "parameters.json"
+	pluginImageResultFile     = "result.json"
+	pluginImageParamPrefix    = "CDS_PARAM_"
+)
+
+// pluginImageResult is the result file written by a docker action plugin in /cds/plugin/result.json
+type pluginImageResult struct {
+	Status string `json:"status"`
+	Reason string `json:"reason"`
+}
+
+// checkPluginImageRequirement checks that docker is available and pulls the image of the plugin if needed
+func checkPluginImageRequirement(image string) (bool, error) {
+	if _, err := exec.LookPath("docker"); err != nil {
+		// Return nil because the error contains 'Executable file not found', that's what we wanted
+		return false, nil
+	}
+
+	if err := exec.Command("docker", "image", "inspect", image).Run(); err == nil {
+		return true, nil
+	}
+
+	if out, err := exec.Command("docker", "pull", image).CombinedOutput(); err != nil {
+		log.Warning("checkPluginImageRequirement> Unable to pull %s: %s", image, out)
+		return false, fmt.Errorf("unable to pull %s: %s", image, err)
+	}
+	return true, nil
+}
+
+// paramEnvName returns the environment variable name of a parameter: cds.version gives CDS_VERSION
+func paramEnvName(name string) string {
+	return strings.ToUpper(strings.Replace(name, ".", "_", -1))
+}
+
+// pluginImageData returns the parameters of a docker action plugin, the same way they are given to a binary plugin:
+// the step parameters, then the job parameters and the build variables.
+// The environment variables are the job parameters and the build variables, as for a script,
+// and the step parameters prefixed with CDS_PARAM_
+func pluginImageData(a *sdk.Action, params []sdk.Parameter, buildVariables []sdk.Variable) (map[string]string, []string) {
+	data := map[string]string{}
+	env := []string{}
+
+	for _, p := range a.Parameters {
+		data[p.Name] = p.Value
+		env = append(env, fmt.Sprintf("%s%s=%s", pluginImageParamPrefix, paramEnvName(p.Name), p.Value))
+	}
+	for _, p := range params {
+		data[p.Name] = p.Value
+		// avoid put private key in environment var as it's a binary value
+		if p.Type == sdk.KeyParameter && !strings.HasSuffix(p.Name, ".pub") {
+			continue
+		}
+		env = append(env, fmt.Sprintf("%s=%s", paramEnvName(p.Name), p.Value))
+	}
+	for _, p := range buildVariables {
+		data[p.Name] = p.Value
+		env = append(env, fmt.Sprintf("%s=%s", paramEnvName(p.Name), p.Value))
+	}
+	return data, env
+}
+
+// pluginImageArgs returns the docker arguments to run the container of a plugin.
+// Environment variables are only given by name, their values are read by docker from its own environment
+// so that secrets don't appear in the command line.
+func pluginImageArgs(name, image, user, workspace, pluginDir string, env []string) []string {
+	args := []string{"run", "--rm", "--name", name,
+		"-v", workspace + ":" + pluginImageWorkspace,
+		"-v", pluginDir + ":" + pluginImageDir,
+		"-w", pluginImageWorkspace,
+	}
+	if user != "" {
+		args = append(args, "--user", user)
+	}
+
+	names := map[string]bool{}
+	for _, e := range env {
+		n := strings.SplitN(e, "=", 2)[0]
+		if names[n] {
+			continue
+		}
+		names[n] = true
+		args = append(args, "-e", n)
+	}
+	return append(args, image)
+}
+
+// readPluginImageResult computes the step result from the exit status and the result file of a docker action plugin
+func readPluginImageResult(pluginDir string, errRun error) sdk.Result {
+	var r pluginImageResult
+	btes, err := ioutil.ReadFile(path.Join(pluginDir, pluginImageResultFile))
+	if err == nil {
+		if err := json.Unmarshal(btes, &r); err != nil {
+			return sdk.Result{Status: sdk.StatusFail.String(), Reason: fmt.Sprintf("Invalid plugin result file: %s", err)}
+		}
+	} else if !os.IsNotExist(err) {
+		return sdk.Result{Status: sdk.StatusFail.String(), Reason: fmt.Sprintf("Unable to read plugin result file: %s", err)}
+	}
+
+	switch {
+	case errRun != nil:
+		reason := r.Reason
+		if reason == "" {
+			reason = fmt.Sprintf("Plugin Failure: %s", errRun)
+		}
+		return sdk.Result{Status: sdk.StatusFail.String(), Reason: reason}
+	case r.Status != "" && r.Status != string(plugin.Success):
+		reason := r.Reason
+		if reason == "" {
+			reason = "Plugin Failure"
+		}
+		return sdk.Result{Status: sdk.StatusFail.String(), Reason: reason}
+	}
+	return sdk.Result{Status: sdk.StatusSuccess.String()}
+}
+
+// runPluginImage runs a docker action plugin: the workspace is mounted in the container,
+// the parameters are given as environment variables and in /cds/plugin/parameters.json
+// and the plugin may write its result in /cds/plugin/result.json
+func (w *currentWorker) runPluginImage(ctx context.Context, a *sdk.Action, image string, params []sdk.Parameter, sendLog LoggerFunc) sdk.Result {
+	fail := func(format string, args ...interface{}) sdk.Result {
+		res := sdk.Result{Status: sdk.StatusFail.String(), Reason: fmt.Sprintf(format, args...)}
+		sendLog(res.Reason)
+		return res
+	}
+
+	workspace, err := os.Getwd()
+	if err != nil {
+		return fail("Unable to get workspace: %s", err)
+	}
+
+	pluginDir, err := ioutil.TempDir(w.basedir, "cds-plugin-")
+	if err != nil {
+		return fail("Unable to create plugin directory: %s", err)
+	}
+	defer os.RemoveAll(pluginDir)
+
+	data, env := pluginImageData(a, params, w.getBuildVariables())
+	btes, err := json.Marshal(data)
+	if err != nil {
+		return fail("Unable to marshal plugin parameters: %s", err)
+	}
+	if err := ioutil.WriteFile(path.Join(pluginDir, pluginImageParametersFile), btes, 0600); err != nil {
+		return fail("Unable to write plugin parameters: %s", err)
+	}
+
+	// files written in the workspace by the plugin must belong to the worker
+	var user string
+	if runtime.GOOS != "windows" {
+		user = fmt.Sprintf("%d:%d", os.Getuid(), os.Getgid())
+	}
+
+	name := filepath.Base(pluginDir)
+	cmd := exec.Command("docker", pluginImageArgs(name, image, user, workspace, pluginDir, env)...)
+	cmd.Env = append(os.Environ(), env...)
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fail("Unable to get plugin output: %s", err)
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return fail("Unable to get plugin output: %s", err)
+	}
+
+	sendLog(fmt.Sprintf("Starting plugin: %s (%s)", a.Name, image))
+	if err := cmd.Start(); err != nil {
+		return fail("Unable to start plugin %s: %s", a.Name, err)
+	}
+
+	// the container is removed if the job is canceled
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			if out, err := exec.Command("docker", "rm", "-f", name).CombinedOutput(); err != nil {
+				log.Warning("runPluginImage> Unable to remove container %s: %s", name, out)
+			}
+		case <-done:
+		}
+	}()
+
+	wg := &sync.WaitGroup{}
+	for _, r := range []io.Reader{stdout, stderr} {
+		wg.Add(1)
+		go func(r io.Reader) {
+			defer wg.Done()
+			reader := bufio.NewReader(r)
+			for {
+				line, err := reader.ReadString('\n')
+				if line != "" {
+					sendLog(line)
+				}
+				if err != nil {
+					return
+				}
+			}
+		}(r)
+	}
+	wg.Wait()
+
+	res := readPluginImageResult(pluginDir, cmd.Wait())
+	if res.Reason != "" {
+		sendLog(res.Reason)
+	}
+	return res
+}
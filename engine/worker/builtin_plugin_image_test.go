@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_pluginImageData(t *testing.T) {
+	a := &sdk.Action{
+		Name:       "plugin-docker",
+		Parameters: []sdk.Parameter{{Name: "target.url", Value: "http://foo"}},
+	}
+	params := []sdk.Parameter{
+		{Name: "cds.version", Value: "12"},
+		{Name: "cds.key.deploy.priv", Type: sdk.KeyParameter, Value: "secret"},
+	}
+	vars := []sdk.Variable{{Name: "cds.build.foo", Value: "bar"}}
+
+	data, env := pluginImageData(a, params, vars)
+	assert.Equal(t, map[string]string{
+		"target.url":          "http://foo",
+		"cds.version":         "12",
+		"cds.key.deploy.priv": "secret",
+		"cds.build.foo":       "bar",
+	}, data)
+	assert.Equal(t, []string{"CDS_PARAM_TARGET_URL=http://foo", "CDS_VERSION=12", "CDS_BUILD_FOO=bar"}, env)
+
+	args := pluginImageArgs("cds-plugin-1", "foo/bar:1.0", "1000:1000", "/ws", "/tmp/plugin", append(env, "CDS_VERSION=13"))
+	assert.Equal(t, []string{"run", "--rm", "--name", "cds-plugin-1",
+		"-v", "/ws:/cds/workspace",
+		"-v", "/tmp/plugin:/cds/plugin",
+		"-w", "/cds/workspace",
+		"--user", "1000:1000",
+		"-e", "CDS_PARAM_TARGET_URL", "-e", "CDS_VERSION", "-e", "CDS_BUILD_FOO",
+		"foo/bar:1.0"}, args)
+}
+
+func Test_readPluginImageResult(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cds-plugin")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.RemoveAll(dir)
+
+	// without result file, the exit status gives the result
+	assert.Equal(t, sdk.StatusSuccess.String(), readPluginImageResult(dir, nil).Status)
+	assert.Equal(t, sdk.StatusFail.String(), readPluginImageResult(dir, fmt.Errorf("exit status 1")).Status)
+
+	resultFile := path.Join(dir, pluginImageResultFile)
+	if !assert.NoError(t, ioutil.WriteFile(resultFile, []byte(`{"status": "Fail", "reason": "deployment failed"}`), 0600)) {
+		t.FailNow()
+	}
+	res := readPluginImageResult(dir, nil)
+	assert.Equal(t, sdk.StatusFail.String(), res.Status)
+	assert.Equal(t, "deployment failed", res.Reason)
+
+	if !assert.NoError(t, ioutil.WriteFile(resultFile, []byte(`{"status": "Success"}`), 0600)) {
+		t.FailNow()
+	}
+	assert.Equal(t, sdk.StatusSuccess.String(), readPluginImageResult(dir, nil).Status)
+
+	if !assert.NoError(t, ioutil.WriteFile(resultFile, []byte(`not json`), 0600)) {
+		t.FailNow()
+	}
+	assert.Equal(t, sdk.StatusFail.String(), readPluginImageResult(dir, nil).Status)
+}
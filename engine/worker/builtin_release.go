@@ -11,6 +11,15 @@ import (
 
 func runRelease(w *currentWorker) BuiltInAction {
 	return func(ctx context.Context, a *sdk.Action, buildID int64, params *[]sdk.Parameter, sendLog LoggerFunc) sdk.Result {
+		if w.currentJob.wJob == nil {
+			res := sdk.Result{
+				Status: sdk.StatusFail.String(),
+				Reason: "Release is only available with CDS Workflow.",
+			}
+			sendLog(res.Reason)
+			return res
+		}
+
 		artifactList := sdk.ParameterFind(a.Parameters, "artifacts")
 		tag := sdk.ParameterFind(a.Parameters, "tag")
 		title := sdk.ParameterFind(a.Parameters, "title")
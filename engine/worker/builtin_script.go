@@ -162,7 +162,7 @@ func runScriptAction(w *currentWorker) BuiltInAction {
 				cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", envName, p.Value))
 			}
 
-			for _, p := range w.currentJob.buildVariables {
+			for _, p := range w.getBuildVariables() {
 				envName := strings.Replace(p.Name, ".", "_", -1)
 				envName = strings.ToUpper(envName)
 				cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", envName, p.Value))
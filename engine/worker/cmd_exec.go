@@ -0,0 +1,400 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v2"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/exportentities"
+	"github.com/ovh/cds/sdk/log"
+)
+
+var (
+	cmdExecParams        []string
+	cmdExecParamsFile    string
+	cmdExecBasedir       string
+	cmdExecArtifactsDir  string
+	cmdExecKeepWorkspace bool
+)
+
+func cmdExec(w *currentWorker) *cobra.Command {
+	c := &cobra.Command{
+		Use:   "exec",
+		Short: "worker exec <pipeline file> [--param name=value] [--params-file <file>]",
+		Long: `Run locally a pipeline exported as yaml or json, without CDS API.
+
+Stages are run in order, and the jobs of a stage one after the other. Pipeline parameters
+are set with --param or --params-file, other parameters (git.branch, cds.application...) can be set the same way.
+Artifacts are uploaded and downloaded in a local directory.`,
+		Run: execCmd(w),
+	}
+	c.Flags().StringArrayVar(&cmdExecParams, "param", nil, "Parameter value, can be repeated. Ex: --param name=value --param git.branch=master")
+	c.Flags().StringVar(&cmdExecParamsFile, "params-file", "", "Yaml or json file with the parameters values. Ex: {\"name\": \"value\"}")
+	c.Flags().StringVar(&cmdExecBasedir, "basedir", "", "This directory (default TMPDIR os environment var) will contains jobs working directories")
+	c.Flags().StringVar(&cmdExecArtifactsDir, "artifacts-dir", "", "Directory where the artifacts are uploaded and downloaded (default <basedir>/artifacts)")
+	c.Flags().BoolVar(&cmdExecKeepWorkspace, "keep-workspace", false, "Keep the working directories of the jobs")
+	return c
+}
+
+func execCmd(w *currentWorker) func(cmd *cobra.Command, args []string) {
+	return func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			sdk.Exit("Wrong usage: Example : worker exec pipeline.yml --param name=value\n")
+		}
+
+		// only the steps logs are printed, unless a log level is given
+		level := "error"
+		if f := cmd.Flag("log-level"); f != nil && f.Changed {
+			level = viper.GetString("log_level")
+		}
+		log.Initialize(&log.Conf{Level: level})
+
+		pip, errP := loadLocalPipeline(args[0])
+		if errP != nil {
+			sdk.Exit("cannot load pipeline %s: %s\n", args[0], errP)
+		}
+
+		values := map[string]string{}
+		if cmdExecParamsFile != "" {
+			btes, err := ioutil.ReadFile(cmdExecParamsFile)
+			if err != nil {
+				sdk.Exit("cannot read parameters file %s: %s\n", cmdExecParamsFile, err)
+			}
+			if err := yaml.Unmarshal(btes, &values); err != nil {
+				sdk.Exit("cannot read parameters file %s: %s\n", cmdExecParamsFile, err)
+			}
+		}
+		for _, p := range cmdExecParams {
+			t := strings.SplitN(p, "=", 2)
+			if len(t) != 2 {
+				sdk.Exit("invalid parameter %s, expected name=value\n", p)
+			}
+			values[t[0]] = t[1]
+		}
+
+		if err := w.initLocal(cmdExecBasedir, cmdExecArtifactsDir); err != nil {
+			sdk.Exit("%s\n", err)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		c := make(chan os.Signal, 1)
+		signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+		defer signal.Stop(c)
+		go func() {
+			select {
+			case <-c:
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+
+		// the worker server is used by worker export, worker upload... in scripts
+		w.initServer(ctx)
+
+		if !w.execPipeline(ctx, pip, localPipelineParameters(pip, values), cmdExecKeepWorkspace) {
+			cancel()
+			os.Exit(1)
+		}
+	}
+}
+
+func (w *currentWorker) initLocal(basedir, artifactsDir string) error {
+	w.local.enabled = true
+	w.status.Name = "local"
+
+	if basedir == "" {
+		basedir = os.TempDir()
+	}
+	if artifactsDir == "" {
+		artifactsDir = path.Join(basedir, "artifacts")
+	}
+
+	// the jobs change the current directory
+	var err error
+	if w.basedir, err = filepath.Abs(basedir); err != nil {
+		return fmt.Errorf("invalid basedir %s: %s", basedir, err)
+	}
+	if w.local.artifactsDir, err = filepath.Abs(artifactsDir); err != nil {
+		return fmt.Errorf("invalid artifacts directory %s: %s", artifactsDir, err)
+	}
+	if err := os.MkdirAll(w.local.artifactsDir, os.FileMode(0755)); err != nil {
+		return fmt.Errorf("cannot create artifacts directory %s: %s", w.local.artifactsDir, err)
+	}
+	return nil
+}
+
+// loadLocalPipeline reads a pipeline exported as yaml or json
+func loadLocalPipeline(filename string) (*sdk.Pipeline, error) {
+	btes, format, err := exportentities.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var p exportentities.Pipeline
+	switch format {
+	case exportentities.FormatJSON:
+		err = json.Unmarshal(btes, &p)
+	default:
+		err = yaml.Unmarshal(btes, &p)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return p.Pipeline()
+}
+
+// localPipelineParameters computes the parameters of the pipeline run from the default values of the pipeline parameters
+// and the given values. A value is set to the pipeline parameter with the same name, as cds.pip.<name>, if any.
+func localPipelineParameters(pip *sdk.Pipeline, values map[string]string) []sdk.Parameter {
+	params := []sdk.Parameter{}
+	for _, p := range pip.Parameter {
+		sdk.AddParameter(&params, "cds.pip."+p.Name, p.Type, p.Value)
+	}
+	sdk.AddParameter(&params, "cds.pipeline", sdk.StringParameter, pip.Name)
+	sdk.AddParameter(&params, "cds.version", sdk.StringParameter, "1")
+	sdk.AddParameter(&params, "cds.buildNumber", sdk.StringParameter, "1")
+
+	names := make([]string, 0, len(values))
+	for n := range values {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	for _, n := range names {
+		name := n
+		for _, p := range pip.Parameter {
+			if p.Name == n {
+				name = "cds.pip." + n
+				break
+			}
+		}
+		setParameter(&params, name, values[n])
+	}
+	return params
+}
+
+// execPipeline runs the stages of the pipeline, it returns false if a job failed
+func (w *currentWorker) execPipeline(ctx context.Context, pip *sdk.Pipeline, params []sdk.Parameter, keepWorkspace bool) bool {
+	t0 := time.Now()
+	stages := pip.Stages
+	sort.SliceStable(stages, func(i, j int) bool {
+		return stages[i].BuildOrder < stages[j].BuildOrder
+	})
+
+	success := true
+	for _, s := range stages {
+		if !success || ctx.Err() != nil {
+			fmt.Printf("Stage %s [%s]\n", s.Name, sdk.StatusNeverBuilt)
+			continue
+		}
+		if !s.Enabled {
+			fmt.Printf("Stage %s [%s]\n", s.Name, sdk.StatusDisabled)
+			continue
+		}
+
+		ok, err := checkStagePrerequisites(s, params)
+		if err != nil {
+			fmt.Printf("Stage %s [%s]: %s\n", s.Name, sdk.StatusFail, err)
+			success = false
+			continue
+		}
+		if !ok {
+			fmt.Printf("Stage %s [%s]: prerequisites not satisfied\n", s.Name, sdk.StatusSkipped)
+			continue
+		}
+
+		fmt.Printf("Stage %s\n", s.Name)
+		for _, j := range s.Jobs {
+			if !j.Enabled {
+				fmt.Printf("Job %s [%s]\n", j.Action.Name, sdk.StatusDisabled)
+				continue
+			}
+
+			fmt.Printf("Job %s\n", j.Action.Name)
+			res := w.execJob(ctx, s, j, params, keepWorkspace)
+			if res.Reason != "" {
+				fmt.Printf("Job %s [%s]: %s\n", j.Action.Name, res.Status, strings.TrimSpace(res.Reason))
+			} else {
+				fmt.Printf("Job %s [%s]\n", j.Action.Name, res.Status)
+			}
+			if res.Status != sdk.StatusSuccess.String() && res.Status != sdk.StatusDisabled.String() {
+				success = false
+			}
+		}
+	}
+
+	status := sdk.StatusSuccess
+	if !success {
+		status = sdk.StatusFail
+	}
+	fmt.Printf("Pipeline %s [%s] (%s)\n", pip.Name, status, sdk.Round(time.Since(t0), time.Second))
+	return success
+}
+
+// execJob runs a job as processJob does, without CDS API
+func (w *currentWorker) execJob(ctx context.Context, s sdk.Stage, j sdk.Job, pipParams []sdk.Parameter, keepWorkspace bool) sdk.Result {
+	// the variables are replaced in the action, keep the pipeline untouched
+	j.Action = copyAction(j.Action)
+	w.currentJob.pbJob = sdk.PipelineBuildJob{}
+	w.currentJob.wJob = nil
+	w.currentJob.buildVariables = nil
+	w.currentJob.stepParams = nil
+
+	for _, r := range j.Action.Requirements {
+		switch r.Type {
+		case sdk.ModelRequirement, sdk.ServiceRequirement, sdk.VolumeRequirement, sdk.PluginRequirement:
+			fmt.Printf("Requirement %s %s ignored\n", r.Type, r.Value)
+			continue
+		}
+		ok, err := checkRequirement(w, r)
+		if err != nil {
+			log.Warning("execJob> error on checkRequirement %s", err)
+		}
+		if !ok {
+			return sdk.Result{
+				Status: sdk.StatusFail.String(),
+				Reason: fmt.Sprintf("requirement %s %s not satisfied", r.Type, r.Value),
+			}
+		}
+	}
+
+	if err := w.resolveLocalActions(&j.Action); err != nil {
+		return sdk.Result{
+			Status: sdk.StatusFail.String(),
+			Reason: err.Error(),
+		}
+	}
+
+	jobPath := path.Join(sdk.ParameterValue(pipParams, "cds.pipeline"), s.Name, j.Action.Name)
+	wd := workingDirectory(w.basedir, jobPath)
+	if err := setupBuildDirectory(wd); err != nil {
+		return sdk.Result{
+			Status: sdk.StatusFail.String(),
+			Reason: fmt.Sprintf("Error: cannot setup working directory: %s", err),
+		}
+	}
+	if !keepWorkspace {
+		defer func() {
+			if err := teardownBuildDirectory(wd); err != nil {
+				log.Error("Cannot remove build directory: %s", err)
+			}
+		}()
+	} else {
+		fmt.Printf("Workspace of job %s: %s\n", j.Action.Name, wd)
+	}
+
+	keysDirectory = workingDirectory(w.basedir, jobPath)
+	if err := os.MkdirAll(keysDirectory, 0755); err != nil {
+		return sdk.Result{
+			Status: sdk.StatusFail.String(),
+			Reason: fmt.Sprintf("Error: cannot setup workingDirectory (%s)", err),
+		}
+	}
+	defer os.RemoveAll(keysDirectory)
+
+	params := make([]sdk.Parameter, len(pipParams))
+	copy(params, pipParams)
+	sdk.AddParameter(&params, "cds.stage", sdk.StringParameter, s.Name)
+	sdk.AddParameter(&params, "cds.job", sdk.StringParameter, j.Action.Name)
+	sdk.AddParameter(&params, "cds.workspace", sdk.StringParameter, wd)
+	sdk.AddParameter(&params, "cds.worker", sdk.StringParameter, w.status.Name)
+
+	processJobParameter(&params, nil)
+	if err := w.processActionVariables(&j.Action, nil, params, nil); err != nil {
+		return sdk.Result{
+			Status: sdk.StatusFail.String(),
+			Reason: fmt.Sprintf("Error: cannot process action %s parameters", j.Action.Name),
+		}
+	}
+
+	return w.startAction(ctx, &j.Action, 0, &params, -1, "")
+}
+
+// resolveLocalActions sets the type of the steps which are not builtin actions. Without CDS API,
+// only the plugins whose binary is in the basedir can be resolved.
+func (w *currentWorker) resolveLocalActions(a *sdk.Action) error {
+	for i := range a.Actions {
+		step := &a.Actions[i]
+		if step.Type != "" {
+			continue
+		}
+		if _, ok := mapBuiltinActions[step.Name]; ok {
+			step.Type = sdk.BuiltinAction
+			continue
+		}
+		if _, err := os.Stat(path.Join(w.basedir, step.Name)); err == nil {
+			step.Type = sdk.PluginAction
+			continue
+		}
+		return fmt.Errorf("action %s can't be run without CDS API, only builtin actions and plugins in %s are available", step.Name, w.basedir)
+	}
+	return nil
+}
+
+func copyAction(a sdk.Action) sdk.Action {
+	c := a
+	c.Parameters = append([]sdk.Parameter(nil), a.Parameters...)
+	c.Requirements = append([]sdk.Requirement(nil), a.Requirements...)
+	c.Actions = make([]sdk.Action, len(a.Actions))
+	for i := range a.Actions {
+		c.Actions[i] = copyAction(a.Actions[i])
+	}
+	return c
+}
+
+// checkStagePrerequisites checks the stage conditions against the parameters, as the API does
+func checkStagePrerequisites(s sdk.Stage, params []sdk.Parameter) (bool, error) {
+	for _, p := range s.Prerequisites {
+		name := p.Parameter
+		if !strings.HasPrefix(name, "git.") && !strings.HasPrefix(name, "cds.") {
+			name = "cds.pip." + name
+		}
+
+		value := sdk.ParameterFind(params, name)
+		if value == nil {
+			continue
+		}
+
+		expectedValue := p.ExpectedValue
+		for _, pp := range params {
+			expectedValue = strings.Replace(expectedValue, "{{."+pp.Name+"}}", pp.Value, -1)
+		}
+		var not bool
+		if strings.HasPrefix(expectedValue, "not ") {
+			expectedValue = strings.Replace(expectedValue, "not ", "", 1)
+			not = true
+		}
+		if !strings.HasPrefix(expectedValue, "^") {
+			expectedValue = "^" + expectedValue
+		}
+		if !strings.HasSuffix(expectedValue, "$") {
+			expectedValue = expectedValue + "$"
+		}
+
+		ok, err := regexp.MatchString(expectedValue, value.Value)
+		if err != nil {
+			return false, fmt.Errorf("invalid condition on %s: %s", p.Parameter, err)
+		}
+		if ok == not {
+			return false, nil
+		}
+	}
+	return true, nil
+}
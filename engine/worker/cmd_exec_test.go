@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+const localPipeline = `name: local
+parameters:
+  word:
+    type: string
+    default: foo
+stages:
+  1|build:
+    jobs:
+      build:
+        steps:
+        - script: echo {{.cds.pip.word}} > word.txt
+        - artifactUpload:
+            path: word.txt
+            tag: "{{.cds.version}}"
+  2|check:
+    jobs:
+      check:
+        steps:
+        - artifactDownload:
+            path: dl
+            tag: "{{.cds.version}}"
+        - script: grep -q bar dl/word.txt
+  3|deploy:
+    conditions:
+      git.branch: master
+    jobs:
+      deploy:
+        steps:
+        - script: exit 1
+`
+
+func Test_execPipeline(t *testing.T) {
+	wd, err := os.Getwd()
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	home := os.Getenv("HOME")
+	defer func() {
+		os.Chdir(wd)
+		os.Setenv("HOME", home)
+	}()
+
+	basedir, err := ioutil.TempDir("", "cds-worker-exec")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.RemoveAll(basedir)
+
+	pipFile := path.Join(basedir, "pipeline.yml")
+	if !assert.NoError(t, ioutil.WriteFile(pipFile, []byte(localPipeline), 0644)) {
+		t.FailNow()
+	}
+	pip, err := loadLocalPipeline(pipFile)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	w := &currentWorker{}
+	if !assert.NoError(t, w.initLocal(basedir, "")) {
+		t.FailNow()
+	}
+
+	params := localPipelineParameters(pip, map[string]string{"word": "bar", "git.branch": "feat/local"})
+	assert.Equal(t, "bar", sdk.ParameterValue(params, "cds.pip.word"))
+	assert.Equal(t, "feat/local", sdk.ParameterValue(params, "git.branch"))
+
+	assert.True(t, w.execPipeline(context.Background(), pip, params, false))
+	_, err = os.Stat(path.Join(basedir, "artifacts", "1", "word.txt"))
+	assert.NoError(t, err)
+
+	params = localPipelineParameters(pip, map[string]string{"word": "baz", "git.branch": "feat/local"})
+	assert.False(t, w.execPipeline(context.Background(), pip, params, false), "check job must fail")
+
+	params = localPipelineParameters(pip, map[string]string{"word": "bar", "git.branch": "master"})
+	assert.False(t, w.execPipeline(context.Background(), pip, params, false), "deploy job must fail")
+}
+
+func Test_checkStagePrerequisites(t *testing.T) {
+	s := sdk.Stage{
+		Prerequisites: []sdk.Prerequisite{
+			{Parameter: "git.branch", ExpectedValue: "master|release/.*"},
+			{Parameter: "env", ExpectedValue: "not {{.cds.pip.skip}}"},
+		},
+	}
+	params := []sdk.Parameter{
+		{Name: "git.branch", Value: "release/1.0"},
+		{Name: "cds.pip.env", Value: "prod"},
+		{Name: "cds.pip.skip", Value: "dev"},
+	}
+
+	ok, err := checkStagePrerequisites(s, params)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	params[1].Value = "dev"
+	ok, err = checkStagePrerequisites(s, params)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	params[1].Value = "prod"
+	params[0].Value = "develop"
+	ok, err = checkStagePrerequisites(s, params)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func Test_updateStepStatusLocal(t *testing.T) {
+	stdout := os.Stdout
+	r, wr, err := os.Pipe()
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	os.Stdout = wr
+	defer func() { os.Stdout = stdout }()
+
+	w := &currentWorker{}
+	w.local.enabled = true
+	assert.NoError(t, w.updateStepStatus(0, 0, sdk.StatusBuilding.String()))
+	assert.NoError(t, w.updateStepStatus(0, 0, sdk.StatusSuccess.String()))
+	assert.NoError(t, w.updateStepStatus(0, 1, sdk.StatusNeverBuilt.String()))
+	wr.Close()
+
+	out, err := ioutil.ReadAll(r)
+	assert.NoError(t, err)
+	assert.Equal(t, "Step 0 [Building]\nStep 0 [Success]\nStep 1 [Never Built]\n", string(out))
+}
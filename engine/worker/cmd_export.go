@@ -92,7 +92,7 @@ func (wk *currentWorker) addVariableInPipelineBuild(v sdk.Variable, params *[]sd
 	// OK, so now we got our new variable. We need to:
 	// - add it as a build var in API
 	if strings.HasPrefix(v.Name, "cds.build") {
-		wk.currentJob.buildVariables = append(wk.currentJob.buildVariables, v)
+		wk.addBuildVariable(v)
 	} else if params != nil {
 		*params = append(*params, sdk.Parameter{
 			Name:  v.Name,
@@ -101,6 +101,11 @@ func (wk *currentWorker) addVariableInPipelineBuild(v sdk.Variable, params *[]sd
 		})
 	}
 
+	// without API, the variable is only available in the current job
+	if wk.local.enabled {
+		return http.StatusOK, nil
+	}
+
 	// - add it in current building Action
 	data, errm := json.Marshal(v)
 	if errm != nil {
@@ -59,6 +59,9 @@ func cmdMain(w *currentWorker) *cobra.Command {
 	flags.String("basedir", "", "This directory (default TMPDIR os environment var) will contains worker working directory and temporary files")
 	viper.BindPFlag("basedir", flags.Lookup("basedir"))
 
+	flags.String("labels", "", "Comma separated labels of the worker, checked by label requirements. Ex: --labels=gpu,ssd")
+	viper.BindPFlag("labels", flags.Lookup("labels"))
+
 	flags.Int("ttl", 30, "Worker time to live (minutes)")
 	viper.BindPFlag("ttl", flags.Lookup("ttl"))
 
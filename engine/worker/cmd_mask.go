@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func cmdMask(w *currentWorker) *cobra.Command {
+	c := &cobra.Command{
+		Use:   "mask",
+		Short: "worker mask [<value>...]",
+		Long: `worker mask command registers values which will be masked in the logs of the current job.
+Values are read from stdin, one per line, if none is given as argument: echo $MY_TOKEN | worker mask`,
+		Run: maskCmd(w),
+	}
+	return c
+}
+
+func maskCmd(w *currentWorker) func(cmd *cobra.Command, args []string) {
+	return func(cmd *cobra.Command, args []string) {
+		portS := os.Getenv(WorkerServerPort)
+		if portS == "" {
+			sdk.Exit("%s not found, are you running inside a CDS worker job?\n", WorkerServerPort)
+		}
+
+		port, errPort := strconv.Atoi(portS)
+		if errPort != nil {
+			sdk.Exit("cannot parse '%s' as a port number", portS)
+		}
+
+		values := args
+		if len(values) == 0 {
+			scanner := bufio.NewScanner(os.Stdin)
+			for scanner.Scan() {
+				if line := scanner.Text(); line != "" {
+					values = append(values, line)
+				}
+			}
+			if err := scanner.Err(); err != nil {
+				sdk.Exit("cannot read stdin: %v\n", err)
+			}
+		}
+
+		if len(values) == 0 {
+			sdk.Exit("Wrong usage: Example : worker mask <value>")
+		}
+
+		data, errMarshal := json.Marshal(values)
+		if errMarshal != nil {
+			sdk.Exit("internal error (%s)\n", errMarshal)
+		}
+
+		req, errRequest := http.NewRequest("POST", fmt.Sprintf("http://127.0.0.1:%d/mask", port), bytes.NewReader(data))
+		if errRequest != nil {
+			sdk.Exit("cannot post worker mask (Request): %s\n", errRequest)
+		}
+
+		client := http.DefaultClient
+		client.Timeout = 5 * time.Second
+
+		resp, errDo := client.Do(req)
+		if errDo != nil {
+			sdk.Exit("command failed: %v\n", errDo)
+		}
+
+		if resp.StatusCode >= 300 {
+			body, err := ioutil.ReadAll(resp.Body)
+			if err != nil {
+				sdk.Exit("mask failed: unable to read body %v\n", err)
+			}
+			defer resp.Body.Close()
+			cdsError := sdk.DecodeError(body)
+			sdk.Exit("mask failed: %v\n", cdsError)
+		}
+	}
+}
+
+func (wk *currentWorker) maskHandler(w http.ResponseWriter, r *http.Request) {
+	data, errRead := ioutil.ReadAll(r.Body)
+	if errRead != nil {
+		writeError(w, r, sdk.ErrWrongRequest)
+		return
+	}
+
+	var values []string
+	if err := json.Unmarshal(data, &values); err != nil {
+		writeError(w, r, sdk.ErrWrongRequest)
+		return
+	}
+
+	masker := getLogSecrets()
+	if masker == nil {
+		writeError(w, r, sdk.ErrWorkflowNodeRunJobNotFound)
+		return
+	}
+
+	for _, v := range values {
+		masker.Add("", v)
+	}
+}
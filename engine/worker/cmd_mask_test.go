@@ -0,0 +1,40 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// Test_maskHandler adds secrets while a job sets and clears its masker, run it with -race
+func Test_maskHandler(t *testing.T) {
+	w := &currentWorker{}
+	defer setLogSecrets(nil)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			setLogSecrets(sdk.NewLogMasker(nil))
+			setLogSecrets(nil)
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		rec := httptest.NewRecorder()
+		w.maskHandler(rec, httptest.NewRequest(http.MethodPost, "/mask", bytes.NewReader([]byte(`["foo"]`))))
+	}
+	wg.Wait()
+
+	setLogSecrets(sdk.NewLogMasker(nil))
+	rec := httptest.NewRecorder()
+	w.maskHandler(rec, httptest.NewRequest(http.MethodPost, "/mask", bytes.NewReader([]byte(`["s3cr3t"]`))))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "password: **********", getLogSecrets().Mask("password: s3cr3t"))
+}
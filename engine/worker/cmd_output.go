@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/sdk"
+)
+
+var (
+	cmdOutputType      string
+	cmdOutputPropagate bool
+)
+
+// stepOutput is sent by worker output to the worker server
+type stepOutput struct {
+	sdk.WorkflowNodeOutput
+	Step      string `json:"step"`
+	Propagate bool   `json:"propagate"`
+}
+
+func cmdOutput(w *currentWorker) *cobra.Command {
+	c := &cobra.Command{
+		Use:   "output",
+		Short: "worker output <name> <value> [--type string|number|boolean] [--propagate]",
+		Long: `worker output command sets an output of the current step, which must be named.
+The next steps of the job get it in the variable steps.<step name>.outputs.<name>.
+With --propagate, the output is also sent to CDS: the next pipelines of the workflow get it in the variable workflow.<node name>.output.<name>`,
+		Run: outputCmd(w),
+	}
+	c.Flags().StringVar(&cmdOutputType, "type", sdk.StringParameter, "Type of the output: string, number or boolean")
+	c.Flags().BoolVar(&cmdOutputPropagate, "propagate", false, "Propagate the output to the next pipelines of the workflow")
+	return c
+}
+
+func outputCmd(w *currentWorker) func(cmd *cobra.Command, args []string) {
+	return func(cmd *cobra.Command, args []string) {
+		portS := os.Getenv(WorkerServerPort)
+		if portS == "" {
+			sdk.Exit("%s not found, are you running inside a CDS worker job?\n", WorkerServerPort)
+		}
+
+		port, errPort := strconv.Atoi(portS)
+		if errPort != nil {
+			sdk.Exit("cannot parse '%s' as a port number", portS)
+		}
+
+		if len(args) != 2 {
+			sdk.Exit("Wrong usage: Example : worker output <name> <value>")
+		}
+
+		step := os.Getenv("CDS_STEP_NAME")
+		if step == "" {
+			sdk.Exit("outputs are only available in named steps\n")
+		}
+
+		o := stepOutput{
+			WorkflowNodeOutput: sdk.WorkflowNodeOutput{
+				Name:  args[0],
+				Type:  cmdOutputType,
+				Value: args[1],
+			},
+			Step:      step,
+			Propagate: cmdOutputPropagate,
+		}
+		if err := o.IsValid(); err != nil {
+			sdk.Exit("output failed: %v\n", err)
+		}
+
+		data, errMarshal := json.Marshal(o)
+		if errMarshal != nil {
+			sdk.Exit("internal error (%s)\n", errMarshal)
+		}
+
+		req, errRequest := http.NewRequest("POST", fmt.Sprintf("http://127.0.0.1:%d/output", port), bytes.NewReader(data))
+		if errRequest != nil {
+			sdk.Exit("cannot post worker output (Request): %s\n", errRequest)
+		}
+
+		client := http.DefaultClient
+		client.Timeout = 5 * time.Minute
+
+		resp, errDo := client.Do(req)
+		if errDo != nil {
+			sdk.Exit("command failed: %v\n", errDo)
+		}
+
+		if resp.StatusCode >= 300 {
+			body, err := ioutil.ReadAll(resp.Body)
+			if err != nil {
+				sdk.Exit("output failed: unable to read body %v\n", err)
+			}
+			defer resp.Body.Close()
+			cdsError := sdk.DecodeError(body)
+			sdk.Exit("output failed: %v\n", cdsError)
+		}
+	}
+}
+
+func (wk *currentWorker) outputHandler(w http.ResponseWriter, r *http.Request) {
+	data, errRead := ioutil.ReadAll(r.Body)
+	if errRead != nil {
+		writeError(w, r, sdk.ErrWrongRequest)
+		return
+	}
+
+	var o stepOutput
+	if err := json.Unmarshal(data, &o); err != nil {
+		writeError(w, r, sdk.ErrWrongRequest)
+		return
+	}
+
+	if err := wk.addStepOutput(o); err != nil {
+		writeError(w, r, err)
+		return
+	}
+}
+
+// addStepOutput makes the output available to the next steps of the job, and sends it to CDS if it is propagated
+func (wk *currentWorker) addStepOutput(o stepOutput) error {
+	if o.Step == "" {
+		return sdk.ErrInvalidStepName
+	}
+	if err := o.IsValid(); err != nil {
+		return err
+	}
+
+	if o.Propagate && !wk.local.enabled {
+		if wk.currentJob.wJob == nil {
+			return sdk.WrapError(sdk.ErrWorkflowNodeRunJobNotFound, "outputs can only be propagated with CDS Workflow")
+		}
+		if err := wk.client.QueueJobSendOutput(wk.currentJob.wJob.ID, o.WorkflowNodeOutput); err != nil {
+			return err
+		}
+	}
+
+	v := sdk.Variable{
+		Name:  "steps." + o.Step + ".outputs." + o.Name,
+		Type:  o.Type,
+		Value: o.Value,
+	}
+	wk.setBuildVariable(v)
+	return nil
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_addStepOutput(t *testing.T) {
+	wd, err := os.Getwd()
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.Chdir(wd)
+
+	basedir, err := ioutil.TempDir("", "cds-worker-output")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.RemoveAll(basedir)
+
+	w := &currentWorker{}
+	if !assert.NoError(t, w.initLocal(basedir, "")) {
+		t.FailNow()
+	}
+	if !assert.NoError(t, os.Chdir(basedir)) {
+		t.FailNow()
+	}
+
+	output := func(step, name, tpe, value string) stepOutput {
+		return stepOutput{
+			WorkflowNodeOutput: sdk.WorkflowNodeOutput{Name: name, Type: tpe, Value: value},
+			Step:               step,
+			Propagate:          true,
+		}
+	}
+	assert.NoError(t, w.addStepOutput(output("build", "digest", sdk.StringParameter, "sha256:abcd")))
+	assert.NoError(t, w.addStepOutput(output("build", "size", sdk.NumberParameter, "42")))
+	assert.NoError(t, w.addStepOutput(output("build", "size", sdk.NumberParameter, "43")))
+	assert.Error(t, w.addStepOutput(output("", "digest", sdk.StringParameter, "sha256:abcd")))
+	assert.Error(t, w.addStepOutput(output("build", "size", sdk.NumberParameter, "big")))
+	assert.Len(t, w.getBuildVariables(), 2)
+
+	script := sdk.NewStepScript(`test "$STEPS_BUILD_OUTPUTS_DIGEST" = "sha256:abcd" && test "{{.steps.build.outputs.size}}" = "43"`)
+	script.Enabled = true
+	script.StepName = "check"
+	script.Condition = `return steps_build_outputs_size == "43"`
+	params := []sdk.Parameter{}
+	r, _ := w.runSteps(context.Background(), []sdk.Action{script}, nil, 0, &params, -1, "", 0)
+	assert.Equal(t, sdk.StatusSuccess.String(), r.Status)
+	assert.Equal(t, sdk.StatusSuccess.String(), sdk.ParameterValue(params, "steps.check.status"))
+	assert.Equal(t, "check", sdk.ParameterValue(params, "cds.step.name"))
+}
@@ -3,8 +3,10 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net"
 	"net/http"
+	"os"
 	"strconv"
 	"strings"
 	"time"
@@ -19,6 +21,10 @@ import (
 // Used only to export build variables for now
 const WorkerServerPort = "CDS_EXPORT_PORT"
 
+// WorkerStepOrder is name of environment variable set to the order of the running step
+// Used by worker upload and worker tmpl, parallel steps are running at the same time
+const WorkerStepOrder = "CDS_STEP_ORDER"
+
 // This handler is started by the worker instance waiting for action
 func (w *currentWorker) serve(c context.Context) (int, error) {
 	listener, err := net.Listen("tcp", "127.0.0.1:0")
@@ -39,6 +45,8 @@ func (w *currentWorker) serve(c context.Context) (int, error) {
 	r.HandleFunc("/tmpl", w.tmplHandler)
 	r.HandleFunc("/tag", w.tagHandler)
 	r.HandleFunc("/exit", w.exitHandler)
+	r.HandleFunc("/mask", w.maskHandler)
+	r.HandleFunc("/output", w.outputHandler)
 
 	srv := &http.Server{
 		Handler:      r,
@@ -63,6 +71,28 @@ func (w *currentWorker) serve(c context.Context) (int, error) {
 	return int(port), nil
 }
 
+// stepOrderFromEnv returns the order of the step running the command
+func stepOrderFromEnv() int {
+	stepOrderS := os.Getenv(WorkerStepOrder)
+	if stepOrderS == "" {
+		sdk.Exit("%s not found, are you running inside a CDS worker job?\n", WorkerStepOrder)
+	}
+	stepOrder, err := strconv.Atoi(stepOrderS)
+	if err != nil {
+		sdk.Exit("cannot parse '%s' as a step order", stepOrderS)
+	}
+	return stepOrder
+}
+
+// stepOrderFromRequest returns the order of the step which sent the request
+func stepOrderFromRequest(r *http.Request) (int, error) {
+	stepOrder, err := strconv.Atoi(r.URL.Query().Get("step"))
+	if err != nil {
+		return 0, sdk.NewError(sdk.ErrWrongRequest, fmt.Errorf("invalid step order: %v", err))
+	}
+	return stepOrder, nil
+}
+
 func writeJSON(w http.ResponseWriter, data interface{}, status int) {
 	b, _ := json.Marshal(data)
 	w.Header().Add("Content-Type", "application/json")
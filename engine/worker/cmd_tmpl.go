@@ -53,7 +53,7 @@ func tmplCmd(w *currentWorker) func(cmd *cobra.Command, args []string) {
 			sdk.Exit("internal error (%s)\n", errMarshal)
 		}
 
-		req, errRequest := http.NewRequest("POST", fmt.Sprintf("http://127.0.0.1:%d/tmpl", port), bytes.NewReader(data))
+		req, errRequest := http.NewRequest("POST", fmt.Sprintf("http://127.0.0.1:%d/tmpl?step=%d", port, stepOrderFromEnv()), bytes.NewReader(data))
 		if errRequest != nil {
 			sdk.Exit("cannot post worker tmpl (Request): %s\n", errRequest)
 		}
@@ -78,6 +78,12 @@ func tmplCmd(w *currentWorker) func(cmd *cobra.Command, args []string) {
 }
 
 func (wk *currentWorker) tmplHandler(w http.ResponseWriter, r *http.Request) {
+	stepOrder, err := stepOrderFromRequest(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
 	// Get body
 	data, errRead := ioutil.ReadAll(r.Body)
 	if errRead != nil {
@@ -100,7 +106,13 @@ func (wk *currentWorker) tmplHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	vars := sdk.ParametersToMap(wk.currentJob.params)
+	params, ok := wk.getStepParams(stepOrder)
+	if !ok {
+		newError := sdk.NewError(sdk.ErrWrongRequest, fmt.Errorf("step %d is not running", stepOrder))
+		writeError(w, r, newError)
+		return
+	}
+	vars := sdk.ParametersToMap(params)
 
 	res, err := sdk.Interpolate(string(btes), vars)
 	if err != nil {
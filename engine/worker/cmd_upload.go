@@ -48,6 +48,7 @@ func uploadCmd(w *currentWorker) func(cmd *cobra.Command, args []string) {
 			sdk.Exit("Wrong usage: Example : worker upload --tag={{.cds.version}} filea fileb filec*")
 		}
 
+		stepOrder := stepOrderFromEnv()
 		for _, arg := range args {
 			a := sdk.Artifact{
 				Name: arg,
@@ -59,7 +60,7 @@ func uploadCmd(w *currentWorker) func(cmd *cobra.Command, args []string) {
 				sdk.Exit("internal error (%s)\n", errMarshal)
 			}
 
-			req, errRequest := http.NewRequest("POST", fmt.Sprintf("http://127.0.0.1:%d/upload", port), bytes.NewReader(data))
+			req, errRequest := http.NewRequest("POST", fmt.Sprintf("http://127.0.0.1:%d/upload?step=%d", port, stepOrder), bytes.NewReader(data))
 			if errRequest != nil {
 				sdk.Exit("cannot post worker upload (Request): %s\n", errRequest)
 			}
@@ -94,6 +95,12 @@ func (wk *currentWorker) uploadHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	stepOrder, err := stepOrderFromRequest(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
 	action := sdk.Action{
 		Parameters: []sdk.Parameter{
 			{
@@ -109,7 +116,7 @@ func (wk *currentWorker) uploadHandler(w http.ResponseWriter, r *http.Request) {
 		},
 	}
 
-	sendLog := getLogger(wk, wk.currentJob.pbJob.ID, wk.currentJob.currentStep)
+	sendLog := getLogger(wk, wk.currentJob.pbJob.ID, stepOrder)
 
 	if wk.currentJob.wJob == nil {
 		if result := runArtifactUpload(wk)(context.Background(), &action, wk.currentJob.pbJob.ID, &wk.currentJob.pbJob.Parameters, sendLog); result.Status != sdk.StatusSuccess.String() {
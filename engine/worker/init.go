@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/spf13/viper"
 	"golang.org/x/net/context"
@@ -80,6 +81,11 @@ func initViper(w *currentWorker) {
 	if w.basedir == "" {
 		w.basedir = os.TempDir()
 	}
+	for _, l := range strings.Split(viper.GetString("labels"), ",") {
+		if l = strings.TrimSpace(l); l != "" {
+			w.labels = append(w.labels, l)
+		}
+	}
 	w.bookedPBJobID = viper.GetInt64("booked_pb_job_id")
 	w.bookedWJobID = viper.GetInt64("booked_workflow_job_id")
 
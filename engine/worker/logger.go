@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/golang/protobuf/ptypes"
@@ -15,13 +16,34 @@ import (
 	"github.com/ovh/cds/sdk/log"
 )
 
-var logsecrets []sdk.Variable
+// logsecrets masks the secrets of the running job, it is set by the job goroutine and read by the worker server handlers
+var (
+	logsecrets      *sdk.LogMasker
+	logsecretsMutex sync.RWMutex
+)
+
+func setLogSecrets(m *sdk.LogMasker) {
+	logsecretsMutex.Lock()
+	defer logsecretsMutex.Unlock()
+	logsecrets = m
+}
+
+func getLogSecrets() *sdk.LogMasker {
+	logsecretsMutex.RLock()
+	defer logsecretsMutex.RUnlock()
+	return logsecrets
+}
 
 func (w *currentWorker) sendLog(buildID int64, value string, stepOrder int, final bool) error {
-	for i := range logsecrets {
-		if len(logsecrets[i].Value) >= 6 {
-			value = strings.Replace(value, logsecrets[i].Value, "**"+logsecrets[i].Name+"**", -1)
+	value = getLogSecrets().Mask(value)
+
+	// there is no API to send the logs to, print them
+	if w.local.enabled {
+		if !strings.HasSuffix(value, "\n") {
+			value += "\n"
 		}
+		fmt.Print(value)
+		return nil
 	}
 
 	var id = w.currentJob.pbJob.PipelineBuildID
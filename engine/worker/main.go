@@ -2,6 +2,7 @@ package main
 
 import (
 	"container/list"
+	"sync"
 
 	"google.golang.org/grpc"
 
@@ -19,6 +20,7 @@ type currentWorker struct {
 	bookedWJobID  int64
 	nbActionsDone int
 	basedir       string
+	labels        []string
 	manualExit    bool
 	logger        struct {
 		logChan chan sdk.Log
@@ -34,19 +36,103 @@ type currentWorker struct {
 		conn    *grpc.ClientConn
 	}
 	currentJob struct {
-		pbJob          sdk.PipelineBuildJob
-		wJob           *sdk.WorkflowNodeJobRun
-		currentStep    int
+		pbJob      sdk.PipelineBuildJob
+		wJob       *sdk.WorkflowNodeJobRun
+		pkey       string
+		gitsshPath string
+		// mutex protects buildVariables, stepParams and pluginVersions: parallel steps
+		// and the worker server handlers read and write them concurrently
+		mutex          sync.RWMutex
 		buildVariables []sdk.Variable
-		pkey           string
-		gitsshPath     string
-		params         []sdk.Parameter
+		// stepParams are the parameters of the running steps, by step order
+		stepParams map[int][]sdk.Parameter
+		// pluginVersions are the plugin versions resolved when checking the requirements of the job,
+		// by plugin reference: the steps of the job run the checked versions
+		pluginVersions map[string]*sdk.ActionPlugin
 	}
 	status struct {
 		Name   string `json:"name"`
 		Status string `json:"status"`
 	}
 	client cdsclient.Interface
+	// local is set by worker exec, which runs a pipeline without CDS API
+	local struct {
+		enabled      bool
+		artifactsDir string
+	}
+}
+
+// getBuildVariables returns a copy of the build variables of the current job
+func (w *currentWorker) getBuildVariables() []sdk.Variable {
+	w.currentJob.mutex.RLock()
+	defer w.currentJob.mutex.RUnlock()
+	return append([]sdk.Variable(nil), w.currentJob.buildVariables...)
+}
+
+func (w *currentWorker) addBuildVariable(v sdk.Variable) {
+	w.currentJob.mutex.Lock()
+	defer w.currentJob.mutex.Unlock()
+	w.currentJob.buildVariables = append(w.currentJob.buildVariables, v)
+}
+
+// setBuildVariable replaces the build variable with the same name, or adds it
+func (w *currentWorker) setBuildVariable(v sdk.Variable) {
+	w.currentJob.mutex.Lock()
+	defer w.currentJob.mutex.Unlock()
+	for i := range w.currentJob.buildVariables {
+		if w.currentJob.buildVariables[i].Name == v.Name {
+			w.currentJob.buildVariables[i] = v
+			return
+		}
+	}
+	w.currentJob.buildVariables = append(w.currentJob.buildVariables, v)
+}
+
+// setStepParams sets the parameters of a running step and returns the previous ones, nil params unset them
+func (w *currentWorker) setStepParams(stepOrder int, params []sdk.Parameter) []sdk.Parameter {
+	w.currentJob.mutex.Lock()
+	defer w.currentJob.mutex.Unlock()
+	previous := w.currentJob.stepParams[stepOrder]
+	if params == nil {
+		delete(w.currentJob.stepParams, stepOrder)
+		return previous
+	}
+	if w.currentJob.stepParams == nil {
+		w.currentJob.stepParams = map[int][]sdk.Parameter{}
+	}
+	w.currentJob.stepParams[stepOrder] = params
+	return previous
+}
+
+// getStepParams returns a copy of the parameters of a running step
+func (w *currentWorker) getStepParams(stepOrder int) ([]sdk.Parameter, bool) {
+	w.currentJob.mutex.RLock()
+	defer w.currentJob.mutex.RUnlock()
+	params, ok := w.currentJob.stepParams[stepOrder]
+	return append([]sdk.Parameter(nil), params...), ok
+}
+
+// resetPluginVersions forgets the plugin versions resolved for the previous job
+func (w *currentWorker) resetPluginVersions() {
+	w.currentJob.mutex.Lock()
+	defer w.currentJob.mutex.Unlock()
+	w.currentJob.pluginVersions = nil
+}
+
+func (w *currentWorker) setPluginVersion(ref string, ap *sdk.ActionPlugin) {
+	w.currentJob.mutex.Lock()
+	defer w.currentJob.mutex.Unlock()
+	if w.currentJob.pluginVersions == nil {
+		w.currentJob.pluginVersions = map[string]*sdk.ActionPlugin{}
+	}
+	w.currentJob.pluginVersions[ref] = ap
+}
+
+func (w *currentWorker) getPluginVersion(ref string) (*sdk.ActionPlugin, bool) {
+	w.currentJob.mutex.RLock()
+	defer w.currentJob.mutex.RUnlock()
+	ap, ok := w.currentJob.pluginVersions[ref]
+	return ap, ok
 }
 
 func main() {
@@ -59,7 +145,10 @@ func main() {
 	cmd.AddCommand(cmdTmpl(w))
 	cmd.AddCommand(cmdTag(w))
 	cmd.AddCommand(cmdExit(w))
+	cmd.AddCommand(cmdMask(w))
+	cmd.AddCommand(cmdOutput(w))
 	cmd.AddCommand(cmdVersion)
 	cmd.AddCommand(cmdRegister(w))
+	cmd.AddCommand(cmdExec(w))
 	cmd.Execute()
 }
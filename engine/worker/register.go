@@ -23,9 +23,10 @@ func (w *currentWorker) register(form worker.RegistrationForm) error {
 
 	log.Debug("Checking %d requirements", len(requirements))
 	form.BinaryCapabilities = LoopPath(w, requirements)
+	form.HostCapabilities = hostCapabilities(w)
 	form.Version = sdk.VERSION
 	form.OS = runtime.GOOS
-	form.Arch = runtime.GOOS
+	form.Arch = runtime.GOARCH
 
 	worker, uptodate, err := w.client.WorkerRegister(form)
 	if err != nil {
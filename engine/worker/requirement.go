@@ -7,8 +7,10 @@ import (
 	"os"
 	"os/exec"
 	"path"
+	"runtime"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/shirou/gopsutil/mem"
@@ -27,6 +29,10 @@ var requirementCheckFuncs = map[string]func(w *currentWorker, r sdk.Requirement)
 	sdk.ServiceRequirement:       checkServiceRequirement,
 	sdk.MemoryRequirement:        checkMemoryRequirement,
 	sdk.VolumeRequirement:        checkVolumeRequirement,
+	sdk.OSArchRequirement:        checkOSArchRequirement,
+	sdk.LabelRequirement:         checkLabelRequirement,
+	sdk.CPURequirement:           checkCPURequirement,
+	sdk.DiskRequirement:          checkDiskRequirement,
 }
 
 func checkRequirements(w *currentWorker, a *sdk.Action, execGroups []sdk.Group, bookedJobID int64) (bool, []sdk.Requirement) {
@@ -36,6 +42,9 @@ func checkRequirements(w *currentWorker, a *sdk.Action, execGroups []sdk.Group,
 		log.Error("WorkerSetStatus> error on WorkerSetStatus(sdk.StatusChecking): %s", err)
 	}
 
+	// The plugin versions checked here are the ones run by the job
+	w.resetPluginVersions()
+
 	log.Debug("checkRequirements> for JobID:%d model of worker: %+v", bookedJobID, w.model)
 	log.Debug("checkRequirements> for JobID:%d execGroups: %+v", bookedJobID, execGroups)
 
@@ -78,18 +87,53 @@ func checkRequirement(w *currentWorker, r sdk.Requirement) (bool, error) {
 	return check(w, r)
 }
 
-func checkPluginRequirement(w *currentWorker, r sdk.Requirement) (bool, error) {
-	pluginBinary := path.Join(w.basedir, r.Name)
+// pluginInstallMutex avoids downloading a plugin twice for parallel steps
+var pluginInstallMutex sync.Mutex
+
+// installPlugin resolves the version of a plugin matching a version range, the latest version without range,
+// and downloads its binary in the basedir as plugin-name@version if it is not already there.
+// The version is resolved once per job. The binary path is empty for a docker plugin.
+func (w *currentWorker) installPlugin(name, versionRange string) (*sdk.ActionPlugin, string, error) {
+	ref := sdk.PluginRef(name, versionRange)
+	ap, ok := w.getPluginVersion(ref)
+	if !ok {
+		var err error
+		ap, err = sdk.GetPluginVersion(name, versionRange)
+		if err != nil {
+			return nil, "", err
+		}
+		w.setPluginVersion(ref, ap)
+	}
+	if ap.IsImage() {
+		return ap, "", nil
+	}
+
+	pluginInstallMutex.Lock()
+	defer pluginInstallMutex.Unlock()
 
+	pluginBinary := path.Join(w.basedir, ap.GetName())
 	if _, err := os.Stat(pluginBinary); os.IsNotExist(err) {
 		//If the file doesn't exist. Download it.
-		if err := sdk.DownloadPlugin(r.Name, w.basedir); err != nil {
-			return false, err
+		if err := sdk.DownloadPluginVersion(ap.Name, ap.Version, w.basedir); err != nil {
+			return nil, "", err
 		}
 		if err := os.Chmod(pluginBinary, 0700); err != nil {
-			return false, err
+			return nil, "", err
 		}
 	}
+	return ap, pluginBinary, nil
+}
+
+// checkPluginRequirement checks the plugin version referenced by the requirement: plugin-name or plugin-name@range
+func checkPluginRequirement(w *currentWorker, r sdk.Requirement) (bool, error) {
+	_, versionRange := sdk.ParsePluginRef(r.Value)
+	ap, pluginBinary, err := w.installPlugin(r.Name, versionRange)
+	if err != nil {
+		return false, err
+	}
+	if ap.IsImage() {
+		return checkPluginImageRequirement(ap.Image)
+	}
 
 	pluginClient := plugin.NewClient(context.Background(), r.Name, pluginBinary, "", "", false)
 	defer pluginClient.Kill()
@@ -199,3 +243,53 @@ func checkVolumeRequirement(w *currentWorker, r sdk.Requirement) (bool, error) {
 	}
 	return false, nil
 }
+
+func checkOSArchRequirement(w *currentWorker, r sdk.Requirement) (bool, error) {
+	return sdk.MatchOSArch(r.Value, runtime.GOOS+"/"+runtime.GOARCH), nil
+}
+
+func checkLabelRequirement(w *currentWorker, r sdk.Requirement) (bool, error) {
+	label := strings.TrimSpace(r.Value)
+	for _, l := range w.labels {
+		if l == label {
+			return true, nil
+		}
+	}
+	// labels can also be set on the worker model
+	return sdk.SatisfyRequirement(w.model.Capabilities, r), nil
+}
+
+func checkCPURequirement(w *currentWorker, r sdk.Requirement) (bool, error) {
+	neededCPU, err := strconv.Atoi(strings.TrimSpace(r.Value))
+	if err != nil {
+		return false, err
+	}
+	return runtime.NumCPU() >= neededCPU, nil
+}
+
+func checkDiskRequirement(w *currentWorker, r sdk.Requirement) (bool, error) {
+	neededDisk, err := strconv.ParseInt(strings.TrimSpace(r.Value), 10, 64)
+	if err != nil {
+		return false, err
+	}
+	free, err := freeDiskSpace(w.basedir)
+	if err != nil {
+		return false, err
+	}
+	//Assuming disk is in megabytes
+	return int64(free/(1024*1024)) >= neededDisk, nil
+}
+
+// hostCapabilities returns the os/architecture, cpu and free disk space of the host, advertised at registration
+func hostCapabilities(w *currentWorker) []sdk.Requirement {
+	capas := []sdk.Requirement{
+		{Name: sdk.OSArchRequirement, Type: sdk.OSArchRequirement, Value: runtime.GOOS + "/" + runtime.GOARCH},
+		{Name: sdk.CPURequirement, Type: sdk.CPURequirement, Value: strconv.Itoa(runtime.NumCPU())},
+	}
+	free, err := freeDiskSpace(w.basedir)
+	if err != nil {
+		log.Warning("hostCapabilities> unable to get free disk space of %s: %s", w.basedir, err)
+		return capas
+	}
+	return append(capas, sdk.Requirement{Name: sdk.DiskRequirement, Type: sdk.DiskRequirement, Value: strconv.FormatUint(free/(1024*1024), 10)})
+}
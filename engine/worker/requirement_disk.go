@@ -0,0 +1,14 @@
+// +build !windows
+
+package main
+
+import "syscall"
+
+// freeDiskSpace returns the free disk space, in bytes, available for the worker in the given directory
+func freeDiskSpace(dir string) (uint64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(dir, &stat); err != nil {
+		return 0, err
+	}
+	return uint64(stat.Bavail) * uint64(stat.Bsize), nil
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+var procGetDiskFreeSpaceExW = syscall.NewLazyDLL("kernel32.dll").NewProc("GetDiskFreeSpaceExW")
+
+// freeDiskSpace returns the free disk space, in bytes, available for the worker in the given directory
+func freeDiskSpace(dir string) (uint64, error) {
+	p, err := syscall.UTF16PtrFromString(dir)
+	if err != nil {
+		return 0, err
+	}
+	var freeBytesAvailable uint64
+	r, _, errCall := procGetDiskFreeSpaceExW.Call(uintptr(unsafe.Pointer(p)), uintptr(unsafe.Pointer(&freeBytesAvailable)), 0, 0)
+	if r == 0 {
+		return 0, errCall
+	}
+	return freeBytesAvailable, nil
+}
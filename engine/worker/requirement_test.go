@@ -2,6 +2,8 @@ package main
 
 import (
 	"os"
+	"runtime"
+	"strconv"
 	"testing"
 
 	"github.com/ovh/cds/sdk"
@@ -89,3 +91,157 @@ func TestNetworkAccessRequirement(t *testing.T) {
 		t.Fatalf("Requirement should not be ok")
 	}
 }
+
+func TestCheckOSArchRequirement(t *testing.T) {
+	r := sdk.Requirement{
+		Type:  sdk.OSArchRequirement,
+		Value: runtime.GOOS,
+	}
+
+	ok, err := checkRequirement(nil, r)
+	if err != nil {
+		t.Fatalf("checkRequirement should not fail: %s", err)
+	}
+	if !ok {
+		t.Fatalf("Requirement should be ok")
+	}
+
+	r.Value = runtime.GOOS + "/" + runtime.GOARCH
+	ok, err = checkRequirement(nil, r)
+	if err != nil {
+		t.Fatalf("checkRequirement should not fail: %s", err)
+	}
+	if !ok {
+		t.Fatalf("Requirement should be ok")
+	}
+
+	r.Value = runtime.GOOS + "/fewfewf"
+	ok, err = checkRequirement(nil, r)
+	if err != nil {
+		t.Fatalf("checkRequirement should not fail: %s", err)
+	}
+	if ok {
+		t.Fatalf("Requirement should not be ok")
+	}
+}
+
+func TestCheckLabelRequirement(t *testing.T) {
+	w := &currentWorker{
+		labels: []string{"ssd"},
+		model: sdk.Model{
+			Capabilities: []sdk.Requirement{
+				{Name: "gpu", Type: sdk.LabelRequirement, Value: "gpu"},
+			},
+		},
+	}
+
+	for _, label := range []string{"ssd", "gpu"} {
+		ok, err := checkRequirement(w, sdk.Requirement{Type: sdk.LabelRequirement, Value: label})
+		if err != nil {
+			t.Fatalf("checkRequirement should not fail: %s", err)
+		}
+		if !ok {
+			t.Fatalf("Requirement %s should be ok", label)
+		}
+	}
+
+	ok, err := checkRequirement(w, sdk.Requirement{Type: sdk.LabelRequirement, Value: "fewfewf"})
+	if err != nil {
+		t.Fatalf("checkRequirement should not fail: %s", err)
+	}
+	if ok {
+		t.Fatalf("Requirement should not be ok")
+	}
+}
+
+func TestCheckCPURequirement(t *testing.T) {
+	r := sdk.Requirement{
+		Type:  sdk.CPURequirement,
+		Value: "1",
+	}
+
+	ok, err := checkRequirement(nil, r)
+	if err != nil {
+		t.Fatalf("checkRequirement should not fail: %s", err)
+	}
+	if !ok {
+		t.Fatalf("Requirement should be ok")
+	}
+
+	r.Value = strconv.Itoa(runtime.NumCPU() + 1)
+	ok, err = checkRequirement(nil, r)
+	if err != nil {
+		t.Fatalf("checkRequirement should not fail: %s", err)
+	}
+	if ok {
+		t.Fatalf("Requirement should not be ok")
+	}
+
+	r.Value = "fewfewf"
+	if _, err := checkRequirement(nil, r); err == nil {
+		t.Fatalf("checkRequirement should fail")
+	}
+}
+
+func TestCheckDiskRequirement(t *testing.T) {
+	w := &currentWorker{basedir: os.TempDir()}
+	r := sdk.Requirement{
+		Type:  sdk.DiskRequirement,
+		Value: "0",
+	}
+
+	ok, err := checkRequirement(w, r)
+	if err != nil {
+		t.Fatalf("checkRequirement should not fail: %s", err)
+	}
+	if !ok {
+		t.Fatalf("Requirement should be ok")
+	}
+
+	r.Value = "1000000000000"
+	ok, err = checkRequirement(w, r)
+	if err != nil {
+		t.Fatalf("checkRequirement should not fail: %s", err)
+	}
+	if ok {
+		t.Fatalf("Requirement should not be ok")
+	}
+}
+
+func TestHostCapabilities(t *testing.T) {
+	w := &currentWorker{basedir: os.TempDir()}
+	m := sdk.Model{Capabilities: hostCapabilities(w)}
+
+	reqs := []sdk.Requirement{
+		{Type: sdk.OSArchRequirement, Value: runtime.GOOS},
+		{Type: sdk.CPURequirement, Value: strconv.Itoa(runtime.NumCPU())},
+		{Type: sdk.DiskRequirement, Value: "0"},
+	}
+	if !m.SatisfyRequirements(reqs) {
+		t.Fatalf("Host capabilities %v should satisfy requirements %v", m.Capabilities, reqs)
+	}
+
+	reqs = append(reqs, sdk.Requirement{Type: sdk.CPURequirement, Value: strconv.Itoa(runtime.NumCPU() + 1)})
+	if m.SatisfyRequirements(reqs) {
+		t.Fatalf("Host capabilities %v should not satisfy requirements %v", m.Capabilities, reqs)
+	}
+}
+
+func TestInstallPluginResolvedOncePerJob(t *testing.T) {
+	w := &currentWorker{basedir: os.TempDir()}
+	w.setPluginVersion(sdk.PluginRef("plugin-deploy", "1.x"), &sdk.ActionPlugin{Name: "plugin-deploy", Version: "1.2.0", Image: "foo/deploy:1.2.0"})
+
+	// The version resolved when checking the requirements is reused, without calling CDS API
+	ap, binary, err := w.installPlugin("plugin-deploy", "1.x")
+	if err != nil {
+		t.Fatalf("installPlugin should not fail: %s", err)
+	}
+	if ap.Version != "1.2.0" || binary != "" {
+		t.Fatalf("installPlugin should return the resolved version, got %s (%s)", ap.Version, binary)
+	}
+
+	w.resetPluginVersions()
+	if _, ok := w.getPluginVersion(sdk.PluginRef("plugin-deploy", "1.x")); ok {
+		t.Fatalf("Plugin versions should be reset for the next job")
+	}
+}
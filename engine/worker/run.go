@@ -8,12 +8,16 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/ovh/cds/engine/api/worker"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
+	"github.com/ovh/cds/sdk/luascript"
 	"github.com/ovh/cds/sdk/vcs"
 )
 
@@ -108,7 +112,7 @@ func (w *currentWorker) startAction(ctx context.Context, a *sdk.Action, buildID
 
 func (w *currentWorker) replaceVariablesPlaceholder(a *sdk.Action, params []sdk.Parameter) {
 	for i := range a.Parameters {
-		for _, v := range w.currentJob.buildVariables {
+		for _, v := range w.getBuildVariables() {
 			a.Parameters[i].Value = strings.Replace(a.Parameters[i].Value, "{{."+v.Name+"}}", v.Value, -1)
 		}
 		for _, v := range params {
@@ -122,12 +126,9 @@ func (w *currentWorker) runJob(ctx context.Context, a *sdk.Action, buildID int64
 	defer func() { log.Info("runJob> end run %d stepOrder:%d %p (%s)", buildID, stepOrder, ctx, ctx.Err()) }()
 	// Replace variable placeholder that may have been added by last step
 	w.replaceVariablesPlaceholder(a, *params)
-	// Set the params
-	w.currentJob.params = *params
-	// Unset the params at the end
-	defer func() {
-		w.currentJob.params = nil
-	}()
+	// Set the params of the step, the children of a step share its order: restore the params of the parent at the end
+	previous := w.setStepParams(stepOrder, append([]sdk.Parameter(nil), (*params)...))
+	defer w.setStepParams(stepOrder, previous)
 
 	//If the action is disabled; skip it
 	if !a.Enabled {
@@ -187,50 +188,68 @@ func (w *currentWorker) runSteps(ctx context.Context, steps []sdk.Action, a *sdk
 		BuildID: buildID,
 	}
 
-	for i, child := range steps {
-		if stepOrder == -1 {
-			w.currentJob.currentStep = stepBaseCount + i
-		} else {
-			w.currentJob.currentStep = stepOrder
-		}
-		childName := fmt.Sprintf("%s/%s-%d", stepName, child.Name, i+1)
-		if !child.Enabled || w.manualExit {
-			// Update step status and continue
-			if err := w.updateStepStatus(buildID, w.currentJob.currentStep, sdk.StatusDisabled.String()); err != nil {
-				log.Warning("Cannot update step (%d) status (%s) for build %d: %s", w.currentJob.currentStep, sdk.StatusDisabled.String(), buildID, err)
+	for i := 0; i < len(steps); i++ {
+		// Consecutive parallel steps are run concurrently, as a group
+		group := []int{i}
+		if steps[i].Parallel {
+			for i+1 < len(steps) && steps[i+1].Parallel {
+				i++
+				group = append(group, i)
 			}
+		}
 
-			if w.manualExit {
-				w.sendLog(buildID, fmt.Sprintf("End of Step %s [Disabled - user worker exit]\n", childName), w.currentJob.currentStep, true)
-			} else {
-				w.sendLog(buildID, fmt.Sprintf("End of Step %s [Disabled]\n", childName), w.currentJob.currentStep, true)
+		if len(group) == 1 {
+			child := steps[i]
+			res, disabled := w.runStep(ctx, &child, buildID, params, jobStepOrder(stepOrder, stepBaseCount, i), fmt.Sprintf("%s/%s-%d", stepName, child.Name, i+1), criticalStepFailed)
+			if disabled {
+				nbDisabledChildren++
+			}
+			if res != nil {
+				r = *res
+				if isStepFailed(r) && !child.Optional {
+					criticalStepFailed = true
+				}
 			}
-			nbDisabledChildren++
 			continue
 		}
 
-		if !criticalStepFailed || child.AlwaysExecuted {
-			// Update step status
-			if err := w.updateStepStatus(buildID, w.currentJob.currentStep, sdk.StatusBuilding.String()); err != nil {
-				log.Warning("Cannot update step (%d) status (%s) for build %d: %s\n", w.currentJob.currentStep, sdk.StatusDisabled.String(), buildID, err)
-			}
-			w.sendLog(buildID, fmt.Sprintf("Starting step %s\n", childName), w.currentJob.currentStep, false)
-
-			r = w.startAction(ctx, &child, buildID, params, w.currentJob.currentStep, childName)
-			if r.Status != sdk.StatusSuccess.String() && !child.Optional {
-				criticalStepFailed = true
+		// All the steps of the group are started, even if one of them fails.
+		// Each step gets its own copy of the parameters, the exported variables are merged at the end of the group
+		results := make([]*sdk.Result, len(group))
+		stepsParams := make([][]sdk.Parameter, len(group))
+		var nbDisabled int32
+		var wg sync.WaitGroup
+		for k, idx := range group {
+			child := steps[idx]
+			stepsParams[k] = append([]sdk.Parameter(nil), (*params)...)
+			wg.Add(1)
+			go func(k, idx int) {
+				defer wg.Done()
+				res, disabled := w.runStep(ctx, &child, buildID, &stepsParams[k], jobStepOrder(stepOrder, stepBaseCount, idx), fmt.Sprintf("%s/%s-%d", stepName, child.Name, idx+1), criticalStepFailed)
+				if disabled {
+					atomic.AddInt32(&nbDisabled, 1)
+				}
+				results[k] = res
+			}(k, idx)
+		}
+		wg.Wait()
+
+		nbDisabledChildren += int(nbDisabled)
+		groupParams := append([]sdk.Parameter(nil), (*params)...)
+		for k, idx := range group {
+			for i, p := range stepsParams[k] {
+				if i >= len(groupParams) {
+					*params = append(*params, p)
+				} else if p.Value != groupParams[i].Value {
+					(*params)[i].Value = p.Value
+				}
 			}
-
-			w.sendLog(buildID, fmt.Sprintf("End of step %s [%s]", childName, r.Status), w.currentJob.currentStep, true)
-
-			// Update step status
-			if err := w.updateStepStatus(buildID, w.currentJob.currentStep, r.Status); err != nil {
-				log.Warning("Cannot update step (%d) status (%s) for build %d: %s", w.currentJob.currentStep, sdk.StatusDisabled.String(), buildID, err)
+			if results[k] == nil {
+				continue
 			}
-		} else if criticalStepFailed && !child.AlwaysExecuted { // Update status of steps which are never built
-			// Update step status
-			if err := w.updateStepStatus(buildID, w.currentJob.currentStep, sdk.StatusNeverBuilt.String()); err != nil {
-				log.Warning("Cannot update step (%d) status (%s) for build %d: %s", w.currentJob.currentStep, sdk.StatusNeverBuilt.String(), buildID, err)
+			r = *results[k]
+			if isStepFailed(*results[k]) && !steps[idx].Optional {
+				criticalStepFailed = true
 			}
 		}
 	}
@@ -244,7 +263,131 @@ func (w *currentWorker) runSteps(ctx context.Context, steps []sdk.Action, a *sdk
 	return r, nbDisabledChildren
 }
 
+// isStepFailed returns true if the step was run and failed, a skipped step doesn't fail the job
+func isStepFailed(r sdk.Result) bool {
+	return r.Status != sdk.StatusSuccess.String() && r.Status != sdk.StatusSkipped.String()
+}
+
+// jobStepOrder returns the order of the i-th step of a job, or the order of the parent step for the children of a step
+func jobStepOrder(stepOrder, stepBaseCount, i int) int {
+	if stepOrder == -1 {
+		return stepBaseCount + i
+	}
+	return stepOrder
+}
+
+// runStep runs a step, sends its logs and updates its status. The result is nil if the step was not run
+func (w *currentWorker) runStep(ctx context.Context, child *sdk.Action, buildID int64, params *[]sdk.Parameter, stepOrder int, childName string, criticalStepFailed bool) (*sdk.Result, bool) {
+	if !child.Enabled || w.manualExit {
+		// Update step status and continue
+		if err := w.updateStepStatus(buildID, stepOrder, sdk.StatusDisabled.String()); err != nil {
+			log.Warning("Cannot update step (%d) status (%s) for build %d: %s", stepOrder, sdk.StatusDisabled.String(), buildID, err)
+		}
+
+		if w.manualExit {
+			w.sendLog(buildID, fmt.Sprintf("End of Step %s [Disabled - user worker exit]\n", childName), stepOrder, true)
+		} else {
+			w.sendLog(buildID, fmt.Sprintf("End of Step %s [Disabled]\n", childName), stepOrder, true)
+		}
+		setStepStatusParameter(params, child, sdk.StatusDisabled.String())
+		return nil, true
+	}
+
+	// Update status of steps which are never built
+	if criticalStepFailed && !child.AlwaysExecuted {
+		if err := w.updateStepStatus(buildID, stepOrder, sdk.StatusNeverBuilt.String()); err != nil {
+			log.Warning("Cannot update step (%d) status (%s) for build %d: %s", stepOrder, sdk.StatusNeverBuilt.String(), buildID, err)
+		}
+		setStepStatusParameter(params, child, sdk.StatusNeverBuilt.String())
+		return nil, false
+	}
+
+	// Skip the step if its condition is not satisfied
+	if child.Condition != "" {
+		ok, err := w.checkStepCondition(child.Condition, *params, criticalStepFailed)
+		if err != nil || !ok {
+			r := sdk.Result{Status: sdk.StatusSkipped.String(), BuildID: buildID}
+			if err != nil {
+				r.Status = sdk.StatusFail.String()
+				r.Reason = fmt.Sprintf("Unable to check condition of step %s: %v", childName, err)
+				w.sendLog(buildID, r.Reason, stepOrder, false)
+			}
+			w.sendLog(buildID, fmt.Sprintf("End of step %s [%s]", childName, r.Status), stepOrder, true)
+			if err := w.updateStepStatus(buildID, stepOrder, r.Status); err != nil {
+				log.Warning("Cannot update step (%d) status (%s) for build %d: %s", stepOrder, r.Status, buildID, err)
+			}
+			setStepStatusParameter(params, child, r.Status)
+			return &r, false
+		}
+	}
+
+	// Update step status
+	if err := w.updateStepStatus(buildID, stepOrder, sdk.StatusBuilding.String()); err != nil {
+		log.Warning("Cannot update step (%d) status (%s) for build %d: %s\n", stepOrder, sdk.StatusBuilding.String(), buildID, err)
+	}
+	w.sendLog(buildID, fmt.Sprintf("Starting step %s\n", childName), stepOrder, false)
+
+	// The step name is needed by worker output, the step order by worker upload and worker tmpl
+	setParameter(params, "cds.step.name", child.StepName)
+	setParameter(params, "cds.step.order", strconv.Itoa(stepOrder))
+	r := w.startAction(ctx, child, buildID, params, stepOrder, childName)
+
+	w.sendLog(buildID, fmt.Sprintf("End of step %s [%s]", childName, r.Status), stepOrder, true)
+
+	// Update step status
+	if err := w.updateStepStatus(buildID, stepOrder, r.Status); err != nil {
+		log.Warning("Cannot update step (%d) status (%s) for build %d: %s", stepOrder, r.Status, buildID, err)
+	}
+	setStepStatusParameter(params, child, r.Status)
+	return &r, false
+}
+
+// checkStepCondition evaluates the lua condition of a step with the job parameters, the build variables,
+// the status of the previous named steps (steps.<name>.status) and the status of the job (cds.job.status)
+func (w *currentWorker) checkStepCondition(condition string, params []sdk.Parameter, criticalStepFailed bool) (bool, error) {
+	vars := sdk.ParametersToMap(params)
+	for _, v := range w.getBuildVariables() {
+		vars[v.Name] = v.Value
+	}
+	vars["cds.job.status"] = sdk.StatusSuccess.String()
+	if criticalStepFailed {
+		vars["cds.job.status"] = sdk.StatusFail.String()
+	}
+
+	check := luascript.NewCheck()
+	check.SetVariables(vars)
+	if err := check.PerformCondition(condition); err != nil {
+		return false, err
+	}
+	return check.Result, nil
+}
+
+// setStepStatusParameter sets the variable steps.<name>.status if the step is named
+func setStepStatusParameter(params *[]sdk.Parameter, step *sdk.Action, status string) {
+	if step.StepName == "" {
+		return
+	}
+	setParameter(params, "steps."+step.StepName+".status", status)
+}
+
+// setParameter sets the value of a parameter, the parameter is added if it doesn't exist
+func setParameter(params *[]sdk.Parameter, name, value string) {
+	for i := range *params {
+		if (*params)[i].Name == name {
+			(*params)[i].Value = value
+			return
+		}
+	}
+	sdk.AddParameter(params, name, sdk.StringParameter, value)
+}
+
 func (w *currentWorker) updateStepStatus(pbJobID int64, stepOrder int, status string) error {
+	// there is no API to send the status to, print it
+	if w.local.enabled {
+		fmt.Printf("Step %d [%s]\n", stepOrder, status)
+		return nil
+	}
+
 	step := sdk.StepStatus{
 		StepOrder: stepOrder,
 		Status:    status,
@@ -403,9 +546,9 @@ func (w *currentWorker) processJob(ctx context.Context, jobInfo *worker.Workflow
 		}
 	}
 
-	logsecrets = jobInfo.Secrets
+	setLogSecrets(sdk.NewLogMasker(jobInfo.Secrets))
 	res := w.startAction(ctx, &jobInfo.NodeJobRun.Job.Action, jobInfo.NodeJobRun.ID, &jobInfo.NodeJobRun.Parameters, -1, "")
-	logsecrets = nil
+	setLogSecrets(nil)
 
 	if err := teardownBuildDirectory(wd); err != nil {
 		log.Error("Cannot remove build directory: %s", err)
@@ -507,10 +650,10 @@ func (w *currentWorker) run(ctx context.Context, pbji *worker.PipelineBuildJobIn
 		}
 	}
 
-	logsecrets = pbji.Secrets
+	setLogSecrets(sdk.NewLogMasker(pbji.Secrets))
 
 	res := w.startAction(ctx, &pbji.PipelineBuildJob.Job.Action, pbji.PipelineBuildJob.ID, &pbji.PipelineBuildJob.Parameters, -1, "")
-	logsecrets = nil
+	setLogSecrets(nil)
 
 	if err := teardownBuildDirectory(wd); err != nil {
 		log.Error("Cannot remove build directory: %s", err)
@@ -1,6 +1,16 @@
 package main
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -50,3 +60,246 @@ func Test_processJobParameter(t *testing.T) {
 		assert.EqualValues(t, tt.want, tt.args.pbJob.Parameters)
 	}
 }
+
+func Test_runStepsParallel(t *testing.T) {
+	wd, err := os.Getwd()
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.Chdir(wd)
+
+	basedir, err := ioutil.TempDir("", "cds-worker-parallel")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.RemoveAll(basedir)
+
+	w := &currentWorker{}
+	if !assert.NoError(t, w.initLocal(basedir, "")) {
+		t.FailNow()
+	}
+	if !assert.NoError(t, os.Chdir(basedir)) {
+		t.FailNow()
+	}
+
+	step := func(script string, parallel, alwaysExecuted bool) sdk.Action {
+		a := sdk.NewStepScript(script)
+		a.Enabled = true
+		a.Parallel = parallel
+		a.AlwaysExecuted = alwaysExecuted
+		return a
+	}
+
+	// each step waits for the file of the other one
+	waitFor := "touch %s; for i in $(seq 1 50); do [ -f %s ] && exit 0; sleep 0.1; done; exit 1"
+	steps := []sdk.Action{
+		step(fmt.Sprintf(waitFor, "a", "b"), true, false),
+		step(fmt.Sprintf(waitFor, "b", "a"), true, false),
+	}
+	params := []sdk.Parameter{}
+	r, _ := w.runSteps(context.Background(), steps, nil, 0, &params, -1, "", 0)
+	assert.Equal(t, sdk.StatusSuccess.String(), r.Status)
+
+	// the other steps of the group are run even if one fails, the next steps are not
+	steps = []sdk.Action{
+		step("exit 1", true, false),
+		step("touch c", true, false),
+		step("touch d", false, false),
+		step("touch e", false, true),
+	}
+	r, _ = w.runSteps(context.Background(), steps, nil, 0, &params, -1, "", 0)
+	assert.Equal(t, sdk.StatusFail.String(), r.Status)
+
+	for f, exists := range map[string]bool{"c": true, "d": false, "e": true} {
+		_, err := os.Stat(path.Join(basedir, f))
+		assert.Equal(t, exists, err == nil, "file %s", f)
+	}
+}
+
+// Test_runStepsParallelHandlers calls worker export, upload and output handlers while parallel steps are running, run it with -race
+func Test_runStepsParallelHandlers(t *testing.T) {
+	wd, err := os.Getwd()
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.Chdir(wd)
+
+	basedir, err := ioutil.TempDir("", "cds-worker-parallel-handlers")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.RemoveAll(basedir)
+
+	w := &currentWorker{}
+	if !assert.NoError(t, w.initLocal(basedir, "")) {
+		t.FailNow()
+	}
+	if !assert.NoError(t, os.Chdir(basedir)) {
+		t.FailNow()
+	}
+	if !assert.NoError(t, ioutil.WriteFile("artifact.txt", []byte("foo"), 0644)) {
+		t.FailNow()
+	}
+
+	var steps []sdk.Action
+	for i := 0; i < 4; i++ {
+		a := sdk.NewStepScript("sleep 0.2")
+		a.Enabled = true
+		a.Parallel = true
+		a.Condition = `return cds_build_foo ~= "stop"`
+		steps = append(steps, a)
+	}
+
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		for i := 0; ; i++ {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			btes, _ := json.Marshal(sdk.Variable{Name: "foo", Type: sdk.StringParameter, Value: fmt.Sprintf("%d", i)})
+			rec := httptest.NewRecorder()
+			w.addBuildVarHandler(rec, httptest.NewRequest(http.MethodPost, "/var", bytes.NewReader(btes)))
+			assert.Equal(t, http.StatusOK, rec.Code)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			btes, _ := json.Marshal(sdk.Artifact{Name: "artifact.txt", Tag: "parallel"})
+			rec := httptest.NewRecorder()
+			w.uploadHandler(rec, httptest.NewRequest(http.MethodPost, "/upload?step=0", bytes.NewReader(btes)))
+			assert.Equal(t, http.StatusOK, rec.Code)
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+		for i := 0; ; i++ {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			o := stepOutput{
+				WorkflowNodeOutput: sdk.WorkflowNodeOutput{Name: fmt.Sprintf("out%d", i%10), Type: sdk.StringParameter, Value: "bar"},
+				Step:               "parallel",
+			}
+			btes, _ := json.Marshal(o)
+			rec := httptest.NewRecorder()
+			w.outputHandler(rec, httptest.NewRequest(http.MethodPost, "/output", bytes.NewReader(btes)))
+			assert.Equal(t, http.StatusOK, rec.Code)
+		}
+	}()
+
+	params := []sdk.Parameter{}
+	r, _ := w.runSteps(context.Background(), steps, nil, 0, &params, -1, "", 0)
+	close(done)
+	wg.Wait()
+	assert.Equal(t, sdk.StatusSuccess.String(), r.Status)
+	assert.NotEmpty(t, w.getBuildVariables())
+
+	_, err = os.Stat(path.Join(basedir, "artifacts", "parallel", "artifact.txt"))
+	assert.NoError(t, err)
+}
+
+// Test_tmplHandlerStepParams checks that worker tmpl uses the parameters of the step which called it
+func Test_tmplHandlerStepParams(t *testing.T) {
+	basedir, err := ioutil.TempDir("", "cds-worker-tmpl")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.RemoveAll(basedir)
+
+	src := path.Join(basedir, "src.tmpl")
+	dst := path.Join(basedir, "dst.txt")
+	if !assert.NoError(t, ioutil.WriteFile(src, []byte("{{.cds.step.name}}"), 0644)) {
+		t.FailNow()
+	}
+
+	w := &currentWorker{}
+	w.setStepParams(1, []sdk.Parameter{{Name: "cds.step.name", Type: sdk.StringParameter, Value: "first"}})
+	w.setStepParams(2, []sdk.Parameter{{Name: "cds.step.name", Type: sdk.StringParameter, Value: "second"}})
+
+	btes, _ := json.Marshal(tmplPath{Path: src, Destination: dst})
+	rec := httptest.NewRecorder()
+	w.tmplHandler(rec, httptest.NewRequest(http.MethodPost, "/tmpl?step=2", bytes.NewReader(btes)))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	res, err := ioutil.ReadFile(dst)
+	assert.NoError(t, err)
+	assert.Equal(t, "second", string(res))
+
+	rec = httptest.NewRecorder()
+	w.tmplHandler(rec, httptest.NewRequest(http.MethodPost, "/tmpl?step=3", bytes.NewReader(btes)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func Test_runStepsCondition(t *testing.T) {
+	wd, err := os.Getwd()
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.Chdir(wd)
+
+	basedir, err := ioutil.TempDir("", "cds-worker-condition")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.RemoveAll(basedir)
+
+	w := &currentWorker{}
+	if !assert.NoError(t, w.initLocal(basedir, "")) {
+		t.FailNow()
+	}
+	if !assert.NoError(t, os.Chdir(basedir)) {
+		t.FailNow()
+	}
+
+	step := func(name, script, condition string, alwaysExecuted bool) sdk.Action {
+		a := sdk.NewStepScript(script)
+		a.Enabled = true
+		a.StepName = name
+		a.Condition = condition
+		a.AlwaysExecuted = alwaysExecuted
+		return a
+	}
+
+	steps := []sdk.Action{
+		step("build", "touch build", "", false),
+		step("deploy", "touch deploy", `return git_branch == "master"`, false),
+	}
+	params := []sdk.Parameter{{Name: "git.branch", Value: "feature", Type: sdk.StringParameter}}
+	r, _ := w.runSteps(context.Background(), steps, nil, 0, &params, -1, "", 0)
+	assert.Equal(t, sdk.StatusSuccess.String(), r.Status)
+	assert.Equal(t, sdk.StatusSuccess.String(), sdk.ParameterValue(params, "steps.build.status"))
+	assert.Equal(t, sdk.StatusSkipped.String(), sdk.ParameterValue(params, "steps.deploy.status"))
+
+	// the steps run after a failure only if they are always executed and their condition is satisfied
+	steps = []sdk.Action{
+		step("tests", "exit 1", "", false),
+		step("debug", "touch debug", `return steps_tests_status == "Fail"`, true),
+		step("notify", "touch notify", `return cds_job_status == "Success"`, true),
+	}
+	r, _ = w.runSteps(context.Background(), steps, nil, 0, &params, -1, "", 0)
+	assert.Equal(t, sdk.StatusFail.String(), r.Status)
+	assert.Equal(t, sdk.StatusSkipped.String(), sdk.ParameterValue(params, "steps.notify.status"))
+
+	for f, exists := range map[string]bool{"build": true, "deploy": false, "debug": true, "notify": false} {
+		_, err := os.Stat(path.Join(basedir, f))
+		assert.Equal(t, exists, err == nil, "file %s", f)
+	}
+
+	// an invalid condition fails the step
+	steps = []sdk.Action{step("invalid", "true", "return ==", false)}
+	r, _ = w.runSteps(context.Background(), steps, nil, 0, &params, -1, "", 0)
+	assert.Equal(t, sdk.StatusFail.String(), r.Status)
+}
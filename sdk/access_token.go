@@ -0,0 +1,136 @@
+package sdk
+
+import (
+	"net/http"
+	"time"
+)
+
+// Scopes of personal access tokens
+const (
+	AccessTokenScopeReadProject = "read_project"
+	AccessTokenScopeRunWorkflow = "run_workflow"
+	AccessTokenScopeAdmin       = "admin"
+)
+
+// AvailableAccessTokenScopes lists all the scopes of personal access tokens
+var AvailableAccessTokenScopes = []string{
+	AccessTokenScopeReadProject,
+	AccessTokenScopeRunWorkflow,
+	AccessTokenScopeAdmin,
+}
+
+// AccessToken is a personal access token, used by scripts to access the API on behalf of a user
+type AccessToken struct {
+	ID         int64      `json:"id" cli:"id,key"`
+	UserID     int64      `json:"-" cli:"-"`
+	Name       string     `json:"name" cli:"name"`
+	Scopes     []string   `json:"scopes" cli:"scopes"`
+	ProjectKey string     `json:"project_key,omitempty" cli:"project"`
+	Created    time.Time  `json:"created" cli:"created"`
+	ExpireAt   *time.Time `json:"expire_at,omitempty" cli:"expire_at"`
+	LastUsed   *time.Time `json:"last_used,omitempty" cli:"last_used"`
+	// Token is only returned at creation
+	Token string `json:"token,omitempty" cli:"token"`
+}
+
+// IsValid checks the name and the scopes of the token
+func (t AccessToken) IsValid() error {
+	if t.Name == "" || len(t.Scopes) == 0 {
+		return ErrWrongRequest
+	}
+	for _, s := range t.Scopes {
+		var found bool
+		for _, a := range AvailableAccessTokenScopes {
+			if s == a {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return ErrWrongRequest
+		}
+	}
+	return nil
+}
+
+// IsExpired returns true if the token can't be used anymore
+func (t AccessToken) IsExpired(now time.Time) bool {
+	return t.ExpireAt != nil && now.After(*t.ExpireAt)
+}
+
+// HasScope returns true if the token has been granted the scope
+func (t AccessToken) HasScope(scope string) bool {
+	for _, s := range t.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Allows returns true if the token scopes allow a request with this method.
+// Read requests need read_project, executions need run_workflow and other writes need admin.
+// run_workflow also allows to read the workflow runs, to follow the runs it triggers.
+func (t AccessToken) Allows(method string, isExecution, isWorkflowRun bool) bool {
+	isRead := method == http.MethodGet || method == http.MethodHead
+	switch {
+	case t.HasScope(AccessTokenScopeAdmin):
+		return true
+	case isRead && t.HasScope(AccessTokenScopeReadProject):
+		return true
+	case isRead && isWorkflowRun:
+		return t.HasScope(AccessTokenScopeRunWorkflow)
+	case method == http.MethodPost && isExecution:
+		return t.HasScope(AccessTokenScopeRunWorkflow)
+	}
+	return false
+}
+
+// RestrictToProject removes from the groups all the permissions not on the project
+func (t AccessToken) RestrictToProject(groups []Group) {
+	if t.ProjectKey == "" {
+		return
+	}
+	for i := range groups {
+		g := &groups[i]
+		projects := g.ProjectGroups[:0]
+		for _, p := range g.ProjectGroups {
+			if p.Project.Key == t.ProjectKey {
+				projects = append(projects, p)
+			}
+		}
+		g.ProjectGroups = projects
+
+		pipelines := g.PipelineGroups[:0]
+		for _, p := range g.PipelineGroups {
+			if p.Pipeline.ProjectKey == t.ProjectKey {
+				pipelines = append(pipelines, p)
+			}
+		}
+		g.PipelineGroups = pipelines
+
+		applications := g.ApplicationGroups[:0]
+		for _, a := range g.ApplicationGroups {
+			if a.Application.ProjectKey == t.ProjectKey {
+				applications = append(applications, a)
+			}
+		}
+		g.ApplicationGroups = applications
+
+		environments := g.EnvironmentGroups[:0]
+		for _, e := range g.EnvironmentGroups {
+			if e.Environment.ProjectKey == t.ProjectKey {
+				environments = append(environments, e)
+			}
+		}
+		g.EnvironmentGroups = environments
+
+		workflows := g.WorkflowGroups[:0]
+		for _, w := range g.WorkflowGroups {
+			if w.Workflow.ProjectKey == t.ProjectKey {
+				workflows = append(workflows, w)
+			}
+		}
+		g.WorkflowGroups = workflows
+	}
+}
@@ -0,0 +1,54 @@
+package sdk
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccessTokenAllows(t *testing.T) {
+	read := AccessToken{Scopes: []string{AccessTokenScopeReadProject}}
+	assert.True(t, read.Allows(http.MethodGet, false, false))
+	assert.True(t, read.Allows(http.MethodGet, false, true))
+	assert.False(t, read.Allows(http.MethodPost, true, true))
+	assert.False(t, read.Allows(http.MethodDelete, false, false))
+
+	run := AccessToken{Scopes: []string{AccessTokenScopeRunWorkflow}}
+	assert.False(t, run.Allows(http.MethodGet, false, false))
+	assert.True(t, run.Allows(http.MethodGet, false, true))
+	assert.True(t, run.Allows(http.MethodPost, true, true))
+	assert.False(t, run.Allows(http.MethodPost, false, true))
+	assert.False(t, run.Allows(http.MethodPut, false, false))
+
+	admin := AccessToken{Scopes: []string{AccessTokenScopeAdmin}}
+	assert.True(t, admin.Allows(http.MethodGet, false, false))
+	assert.True(t, admin.Allows(http.MethodDelete, false, false))
+
+	assert.False(t, AccessToken{}.Allows(http.MethodGet, false, true))
+	assert.Equal(t, ErrWrongRequest, AccessToken{Name: "ci", Scopes: []string{"everything"}}.IsValid())
+	assert.NoError(t, AccessToken{Name: "ci", Scopes: []string{AccessTokenScopeRunWorkflow}}.IsValid())
+
+	yesterday := time.Now().Add(-24 * time.Hour)
+	assert.True(t, AccessToken{ExpireAt: &yesterday}.IsExpired(time.Now()))
+	assert.False(t, AccessToken{}.IsExpired(time.Now()))
+}
+
+func TestAccessTokenRestrictToProject(t *testing.T) {
+	groups := []Group{{
+		Name: "devs",
+		ProjectGroups: []ProjectGroup{
+			{Project: Project{Key: "FOO"}, Permission: 7},
+			{Project: Project{Key: "BAR"}, Permission: 7},
+		},
+		WorkflowGroups: []WorkflowGroup{
+			{Workflow: Workflow{Name: "build", ProjectKey: "BAR"}, Permission: 7},
+		},
+	}}
+
+	AccessToken{ProjectKey: "FOO"}.RestrictToProject(groups)
+	assert.Len(t, groups[0].ProjectGroups, 1)
+	assert.Equal(t, "FOO", groups[0].ProjectGroups[0].Project.Key)
+	assert.Len(t, groups[0].WorkflowGroups, 0)
+}
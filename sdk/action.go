@@ -3,6 +3,7 @@ package sdk
 import (
 	"encoding/json"
 	"fmt"
+	"regexp"
 	"time"
 )
 
@@ -19,6 +20,10 @@ type Action struct {
 	Deprecated     bool          `json:"deprecated" yaml:"-"`
 	Optional       bool          `json:"optional" yaml:"-"`
 	AlwaysExecuted bool          `json:"always_executed" yaml:"-"`
+	Parallel       bool          `json:"parallel" yaml:"-"`
+	StepName       string        `json:"step_name" yaml:"-"`
+	Condition      string        `json:"condition" yaml:"-"`
+	PluginVersion  string        `json:"plugin_version" yaml:"-"`
 	LastModified   int64         `json:"last_modified" cli:"modified"`
 }
 
@@ -44,11 +49,26 @@ type ActionPlugin struct {
 	Perm       uint32 `json:"perm,omitempty"`
 	MD5sum     string `json:"md5sum,omitempty"`
 	ObjectPath string `json:"object_path,omitempty"`
+
+	// Image is the OCI image of a docker action plugin, empty for binary plugins
+	Image      string      `json:"image,omitempty"`
+	Parameters []Parameter `json:"parameters,omitempty"`
+
+	// Version is the semver version of the plugin, empty for plugins uploaded before versioning
+	Version    string    `json:"version"`
+	Deprecated bool      `json:"deprecated"`
+	Usage      int64     `json:"usage"`
+	Created    time.Time `json:"created"`
+}
+
+//IsImage returns true if the action plugin is run from a docker image
+func (a *ActionPlugin) IsImage() bool {
+	return a.Image != ""
 }
 
-//GetName returns the name the action plugin
+//GetName returns the name the action plugin, suffixed with its version
 func (a *ActionPlugin) GetName() string {
-	return a.Name
+	return PluginRef(a.Name, a.Version)
 }
 
 //GetPath returns the storage path of the action plugin
@@ -71,8 +91,30 @@ const (
 	GitCloneAction = "GitClone"
 	GitTagAction   = "GitTag"
 	ReleaseAction  = "Release"
+	CoverageAction = "Coverage"
+	AnalysisAction = "StaticAnalysis"
 )
 
+// StepNamePattern is the pattern of the step names, they are used in variables names such as steps.<name>.status
+const StepNamePattern = "^[a-zA-Z0-9_]{1,}$"
+
+var stepNamePatternRegex = regexp.MustCompile(StepNamePattern)
+
+// CheckStepNames checks that the named steps of the action have a valid and unique name
+func (a *Action) CheckStepNames() error {
+	names := map[string]bool{}
+	for _, step := range a.Actions {
+		if step.StepName == "" {
+			continue
+		}
+		if !stepNamePatternRegex.MatchString(step.StepName) || names[step.StepName] {
+			return ErrInvalidStepName
+		}
+		names[step.StepName] = true
+	}
+	return nil
+}
+
 // NewAction instanciate a new Action
 func NewAction(name string) *Action {
 	a := &Action{
@@ -20,12 +20,17 @@ type ActionScript struct {
 	Steps        []struct {
 		Enabled          *bool                        `json:"enabled"`
 		AlwaysExecuted   bool                         `json:"always_executed"`
+		Parallel         bool                         `json:"parallel"`
+		Name             string                       `json:"name,omitempty"`
+		Condition        string                       `json:"condition,omitempty"`
 		ArtifactUpload   map[string]string            `json:"artifactUpload,omitempty"`
 		ArtifactDownload map[string]string            `json:"artifactDownload,omitempty"`
 		GitClone         map[string]string            `json:"gitClone,omitempty"`
 		GitTag           map[string]string            `json:"gitTag,omitempty"`
 		Script           string                       `json:"script,omitempty"`
 		JUnitReport      string                       `json:"jUnitReport,omitempty"`
+		Coverage         map[string]string            `json:"coverage,omitempty"`
+		StaticAnalysis   map[string]string            `json:"staticAnalysis,omitempty"`
 		Plugin           map[string]map[string]string `json:"plugin,omitempty"`
 		Release          map[string]string            `json:"release,omitempty"`
 	} `json:"steps"`
@@ -93,6 +98,26 @@ func NewStepRelease(v map[string]string) Action {
 	return newAction
 }
 
+// NewStepCoverage returns an action (basically used as a step of a job) of Coverage type
+func NewStepCoverage(v map[string]string) Action {
+	newAction := Action{
+		Name:       CoverageAction,
+		Type:       BuiltinAction,
+		Parameters: ParametersFromMap(v),
+	}
+	return newAction
+}
+
+// NewStepAnalysis returns an action (basically used as a step of a job) of StaticAnalysis type
+func NewStepAnalysis(v map[string]string) Action {
+	newAction := Action{
+		Name:       AnalysisAction,
+		Type:       BuiltinAction,
+		Parameters: ParametersFromMap(v),
+	}
+	return newAction
+}
+
 // NewStepArtifactUpload returns an action (basically used as a step of a job) of artifact upload type
 func NewStepArtifactUpload(v map[string]string) Action {
 	newAction := Action{
@@ -119,10 +144,15 @@ func NewStepPlugin(v map[string]map[string]string) (*Action, error) {
 		return nil, fmt.Errorf("Malformatted plugin step")
 	}
 	for k, v := range v {
+		name, versionRange := ParsePluginRef(k)
+		if err := CheckPluginVersionRange(versionRange); err != nil {
+			return nil, err
+		}
 		newAction := Action{
-			Name:       k,
-			Type:       PluginAction,
-			Parameters: []Parameter{},
+			Name:          name,
+			Type:          PluginAction,
+			PluginVersion: versionRange,
+			Parameters:    []Parameter{},
 		}
 		for p, val := range v {
 			newAction.Parameters = append(newAction.Parameters, Parameter{
@@ -197,6 +227,18 @@ func NewActionFromScript(btes []byte) (*Action, error) {
 			goto next
 		}
 
+		//Action builtin = Coverage
+		if v.Coverage != nil {
+			newAction = NewStepCoverage(v.Coverage)
+			goto next
+		}
+
+		//Action builtin = StaticAnalysis
+		if v.StaticAnalysis != nil {
+			newAction = NewStepAnalysis(v.StaticAnalysis)
+			goto next
+		}
+
 		//Action builtin = ArtifactUpload
 		if v.ArtifactUpload != nil {
 			newAction = NewStepArtifactUpload(v.ArtifactUpload)
@@ -243,6 +285,9 @@ func NewActionFromScript(btes []byte) (*Action, error) {
 			newAction.Enabled = true
 		}
 		newAction.AlwaysExecuted = v.AlwaysExecuted
+		newAction.Parallel = v.Parallel
+		newAction.StepName = v.Name
+		newAction.Condition = v.Condition
 		a.Actions = append(a.Actions, newAction)
 	}
 
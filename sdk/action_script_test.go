@@ -245,6 +245,24 @@ steps  = [{
 
 }
 
+func TestLoadFromActionScriptWithPluginVersion(t *testing.T) {
+	b := []byte(`
+steps  = [{
+	plugin = {
+        "my-plugin@>=1.2.0 <2.0.0" = {
+            "param1" = "value1"
+        }
+    }
+}]`)
+
+	a, err := NewActionFromScript(b)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, "my-plugin", a.Actions[0].Name)
+	assert.Equal(t, ">=1.2.0 <2.0.0", a.Actions[0].PluginVersion)
+}
+
 func TestTestLoadFromActionScriptWithError(t *testing.T) {
 	b := []byte(`
 steps  = [{
@@ -0,0 +1,130 @@
+package sdk
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"sort"
+	"strings"
+)
+
+// Static analysis report formats handled by the StaticAnalysis builtin action
+const (
+	AnalysisFormatSARIF      = "sarif"
+	AnalysisFormatCheckstyle = "checkstyle"
+)
+
+// Findings severities, from the lowest to the highest
+const (
+	SeverityInfo     = "info"
+	SeverityLow      = "low"
+	SeverityMedium   = "medium"
+	SeverityHigh     = "high"
+	SeverityCritical = "critical"
+)
+
+var severities = []string{SeverityInfo, SeverityLow, SeverityMedium, SeverityHigh, SeverityCritical}
+
+//SeverityLevel returns the rank of a severity, -1 if the severity is unknown
+func SeverityLevel(s string) int {
+	for i, v := range severities {
+		if v == strings.ToLower(s) {
+			return i
+		}
+	}
+	return -1
+}
+
+//AnalysisReport is the list of the findings of static analysis and security scan reports attached to a workflow node run
+type AnalysisReport struct {
+	Findings           []Finding `json:"findings"`
+	ReferenceNodeRunID int64     `json:"reference_node_run_id,omitempty"`
+}
+
+//Finding is an issue found by a static analysis or a security scan tool
+type Finding struct {
+	Tool        string `json:"tool"`
+	RuleID      string `json:"rule_id"`
+	Severity    string `json:"severity"`
+	Message     string `json:"message"`
+	File        string `json:"file,omitempty"`
+	Line        int    `json:"line,omitempty"`
+	Fingerprint string `json:"fingerprint"`
+	New         bool   `json:"new"`
+}
+
+//ComputeFingerprint computes the fingerprint used to match the finding between runs.
+//The line is not part of it, so that findings are not reported as new when code moves.
+func (f *Finding) ComputeFingerprint() {
+	h := sha1.New()
+	for _, s := range []string{f.Tool, f.RuleID, f.File, f.Message} {
+		h.Write([]byte(s))
+		h.Write([]byte{0})
+	}
+	f.Fingerprint = hex.EncodeToString(h.Sum(nil))
+}
+
+//Merge adds the findings of another report, skipping the ones already known at the same line
+func (r *AnalysisReport) Merge(o AnalysisReport) {
+	type key struct {
+		fingerprint string
+		line        int
+	}
+	known := map[key]bool{}
+	for _, f := range r.Findings {
+		known[key{f.Fingerprint, f.Line}] = true
+	}
+	findings := r.Findings
+	if findings == nil {
+		findings = []Finding{}
+	}
+	for _, f := range o.Findings {
+		if f.Fingerprint == "" {
+			f.ComputeFingerprint()
+		}
+		if known[key{f.Fingerprint, f.Line}] {
+			continue
+		}
+		known[key{f.Fingerprint, f.Line}] = true
+		findings = append(findings, f)
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		if SeverityLevel(findings[i].Severity) != SeverityLevel(findings[j].Severity) {
+			return SeverityLevel(findings[i].Severity) > SeverityLevel(findings[j].Severity)
+		}
+		if findings[i].File != findings[j].File {
+			return findings[i].File < findings[j].File
+		}
+		return findings[i].Line < findings[j].Line
+	})
+	r.Findings = findings
+}
+
+//FlagNew flags the findings which are not in the reference report. Without reference, no finding is new:
+//the first run on a branch can't tell which findings have been introduced by the branch.
+func (r *AnalysisReport) FlagNew(ref *AnalysisReport) {
+	known := map[string]bool{}
+	if ref != nil {
+		for _, f := range ref.Findings {
+			known[f.Fingerprint] = true
+		}
+	}
+	for i := range r.Findings {
+		r.Findings[i].New = ref != nil && !known[r.Findings[i].Fingerprint]
+	}
+}
+
+//Filter returns the findings with the given severity or higher, only the new ones if onlyNew is true
+func (r *AnalysisReport) Filter(minSeverity string, onlyNew bool) []Finding {
+	level := SeverityLevel(minSeverity)
+	res := []Finding{}
+	for _, f := range r.Findings {
+		if onlyNew && !f.New {
+			continue
+		}
+		if SeverityLevel(f.Severity) < level {
+			continue
+		}
+		res = append(res, f)
+	}
+	return res
+}
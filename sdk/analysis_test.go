@@ -0,0 +1,36 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalysisReportFlagNew(t *testing.T) {
+	previous := AnalysisReport{}
+	previous.Merge(AnalysisReport{Findings: []Finding{
+		{Tool: "gosec", RuleID: "G101", Severity: SeverityHigh, File: "main.go", Line: 10, Message: "hardcoded credentials"},
+	}})
+
+	current := AnalysisReport{}
+	current.Merge(AnalysisReport{Findings: []Finding{
+		// moved, but not new
+		{Tool: "gosec", RuleID: "G101", Severity: SeverityHigh, File: "main.go", Line: 15, Message: "hardcoded credentials"},
+		{Tool: "gosec", RuleID: "G104", Severity: SeverityLow, File: "main.go", Line: 20, Message: "errors unhandled"},
+	}})
+	// uploaded twice
+	current.Merge(AnalysisReport{Findings: []Finding{
+		{Tool: "gosec", RuleID: "G104", Severity: SeverityLow, File: "main.go", Line: 20, Message: "errors unhandled"},
+	}})
+	assert.Len(t, current.Findings, 2)
+
+	current.FlagNew(&previous)
+	assert.Len(t, current.Filter(SeverityHigh, true), 0)
+	assert.Len(t, current.Filter(SeverityHigh, false), 1)
+	assert.Len(t, current.Filter(SeverityLow, true), 1)
+	assert.Len(t, current.Filter("", false), 2)
+
+	current.FlagNew(nil)
+	assert.Len(t, current.Filter(SeverityLow, true), 0)
+	assert.Len(t, current.Filter(SeverityLow, false), 2)
+}
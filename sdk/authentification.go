@@ -28,3 +28,23 @@ func NewAuth(hashedToken string) *Auth {
 	}
 	return a
 }
+
+// OIDCDeviceAuthorization is returned by the OpenID provider to start a device authorization flow
+type OIDCDeviceAuthorization struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete,omitempty"`
+	ExpiresIn               int    `json:"expires_in"`
+	Interval                int    `json:"interval"`
+}
+
+// OIDCDeviceTokenRequest is sent to get a session once the device authorization is approved
+type OIDCDeviceTokenRequest struct {
+	DeviceCode string `json:"device_code"`
+}
+
+// OIDCSessionRequest is sent by the UI to get a session after the authorization code flow
+type OIDCSessionRequest struct {
+	Code string `json:"code"`
+}
@@ -228,3 +228,9 @@ func (c *client) QueueJobTag(jobID int64, tags []sdk.WorkflowRunTag) error {
 	_, err := c.PostJSON(path, tags, nil)
 	return err
 }
+
+func (c *client) QueueJobSendOutput(jobID int64, output sdk.WorkflowNodeOutput) error {
+	path := fmt.Sprintf("/queue/workflows/%d/output", jobID)
+	_, err := c.PostJSON(path, output, nil)
+	return err
+}
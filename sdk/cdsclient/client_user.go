@@ -105,3 +105,20 @@ func (c *client) UserConfirm(username, token string) (bool, string, error) {
 	}
 	return true, res.Password, nil
 }
+
+func (c *client) UserLoginOIDCDevice() (*sdk.OIDCDeviceAuthorization, error) {
+	res := &sdk.OIDCDeviceAuthorization{}
+	if _, err := c.PostJSON("/login/oidc/device", nil, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (c *client) UserLoginOIDCDeviceToken(deviceCode string) (*sdk.UserAPIResponse, error) {
+	req := sdk.OIDCDeviceTokenRequest{DeviceCode: deviceCode}
+	res := &sdk.UserAPIResponse{}
+	if _, err := c.PostJSON("/login/oidc/device/token", req, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
@@ -0,0 +1,28 @@
+package cdsclient
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func (c *client) UserAccessTokenList(username string) ([]sdk.AccessToken, error) {
+	tokens := []sdk.AccessToken{}
+	if _, err := c.GetJSON(fmt.Sprintf("/user/%s/tokens", url.QueryEscape(username)), &tokens); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+func (c *client) UserAccessTokenCreate(username string, t sdk.AccessToken) (*sdk.AccessToken, error) {
+	if _, err := c.PostJSON(fmt.Sprintf("/user/%s/tokens", url.QueryEscape(username)), t, &t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (c *client) UserAccessTokenRevoke(username string, id int64) error {
+	_, err := c.DeleteJSON(fmt.Sprintf("/user/%s/tokens/%d", url.QueryEscape(username), id), nil)
+	return err
+}
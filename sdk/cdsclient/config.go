@@ -9,4 +9,6 @@ type Config struct {
 	userAgent string
 	Verbose   bool
 	Retry     int
+	// AccessToken is a personal access token, used instead of the user session token
+	AccessToken string
 }
@@ -18,6 +18,8 @@ import (
 const (
 	//SessionTokenHeader is user as HTTP header
 	SessionTokenHeader = "Session-Token"
+	// AccessTokenHeader is used as HTTP header for personal access tokens
+	AccessTokenHeader = "X-Cds-Access-Token"
 	// AuthHeader is used as HTTP header
 	AuthHeader = "X_AUTH_HEADER"
 	// RequestedWithHeader is used as HTTP header
@@ -126,8 +128,10 @@ func (c *client) Request(method string, path string, body io.Reader, mods ...Req
 		}
 	}
 
-	if err := sdk.DecodeError(bodyBtes); err != nil {
-		return nil, code, err
+	//The status lets the callers know if the request can be retried
+	if e, ok := sdk.DecodeError(bodyBtes).(sdk.Error); ok {
+		e.Status = code
+		return nil, code, e
 	}
 
 	return bodyBtes, code, nil
@@ -171,6 +175,9 @@ func (c *client) Stream(method string, path string, body io.Reader, noTimeout bo
 				req.Header.Add(SessionTokenHeader, c.config.Token)
 				req.SetBasicAuth(c.config.User, c.config.Token)
 			}
+			if c.config.AccessToken != "" {
+				req.Header.Set(AccessTokenHeader, c.config.AccessToken)
+			}
 		}
 
 		if c.config.Verbose {
@@ -267,6 +274,9 @@ func (c *client) UploadMultiPart(method string, path string, body *bytes.Buffer,
 			req.Header.Add(SessionTokenHeader, c.config.Token)
 			req.SetBasicAuth(c.config.User, c.config.Token)
 		}
+		if c.config.AccessToken != "" {
+			req.Header.Set(AccessTokenHeader, c.config.AccessToken)
+		}
 	}
 
 	resp, err := NoTimeout(c.HTTPClient).Do(req)
@@ -145,6 +145,7 @@ type QueueClient interface {
 	QueueSendResult(int64, sdk.Result) error
 	QueueArtifactUpload(id int64, tag, filePath string) error
 	QueueJobTag(jobID int64, tags []sdk.WorkflowRunTag) error
+	QueueJobSendOutput(jobID int64, output sdk.WorkflowNodeOutput) error
 }
 
 // TemplateClient exposes queue related functions
@@ -163,6 +164,11 @@ type UserClient interface {
 	UserGetGroups(username string) (map[string][]sdk.Group, error)
 	UserReset(username, email, callback string) error
 	UserConfirm(username, token string) (bool, string, error)
+	UserLoginOIDCDevice() (*sdk.OIDCDeviceAuthorization, error)
+	UserLoginOIDCDeviceToken(deviceCode string) (*sdk.UserAPIResponse, error)
+	UserAccessTokenList(username string) ([]sdk.AccessToken, error)
+	UserAccessTokenCreate(username string, t sdk.AccessToken) (*sdk.AccessToken, error)
+	UserAccessTokenRevoke(username string, id int64) error
 }
 
 // WorkerClient exposes workers functions
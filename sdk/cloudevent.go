@@ -0,0 +1,225 @@
+package sdk
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/fatih/structs"
+	"github.com/mitchellh/mapstructure"
+)
+
+// CloudEvents 1.0 encoding of the events
+const (
+	CloudEventsSpecVersion = "1.0"
+	CloudEventsContentType = "application/cloudevents+json"
+	// CloudEventsStructured encodes the whole event as a JSON document
+	CloudEventsStructured = "structured"
+	// CloudEventsBinary sends the attributes as headers, and the data as body
+	CloudEventsBinary = "binary"
+)
+
+// CloudEventsHeaderPrefix prefixes the attributes of the events sent in binary mode over HTTP
+const CloudEventsHeaderPrefix = "Ce-"
+
+// CloudEventTypes are the CloudEvents types of the events. The version suffix changes on breaking changes of the payload.
+var CloudEventTypes = map[string]string{
+	fmt.Sprintf("%T", EventWorkflowRun{}):        "com.ovh.cds.workflow.run.v1",
+	fmt.Sprintf("%T", EventWorkflowNodeRun{}):    "com.ovh.cds.workflow.node.run.v1",
+	fmt.Sprintf("%T", EventWorkflowNodeJobRun{}): "com.ovh.cds.workflow.node.job.run.v1",
+	fmt.Sprintf("%T", EventPipelineBuild{}):      "com.ovh.cds.pipeline.build.v1",
+	fmt.Sprintf("%T", EventJob{}):                "com.ovh.cds.job.v1",
+	fmt.Sprintf("%T", EventNotif{}):              "com.ovh.cds.notification.v1",
+	fmt.Sprintf("%T", EventEngine{}):             "com.ovh.cds.engine.v1",
+}
+
+// CloudEventPayloads are the payload schemas of the CloudEvents types
+var CloudEventPayloads = map[string]reflect.Type{
+	"com.ovh.cds.workflow.run.v1":          reflect.TypeOf(EventWorkflowRun{}),
+	"com.ovh.cds.workflow.node.run.v1":     reflect.TypeOf(EventWorkflowNodeRun{}),
+	"com.ovh.cds.workflow.node.job.run.v1": reflect.TypeOf(EventWorkflowNodeJobRun{}),
+	"com.ovh.cds.pipeline.build.v1":        reflect.TypeOf(EventPipelineBuild{}),
+	"com.ovh.cds.job.v1":                   reflect.TypeOf(EventJob{}),
+	"com.ovh.cds.notification.v1":          reflect.TypeOf(EventNotif{}),
+	"com.ovh.cds.engine.v1":                reflect.TypeOf(EventEngine{}),
+}
+
+// CloudEvent is a CloudEvents 1.0 envelope of a CDS event
+type CloudEvent struct {
+	SpecVersion     string          `json:"specversion"`
+	ID              string          `json:"id"`
+	Source          string          `json:"source"`
+	Type            string          `json:"type"`
+	Subject         string          `json:"subject,omitempty"`
+	Time            time.Time       `json:"time"`
+	DataContentType string          `json:"datacontenttype,omitempty"`
+	Data            json.RawMessage `json:"data,omitempty"`
+}
+
+// NewCloudEvent converts an event to a CloudEvent. The subject is project/workflow/run number, as far as they are known.
+// The data is the typed payload of the event, or the raw payload if it can't be typed.
+func NewCloudEvent(e Event, source string) (CloudEvent, error) {
+	ce := CloudEvent{
+		SpecVersion:     CloudEventsSpecVersion,
+		ID:              e.ID,
+		Source:          source,
+		Type:            CloudEventType(e.EventType),
+		Time:            e.Timestamp,
+		DataContentType: "application/json",
+	}
+	if ce.ID == "" {
+		ce.ID = fmt.Sprintf("%s-%d", e.CDSName, e.Timestamp.UnixNano())
+	}
+
+	subject := []string{}
+	for _, k := range []string{"ProjectKey", "WorkflowName", "Number"} {
+		v, ok := e.Payload[k]
+		if !ok || v == nil || fmt.Sprintf("%v", v) == "" || fmt.Sprintf("%v", v) == "0" {
+			break
+		}
+		subject = append(subject, fmt.Sprintf("%v", v))
+	}
+	ce.Subject = strings.Join(subject, "/")
+
+	var data interface{} = e.Payload
+	if t, ok := CloudEventPayloads[ce.Type]; ok {
+		payload := reflect.New(t).Interface()
+		decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+			WeaklyTypedInput: true,
+			DecodeHook:       stringToTimeHook,
+			Result:           payload,
+		})
+		if err != nil {
+			return ce, err
+		}
+		if err := decoder.Decode(e.Payload); err == nil {
+			data = payload
+		}
+	}
+
+	b, err := json.Marshal(data)
+	if err != nil {
+		return ce, err
+	}
+	ce.Data = b
+	return ce, nil
+}
+
+// stringToTimeHook reads the dates of payloads which have been stored as JSON
+func stringToTimeHook(f reflect.Type, t reflect.Type, data interface{}) (interface{}, error) {
+	if f.Kind() != reflect.String || t != reflect.TypeOf(time.Time{}) {
+		return data, nil
+	}
+	return time.Parse(time.RFC3339Nano, data.(string))
+}
+
+// CloudEventType returns the CloudEvents type of an event type
+func CloudEventType(eventType string) string {
+	if t, ok := CloudEventTypes[eventType]; ok {
+		return t
+	}
+	return "com.ovh.cds." + strings.ToLower(strings.TrimPrefix(eventType, "sdk.Event")) + ".v1"
+}
+
+// Decode returns the typed payload of the event
+func (c CloudEvent) Decode() (interface{}, error) {
+	t, ok := CloudEventPayloads[c.Type]
+	if !ok {
+		return nil, fmt.Errorf("Unknown event type %s", c.Type)
+	}
+	payload := reflect.New(t).Interface()
+	if err := json.Unmarshal(c.Data, payload); err != nil {
+		return nil, err
+	}
+	return reflect.ValueOf(payload).Elem().Interface(), nil
+}
+
+// Attributes returns the attributes of the event, without the data
+func (c CloudEvent) Attributes() map[string]string {
+	attrs := map[string]string{
+		"specversion": c.SpecVersion,
+		"id":          c.ID,
+		"source":      c.Source,
+		"type":        c.Type,
+		"time":        c.Time.UTC().Format(time.RFC3339Nano),
+	}
+	if c.Subject != "" {
+		attrs["subject"] = c.Subject
+	}
+	return attrs
+}
+
+// Encode returns the content type and the body of the event in structured or binary mode, and the headers in binary mode
+func (c CloudEvent) Encode(mode string) (string, []byte, map[string]string, error) {
+	switch mode {
+	case CloudEventsStructured:
+		b, err := json.Marshal(c)
+		return CloudEventsContentType, b, nil, err
+	case CloudEventsBinary:
+		return c.DataContentType, c.Data, c.Attributes(), nil
+	}
+	return "", nil, nil, fmt.Errorf("Unknown CloudEvents mode %s", mode)
+}
+
+// HTTPHeaders returns the headers of the event in binary mode over HTTP
+func (c CloudEvent) HTTPHeaders() http.Header {
+	h := http.Header{}
+	for k, v := range c.Attributes() {
+		h.Set(CloudEventsHeaderPrefix+k, v)
+	}
+	h.Set("Content-Type", c.DataContentType)
+	return h
+}
+
+// ParseCloudEventHTTP reads an event received over HTTP in structured or binary mode
+func ParseCloudEventHTTP(h http.Header, body []byte) (CloudEvent, error) {
+	var c CloudEvent
+	if strings.HasPrefix(h.Get("Content-Type"), CloudEventsContentType) {
+		err := json.Unmarshal(body, &c)
+		return c, err
+	}
+
+	c.SpecVersion = h.Get(CloudEventsHeaderPrefix + "specversion")
+	if c.SpecVersion == "" {
+		return c, fmt.Errorf("Not a CloudEvent")
+	}
+	c.ID = h.Get(CloudEventsHeaderPrefix + "id")
+	c.Source = h.Get(CloudEventsHeaderPrefix + "source")
+	c.Type = h.Get(CloudEventsHeaderPrefix + "type")
+	c.Subject = h.Get(CloudEventsHeaderPrefix + "subject")
+	c.DataContentType = h.Get("Content-Type")
+	c.Data = body
+	if t := h.Get(CloudEventsHeaderPrefix + "time"); t != "" {
+		var err error
+		if c.Time, err = time.Parse(time.RFC3339Nano, t); err != nil {
+			return c, err
+		}
+	}
+	return c, nil
+}
+
+// ToEvent converts back a CloudEvent to a CDS event
+func (c CloudEvent) ToEvent() (Event, error) {
+	e := Event{
+		ID:        c.ID,
+		Timestamp: c.Time,
+	}
+	for t, ct := range CloudEventTypes {
+		if ct == c.Type {
+			e.EventType = t
+			break
+		}
+	}
+	if e.EventType == "" {
+		return e, fmt.Errorf("Unknown event type %s", c.Type)
+	}
+	payload, err := c.Decode()
+	if err != nil {
+		return e, err
+	}
+	e.Payload = structs.Map(payload)
+	return e, nil
+}
@@ -0,0 +1,54 @@
+package sdk
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/fatih/structs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCloudEvent(t *testing.T) {
+	// Payloads are read back from JSON by the brokers
+	b, err := json.Marshal(structs.Map(EventWorkflowRun{ID: 42, Number: 12, ProjectKey: "PROJ", WorkflowName: "wf", Status: StatusSuccess.String()}))
+	assert.NoError(t, err)
+	e := Event{
+		ID:        "6c2a2c5e-1b1c-4c5e-9f49-1b4b6d1e2d3a",
+		Timestamp: time.Date(2018, 6, 1, 12, 0, 0, 0, time.UTC),
+		EventType: "sdk.EventWorkflowRun",
+	}
+	assert.NoError(t, json.Unmarshal(b, &e.Payload))
+
+	ce, err := NewCloudEvent(e, "https://cds.local")
+	assert.NoError(t, err)
+	assert.Equal(t, "com.ovh.cds.workflow.run.v1", ce.Type)
+	assert.Equal(t, "PROJ/wf/12", ce.Subject)
+	assert.Equal(t, e.ID, ce.ID)
+
+	contentType, body, headers, err := ce.Encode(CloudEventsStructured)
+	assert.NoError(t, err)
+	assert.Nil(t, headers)
+	h := http.Header{}
+	h.Set("Content-Type", contentType)
+	structured, err := ParseCloudEventHTTP(h, body)
+	assert.NoError(t, err)
+	assert.Equal(t, ce.Subject, structured.Subject)
+
+	_, body, headers, err = ce.Encode(CloudEventsBinary)
+	assert.NoError(t, err)
+	assert.Equal(t, "1.0", headers["specversion"])
+	binary, err := ParseCloudEventHTTP(ce.HTTPHeaders(), body)
+	assert.NoError(t, err)
+	assert.True(t, ce.Time.Equal(binary.Time))
+
+	back, err := binary.ToEvent()
+	assert.NoError(t, err)
+	assert.Equal(t, "sdk.EventWorkflowRun", back.EventType)
+	assert.Equal(t, "PROJ", back.Payload["ProjectKey"])
+	assert.Equal(t, int64(12), back.Payload["Number"])
+
+	_, _, _, err = ce.Encode("unknown")
+	assert.Error(t, err)
+}
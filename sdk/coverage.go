@@ -0,0 +1,90 @@
+package sdk
+
+import (
+	"sort"
+	"time"
+)
+
+// Coverage report formats handled by the Coverage builtin action
+const (
+	CoverageFormatCobertura = "cobertura"
+	CoverageFormatLCOV      = "lcov"
+	CoverageFormatGo        = "go"
+)
+
+//Coverage is the normalized summary of coverage reports attached to a workflow node run
+type Coverage struct {
+	Lines              CoverageMetric    `json:"lines"`
+	Branches           CoverageMetric    `json:"branches"`
+	Packages           []CoveragePackage `json:"packages,omitempty"`
+	Delta              float64           `json:"delta"`
+	ReferenceNodeRunID int64             `json:"reference_node_run_id,omitempty"`
+}
+
+//CoverageMetric counts covered elements (lines or branches) against the total
+type CoverageMetric struct {
+	Covered int64 `json:"covered"`
+	Total   int64 `json:"total"`
+}
+
+//CoveragePackage is the coverage of a package, or a directory for reports without package notion
+type CoveragePackage struct {
+	Name     string         `json:"name"`
+	Lines    CoverageMetric `json:"lines"`
+	Branches CoverageMetric `json:"branches"`
+}
+
+//WorkflowNodeRunCoverage is an entry of the coverage history of a workflow
+type WorkflowNodeRunCoverage struct {
+	WorkflowRunNumber int64     `json:"num"`
+	SubNumber         int64     `json:"subnumber"`
+	WorkflowNodeRunID int64     `json:"workflow_node_run_id"`
+	WorkflowNodeID    int64     `json:"workflow_node_id"`
+	Start             time.Time `json:"start"`
+	Coverage          Coverage  `json:"coverage"`
+}
+
+//Add adds the counters of another metric
+func (m *CoverageMetric) Add(o CoverageMetric) {
+	m.Covered += o.Covered
+	m.Total += o.Total
+}
+
+//Percent returns the coverage rate between 0 and 100, 0 if there is nothing to cover
+func (m CoverageMetric) Percent() float64 {
+	if m.Total == 0 {
+		return 0
+	}
+	return float64(m.Covered) * 100 / float64(m.Total)
+}
+
+//Merge adds the packages of another report. A package already known is replaced by the one of the new report.
+func (c *Coverage) Merge(o Coverage) {
+	for _, p := range o.Packages {
+		var found bool
+		for i := range c.Packages {
+			if c.Packages[i].Name == p.Name {
+				c.Packages[i] = p
+				found = true
+				break
+			}
+		}
+		if !found {
+			c.Packages = append(c.Packages, p)
+		}
+	}
+	c.Compute()
+}
+
+//Compute sorts the packages and computes global lines and branches coverage from them
+func (c *Coverage) Compute() {
+	sort.Slice(c.Packages, func(i, j int) bool {
+		return c.Packages[i].Name < c.Packages[j].Name
+	})
+	c.Lines = CoverageMetric{}
+	c.Branches = CoverageMetric{}
+	for _, p := range c.Packages {
+		c.Lines.Add(p.Lines)
+		c.Branches.Add(p.Branches)
+	}
+}
@@ -128,6 +128,15 @@ var (
 	ErrWorkflowNodeRunJobNotFound            = Error{ID: 112, Status: http.StatusNotFound}
 	ErrBuiltinKeyNotFound                    = Error{ID: 113, Status: http.StatusInternalServerError}
 	ErrStepNotFound                          = Error{ID: 114, Status: http.StatusNotFound}
+	ErrVaultSecretNotFound                   = Error{ID: 115, Status: http.StatusNotFound}
+	ErrOIDCAuthorizationPending              = Error{ID: 116, Status: http.StatusBadRequest}
+	ErrOIDCLoginFailed                       = Error{ID: 117, Status: http.StatusUnauthorized}
+	ErrInvalidStepName                       = Error{ID: 118, Status: http.StatusBadRequest}
+	ErrInvalidNodeOutput                     = Error{ID: 119, Status: http.StatusBadRequest}
+	ErrInvalidPluginVersion                  = Error{ID: 120, Status: http.StatusBadRequest}
+	ErrPluginVersionNotFound                 = Error{ID: 121, Status: http.StatusNotFound}
+	ErrOIDCSlowDown                          = Error{ID: 122, Status: http.StatusBadRequest}
+	ErrPluginVersionExists                   = Error{ID: 123, Status: http.StatusConflict}
 )
 
 var errorsAmericanEnglish = map[int]string{
@@ -244,6 +253,15 @@ var errorsAmericanEnglish = map[int]string{
 	ErrWorkflowNodeRunJobNotFound.ID:            "Job not found",
 	ErrBuiltinKeyNotFound.ID:                    "Encryption Key not found",
 	ErrStepNotFound.ID:                          "Step not found",
+	ErrVaultSecretNotFound.ID:                   "Secret not found in vault",
+	ErrOIDCAuthorizationPending.ID:              "Authorization is pending, waiting for user approval",
+	ErrOIDCLoginFailed.ID:                       "Unable to log in with the OpenID provider",
+	ErrInvalidStepName.ID:                       "Step name must respect the following pattern: '^[a-zA-Z0-9_]{1,}$' and be unique in the job",
+	ErrInvalidNodeOutput.ID:                     "Invalid output: the name must respect the pattern '^[a-zA-Z0-9_]{1,}$', the type must be string, number or boolean, the value is limited to 4096 characters and a pipeline has at most 32 outputs",
+	ErrInvalidPluginVersion.ID:                  "Invalid plugin version: a version must be semver (1.2.0) and a version range a semver range (>=1.2.0 <2.0.0, 1.x)",
+	ErrPluginVersionNotFound.ID:                 "Plugin version not found",
+	ErrOIDCSlowDown.ID:                          "Authorization is pending, poll less frequently",
+	ErrPluginVersionExists.ID:                   "Plugin version already exists, a version cannot be replaced: upload a new version",
 }
 
 var errorsFrench = map[int]string{
@@ -360,6 +378,15 @@ var errorsFrench = map[int]string{
 	ErrWorkflowNodeRunJobNotFound.ID:            "Job non trouvé",
 	ErrBuiltinKeyNotFound.ID:                    "Clé de chiffrage introuvable",
 	ErrStepNotFound.ID:                          "Step introuvable",
+	ErrVaultSecretNotFound.ID:                   "Secret introuvable dans vault",
+	ErrOIDCAuthorizationPending.ID:              "Autorisation en attente de validation par l'utilisateur",
+	ErrOIDCLoginFailed.ID:                       "Impossible de se connecter avec le fournisseur OpenID",
+	ErrInvalidStepName.ID:                       "Le nom de l'étape doit respecter le pattern suivant: '^[a-zA-Z0-9_]{1,}$' et être unique dans le job",
+	ErrInvalidNodeOutput.ID:                     "Sortie invalide : le nom doit respecter le pattern '^[a-zA-Z0-9_]{1,}$', le type doit être string, number ou boolean, la valeur est limitée à 4096 caractères et un pipeline a au plus 32 sorties",
+	ErrInvalidPluginVersion.ID:                  "Version de plugin invalide : une version doit être semver (1.2.0) et un intervalle de versions un intervalle semver (>=1.2.0 <2.0.0, 1.x)",
+	ErrPluginVersionNotFound.ID:                 "Version de plugin introuvable",
+	ErrOIDCSlowDown.ID:                          "Autorisation en attente, interrogez moins fréquemment",
+	ErrPluginVersionExists.ID:                   "Cette version du plugin existe déjà, une version ne peut pas être remplacée : envoyez une nouvelle version",
 }
 
 var errorsLanguages = []map[int]string{
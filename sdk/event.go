@@ -9,6 +9,7 @@ import (
 // Status is  "Waiting" "Building" "Success" "Fail" "Unknown", optional
 // DateEvent is a date (timestamp format)
 type Event struct {
+	ID        string                 `json:"id,omitempty"`
 	Timestamp time.Time              `json:"timestamp"`
 	Hostname  string                 `json:"hostname"`
 	CDSName   string                 `json:"cdsname"`
@@ -25,23 +26,26 @@ type EventEngine struct {
 // EventWorkflowNodeJobRun contains event data for a workflow node run job
 type EventWorkflowNodeJobRun struct {
 	ID                int64  `json:"id"`
-	WorkflowNodeRunID int64  `json:"workflow_node_run_id,omitempty""`
+	WorkflowNodeRunID int64  `json:"workflow_node_run_id,omitempty"`
 	Status            string `json:"status"`
 	Queued            int64  `json:"queued,omitempty"`
 	Start             int64  `json:"start,omitempty"`
 	Done              int64  `json:"done,omitempty"`
 	Model             string `json:"model,omitempty"`
+	ProjectKey        string `json:"project_key,omitempty"`
+	WorkflowName      string `json:"workflow_name,omitempty"`
 }
 
 // EventWorkflowNodeRun contains event data for a workflow node run
 type EventWorkflowNodeRun struct {
 	ID                    int64                     `json:"id,omitempty"`
 	Number                int64                     `json:"num,omitempty"`
-	SubNumber             int64                     `json:"num,omitempty"`
+	SubNumber             int64                     `json:"subnumber,omitempty"`
 	Status                string                    `json:"status,omitempty"`
 	Start                 int64                     `json:"start,omitempty"`
 	Done                  int64                     `json:"done,omitempty"`
 	WorkflowName          string                    `json:"workflow_name,omitempty"`
+	WorkflowNodeName      string                    `json:"workflow_node_name,omitempty"`
 	PipelineName          string                    `json:"pipeline_name,omitempty"`
 	ProjectKey            string                    `json:"project_key,omitempty"`
 	ApplicationName       string                    `json:"application_name,omitempty"`
@@ -49,7 +53,7 @@ type EventWorkflowNodeRun struct {
 	Payload               interface{}               `json:"payload,omitempty"`
 	HookEvent             *WorkflowNodeRunHookEvent `json:"hook_event"`
 	Manual                *WorkflowNodeRunManual    `json:"manual"`
-	SourceNodeRuns        []int64                   `json:"manual"`
+	SourceNodeRuns        []int64                   `json:"source_node_runs"`
 	WorkflowRunID         int64                     `json:"workflow_run_id"`
 	RepositoryManagerName string                    `json:"repository_manager_name"`
 }
@@ -0,0 +1,76 @@
+package event
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/streadway/amqp"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// ConsumeAMQP consume CDS Event from an AMQP 0.9.1 exchange.
+// The queue is declared and bound to the exchange with the binding key, '#' to receive all the events.
+// Without queue name, an exclusive queue is declared and deleted on disconnection.
+// Messages are acknowledged once processed. On error, a message is requeued once, then rejected.
+func ConsumeAMQP(url, exchange, queue, bindingKey string, ProcessEventFunc func(sdk.Event) error, ErrorLogFunc func(string, ...interface{})) error {
+	conn, err := amqp.Dial(url)
+	if err != nil {
+		return fmt.Errorf("Error connecting: %s", err)
+	}
+	defer conn.Close()
+
+	channel, err := conn.Channel()
+	if err != nil {
+		return fmt.Errorf("Error opening channel: %s", err)
+	}
+	if err := channel.ExchangeDeclare(exchange, amqp.ExchangeTopic, true, false, false, false, nil); err != nil {
+		return fmt.Errorf("Error declaring exchange %s: %s", exchange, err)
+	}
+	// a server-named queue is not found again on reconnection: it must not outlive the connection
+	durable := queue != ""
+	q, err := channel.QueueDeclare(queue, durable, !durable, !durable, false, nil)
+	if err != nil {
+		return fmt.Errorf("Error declaring queue %s: %s", queue, err)
+	}
+	if bindingKey == "" {
+		bindingKey = "#"
+	}
+	if err := channel.QueueBind(q.Name, bindingKey, exchange, false, nil); err != nil {
+		return fmt.Errorf("Error binding queue %s: %s", q.Name, err)
+	}
+	if err := channel.Qos(10, 0, false); err != nil {
+		return fmt.Errorf("Error setting QoS: %s", err)
+	}
+
+	deliveries, err := channel.Consume(q.Name, "", false, false, false, false, nil)
+	if err != nil {
+		return fmt.Errorf("Error creating consumer: %s", err)
+	}
+
+	// Consume message
+	for msg := range deliveries {
+		headers := map[string]string{}
+		for k, v := range msg.Headers {
+			if strings.HasPrefix(k, "cloudEvents:") {
+				headers[strings.TrimPrefix(k, "cloudEvents:")] = fmt.Sprintf("%v", v)
+			}
+		}
+		if headers["specversion"] != "" {
+			headers["datacontenttype"] = msg.ContentType
+		}
+		event, err := decodeEvent(msg.Body, headers)
+		if err != nil {
+			ErrorLogFunc("Error during consumption: %s", err)
+			msg.Nack(false, false)
+			continue
+		}
+		if err := ProcessEventFunc(event); err != nil {
+			ErrorLogFunc("Error on ProcessEventFunc:%s", err)
+			msg.Nack(false, !msg.Redelivered)
+		} else {
+			msg.Ack(false)
+		}
+	}
+	return fmt.Errorf("AMQP connection closed")
+}
@@ -0,0 +1,39 @@
+package event
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// decodeEvent reads a CDS event, or a CloudEvent in structured mode or in binary mode with its attributes in headers
+func decodeEvent(body []byte, headers map[string]string) (sdk.Event, error) {
+	if specversion := headers["specversion"]; specversion != "" {
+		ce := sdk.CloudEvent{
+			SpecVersion:     specversion,
+			ID:              headers["id"],
+			Source:          headers["source"],
+			Type:            headers["type"],
+			Subject:         headers["subject"],
+			DataContentType: headers["datacontenttype"],
+			Data:            body,
+		}
+		ce.Time, _ = time.Parse(time.RFC3339Nano, headers["time"])
+		return ce.ToEvent()
+	}
+
+	var event sdk.Event
+	if strings.Contains(string(body), `"specversion"`) {
+		var ce sdk.CloudEvent
+		if err := json.Unmarshal(body, &ce); err != nil {
+			return event, err
+		}
+		if ce.SpecVersion != "" {
+			return ce.ToEvent()
+		}
+	}
+	err := json.Unmarshal(body, &event)
+	return event, err
+}
@@ -1,7 +1,6 @@
 package event
 
 import (
-	"encoding/json"
 	"fmt"
 
 	"github.com/Shopify/sarama"
@@ -46,8 +45,12 @@ func ConsumeKafka(addr, topic, group, user, password string, ProcessEventFunc fu
 
 	// Consume message
 	for msg := range consumer.Messages() {
-		var event sdk.Event
-		json.Unmarshal(msg.Value, &event)
+		event, err := decodeEvent(msg.Value, nil)
+		if err != nil {
+			ErrorLogFunc("Error during consumption: %s", err)
+			consumer.MarkOffset(msg, "delivered")
+			continue
+		}
 		if err := ProcessEventFunc(event); err != nil {
 			ErrorLogFunc("Error on ProcessEventFunc:%s", err)
 		} else {
@@ -0,0 +1,54 @@
+package event
+
+import (
+	"fmt"
+
+	"github.com/nats-io/go-nats"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// ConsumeNATS consume CDS Event from a NATS subject.
+// Consumers sharing the same queue group receive each event only once.
+func ConsumeNATS(url, subject, queue, user, password string, ProcessEventFunc func(sdk.Event) error, ErrorLogFunc func(string, ...interface{})) error {
+	opts := []nats.Option{
+		nats.MaxReconnects(-1),
+		nats.DisconnectHandler(func(*nats.Conn) {
+			ErrorLogFunc("Disconnected from NATS")
+		}),
+	}
+	if user != "" {
+		opts = append(opts, nats.UserInfo(user, password))
+	}
+	conn, err := nats.Connect(url, opts...)
+	if err != nil {
+		return fmt.Errorf("Error connecting: %s", err)
+	}
+	defer conn.Close()
+
+	msgs := make(chan *nats.Msg, 64)
+	closed := make(chan struct{})
+	conn.SetClosedHandler(func(*nats.Conn) {
+		close(closed)
+	})
+	if _, err := conn.ChanQueueSubscribe(subject, queue, msgs); err != nil {
+		return fmt.Errorf("Error subscribing to %s: %s", subject, err)
+	}
+
+	// Consume message
+	for {
+		select {
+		case msg := <-msgs:
+			event, err := decodeEvent(msg.Data, nil)
+			if err != nil {
+				ErrorLogFunc("Error during consumption: %s", err)
+				continue
+			}
+			if err := ProcessEventFunc(event); err != nil {
+				ErrorLogFunc("Error on ProcessEventFunc:%s", err)
+			}
+		case <-closed:
+			return fmt.Errorf("NATS connection closed")
+		}
+	}
+}
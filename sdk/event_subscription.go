@@ -0,0 +1,125 @@
+package sdk
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// Headers sent with the events delivered to webhook subscriptions
+const (
+	EventSignatureHeader = "X-Cds-Signature"
+	EventTypeHeader      = "X-Cds-Event"
+	EventDeliveryHeader  = "X-Cds-Delivery"
+)
+
+// Event types available for subscriptions
+var AvailableEventSubscriptionTypes = []string{
+	fmt.Sprintf("%T", EventWorkflowRun{}),
+	fmt.Sprintf("%T", EventWorkflowNodeRun{}),
+	fmt.Sprintf("%T", EventWorkflowNodeJobRun{}),
+}
+
+// Status of event deliveries. Dead deliveries have exhausted their attempts.
+const (
+	EventDeliveryPending = "Pending"
+	EventDeliverySuccess = "Success"
+	EventDeliveryDead    = "Dead"
+)
+
+// EventSubscription is a webhook receiving the events of a project
+type EventSubscription struct {
+	ID         int64     `json:"id" cli:"id,key"`
+	ProjectID  int64     `json:"-" cli:"-"`
+	ProjectKey string    `json:"project_key" cli:"-"`
+	Name       string    `json:"name" cli:"name"`
+	URL        string    `json:"url" cli:"url"`
+	Secret     string    `json:"secret,omitempty" cli:"-"`
+	EventTypes []string  `json:"event_types" cli:"event_types"`
+	Workflows  []string  `json:"workflows,omitempty" cli:"workflows"`
+	Statuses   []string  `json:"statuses,omitempty" cli:"statuses"`
+	Enabled    bool      `json:"enabled" cli:"enabled"`
+	Created    time.Time `json:"created" cli:"created"`
+	// CloudEvents mode of the deliveries, structured or binary. Events are sent as sdk.Event if empty.
+	CloudEvents string `json:"cloud_events,omitempty" cli:"cloud_events"`
+}
+
+// EventDelivery is the delivery of an event to a subscription
+type EventDelivery struct {
+	ID             int64           `json:"id" cli:"id,key"`
+	SubscriptionID int64           `json:"subscription_id" cli:"-"`
+	EventType      string          `json:"event_type" cli:"event_type"`
+	Payload        json.RawMessage `json:"payload,omitempty" cli:"-"`
+	Status         string          `json:"status" cli:"status"`
+	Attempts       int             `json:"attempts" cli:"attempts"`
+	ResponseCode   int             `json:"response_code,omitempty" cli:"response_code"`
+	Error          string          `json:"error,omitempty" cli:"error"`
+	Created        time.Time       `json:"created" cli:"created"`
+	LastAttempt    *time.Time      `json:"last_attempt,omitempty" cli:"last_attempt"`
+	NextAttempt    time.Time       `json:"next_attempt" cli:"-"`
+}
+
+// IsValid checks the name, the URL and the event types of the subscription
+func (s EventSubscription) IsValid() error {
+	if !NamePatternRegex.MatchString(s.Name) {
+		return ErrInvalidName
+	}
+	u, err := url.Parse(s.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return NewError(ErrWrongRequest, fmt.Errorf("Invalid URL %s", s.URL))
+	}
+	if len(s.EventTypes) == 0 {
+		return NewError(ErrWrongRequest, fmt.Errorf("Missing event types"))
+	}
+	if s.CloudEvents != "" && s.CloudEvents != CloudEventsStructured && s.CloudEvents != CloudEventsBinary {
+		return NewError(ErrWrongRequest, fmt.Errorf("Invalid CloudEvents mode %s", s.CloudEvents))
+	}
+	for _, t := range s.EventTypes {
+		var found bool
+		for _, a := range AvailableEventSubscriptionTypes {
+			if t == a {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return NewError(ErrWrongRequest, fmt.Errorf("Unsupported event type %s", t))
+		}
+	}
+	return nil
+}
+
+// Matches returns true if the event has to be delivered to the subscription
+func (s EventSubscription) Matches(e Event) bool {
+	if !s.Enabled || len(s.EventTypes) == 0 || fmt.Sprintf("%v", e.Payload["ProjectKey"]) != s.ProjectKey {
+		return false
+	}
+	var in = func(v interface{}, values []string) bool {
+		if len(values) == 0 {
+			return true
+		}
+		for _, value := range values {
+			if fmt.Sprintf("%v", v) == value {
+				return true
+			}
+		}
+		return false
+	}
+	return in(e.EventType, s.EventTypes) && in(e.Payload["WorkflowName"], s.Workflows) && in(e.Payload["Status"], s.Statuses)
+}
+
+// EventSignature returns the value of the signature header of an event body: the hex encoded HMAC-SHA256 of the body
+func EventSignature(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// CheckEventSignature checks the signature header of an event body
+func CheckEventSignature(secret string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(EventSignature(secret, body)), []byte(signature))
+}
@@ -44,11 +44,21 @@ type Job struct {
 // Step represents exported step used in a job
 type Step map[string]interface{}
 
+// stepAttributes are the keys of a step which are not the action of the step
+var stepAttributes = map[string]bool{
+	"enabled":         true,
+	"optional":        true,
+	"always_executed": true,
+	"parallel":        true,
+	"name":            true,
+	"condition":       true,
+}
+
 // IsValid returns true is the step is valid
 func (s Step) IsValid() bool {
 	keys := []string{}
 	for k := range s {
-		if k != "enabled" && k != "optional" && k != "always_executed" {
+		if !stepAttributes[k] {
 			keys = append(keys, k)
 		}
 	}
@@ -58,7 +68,7 @@ func (s Step) IsValid() bool {
 func (s Step) key() string {
 	keys := []string{}
 	for k := range s {
-		if k != "enabled" && k != "optional" && k != "always_executed" {
+		if !stepAttributes[k] {
 			keys = append(keys, k)
 		}
 	}
@@ -96,6 +106,10 @@ func (s Step) AsScript() (*sdk.Action, bool, error) {
 	if err != nil {
 		return nil, true, err
 	}
+	a.Parallel, err = s.IsFlagged("parallel")
+	if err != nil {
+		return nil, true, err
+	}
 
 	return &a, true, nil
 }
@@ -122,10 +136,17 @@ func (s Step) AsAction() (*sdk.Action, bool, error) {
 		return nil, true, sdk.WrapError(err, "Malformatted Step")
 	}
 
-	a, err := sdk.NewStepDefault(actionName, argss)
+	// a plugin version is pinned with plugin-name@version
+	name, versionRange := sdk.ParsePluginRef(actionName)
+	if err := sdk.CheckPluginVersionRange(versionRange); err != nil {
+		return nil, true, err
+	}
+
+	a, err := sdk.NewStepDefault(name, argss)
 	if err != nil {
 		return nil, true, err
 	}
+	a.PluginVersion = versionRange
 
 	a.Enabled, err = s.IsFlagged("enabled")
 	if err != nil {
@@ -139,6 +160,10 @@ func (s Step) AsAction() (*sdk.Action, bool, error) {
 	if err != nil {
 		return nil, true, err
 	}
+	a.Parallel, err = s.IsFlagged("parallel")
+	if err != nil {
+		return nil, true, err
+	}
 	return a, true, nil
 }
 
@@ -169,6 +194,10 @@ func (s Step) AsJUnitReport() (*sdk.Action, bool, error) {
 	if err != nil {
 		return nil, true, err
 	}
+	a.Parallel, err = s.IsFlagged("parallel")
+	if err != nil {
+		return nil, true, err
+	}
 	a.Optional, err = s.IsFlagged("optional")
 	if err != nil {
 		return nil, true, err
@@ -212,6 +241,96 @@ func (s Step) AsGitClone() (*sdk.Action, bool, error) {
 	if err != nil {
 		return nil, true, err
 	}
+	a.Parallel, err = s.IsFlagged("parallel")
+	if err != nil {
+		return nil, true, err
+	}
+
+	return &a, true, nil
+}
+
+//AsCoverage returns the step a sdk.Action
+func (s Step) AsCoverage() (*sdk.Action, bool, error) {
+	if !s.IsValid() {
+		return nil, false, fmt.Errorf("Malformatted Step")
+	}
+
+	bI, ok := s["coverage"]
+	if !ok {
+		return nil, false, nil
+	}
+
+	if reflect.ValueOf(bI).Kind() != reflect.Map {
+		return nil, false, nil
+	}
+
+	argss := map[string]string{}
+	if err := mapstructure.Decode(bI, &argss); err != nil {
+		return nil, true, sdk.WrapError(err, "Malformatted Step")
+	}
+
+	a := sdk.NewStepCoverage(argss)
+
+	var err error
+	a.Enabled, err = s.IsFlagged("enabled")
+	if err != nil {
+		return nil, true, err
+	}
+	a.Optional, err = s.IsFlagged("optional")
+	if err != nil {
+		return nil, true, err
+	}
+	a.AlwaysExecuted, err = s.IsFlagged("always_executed")
+	if err != nil {
+		return nil, true, err
+	}
+	a.Parallel, err = s.IsFlagged("parallel")
+	if err != nil {
+		return nil, true, err
+	}
+
+	return &a, true, nil
+}
+
+//AsAnalysis returns the step a sdk.Action
+func (s Step) AsAnalysis() (*sdk.Action, bool, error) {
+	if !s.IsValid() {
+		return nil, false, fmt.Errorf("Malformatted Step")
+	}
+
+	bI, ok := s["staticAnalysis"]
+	if !ok {
+		return nil, false, nil
+	}
+
+	if reflect.ValueOf(bI).Kind() != reflect.Map {
+		return nil, false, nil
+	}
+
+	argss := map[string]string{}
+	if err := mapstructure.Decode(bI, &argss); err != nil {
+		return nil, true, sdk.WrapError(err, "Malformatted Step")
+	}
+
+	a := sdk.NewStepAnalysis(argss)
+
+	var err error
+	a.Enabled, err = s.IsFlagged("enabled")
+	if err != nil {
+		return nil, true, err
+	}
+	a.Optional, err = s.IsFlagged("optional")
+	if err != nil {
+		return nil, true, err
+	}
+	a.AlwaysExecuted, err = s.IsFlagged("always_executed")
+	if err != nil {
+		return nil, true, err
+	}
+	a.Parallel, err = s.IsFlagged("parallel")
+	if err != nil {
+		return nil, true, err
+	}
 
 	return &a, true, nil
 }
@@ -251,6 +370,10 @@ func (s Step) AsArtifactUpload() (*sdk.Action, bool, error) {
 	if err != nil {
 		return nil, true, err
 	}
+	a.Parallel, err = s.IsFlagged("parallel")
+	if err != nil {
+		return nil, true, err
+	}
 
 	return &a, true, nil
 }
@@ -285,6 +408,10 @@ func (s Step) AsArtifactDownload() (*sdk.Action, bool, error) {
 	if err != nil {
 		return nil, true, err
 	}
+	a.Parallel, err = s.IsFlagged("parallel")
+	if err != nil {
+		return nil, true, err
+	}
 
 	return &a, true, nil
 }
@@ -303,6 +430,19 @@ func (s Step) IsFlagged(flag string) (bool, error) {
 	return bS, nil
 }
 
+// Attribute returns the value of a string attribute of the step, such as its name or condition
+func (s Step) Attribute(attr string) (string, error) {
+	vI, ok := s[attr]
+	if !ok {
+		return "", nil
+	}
+	vS, ok := vI.(string)
+	if !ok {
+		return "", fmt.Errorf("Malformatted Step : %s attribute must be a string", attr)
+	}
+	return vS, nil
+}
+
 // Requirement represents an exported sdk.Requirement
 type Requirement struct {
 	Binary   string             `json:"binary,omitempty" yaml:"binary,omitempty"`
@@ -312,6 +452,10 @@ type Requirement struct {
 	Plugin   string             `json:"plugin,omitempty" yaml:"plugin,omitempty"`
 	Service  ServiceRequirement `json:"service,omitempty" yaml:"service,omitempty"`
 	Memory   string             `json:"memory,omitempty" yaml:"memory,omitempty"`
+	OSArch   string             `json:"os-architecture,omitempty" yaml:"os-architecture,omitempty"`
+	Label    string             `json:"label,omitempty" yaml:"label,omitempty"`
+	CPU      string             `json:"cpu,omitempty" yaml:"cpu,omitempty"`
+	Disk     string             `json:"disk,omitempty" yaml:"disk,omitempty"`
 }
 
 // ServiceRequirement represents an exported sdk.Requirement of type ServiceRequirement
@@ -422,6 +566,14 @@ func newRequirements(req []sdk.Requirement) []Requirement {
 			res = append(res, Requirement{Service: ServiceRequirement{Name: r.Name, Value: r.Value}})
 		case sdk.MemoryRequirement:
 			res = append(res, Requirement{Memory: r.Value})
+		case sdk.OSArchRequirement:
+			res = append(res, Requirement{OSArch: r.Value})
+		case sdk.LabelRequirement:
+			res = append(res, Requirement{Label: r.Value})
+		case sdk.CPURequirement:
+			res = append(res, Requirement{CPU: r.Value})
+		case sdk.DiskRequirement:
+			res = append(res, Requirement{Disk: r.Value})
 		}
 	}
 	return res
@@ -460,6 +612,15 @@ func newSteps(a sdk.Action) []Step {
 		if act.AlwaysExecuted {
 			s["always_executed"] = act.AlwaysExecuted
 		}
+		if act.Parallel {
+			s["parallel"] = act.Parallel
+		}
+		if act.StepName != "" {
+			s["name"] = act.StepName
+		}
+		if act.Condition != "" {
+			s["condition"] = act.Condition
+		}
 
 		switch act.Type {
 		case sdk.BuiltinAction:
@@ -536,6 +697,22 @@ func newSteps(a sdk.Action) []Step {
 				if path != nil {
 					s["jUnitReport"] = path.Value
 				}
+			case sdk.CoverageAction:
+				coverageArgs := map[string]string{}
+				for _, p := range act.Parameters {
+					if p.Value != "" {
+						coverageArgs[p.Name] = p.Value
+					}
+				}
+				s["coverage"] = coverageArgs
+			case sdk.AnalysisAction:
+				analysisArgs := map[string]string{}
+				for _, p := range act.Parameters {
+					if p.Value != "" {
+						analysisArgs[p.Name] = p.Value
+					}
+				}
+				s["staticAnalysis"] = analysisArgs
 			}
 		default:
 			args := map[string]string{}
@@ -544,7 +721,7 @@ func newSteps(a sdk.Action) []Step {
 					args[p.Name] = p.Value
 				}
 			}
-			s[act.Name] = args
+			s[sdk.PluginRef(act.Name, act.PluginVersion)] = args
 		}
 		res = append(res, s)
 	}
@@ -693,10 +870,31 @@ func computeSteps(steps []Step) ([]sdk.Action, error) {
 		}
 		res = append(res, *a)
 	}
+	parent := sdk.Action{Actions: res}
+	if err := parent.CheckStepNames(); err != nil {
+		return nil, err
+	}
 	return res, nil
 }
 
-func computeStep(s Step) (a *sdk.Action, e error) {
+func computeStep(s Step) (*sdk.Action, error) {
+	a, err := computeStepAction(s)
+	if err != nil {
+		return nil, err
+	}
+
+	a.StepName, err = s.Attribute("name")
+	if err != nil {
+		return nil, err
+	}
+	a.Condition, err = s.Attribute("condition")
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func computeStepAction(s Step) (a *sdk.Action, e error) {
 	if !s.IsValid() {
 		e = fmt.Errorf("Malformatted step")
 		return
@@ -718,6 +916,16 @@ func computeStep(s Step) (a *sdk.Action, e error) {
 		return
 	}
 
+	a, ok, e = s.AsCoverage()
+	if ok {
+		return
+	}
+
+	a, ok, e = s.AsAnalysis()
+	if ok {
+		return
+	}
+
 	a, ok, e = s.AsGitClone()
 	if ok {
 		return
@@ -761,13 +969,29 @@ func computeJobRequirements(req []Requirement) []sdk.Requirement {
 			val = r.Network
 			tpe = sdk.NetworkAccessRequirement
 		} else if r.Plugin != "" {
-			name = r.Plugin
+			name, _ = sdk.ParsePluginRef(r.Plugin)
 			val = r.Plugin
 			tpe = sdk.PluginRequirement
 		} else if r.Service.Name != "" {
 			name = r.Service.Name
 			val = r.Service.Value
 			tpe = sdk.ServiceRequirement
+		} else if r.OSArch != "" {
+			name = "os-architecture"
+			val = r.OSArch
+			tpe = sdk.OSArchRequirement
+		} else if r.Label != "" {
+			name = r.Label
+			val = r.Label
+			tpe = sdk.LabelRequirement
+		} else if r.CPU != "" {
+			name = "cpu"
+			val = r.CPU
+			tpe = sdk.CPURequirement
+		} else if r.Disk != "" {
+			name = "disk"
+			val = r.Disk
+			tpe = sdk.DiskRequirement
 		}
 		res = append(res, sdk.Requirement{
 			Name:  name,
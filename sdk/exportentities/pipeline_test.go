@@ -541,6 +541,105 @@ steps:
 	assert.Len(t, p.Stages[0].Jobs[0].Action.Actions[0].Parameters, 7)
 }
 
+func Test_ImportPipelineWithParallelSteps(t *testing.T) {
+	in := `name: build-all
+steps:
+- script: make
+- script: make lint
+  parallel: true
+- script: make test
+  parallel: true
+  optional: true
+- script: make package
+`
+
+	payload := &Pipeline{}
+	test.NoError(t, yaml.Unmarshal([]byte(in), payload))
+
+	p, err := payload.Pipeline()
+	test.NoError(t, err)
+
+	steps := p.Stages[0].Jobs[0].Action.Actions
+	assert.Len(t, steps, 4)
+	assert.False(t, steps[0].Parallel)
+	assert.True(t, steps[1].Parallel)
+	assert.True(t, steps[2].Parallel)
+	assert.True(t, steps[2].Optional)
+	assert.False(t, steps[3].Parallel)
+
+	exported := NewPipeline(p)
+	assert.Equal(t, payload.Steps, exported.Steps)
+}
+
+func Test_ImportPipelineWithStepConditions(t *testing.T) {
+	in := `name: build-all
+steps:
+- script: make test
+  name: tests
+- artifactUpload:
+    path: debug.log
+    tag: debug
+  name: debug
+  always_executed: true
+  condition: return steps_tests_status == "Fail"
+`
+
+	payload := &Pipeline{}
+	test.NoError(t, yaml.Unmarshal([]byte(in), payload))
+
+	p, err := payload.Pipeline()
+	test.NoError(t, err)
+
+	steps := p.Stages[0].Jobs[0].Action.Actions
+	assert.Len(t, steps, 2)
+	assert.Equal(t, "tests", steps[0].StepName)
+	assert.Equal(t, "", steps[0].Condition)
+	assert.Equal(t, "debug", steps[1].StepName)
+	assert.Equal(t, `return steps_tests_status == "Fail"`, steps[1].Condition)
+
+	exported := NewPipeline(p)
+	assert.Equal(t, "tests", exported.Steps[0]["name"])
+	assert.Equal(t, "debug", exported.Steps[1]["name"])
+	assert.Equal(t, `return steps_tests_status == "Fail"`, exported.Steps[1]["condition"])
+
+	payload.Steps[1]["name"] = "tests"
+	_, err = payload.Pipeline()
+	assert.Error(t, err)
+}
+
+func Test_ImportPipelineWithPluginVersion(t *testing.T) {
+	in := `name: deploy
+steps:
+- plugin-marathon@1.x:
+    url: http://marathon
+- plugin-tmpl:
+    file: app.tmpl
+`
+
+	payload := &Pipeline{}
+	test.NoError(t, yaml.Unmarshal([]byte(in), payload))
+
+	p, err := payload.Pipeline()
+	test.NoError(t, err)
+
+	steps := p.Stages[0].Jobs[0].Action.Actions
+	assert.Len(t, steps, 2)
+	assert.Equal(t, "plugin-marathon", steps[0].Name)
+	assert.Equal(t, "1.x", steps[0].PluginVersion)
+	assert.Equal(t, "plugin-tmpl", steps[1].Name)
+	assert.Equal(t, "", steps[1].PluginVersion)
+
+	exported := NewPipeline(p)
+	_, ok := exported.Steps[0]["plugin-marathon@1.x"]
+	assert.True(t, ok)
+	_, ok = exported.Steps[1]["plugin-tmpl"]
+	assert.True(t, ok)
+
+	payload.Steps[0] = Step{"plugin-marathon@latest": map[string]interface{}{"url": "http://marathon"}}
+	_, err = payload.Pipeline()
+	assert.Error(t, err)
+}
+
 func Test_IsFlagged(t *testing.T) {
 	testc := []struct {
 		flag     string
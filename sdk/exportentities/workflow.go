@@ -1,6 +1,10 @@
 package exportentities
 
-import "github.com/ovh/cds/sdk"
+import (
+	"encoding/json"
+
+	"github.com/ovh/cds/sdk"
+)
 
 type Workflow struct {
 	Version string `json:"version,omitempty" yaml:"version,omitempty"`
@@ -15,6 +19,7 @@ type Workflow struct {
 	EnvironmentName string                      `json:"environment,omitempty" yaml:"environment,omitempty"`
 	PipelineHooks   []HookEntry                 `json:"pipeline_hooks,omitempty" yaml:"pipeline_hooks,omitempty"`
 	Permissions     map[string]int              `json:"permissions,omitempty" yaml:"permissions,omitempty"`
+	Notifications   []NotificationEntry         `json:"notifications,omitempty" yaml:"notifications,omitempty"`
 }
 
 type WorkflowEntry struct {
@@ -30,6 +35,72 @@ type HookEntry struct {
 	Config map[string]string `json:"config,omitempty" yaml:"config,omitempty"`
 }
 
+type NotificationEntry struct {
+	Type     sdk.UserNotificationSettingsType `json:"type" yaml:"type"`
+	Nodes    []string                         `json:"nodes,omitempty" yaml:"nodes,omitempty"`
+	Settings sdk.UserNotificationSettings     `json:"settings,omitempty" yaml:"settings,omitempty"`
+}
+
+//UnmarshalJSON parses the settings according to the notification type
+func (n *NotificationEntry) UnmarshalJSON(b []byte) error {
+	var input struct {
+		Type     sdk.UserNotificationSettingsType `json:"type"`
+		Nodes    []string                         `json:"nodes"`
+		Settings json.RawMessage                  `json:"settings"`
+	}
+	if err := json.Unmarshal(b, &input); err != nil {
+		return err
+	}
+
+	settings, err := sdk.ParseWorkflowNotificationSettings(input.Type, input.Settings)
+	if err != nil {
+		return err
+	}
+
+	n.Type = input.Type
+	n.Nodes = input.Nodes
+	n.Settings = settings
+	return nil
+}
+
+//UnmarshalYAML parses the settings according to the notification type
+func (n *NotificationEntry) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var input struct {
+		Type  sdk.UserNotificationSettingsType `yaml:"type"`
+		Nodes []string                         `yaml:"nodes"`
+	}
+	if err := unmarshal(&input); err != nil {
+		return err
+	}
+
+	// Get empty settings of the notification type, then fill them
+	settings, err := sdk.ParseWorkflowNotificationSettings(input.Type, nil)
+	if err != nil {
+		return err
+	}
+	switch s := settings.(type) {
+	case *sdk.JabberEmailUserNotificationSettings:
+		content := struct {
+			Settings *sdk.JabberEmailUserNotificationSettings `yaml:"settings"`
+		}{s}
+		if err := unmarshal(&content); err != nil {
+			return err
+		}
+	case *sdk.WebhookUserNotificationSettings:
+		content := struct {
+			Settings *sdk.WebhookUserNotificationSettings `yaml:"settings"`
+		}{s}
+		if err := unmarshal(&content); err != nil {
+			return err
+		}
+	}
+
+	n.Type = input.Type
+	n.Nodes = input.Nodes
+	n.Settings = settings
+	return nil
+}
+
 type WorkflowVersion string
 
 const WorkflowVersion1 = "v1.0"
@@ -50,6 +121,14 @@ func NewWorkflow(w sdk.Workflow, withPermission bool) (Workflow, error) {
 		}
 	}
 
+	for _, n := range w.Notifications {
+		e.Notifications = append(e.Notifications, NotificationEntry{
+			Type:     n.Type,
+			Nodes:    n.SourceNodeRefs,
+			Settings: n.Settings,
+		})
+	}
+
 	var craftWorkflowEntry = func(n *sdk.WorkflowNode) (WorkflowEntry, error) {
 		entry := WorkflowEntry{}
 
@@ -0,0 +1,56 @@
+package exportentities
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestWorkflowNotificationsRoundTrip(t *testing.T) {
+	w := sdk.Workflow{
+		Name: "build",
+		Root: &sdk.WorkflowNode{ID: 1, Name: "root", Pipeline: sdk.Pipeline{Name: "pip"}, Context: &sdk.WorkflowNodeContext{}},
+		Notifications: []sdk.WorkflowNotification{
+			{
+				Type:           sdk.EmailUserNotification,
+				SourceNodeRefs: []string{"root"},
+				Settings: &sdk.JabberEmailUserNotificationSettings{
+					OnSuccess:  sdk.UserNotificationChange,
+					OnFailure:  sdk.UserNotificationAlways,
+					Recipients: []string{"devs@cds.local"},
+				},
+			},
+			{
+				Type: sdk.WebhookUserNotification,
+				Settings: &sdk.WebhookUserNotificationSettings{
+					OnFailure: sdk.UserNotificationAlways,
+					URL:       "https://chat.cds.local/hook",
+					Headers:   map[string]string{"X-Token": "{{.cds.proj.token}}"},
+				},
+			},
+		},
+	}
+
+	e, err := NewWorkflow(w, false)
+	assert.NoError(t, err)
+
+	for _, f := range []Format{FormatJSON, FormatYAML} {
+		b, err := Marshal(e, f)
+		assert.NoError(t, err)
+
+		var got Workflow
+		if f == FormatJSON {
+			assert.NoError(t, json.Unmarshal(b, &got))
+		} else {
+			assert.NoError(t, yaml.Unmarshal(b, &got))
+		}
+		assert.Equal(t, e.Notifications, got.Notifications, string(b))
+	}
+
+	var unknown NotificationEntry
+	assert.Error(t, json.Unmarshal([]byte(`{"type":"irc","settings":{}}`), &unknown))
+}
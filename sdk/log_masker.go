@@ -0,0 +1,93 @@
+package sdk
+
+import (
+	"encoding/base64"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// SecretMinLengthToMask is the minimal length of a secret value to be masked in logs.
+// Shorter values would mask too many innocent strings.
+const SecretMinLengthToMask = 6
+
+// LogMasker redacts secret values, and their base64 and URL-encoded variants, from logs
+type LogMasker struct {
+	mutex        sync.RWMutex
+	replacements []logMaskerReplacement
+}
+
+type logMaskerReplacement struct {
+	value string
+	mask  string
+}
+
+// NewLogMasker returns a LogMasker for all the given secrets
+func NewLogMasker(secrets []Variable) *LogMasker {
+	m := &LogMasker{}
+	for _, s := range secrets {
+		m.Add(s.Name, s.Value)
+	}
+	return m
+}
+
+// Add registers a new value to mask. If name is empty, the value is replaced by the password placeholder.
+func (m *LogMasker) Add(name, value string) {
+	if len(value) < SecretMinLengthToMask {
+		return
+	}
+
+	mask := PasswordPlaceholder
+	if name != "" {
+		mask = "**" + name + "**"
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	for _, v := range secretVariants(value) {
+		m.replacements = append(m.replacements, logMaskerReplacement{value: v, mask: mask})
+	}
+	// Longest values first, so a secret containing another one is fully masked
+	sort.SliceStable(m.replacements, func(i, j int) bool {
+		return len(m.replacements[i].value) > len(m.replacements[j].value)
+	})
+}
+
+// Mask returns the string with all the registered values redacted
+func (m *LogMasker) Mask(s string) string {
+	if m == nil {
+		return s
+	}
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	for _, r := range m.replacements {
+		s = strings.Replace(s, r.value, r.mask, -1)
+	}
+	return s
+}
+
+func secretVariants(value string) []string {
+	variants := []string{value}
+	candidates := []string{
+		base64.StdEncoding.EncodeToString([]byte(value)),
+		base64.RawStdEncoding.EncodeToString([]byte(value)),
+		base64.URLEncoding.EncodeToString([]byte(value)),
+		base64.RawURLEncoding.EncodeToString([]byte(value)),
+		url.QueryEscape(value),
+		url.PathEscape(value),
+	}
+	for _, c := range candidates {
+		var found bool
+		for _, v := range variants {
+			if v == c {
+				found = true
+				break
+			}
+		}
+		if !found {
+			variants = append(variants, c)
+		}
+	}
+	return variants
+}
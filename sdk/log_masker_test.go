@@ -0,0 +1,27 @@
+package sdk
+
+import (
+	"encoding/base64"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogMasker(t *testing.T) {
+	m := NewLogMasker([]Variable{
+		{Name: "cds.proj.password", Type: SecretVariable, Value: "my p@ssword/42"},
+		{Name: "cds.app.short", Type: SecretVariable, Value: "123"},
+	})
+
+	assert.Equal(t, "password is **cds.proj.password**", m.Mask("password is my p@ssword/42"))
+	assert.Equal(t, "encoded **cds.proj.password**", m.Mask("encoded "+base64.StdEncoding.EncodeToString([]byte("my p@ssword/42"))))
+	assert.Equal(t, "url **cds.proj.password**", m.Mask("url "+url.QueryEscape("my p@ssword/42")))
+	assert.Equal(t, "short values are not masked: 123", m.Mask("short values are not masked: 123"))
+
+	m.Add("", "registered-at-runtime")
+	assert.Equal(t, "token="+PasswordPlaceholder, m.Mask("token=registered-at-runtime"))
+
+	var nilMasker *LogMasker
+	assert.Equal(t, "my p@ssword/42", nilMasker.Mask("my p@ssword/42"))
+}
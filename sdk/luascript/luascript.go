@@ -65,3 +65,15 @@ func (c *Check) Perform(script string) error {
 	c.Result = ok
 	return nil
 }
+
+//PerformCondition performs the lua script of a condition, the result is true if the script returns neither nil nor false
+func (c *Check) PerformCondition(script string) error {
+	if err := c.state.DoString(script); err != nil {
+		c.IsError = true
+		return err
+	}
+
+	c.IsError = false
+	c.Result = lua.LVAsBool(c.state.Get(-1))
+	return nil
+}
@@ -0,0 +1,40 @@
+package luascript
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckPerformCondition(t *testing.T) {
+	testcases := []struct {
+		script   string
+		expected bool
+	}{
+		{script: `return git_branch == "master"`, expected: true},
+		{script: `return git_branch ~= "master"`, expected: false},
+		{script: `return cds_job_status == "Fail" or git_branch == "develop"`, expected: false},
+		{script: `return nil`, expected: false},
+	}
+
+	for _, tc := range testcases {
+		c := NewCheck()
+		c.SetVariables(map[string]string{"git.branch": "master", "cds.job.status": "Success"})
+		assert.NoError(t, c.PerformCondition(tc.script))
+		assert.Equal(t, tc.expected, c.Result, tc.script)
+	}
+
+	c := NewCheck()
+	assert.Error(t, c.PerformCondition(`git_branch ==`))
+	assert.True(t, c.IsError)
+}
+
+func TestCheckPerform(t *testing.T) {
+	// workflow conditions rely on the result of Perform
+	c := NewCheck()
+	c.SetVariables(map[string]string{"git.branch": "master"})
+	assert.NoError(t, c.Perform(`return git_branch ~= "master"`))
+	assert.True(t, c.Result)
+	assert.NoError(t, c.Perform(`return git_branch == "master"`))
+	assert.False(t, c.Result)
+}
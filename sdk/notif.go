@@ -7,8 +7,9 @@ type UserNotificationSettingsType string
 
 //const
 const (
-	EmailUserNotification  UserNotificationSettingsType = "email"
-	JabberUserNotification UserNotificationSettingsType = "jabber"
+	EmailUserNotification   UserNotificationSettingsType = "email"
+	JabberUserNotification  UserNotificationSettingsType = "jabber"
+	WebhookUserNotification UserNotificationSettingsType = "webhook"
 )
 
 //UserNotificationEventType always/never/change
@@ -50,13 +51,13 @@ type UserNotificationSettings interface {
 
 // JabberEmailUserNotificationSettings are jabber or email settings
 type JabberEmailUserNotificationSettings struct {
-	OnSuccess    UserNotificationEventType `json:"on_success"`
-	OnFailure    UserNotificationEventType `json:"on_failure"`
-	OnStart      bool                      `json:"on_start"`
-	SendToGroups bool                      `json:"send_to_groups"`
-	SendToAuthor bool                      `json:"send_to_author"`
-	Recipients   []string                  `json:"recipients"`
-	Template     UserNotificationTemplate  `json:"template"`
+	OnSuccess    UserNotificationEventType `json:"on_success" yaml:"on_success,omitempty"`
+	OnFailure    UserNotificationEventType `json:"on_failure" yaml:"on_failure,omitempty"`
+	OnStart      bool                      `json:"on_start" yaml:"on_start,omitempty"`
+	SendToGroups bool                      `json:"send_to_groups" yaml:"send_to_groups,omitempty"`
+	SendToAuthor bool                      `json:"send_to_author" yaml:"send_to_author,omitempty"`
+	Recipients   []string                  `json:"recipients" yaml:"recipients,omitempty"`
+	Template     UserNotificationTemplate  `json:"template" yaml:"template,omitempty"`
 }
 
 //Success returns always/never/change
@@ -82,8 +83,8 @@ func (n *JabberEmailUserNotificationSettings) JSON() string {
 
 // UserNotificationTemplate is the notification content
 type UserNotificationTemplate struct {
-	Subject string `json:"subject,omitempty"`
-	Body    string `json:"body,omitempty"`
+	Subject string `json:"subject,omitempty" yaml:"subject,omitempty"`
+	Body    string `json:"body,omitempty" yaml:"body,omitempty"`
 }
 
 //userNotificationInput is a way to parse notification
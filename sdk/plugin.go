@@ -2,9 +2,11 @@ package sdk
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"mime/multipart"
+	"net/url"
 	"os"
 	"path"
 	"path/filepath"
@@ -12,9 +14,17 @@ import (
 
 //DownloadPlugin download plugin from action
 func DownloadPlugin(name string, destdir string) error {
+	return DownloadPluginVersion(name, "", destdir)
+}
+
+//DownloadPluginVersion downloads a version of a plugin as name@version, the latest version as name without version
+func DownloadPluginVersion(name, version string, destdir string) error {
 	var lasterr error
 	for retry := 5; retry >= 0; retry-- {
 		uri := fmt.Sprintf("/plugin/download/%s", name)
+		if version != "" {
+			uri += "?version=" + url.QueryEscape(version)
+		}
 		reader, code, err := Stream("GET", uri, nil)
 		if err != nil {
 			lasterr = err
@@ -24,7 +34,7 @@ func DownloadPlugin(name string, destdir string) error {
 			lasterr = fmt.Errorf("HTTP %d", code)
 			continue
 		}
-		destPath := path.Join(destdir, name)
+		destPath := path.Join(destdir, PluginRef(name, version))
 		//If the file already exists, remove it
 		if _, errstat := os.Stat(destPath); errstat == nil {
 			os.RemoveAll(destPath)
@@ -48,8 +58,8 @@ func DownloadPlugin(name string, destdir string) error {
 	return fmt.Errorf("x5: %s", lasterr)
 }
 
-//UploadPlugin uploads binary file to perform a new action
-func UploadPlugin(filePath string, update bool) ([]byte, error) {
+//UploadPlugin uploads binary file to perform a new action. Without version, the version of the plugin binary is used.
+func UploadPlugin(filePath string, version string, update bool) ([]byte, error) {
 	if _, err := os.Stat(filePath); os.IsNotExist(err) {
 		return nil, err
 	}
@@ -73,6 +83,12 @@ func UploadPlugin(filePath string, update bool) ([]byte, error) {
 		return nil, err
 	}
 
+	if version != "" {
+		if err := writer.WriteField("version", version); err != nil {
+			return nil, err
+		}
+	}
+
 	if err := writer.Close(); err != nil {
 		return nil, err
 	}
@@ -92,6 +108,95 @@ func UploadPlugin(filePath string, update bool) ([]byte, error) {
 	return btes, nil
 }
 
+//UploadImagePlugin declares a docker action plugin, from its metadata, image and parameters
+func UploadImagePlugin(ap ActionPlugin, update bool) ([]byte, error) {
+	data, err := json.Marshal(ap)
+	if err != nil {
+		return nil, err
+	}
+
+	method := "POST"
+	if update {
+		method = "PUT"
+	}
+	btes, code, err := Request(method, "/plugin", data, SetHeader("Content-Type", "application/json"))
+	if err != nil {
+		return nil, err
+	}
+
+	if code >= 300 {
+		return nil, fmt.Errorf("HTTP Error %d\n", code)
+	}
+
+	return btes, nil
+}
+
+//GetPluginVersions returns the versions of a plugin, the highest first
+func GetPluginVersions(name string) ([]ActionPlugin, error) {
+	data, code, err := Request("GET", fmt.Sprintf("/plugin/%s/versions", name), nil)
+	if err != nil {
+		return nil, err
+	}
+	if code >= 300 {
+		return nil, fmt.Errorf("HTTP %d", code)
+	}
+
+	versions := []ActionPlugin{}
+	if err := json.Unmarshal(data, &versions); err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+//GetPluginVersion returns the highest version of a plugin matching a version range, the latest version without range
+func GetPluginVersion(name, versionRange string) (*ActionPlugin, error) {
+	uri := fmt.Sprintf("/plugin/%s/version", name)
+	if versionRange != "" {
+		uri += "?range=" + url.QueryEscape(versionRange)
+	}
+	data, code, err := Request("GET", uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	if code >= 300 {
+		return nil, fmt.Errorf("HTTP %d", code)
+	}
+
+	ap := &ActionPlugin{}
+	if err := json.Unmarshal(data, ap); err != nil {
+		return nil, err
+	}
+	return ap, nil
+}
+
+//DeprecatePluginVersion deprecates a version of a plugin, or cancels its deprecation
+func DeprecatePluginVersion(name, version string, deprecated bool) error {
+	data, err := json.Marshal(ActionPlugin{Name: name, Version: version, Deprecated: deprecated})
+	if err != nil {
+		return err
+	}
+	_, code, err := Request("PUT", fmt.Sprintf("/plugin/%s/versions/%s", name, url.PathEscape(version)), data)
+	if err != nil {
+		return err
+	}
+	if code >= 300 {
+		return fmt.Errorf("HTTP %d", code)
+	}
+	return nil
+}
+
+//DeletePluginVersion deletes a version of a plugin
+func DeletePluginVersion(name, version string) error {
+	_, code, err := Request("DELETE", fmt.Sprintf("/plugin/%s/versions/%s", name, url.PathEscape(version)), nil)
+	if err != nil {
+		return err
+	}
+	if code >= 300 {
+		return fmt.Errorf("HTTP %d", code)
+	}
+	return nil
+}
+
 //DeletePlugin delete plugin
 func DeletePlugin(name string) error {
 	path := fmt.Sprintf("/plugin/%s", name)
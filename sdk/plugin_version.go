@@ -0,0 +1,98 @@
+package sdk
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/blang/semver"
+)
+
+//PluginRef returns the reference of a plugin version or version range: name@version, or name without version
+func PluginRef(name, version string) string {
+	if version == "" {
+		return name
+	}
+	return name + "@" + version
+}
+
+//ParsePluginRef returns the plugin name and the version range of a plugin reference: name@version
+func ParsePluginRef(ref string) (string, string) {
+	t := strings.SplitN(ref, "@", 2)
+	if len(t) == 1 {
+		return t[0], ""
+	}
+	return t[0], t[1]
+}
+
+//CheckPluginVersion checks that a plugin version is semver and returns its canonical form: v1.2 gives 1.2.0
+func CheckPluginVersion(version string) (string, error) {
+	v, err := semver.ParseTolerant(version)
+	if err != nil {
+		return "", WrapError(ErrInvalidPluginVersion, "CheckPluginVersion> %s: %s", version, err)
+	}
+	return v.String(), nil
+}
+
+//CheckPluginVersionRange checks that a plugin version range is a semver range: 1.2.0, >=1.2.0 <2.0.0, 1.x
+func CheckPluginVersionRange(versionRange string) error {
+	if versionRange == "" {
+		return nil
+	}
+	if _, err := semver.ParseRange(versionRange); err != nil {
+		return WrapError(ErrInvalidPluginVersion, "CheckPluginVersionRange> %s: %s", versionRange, err)
+	}
+	return nil
+}
+
+//SortPluginVersions sorts the versions of a plugin, the highest first. The versions uploaded before versioning are the lowest.
+func SortPluginVersions(versions []ActionPlugin) {
+	sort.SliceStable(versions, func(i, j int) bool {
+		vi, erri := semver.Parse(versions[i].Version)
+		vj, errj := semver.Parse(versions[j].Version)
+		if erri != nil || errj != nil {
+			return erri == nil && errj != nil
+		}
+		return vi.GT(vj)
+	})
+}
+
+//ResolvePluginVersion returns the highest version of a plugin matching a version range, the latest version without range.
+//Deprecated versions are only returned if no other version matches.
+func ResolvePluginVersion(versions []ActionPlugin, versionRange string) (*ActionPlugin, error) {
+	match := func(semver.Version) bool { return true }
+	if versionRange != "" {
+		r, err := semver.ParseRange(versionRange)
+		if err != nil {
+			return nil, WrapError(ErrInvalidPluginVersion, "ResolvePluginVersion> %s: %s", versionRange, err)
+		}
+		match = r
+	}
+
+	sorted := make([]ActionPlugin, len(versions))
+	copy(sorted, versions)
+	SortPluginVersions(sorted)
+
+	var deprecated *ActionPlugin
+	for i := range sorted {
+		v, err := semver.Parse(sorted[i].Version)
+		if err != nil {
+			// a plugin uploaded before versioning only matches without range
+			if versionRange != "" {
+				continue
+			}
+		} else if !match(v) {
+			continue
+		}
+		if !sorted[i].Deprecated {
+			return &sorted[i], nil
+		}
+		if deprecated == nil {
+			deprecated = &sorted[i]
+		}
+	}
+
+	if deprecated != nil {
+		return deprecated, nil
+	}
+	return nil, ErrPluginVersionNotFound
+}
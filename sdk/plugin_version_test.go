@@ -0,0 +1,103 @@
+package sdk
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePluginRef(t *testing.T) {
+	name, versionRange := ParsePluginRef("plugin-foo@>=1.2.0 <2.0.0")
+	assert.Equal(t, "plugin-foo", name)
+	assert.Equal(t, ">=1.2.0 <2.0.0", versionRange)
+
+	name, versionRange = ParsePluginRef("plugin-foo")
+	assert.Equal(t, "plugin-foo", name)
+	assert.Equal(t, "", versionRange)
+
+	assert.Equal(t, "plugin-foo@1.x", PluginRef("plugin-foo", "1.x"))
+	assert.Equal(t, "plugin-foo", PluginRef("plugin-foo", ""))
+}
+
+func TestCheckPluginVersion(t *testing.T) {
+	v, err := CheckPluginVersion("v1.2")
+	assert.NoError(t, err)
+	assert.Equal(t, "1.2.0", v)
+
+	_, err = CheckPluginVersion("snapshot")
+	assert.Error(t, err)
+
+	assert.NoError(t, CheckPluginVersionRange(">=1.2.0 <2.0.0"))
+	assert.NoError(t, CheckPluginVersionRange("1.x"))
+	assert.Error(t, CheckPluginVersionRange("latest"))
+}
+
+func TestResolvePluginVersion(t *testing.T) {
+	versions := []ActionPlugin{
+		{Name: "plugin-foo", Version: ""},
+		{Name: "plugin-foo", Version: "1.2.0"},
+		{Name: "plugin-foo", Version: "2.0.0", Deprecated: true},
+		{Name: "plugin-foo", Version: "1.10.0"},
+		{Name: "plugin-foo", Version: "1.3.0", Deprecated: true},
+	}
+
+	tests := []struct {
+		versionRange string
+		expected     string
+	}{
+		{"", "1.10.0"},
+		{"1.2.0", "1.2.0"},
+		{"1.x", "1.10.0"},
+		{">=1.2.0 <1.5.0", "1.2.0"},
+		{"2.0.0", "2.0.0"},
+		{">=1.3.0 <1.4.0", "1.3.0"},
+	}
+	for _, tt := range tests {
+		ap, err := ResolvePluginVersion(versions, tt.versionRange)
+		if !assert.NoError(t, err, tt.versionRange) {
+			continue
+		}
+		assert.Equal(t, tt.expected, ap.Version, tt.versionRange)
+	}
+
+	_, err := ResolvePluginVersion(versions, "3.x")
+	assert.Equal(t, ErrPluginVersionNotFound, err)
+
+	_, err = ResolvePluginVersion(versions, "latest")
+	assert.Error(t, err)
+
+	// a plugin uploaded before versioning is only resolved without range
+	ap, err := ResolvePluginVersion(versions[:1], "")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "", ap.Version)
+	}
+	_, err = ResolvePluginVersion(versions[:1], "1.x")
+	assert.Equal(t, ErrPluginVersionNotFound, err)
+
+	SortPluginVersions(versions)
+	assert.Equal(t, []string{"2.0.0", "1.10.0", "1.3.0", "1.2.0", ""}, []string{versions[0].Version, versions[1].Version, versions[2].Version, versions[3].Version, versions[4].Version})
+}
+
+func TestPluginVersionHTTPStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// a valid body, only the status tells the request failed
+		w.WriteHeader(http.StatusMultipleChoices)
+		if strings.HasSuffix(r.URL.Path, "/versions") {
+			w.Write([]byte("[]"))
+		} else {
+			w.Write([]byte("{}"))
+		}
+	}))
+	defer srv.Close()
+	InitEndpoint(srv.URL)
+
+	_, err := GetPluginVersions("plugin-foo")
+	assert.Error(t, err)
+	_, err = GetPluginVersion("plugin-foo", ">=1.0.0")
+	assert.Error(t, err)
+	assert.Error(t, DeprecatePluginVersion("plugin-foo", "1.0.0", true))
+	assert.Error(t, DeletePluginVersion("plugin-foo", "1.0.0"))
+}
@@ -101,6 +101,25 @@ func ConnectReposManagerCallback(key, name, requestToken, verifier string) (stri
 	return r["access_token"].(string), r["access_token_secret"].(string), nil
 }
 
+//ConnectReposManagerWithSSHKey links a repositories manager without any API, such as a plain git server, to a project.
+//The repositories manager is reached with the given SSH key of the project
+func ConnectReposManagerWithSSHKey(key, name, requestToken, sshKey string) error {
+	uri := fmt.Sprintf("/project/%s/repositories_manager/%s/authorize/callback", key, name)
+	tv := map[string]string{
+		"request_token": requestToken,
+		"ssh_key":       sshKey,
+	}
+	b, _ := json.Marshal(tv)
+	_, code, err := Request("POST", uri, b)
+	if err != nil {
+		return err
+	}
+	if code >= 300 {
+		return fmt.Errorf("HTTP %d", code)
+	}
+	return nil
+}
+
 //DisconnectReposManager removes access token for the project
 func DisconnectReposManager(key, name string) error {
 	uri := fmt.Sprintf("/project/%s/repositories_manager/%s", key, name)
@@ -298,6 +317,23 @@ type VCSCommit struct {
 	URL       string    `json:"url"`
 }
 
+//VCSContent types
+const (
+	VCSContentTypeFile      = "file"
+	VCSContentTypeDirectory = "dir"
+)
+
+//VCSContent represents a file or a directory of a repository at a given ref.
+//Content is only set on files, Entries only on directories
+type VCSContent struct {
+	Name    string       `json:"name"`
+	Path    string       `json:"path"`
+	Type    string       `json:"type"`
+	Size    int64        `json:"size"`
+	Content []byte       `json:"content,omitempty"`
+	Entries []VCSContent `json:"entries,omitempty"`
+}
+
 //VCSRemote represents remotes known by the repositories manager
 type VCSRemote struct {
 	Name string `json:"name"`
@@ -360,4 +396,5 @@ type VCSHook struct {
 	Body        string   `json:"body"`
 	InsecureSSL bool     `json:"insecure_ssl"`
 	Workflow    bool     `json:"workflow"`
+	Secret      string   `json:"secret,omitempty"`
 }
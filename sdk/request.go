@@ -35,6 +35,8 @@ var (
 	RequestedWithValue = "X-CDS-SDK"
 	//SessionTokenHeader is user as HTTP header
 	SessionTokenHeader = "Session-Token"
+	// AccessTokenHeader is used as HTTP header for personal access tokens
+	AccessTokenHeader = "X-Cds-Access-Token"
 	// HTTP client
 	client HTTPClient
 	// current agent calling
@@ -1,5 +1,10 @@
 package sdk
 
+import (
+	"strconv"
+	"strings"
+)
+
 const (
 	//BinaryRequirement refers to the need to a specific binary on host running the action
 	BinaryRequirement = "binary"
@@ -17,6 +22,14 @@ const (
 	MemoryRequirement = "memory"
 	// VolumeRequirement set Volume limit on a container
 	VolumeRequirement = "volume"
+	// OSArchRequirement checks the operating system and the architecture of the worker, ex: linux/amd64 or linux
+	OSArchRequirement = "os-architecture"
+	// LabelRequirement checks the worker has the given label
+	LabelRequirement = "label"
+	// CPURequirement checks the minimum number of CPU of the worker
+	CPURequirement = "cpu"
+	// DiskRequirement checks the minimum free disk space of the worker, in megabytes
+	DiskRequirement = "disk"
 )
 
 var (
@@ -30,6 +43,25 @@ var (
 		ServiceRequirement,
 		MemoryRequirement,
 		VolumeRequirement,
+		OSArchRequirement,
+		LabelRequirement,
+		CPURequirement,
+		DiskRequirement,
+	}
+
+	// ModelCapabilitiesRequirementsType List of the requirements a worker model has to advertise in its capabilities
+	ModelCapabilitiesRequirementsType = []string{
+		OSArchRequirement,
+		LabelRequirement,
+		CPURequirement,
+		DiskRequirement,
+	}
+
+	// HostCapabilitiesRequirementsType List of the capabilities a worker advertises about its host when it registers
+	HostCapabilitiesRequirementsType = []string{
+		OSArchRequirement,
+		CPURequirement,
+		DiskRequirement,
 	}
 )
 
@@ -63,3 +95,78 @@ func (a *Action) Requirement(name string, t string, value string) *Action {
 	a.Requirements = append(a.Requirements, r)
 	return a
 }
+
+// MatchOSArch returns true if the os/arch value matches the requirement value.
+// The architecture is optional in the requirement: "linux" matches "linux/amd64"
+func MatchOSArch(requirement, value string) bool {
+	reqOS, reqArch := splitOSArch(requirement)
+	valueOS, valueArch := splitOSArch(value)
+	if reqOS != valueOS {
+		return false
+	}
+	return reqArch == "" || reqArch == valueArch
+}
+
+func splitOSArch(s string) (string, string) {
+	t := strings.SplitN(strings.ToLower(strings.TrimSpace(s)), "/", 2)
+	if len(t) == 1 {
+		return t[0], ""
+	}
+	return t[0], t[1]
+}
+
+// IsCapabilityRequirement returns true if the requirement has to be satisfied by worker model capabilities
+func IsCapabilityRequirement(r Requirement) bool {
+	for _, t := range ModelCapabilitiesRequirementsType {
+		if r.Type == t {
+			return true
+		}
+	}
+	return false
+}
+
+// IsHostCapabilityRequirement returns true if the requirement is advertised by the worker when it registers
+func IsHostCapabilityRequirement(r Requirement) bool {
+	for _, t := range HostCapabilitiesRequirementsType {
+		if r.Type == t {
+			return true
+		}
+	}
+	return false
+}
+
+// SatisfyRequirement returns true if one of the capabilities satisfies the os-architecture, label, cpu or disk requirement
+func SatisfyRequirement(capabilities []Requirement, r Requirement) bool {
+	for _, c := range capabilities {
+		if c.Type != r.Type {
+			continue
+		}
+		switch r.Type {
+		case OSArchRequirement:
+			if MatchOSArch(r.Value, c.Value) {
+				return true
+			}
+		case LabelRequirement:
+			if strings.TrimSpace(c.Value) == strings.TrimSpace(r.Value) {
+				return true
+			}
+		case CPURequirement, DiskRequirement:
+			needed, errN := strconv.ParseInt(strings.TrimSpace(r.Value), 10, 64)
+			available, errA := strconv.ParseInt(strings.TrimSpace(c.Value), 10, 64)
+			if errN == nil && errA == nil && available >= needed {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// SatisfyRequirements returns true if the model capabilities satisfy all the os-architecture, label, cpu and disk requirements
+func (m *Model) SatisfyRequirements(requirements []Requirement) bool {
+	for _, r := range requirements {
+		if IsCapabilityRequirement(r) && !SatisfyRequirement(m.Capabilities, r) {
+			return false
+		}
+	}
+	return true
+}
@@ -27,6 +27,7 @@ const (
 	BooleanVariable    = "boolean"
 	NumberVariable     = "number"
 	RepositoryVariable = "repository"
+	VaultVariable      = "vault"
 )
 
 var (
@@ -38,6 +39,7 @@ var (
 		KeyVariable,
 		BooleanVariable,
 		NumberVariable,
+		VaultVariable,
 	}
 )
 
@@ -51,6 +53,11 @@ func NeedPlaceholder(t string) bool {
 	}
 }
 
+// IsVaultVariable returns true if variable value is a path to a secret stored in Vault
+func IsVaultVariable(t string) bool {
+	return t == VaultVariable
+}
+
 // VariablerFind return a variable given its name if it exists in array
 func VariablerFind(vars []Variable, s string) *Variable {
 	for _, v := range vars {
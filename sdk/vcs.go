@@ -24,6 +24,9 @@ type VCSAuthorizedClient interface {
 	Commits(repo, branch, since, until string) ([]VCSCommit, error)
 	Commit(repo, hash string) (VCSCommit, error)
 
+	//Contents returns a file with its content, or a directory with its entries, at the given ref
+	Contents(repo, path, ref string) (VCSContent, error)
+
 	// PullRequests
 	PullRequests(string) ([]VCSPullRequest, error)
 
@@ -0,0 +1,47 @@
+package git
+
+import (
+	"os"
+)
+
+// LsRemote lists the references of a remote repository, with the symbolic references such as HEAD
+func LsRemote(repo string, auth *AuthOpts, output *OutputOpts) error {
+	repoURL, err := getRepoURL(repo, auth)
+	if err != nil {
+		return err
+	}
+	commands := []cmd{
+		{
+			cmd:  "git",
+			args: []string{"ls-remote", "--symref", repoURL},
+		},
+	}
+	return runGitCommands(repo, commands, auth, output)
+}
+
+// Mirror makes a bare mirror of a repository in path, or updates all its references if the mirror already exists
+func Mirror(repo string, path string, auth *AuthOpts, output *OutputOpts) error {
+	repoURL, err := getRepoURL(repo, auth)
+	if err != nil {
+		return err
+	}
+	return runGitCommands(repo, prepareGitMirrorCommands(repoURL, path), auth, output)
+}
+
+func prepareGitMirrorCommands(repo string, path string) cmds {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return cmds{
+			{
+				cmd:  "git",
+				args: []string{"clone", "--mirror", "--quiet", repo, path},
+			},
+		}
+	}
+	return cmds{
+		{
+			dir:  path,
+			cmd:  "git",
+			args: []string{"fetch", "--prune", "--quiet", "origin"},
+		},
+	}
+}
@@ -3,6 +3,7 @@ package sdk
 import (
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 )
 
@@ -15,6 +16,11 @@ const (
 	WorkflowConditionsOperatorGreaterThan        = "gt"
 	WorkflowConditionsOperatorGreaterOrEqualThan = "ge"
 	WorkflowConditionsOperatorRegex              = "regex"
+	// Numeric operators, the other comparison operators compare strings
+	WorkflowConditionsOperatorNumberLessThan           = "num_lt"
+	WorkflowConditionsOperatorNumberLessOrEqualThan    = "num_le"
+	WorkflowConditionsOperatorNumberGreaterThan        = "num_gt"
+	WorkflowConditionsOperatorNumberGreaterOrEqualThan = "num_ge"
 )
 
 // Workflow conditions operator
@@ -27,6 +33,10 @@ var (
 		WorkflowConditionsOperatorGreaterThan:        ">",
 		WorkflowConditionsOperatorGreaterOrEqualThan: ">=",
 		WorkflowConditionsOperatorRegex:              "match",
+		WorkflowConditionsOperatorNumberLessThan:           "< (number)",
+		WorkflowConditionsOperatorNumberLessOrEqualThan:    "<= (number)",
+		WorkflowConditionsOperatorNumberGreaterThan:        "> (number)",
+		WorkflowConditionsOperatorNumberGreaterOrEqualThan: ">= (number)",
 	}
 )
 
@@ -68,6 +78,10 @@ func WorkflowCheckConditions(conditions []WorkflowNodeCondition, params []Parame
 		case WorkflowConditionsOperatorGreaterOrEqualThan:
 			conditionsOK = conditionsOK && strings.Compare(mapParams[cond.Variable], cond.Value) >= 0
 
+		case WorkflowConditionsOperatorNumberLessThan, WorkflowConditionsOperatorNumberLessOrEqualThan,
+			WorkflowConditionsOperatorNumberGreaterThan, WorkflowConditionsOperatorNumberGreaterOrEqualThan:
+			conditionsOK = conditionsOK && checkNumberCondition(mapParams[cond.Variable], cond.Operator, cond.Value)
+
 		case WorkflowConditionsOperatorRegex:
 			match, err := regexp.MatchString(cond.Value, mapParams[cond.Variable])
 			if err != nil {
@@ -79,3 +93,23 @@ func WorkflowCheckConditions(conditions []WorkflowNodeCondition, params []Parame
 
 	return conditionsOK, nil
 }
+
+// checkNumberCondition compares two values as numbers, such as cds.coverage.delta. The condition is false if one of them isn't a number.
+func checkNumberCondition(value, operator, expected string) bool {
+	v, errV := strconv.ParseFloat(value, 64)
+	e, errE := strconv.ParseFloat(expected, 64)
+	if errV != nil || errE != nil {
+		return false
+	}
+	switch operator {
+	case WorkflowConditionsOperatorNumberLessThan:
+		return v < e
+	case WorkflowConditionsOperatorNumberLessOrEqualThan:
+		return v <= e
+	case WorkflowConditionsOperatorNumberGreaterThan:
+		return v > e
+	case WorkflowConditionsOperatorNumberGreaterOrEqualThan:
+		return v >= e
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkflowCheckConditionsNumbers(t *testing.T) {
+	params := []Parameter{
+		{Name: "cds.coverage.delta", Value: "-2.50"},
+		{Name: "git.branch", Value: "master"},
+	}
+
+	ok, err := WorkflowCheckConditions([]WorkflowNodeCondition{
+		{Variable: "cds.coverage.delta", Operator: WorkflowConditionsOperatorNumberGreaterOrEqualThan, Value: "-1"},
+	}, params)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	ok, err = WorkflowCheckConditions([]WorkflowNodeCondition{
+		{Variable: "cds.coverage.delta", Operator: WorkflowConditionsOperatorNumberLessThan, Value: "-1"},
+		{Variable: "git.branch", Operator: WorkflowConditionsOperatorLessThan, Value: "release"},
+	}, params)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestWorkflowCheckConditionsMixedValues(t *testing.T) {
+	tests := []struct {
+		value, operator, expected string
+		ok                        bool
+	}{
+		// numeric operators compare numbers
+		{"10", WorkflowConditionsOperatorNumberGreaterThan, "9", true},
+		{"2", WorkflowConditionsOperatorNumberLessThan, "10", true},
+		{"010", WorkflowConditionsOperatorNumberGreaterOrEqualThan, "10", true},
+		{"1.5", WorkflowConditionsOperatorNumberLessOrEqualThan, "1.5", true},
+		// numeric conditions are false if a value isn't a number
+		{"v10", WorkflowConditionsOperatorNumberGreaterThan, "v9", false},
+		{"", WorkflowConditionsOperatorNumberLessThan, "1", false},
+		// the other operators still compare strings
+		{"10", WorkflowConditionsOperatorGreaterThan, "9", false},
+		{"2", WorkflowConditionsOperatorLessThan, "10", false},
+		{"10", WorkflowConditionsOperatorGreaterThan, "v9", false},
+		{"1.10.0", WorkflowConditionsOperatorLessThan, "1.9.0", true},
+		{"", WorkflowConditionsOperatorLessThan, "1", true},
+		{"master", WorkflowConditionsOperatorLessOrEqualThan, "release", true},
+	}
+	for _, tt := range tests {
+		ok, err := WorkflowCheckConditions([]WorkflowNodeCondition{
+			{Variable: "cds.var", Operator: tt.operator, Value: tt.expected},
+		}, []Parameter{{Name: "cds.var", Value: tt.value}})
+		assert.NoError(t, err)
+		assert.Equal(t, tt.ok, ok, "%s %s %s", tt.value, tt.operator, tt.expected)
+	}
+}
@@ -4,6 +4,7 @@ const (
 	GitlabIcon    = "Gitlab"
 	GitHubIcon    = "Github"
 	BitbucketIcon = "Bitbucket"
+	GiteaIcon     = "Gitea"
 )
 
 // FilterHooksConfig filter all hooks configuration and remove somme configuration key
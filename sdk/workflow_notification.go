@@ -0,0 +1,116 @@
+package sdk
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// WorkflowNotification is a notification rule on a workflow.
+// It applies to the nodes listed by name in SourceNodeRefs, or to all nodes if empty.
+type WorkflowNotification struct {
+	SourceNodeRefs []string                     `json:"source_node_ref,omitempty"`
+	Type           UserNotificationSettingsType `json:"type"`
+	Settings       UserNotificationSettings     `json:"settings"`
+}
+
+// WebhookUserNotificationSettings are generic HTTP webhook settings
+type WebhookUserNotificationSettings struct {
+	OnSuccess UserNotificationEventType `json:"on_success" yaml:"on_success,omitempty"`
+	OnFailure UserNotificationEventType `json:"on_failure" yaml:"on_failure,omitempty"`
+	OnStart   bool                      `json:"on_start" yaml:"on_start,omitempty"`
+	URL       string                    `json:"url" yaml:"url"`
+	Method    string                    `json:"method,omitempty" yaml:"method,omitempty"`
+	Headers   map[string]string         `json:"headers,omitempty" yaml:"headers,omitempty"`
+	Template  UserNotificationTemplate  `json:"template" yaml:"template,omitempty"`
+}
+
+//Success returns always/never/change
+func (n *WebhookUserNotificationSettings) Success() UserNotificationEventType {
+	return n.OnSuccess
+}
+
+//Failure returns always/never/change
+func (n *WebhookUserNotificationSettings) Failure() UserNotificationEventType {
+	return n.OnFailure
+}
+
+//Start returns true if the webhook is called when the node starts
+func (n *WebhookUserNotificationSettings) Start() bool {
+	return n.OnStart
+}
+
+//JSON returns json as string
+func (n *WebhookUserNotificationSettings) JSON() string {
+	b, _ := json.Marshal(n)
+	return string(b)
+}
+
+//UnmarshalJSON parses the settings according to the notification type
+func (n *WorkflowNotification) UnmarshalJSON(b []byte) error {
+	var input struct {
+		SourceNodeRefs []string                     `json:"source_node_ref"`
+		Type           UserNotificationSettingsType `json:"type"`
+		Settings       json.RawMessage              `json:"settings"`
+	}
+	if err := json.Unmarshal(b, &input); err != nil {
+		return err
+	}
+
+	settings, err := ParseWorkflowNotificationSettings(input.Type, input.Settings)
+	if err != nil {
+		return err
+	}
+
+	n.SourceNodeRefs = input.SourceNodeRefs
+	n.Type = input.Type
+	n.Settings = settings
+	return nil
+}
+
+// ParseWorkflowNotificationSettings returns the settings of a notification type
+func ParseWorkflowNotificationSettings(t UserNotificationSettingsType, b []byte) (UserNotificationSettings, error) {
+	var settings UserNotificationSettings
+	switch t {
+	case EmailUserNotification, JabberUserNotification:
+		settings = &JabberEmailUserNotificationSettings{}
+	case WebhookUserNotification:
+		settings = &WebhookUserNotificationSettings{}
+	default:
+		return nil, ErrNotSupportedUserNotification
+	}
+	if len(b) > 0 {
+		if err := json.Unmarshal(b, settings); err != nil {
+			return nil, ErrParseUserNotification
+		}
+	}
+	return settings, nil
+}
+
+// IsValid checks the notification settings and the node references against the workflow
+func (n WorkflowNotification) IsValid(w *Workflow) error {
+	if n.Settings == nil {
+		return NewError(ErrWorkflowInvalid, fmt.Errorf("Missing settings on %s notification", n.Type))
+	}
+	if webhook, ok := n.Settings.(*WebhookUserNotificationSettings); ok && webhook.URL == "" {
+		return NewError(ErrWorkflowInvalid, fmt.Errorf("Missing URL on webhook notification"))
+	}
+	for _, ref := range n.SourceNodeRefs {
+		if w.Root == nil || w.GetNodeByName(ref) == nil {
+			return NewError(ErrWorkflowInvalid, fmt.Errorf("Unknown node %s in %s notification", ref, n.Type))
+		}
+	}
+	return nil
+}
+
+// Matches returns true if the notification applies to the node
+func (n WorkflowNotification) Matches(nodeName string) bool {
+	if len(n.SourceNodeRefs) == 0 {
+		return true
+	}
+	for _, ref := range n.SourceNodeRefs {
+		if ref == nodeName {
+			return true
+		}
+	}
+	return false
+}
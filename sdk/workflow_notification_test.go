@@ -0,0 +1,59 @@
+package sdk
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkflowNotification(t *testing.T) {
+	w := &Workflow{
+		Root: &WorkflowNode{
+			Name: "build",
+			Triggers: []WorkflowNodeTrigger{
+				{WorkflowDestNode: WorkflowNode{Name: "deploy"}},
+			},
+		},
+		Notifications: []WorkflowNotification{
+			{
+				SourceNodeRefs: []string{"deploy"},
+				Type:           WebhookUserNotification,
+				Settings: &WebhookUserNotificationSettings{
+					OnFailure: UserNotificationAlways,
+					URL:       "https://chat.local/hook",
+					Template:  UserNotificationTemplate{Body: `{"text": "{{.cds.workflow}} is {{.cds.status}}"}`},
+				},
+			},
+			{
+				Type: EmailUserNotification,
+				Settings: &JabberEmailUserNotificationSettings{
+					OnSuccess:    UserNotificationChange,
+					SendToGroups: true,
+				},
+			},
+		},
+	}
+
+	b, err := json.Marshal(w.Notifications)
+	assert.NoError(t, err)
+
+	var notifications []WorkflowNotification
+	assert.NoError(t, json.Unmarshal(b, &notifications))
+	assert.Equal(t, w.Notifications, notifications)
+
+	for _, n := range notifications {
+		assert.NoError(t, n.IsValid(w))
+	}
+	assert.True(t, notifications[0].Matches("deploy"))
+	assert.False(t, notifications[0].Matches("build"))
+	assert.True(t, notifications[1].Matches("build"))
+
+	unknownNode := WorkflowNotification{SourceNodeRefs: []string{"unknown"}, Type: JabberUserNotification, Settings: &JabberEmailUserNotificationSettings{}}
+	assert.Error(t, unknownNode.IsValid(w))
+	missingURL := WorkflowNotification{Type: WebhookUserNotification, Settings: &WebhookUserNotificationSettings{}}
+	assert.Error(t, missingURL.IsValid(w))
+
+	var unsupported WorkflowNotification
+	assert.Error(t, json.Unmarshal([]byte(`{"type": "carrier-pigeon"}`), &unsupported))
+}
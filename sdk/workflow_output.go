@@ -0,0 +1,69 @@
+package sdk
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Limits of the outputs of a workflow node run
+const (
+	WorkflowNodeOutputMaxSize  = 4096
+	WorkflowNodeOutputMaxCount = 32
+	// WorkflowNodeOutputPrefix is the prefix of the build parameters of the outputs of a node run,
+	// the children nodes get them as workflow.<node name>.output.<name>
+	WorkflowNodeOutputPrefix = "cds.output."
+)
+
+//WorkflowNodeOutput is a value sent by a step which is propagated to the next nodes of a workflow
+type WorkflowNodeOutput struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+//IsValid checks the name, the type and the size of an output
+func (o WorkflowNodeOutput) IsValid() error {
+	if !stepNamePatternRegex.MatchString(o.Name) || len(o.Value) > WorkflowNodeOutputMaxSize {
+		return ErrInvalidNodeOutput
+	}
+	switch o.Type {
+	case StringParameter:
+	case NumberParameter:
+		if _, err := strconv.ParseFloat(o.Value, 64); err != nil {
+			return ErrInvalidNodeOutput
+		}
+	case BooleanParameter:
+		if _, err := strconv.ParseBool(o.Value); err != nil {
+			return ErrInvalidNodeOutput
+		}
+	default:
+		return ErrInvalidNodeOutput
+	}
+	return nil
+}
+
+//AddNodeOutput adds or replaces an output in the build parameters of a node run
+func AddNodeOutput(params *[]Parameter, o WorkflowNodeOutput) error {
+	if err := o.IsValid(); err != nil {
+		return err
+	}
+
+	name := WorkflowNodeOutputPrefix + o.Name
+	var count int
+	for i := range *params {
+		p := &(*params)[i]
+		if p.Name == name {
+			p.Type = o.Type
+			p.Value = o.Value
+			return nil
+		}
+		if strings.HasPrefix(p.Name, WorkflowNodeOutputPrefix) {
+			count++
+		}
+	}
+	if count >= WorkflowNodeOutputMaxCount {
+		return ErrInvalidNodeOutput
+	}
+	AddParameter(params, name, o.Type, o.Value)
+	return nil
+}
@@ -0,0 +1,46 @@
+package sdk
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkflowNodeOutputIsValid(t *testing.T) {
+	testcases := []struct {
+		output WorkflowNodeOutput
+		valid  bool
+	}{
+		{output: WorkflowNodeOutput{Name: "image_digest", Type: StringParameter, Value: "sha256:abcd"}, valid: true},
+		{output: WorkflowNodeOutput{Name: "size", Type: NumberParameter, Value: "12.5"}, valid: true},
+		{output: WorkflowNodeOutput{Name: "size", Type: NumberParameter, Value: "big"}, valid: false},
+		{output: WorkflowNodeOutput{Name: "deploy", Type: BooleanParameter, Value: "true"}, valid: true},
+		{output: WorkflowNodeOutput{Name: "deploy", Type: BooleanParameter, Value: "yes"}, valid: false},
+		{output: WorkflowNodeOutput{Name: "image.digest", Type: StringParameter, Value: "sha256:abcd"}, valid: false},
+		{output: WorkflowNodeOutput{Name: "key", Type: KeyParameter, Value: "xxx"}, valid: false},
+		{output: WorkflowNodeOutput{Name: "big", Type: StringParameter, Value: strings.Repeat("a", WorkflowNodeOutputMaxSize+1)}, valid: false},
+	}
+
+	for _, tc := range testcases {
+		err := tc.output.IsValid()
+		assert.Equal(t, tc.valid, err == nil, "%+v", tc.output)
+	}
+}
+
+func TestAddNodeOutput(t *testing.T) {
+	params := []Parameter{{Name: "cds.version", Type: StringParameter, Value: "1"}}
+
+	assert.NoError(t, AddNodeOutput(&params, WorkflowNodeOutput{Name: "digest", Type: StringParameter, Value: "a"}))
+	assert.NoError(t, AddNodeOutput(&params, WorkflowNodeOutput{Name: "digest", Type: StringParameter, Value: "b"}))
+	assert.Len(t, params, 2)
+	assert.Equal(t, "b", ParameterValue(params, "cds.output.digest"))
+
+	for i := 1; i < WorkflowNodeOutputMaxCount; i++ {
+		assert.NoError(t, AddNodeOutput(&params, WorkflowNodeOutput{Name: fmt.Sprintf("o%d", i), Type: StringParameter, Value: "v"}))
+	}
+	assert.Error(t, AddNodeOutput(&params, WorkflowNodeOutput{Name: "onemore", Type: StringParameter, Value: "v"}))
+	// an existing output can still be updated
+	assert.NoError(t, AddNodeOutput(&params, WorkflowNodeOutput{Name: "digest", Type: StringParameter, Value: "c"}))
+}
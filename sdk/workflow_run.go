@@ -107,6 +107,8 @@ type WorkflowNodeRun struct {
 	BuildParameters    []Parameter                      `json:"build_parameters"`
 	Artifacts          []WorkflowNodeRunArtifact        `json:"artifacts,omitempty"`
 	Tests              *venom.Tests                     `json:"tests,omitempty"`
+	Coverage           *Coverage                        `json:"coverage,omitempty"`
+	Findings           *AnalysisReport                  `json:"findings,omitempty"`
 	Commits            []VCSCommit                      `json:"commits,omitempty"`
 	TriggersRun        map[int64]WorkflowNodeTriggerRun `json:"triggers_run,omitempty"`
 }
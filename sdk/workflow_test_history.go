@@ -0,0 +1,163 @@
+package sdk
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/ovh/venom"
+)
+
+// Test case statuses in the test history of a workflow
+const (
+	TestStatusSuccess = "success"
+	TestStatusFail    = "fail"
+	TestStatusSkipped = "skipped"
+)
+
+//WorkflowNodeRunTests is the test results of a node run, used to compute the test history of a workflow
+type WorkflowNodeRunTests struct {
+	WorkflowRunNumber int64       `json:"num"`
+	SubNumber         int64       `json:"subnumber"`
+	WorkflowNodeRunID int64       `json:"workflow_node_run_id"`
+	Hash              string      `json:"hash"`
+	Start             time.Time   `json:"start"`
+	Tests             venom.Tests `json:"tests"`
+}
+
+//WorkflowTestCase is the history of a test case across the runs of a workflow
+type WorkflowTestCase struct {
+	TestSuite       string                `json:"testsuite"`
+	Name            string                `json:"name"`
+	Runs            []WorkflowTestCaseRun `json:"runs"`
+	Failures        int                   `json:"failures"`
+	AverageDuration float64               `json:"average_duration"`
+	Flakiness       float64               `json:"flakiness"`
+	Quarantined     bool                  `json:"quarantined"`
+}
+
+//WorkflowTestCaseRun is the result of a test case in a node run
+type WorkflowTestCaseRun struct {
+	WorkflowRunNumber int64     `json:"num"`
+	SubNumber         int64     `json:"subnumber"`
+	WorkflowNodeRunID int64     `json:"workflow_node_run_id"`
+	Hash              string    `json:"hash"`
+	Start             time.Time `json:"start"`
+	Status            string    `json:"status"`
+	Duration          float64   `json:"duration"`
+}
+
+//WorkflowTestQuarantine is a test case of a workflow whose failures don't fail the JUnit step
+type WorkflowTestQuarantine struct {
+	ID         int64     `json:"id" db:"id"`
+	WorkflowID int64     `json:"workflow_id" db:"workflow_id"`
+	TestSuite  string    `json:"testsuite" db:"testsuite"`
+	TestCase   string    `json:"testcase" db:"testcase"`
+	Author     string    `json:"author" db:"author"`
+	Created    time.Time `json:"created" db:"created"`
+}
+
+//IsFlaky returns true if the test case failed then passed on the same commit
+func (t WorkflowTestCase) IsFlaky() bool {
+	return t.Flakiness > 0
+}
+
+//TestCaseStatus returns the status of a venom test case
+func TestCaseStatus(tc venom.TestCase) string {
+	switch {
+	case len(tc.Failures) > 0 || len(tc.Errors) > 0:
+		return TestStatusFail
+	case len(tc.Skipped) > 0:
+		return TestStatusSkipped
+	}
+	return TestStatusSuccess
+}
+
+//ComputeTestsHistory computes the history of each test case from the test results of node runs, the most recent first.
+//The flakiness of a test case is the ratio of the commits on which it both failed and passed, among the tested commits.
+func ComputeTestsHistory(nodeRuns []WorkflowNodeRunTests, quarantine []WorkflowTestQuarantine) []WorkflowTestCase {
+	type key struct {
+		testsuite, name string
+	}
+	index := map[key]*WorkflowTestCase{}
+	keys := []key{}
+
+	for _, nr := range nodeRuns {
+		for _, ts := range nr.Tests.TestSuites {
+			for _, tc := range ts.TestCases {
+				k := key{ts.Name, tc.Name}
+				t, ok := index[k]
+				if !ok {
+					t = &WorkflowTestCase{TestSuite: ts.Name, Name: tc.Name}
+					index[k] = t
+					keys = append(keys, k)
+				}
+				duration, _ := strconv.ParseFloat(tc.Time, 64)
+				t.Runs = append(t.Runs, WorkflowTestCaseRun{
+					WorkflowRunNumber: nr.WorkflowRunNumber,
+					SubNumber:         nr.SubNumber,
+					WorkflowNodeRunID: nr.WorkflowNodeRunID,
+					Hash:              nr.Hash,
+					Start:             nr.Start,
+					Status:            TestCaseStatus(tc),
+					Duration:          duration,
+				})
+			}
+		}
+	}
+
+	for _, q := range quarantine {
+		if t, ok := index[key{q.TestSuite, q.TestCase}]; ok {
+			t.Quarantined = true
+		}
+	}
+
+	res := make([]WorkflowTestCase, 0, len(keys))
+	for _, k := range keys {
+		t := index[k]
+		var totalDuration float64
+		var nbRuns int
+		commits := map[string]map[string]bool{}
+		for _, r := range t.Runs {
+			if r.Status == TestStatusSkipped {
+				continue
+			}
+			nbRuns++
+			totalDuration += r.Duration
+			if r.Status == TestStatusFail {
+				t.Failures++
+			}
+			if r.Hash == "" {
+				continue
+			}
+			if commits[r.Hash] == nil {
+				commits[r.Hash] = map[string]bool{}
+			}
+			commits[r.Hash][r.Status] = true
+		}
+		if nbRuns > 0 {
+			t.AverageDuration = totalDuration / float64(nbRuns)
+		}
+		var flakyCommits int
+		for _, statuses := range commits {
+			if statuses[TestStatusFail] && statuses[TestStatusSuccess] {
+				flakyCommits++
+			}
+		}
+		if len(commits) > 0 {
+			t.Flakiness = float64(flakyCommits) / float64(len(commits))
+		}
+		res = append(res, *t)
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		if res[i].Flakiness != res[j].Flakiness {
+			return res[i].Flakiness > res[j].Flakiness
+		}
+		if res[i].TestSuite != res[j].TestSuite {
+			return res[i].TestSuite < res[j].TestSuite
+		}
+		return res[i].Name < res[j].Name
+	})
+	return res
+}
@@ -0,0 +1,47 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/ovh/venom"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeTestsHistory(t *testing.T) {
+	failed := venom.TestCase{Name: "TestB", Failures: []venom.Failure{{Value: "boom"}}, Time: "3"}
+	nodeRuns := []WorkflowNodeRunTests{
+		{WorkflowRunNumber: 3, WorkflowNodeRunID: 30, Hash: "bbb", Tests: venom.Tests{TestSuites: []venom.TestSuite{
+			{Name: "pkg", TestCases: []venom.TestCase{{Name: "TestA", Time: "1"}, {Name: "TestB", Time: "1"}}},
+		}}},
+		{WorkflowRunNumber: 2, WorkflowNodeRunID: 20, Hash: "bbb", Tests: venom.Tests{TestSuites: []venom.TestSuite{
+			{Name: "pkg", TestCases: []venom.TestCase{{Name: "TestA", Time: "2"}, failed}},
+		}}},
+		{WorkflowRunNumber: 1, WorkflowNodeRunID: 10, Hash: "aaa", Tests: venom.Tests{TestSuites: []venom.TestSuite{
+			{Name: "pkg", TestCases: []venom.TestCase{{Name: "TestA", Time: "3"}, {Name: "TestB", Skipped: []venom.Skipped{{Value: "skip"}}}}},
+		}}},
+	}
+	quarantine := []WorkflowTestQuarantine{{TestSuite: "pkg", TestCase: "TestB"}}
+
+	tests := ComputeTestsHistory(nodeRuns, quarantine)
+	if !assert.Len(t, tests, 2) {
+		t.FailNow()
+	}
+
+	// flaky tests first
+	assert.Equal(t, "TestB", tests[0].Name)
+	assert.True(t, tests[0].IsFlaky())
+	assert.Equal(t, 1.0, tests[0].Flakiness)
+	assert.Equal(t, 1, tests[0].Failures)
+	assert.Equal(t, 2.0, tests[0].AverageDuration)
+	assert.True(t, tests[0].Quarantined)
+	if assert.Len(t, tests[0].Runs, 3) {
+		assert.Equal(t, TestStatusSuccess, tests[0].Runs[0].Status)
+		assert.Equal(t, TestStatusFail, tests[0].Runs[1].Status)
+		assert.Equal(t, TestStatusSkipped, tests[0].Runs[2].Status)
+	}
+
+	assert.Equal(t, "TestA", tests[1].Name)
+	assert.False(t, tests[1].IsFlaky())
+	assert.Equal(t, 2.0, tests[1].AverageDuration)
+	assert.False(t, tests[1].Quarantined)
+}
@@ -11,6 +11,9 @@ export class Action {
     actions: Array<Action>;
     optional: boolean;
     always_executed: boolean;
+    parallel: boolean;
+    step_name: string;
+    condition: string;
     last_modified: boolean;
     enabled: boolean;
     deprecated: boolean;
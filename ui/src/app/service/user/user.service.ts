@@ -45,6 +45,20 @@ export class UserService {
         });
     }
 
+    /**
+     * LogIn user to API with the one-time code given by the OpenID Connect callback
+     * @param code One-time code
+     * @returns {Observable<User>}
+     */
+    loginOIDC(code: string): Observable<User> {
+        return this._http.post<any>('/login/oidc/session', {code: code}, {observe: 'response'}).map(res => {
+            let u = res.body.user;
+            u.token = res.headers.get(this._authStore.localStorageSessionKey);
+            this._authStore.addUser(u, true);
+            return u;
+        });
+    }
+
     resetPassword(user: User, href: string) {
         let request = {
             user: user,
@@ -4,6 +4,7 @@ import {UserService} from '../../../service/user/user.service';
 import {Router, ActivatedRoute} from '@angular/router';
 import {AuthentificationStore} from '../../../service/auth/authentification.store';
 import {AccountComponent} from '../account.component';
+import {environment} from '../../../../environments/environment';
 
 @Component({
     selector: 'app-account-login',
@@ -22,17 +23,30 @@ export class LoginComponent extends AccountComponent {
 
         this._route.queryParams.subscribe(queryParams => {
            this.redirect = queryParams.redirect;
+           if (queryParams.oidc_code) {
+               this.signInOIDC(queryParams.oidc_code);
+           }
         });
     }
 
     signIn() {
-        this._userService.login(this.user).subscribe(() => {
-            if (this.redirect) {
-                this._router.navigateByUrl(decodeURIComponent(this.redirect));
-            } else {
-                this._router.navigate(['home']);
-            }
-        });
+        this._userService.login(this.user).subscribe(() => this.navigateAfterLogin());
+    }
+
+    signInOIDC(code: string) {
+        this._userService.loginOIDC(code).subscribe(() => this.navigateAfterLogin());
+    }
+
+    navigateToOIDC() {
+        window.location.href = environment.apiURL + '/login/oidc/authorize';
+    }
+
+    navigateAfterLogin() {
+        if (this.redirect) {
+            this._router.navigateByUrl(decodeURIComponent(this.redirect));
+        } else {
+            this._router.navigate(['home']);
+        }
     }
 
     navigateToSignUp() {